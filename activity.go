@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// --- ACTIVITY LOG ---
+//
+// Every change made through `todo serve` is appended to activity.jsonl in the
+// config dir together with the user who made it. New tasks also carry a
+// hidden by:<user> token shown in the detail view.
+
+const activityFile = "activity.jsonl"
+
+type activityEntry struct {
+	Time   time.Time `json:"time"`
+	File   string    `json:"file"`
+	User   string    `json:"user"`
+	Action string    `json:"action"`
+	ID     string    `json:"id"`
+	Title  string    `json:"title"`
+}
+
+type userKey struct{}
+
+// requestUser is the basic-auth user, else the X-Todo-User header a token
+// client sends, else "api".
+func requestUser(r *http.Request) string {
+	if u, ok := r.Context().Value(userKey{}).(string); ok && u != "" {
+		return u
+	}
+	if u := strings.Join(strings.Fields(r.Header.Get("X-Todo-User")), "_"); u != "" {
+		return u
+	}
+	return "api"
+}
+
+func withUser(r *http.Request, user string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), userKey{}, user))
+}
+
+func activityPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, appName, activityFile), nil
+}
+
+func appendActivity(e activityEntry) {
+	path, err := activityPath()
+	if err != nil {
+		return
+	}
+	os.MkdirAll(filepath.Dir(path), 0755)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	data, _ := json.Marshal(e)
+	f.Write(append(data, '\n'))
+}
+
+// readActivity returns the entries of one file, optionally of a single author.
+func readActivity(filename, author string) []activityEntry {
+	path, err := activityPath()
+	if err != nil {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	key := stateKey(filename)
+	out := []activityEntry{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e activityEntry
+		if json.Unmarshal(scanner.Bytes(), &e) != nil || e.File != key {
+			continue
+		}
+		if author != "" && !strings.EqualFold(e.User, author) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+func (s *apiServer) logActivity(r *http.Request, action string, t apiTask) {
+	switch action {
+	case "add":
+		s.fireHook("add", &t)
+	case "done":
+		s.fireHook("complete", &t)
+	}
+	appendActivity(activityEntry{
+		Time:   time.Now(),
+		File:   stateKey(s.filename),
+		User:   requestUser(r),
+		Action: action,
+		ID:     t.ID,
+		Title:  t.Title,
+	})
+}
+
+func (s *apiServer) handleActivity(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, readActivity(s.filename, r.URL.Query().Get("author")))
+}
+
+// runActivity implements `todo activity [--author name] [file]`.
+func runActivity(args []string) {
+	fs := flag.NewFlagSet("activity", flag.ExitOnError)
+	author := fs.String("author", "", "only show changes by this user")
+	fs.Parse(args)
+
+	filename := "todo.md"
+	if fs.NArg() > 0 {
+		filename = fs.Arg(0)
+	}
+	for _, e := range readActivity(filename, *author) {
+		fmt.Printf("%s  %-10s %-8s %s\n", e.Time.Local().Format("2006-01-02 15:04"), e.User, e.Action, e.Title)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+)
+
+// --- QUICK ADD (todo add) ---
+//
+// `todo add call mom` adds a task to the end of the list without opening
+// it; `todo add -` (or `todo add` in a pipe) reads the tasks from stdin, one
+// per line, so other programs can feed the list:
+//
+//	git log --format='- review %s' -3 | todo add
+//
+// The lines are read as a paste in the app is: bullets and checkboxes are
+// dropped, "[x]" marks a task done, indentation nests the tasks and dates
+// like "due:fri" are filled in. The file is --file, else the usual list. An
+// open TUI picks the change up as it does any edit from outside, merging it
+// with changes of its own not saved yet (see listWriter); the "add" and
+// "save" hooks run as they do there.
+
+// addTasks appends the tasks in text to filename and returns them.
+func addTasks(filename, text string, cfg Config, now time.Time) ([]model.Item, error) {
+	added := pastedItems(text, now)
+	if len(added) == 0 {
+		return nil, errors.New("nothing to add")
+	}
+	var items []model.Item
+	at := 0
+	err := withWriteLock(filename, func() error {
+		var trash []model.Item
+		var err error
+		if items, trash, err = storage.Load(filename); err != nil {
+			return err
+		}
+		at = len(items)
+		items = append(items, added...)
+		return saveList(filename, items, trash, cfg.cloudSafe(filename))
+	})
+	if err != nil {
+		return nil, err
+	}
+	recordHistory(filename, items, now)
+
+	// Program zaraz się kończy, więc hooki czekamy tu
+	for i := range added {
+		task := toAPITask(items, at+i)
+		if h, ok := newHookCall(cfg.Hooks, "add", filename, &task); ok {
+			if err := h.run(); err != nil {
+				fmt.Fprintf(os.Stderr, "todo add: hook add: %v\n", err)
+			}
+		}
+	}
+	if h, ok := newHookCall(cfg.Hooks, "save", filename, nil); ok {
+		if err := h.run(); err != nil {
+			fmt.Fprintf(os.Stderr, "todo add: hook save: %v\n", err)
+		}
+	}
+	return added, nil
+}
+
+func runAdd(args []string) {
+	fs := flag.NewFlagSet("add", flag.ExitOnError)
+	file := fs.String("file", "", "the list to add to (default: the usual one)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: todo add [--file todo.md] TITLE ...")
+		fmt.Fprintln(fs.Output(), "       todo add [--file todo.md] [-] < tasks.txt")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var text string
+	switch {
+	case fs.NArg() == 0 && term.IsTerminal(os.Stdin.Fd()):
+		fs.Usage()
+		os.Exit(2)
+	case fs.NArg() == 0 || (fs.NArg() == 1 && fs.Arg(0) == "-"):
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		text = string(data)
+	default:
+		// Tytuł z argumentów to zawsze jedno zadanie
+		text = strings.Join(strings.Fields(strings.Join(fs.Args(), " ")), " ")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	filename := *file
+	if filename == "" {
+		filename = defaultFile()
+	}
+	added, err := addTasks(filename, text, cfg, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	printAdded(added, filename)
+}
+
+// printAdded tells what addTasks added.
+func printAdded(added []model.Item, filename string) {
+	if len(added) == 1 {
+		fmt.Printf("Added %q to %s\n", model.DisplayTitle(added[0].Title), abbreviateHome(filename))
+	} else {
+		fmt.Printf("Added %d tasks to %s\n", len(added), abbreviateHome(filename))
+	}
+}
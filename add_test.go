@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddTasks(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	f := filepath.Join(dir, "todo.md")
+	os.WriteFile(f, []byte("Notes on top.\n\n- [ ] first\n"), 0644)
+	now := time.Date(2030, 1, 2, 9, 0, 0, 0, time.Local)
+
+	var cfg Config
+	log := filepath.Join(dir, "hooks.log")
+	if runtime.GOOS != "windows" {
+		cfg.Hooks = map[string]string{"add": "cat >> " + log + "; echo >> " + log}
+	}
+	added, err := addTasks(f, "* plan trip\n  - [x] book train\n\tpack due:+1d\n\n", cfg, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 3 {
+		t.Fatalf("added %d tasks", len(added))
+	}
+	want := "Notes on top.\n\n- [ ] first\n" +
+		"- [ ] plan trip created:2030-01-02\n" +
+		"  - [x] book train created:2030-01-02\n" +
+		"    - [ ] pack due:2030-01-03 created:2030-01-02\n"
+	if data, _ := os.ReadFile(f); string(data) != want {
+		t.Errorf("saved:\n%s", data)
+	}
+	if runtime.GOOS != "windows" {
+		data, _ := os.ReadFile(log)
+		if n := strings.Count(string(data), `"event":"add"`); n != 3 {
+			t.Errorf("add hook ran %d times:\n%s", n, data)
+		}
+	}
+
+	if _, err := addTasks(f, " \n\t\n", cfg, now); err == nil {
+		t.Error("blank input must add nothing")
+	}
+	fresh := filepath.Join(dir, "new.md")
+	if _, err := addTasks(fresh, "call mom", Config{}, now); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(fresh); string(data) != "- [ ] call mom created:2030-01-02\n" {
+		t.Errorf("new list = %q", data)
+	}
+}
+
+func TestAddWhileTheAppHasUnsavedChanges(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	f := filepath.Join(t.TempDir(), "todo.md")
+	os.WriteFile(f, []byte("- [ ] a\n- [ ] b\n"), 0644)
+	m := initialModel(f)
+	defer m.lock.release()
+
+	next, _ := m.Update(keyMsg(" "))
+	m = next.(app)
+	if _, err := addTasks(f, "from outside", Config{}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	next, cmd := m.Update(saveTickMsg{})
+	m = next.(app)
+	next, _ = m.Update(cmd())
+	m = next.(app)
+	data, _ := os.ReadFile(f)
+	if !strings.HasPrefix(string(data), "- [x] a\n- [ ] b\n- [ ] from outside") {
+		t.Fatalf("the app's save dropped the added task: %q", data)
+	}
+
+	// Watcher wczytuje scaloną listę
+	next, _ = m.Update(fileCheckMsg{})
+	m = next.(app)
+	if got := flat(m.items); len(got) != 3 || !strings.HasPrefix(got[2], "0:from outside") {
+		t.Errorf("after the merge the app shows %q", got)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- AGENDA ---
+
+// agendaEntries returns the indices of open tasks with a due date, earliest first.
+func agendaEntries(items []model.Item) []int {
+	var out []int
+	for i, it := range items {
+		if _, _, ok := model.DueTime(it.Title); ok && !it.Done {
+			out = append(out, i)
+		}
+	}
+	sort.SliceStable(out, func(a, b int) bool {
+		da, _, _ := model.DueTime(items[out[a]].Title)
+		db, _, _ := model.DueTime(items[out[b]].Title)
+		return da.Before(db)
+	})
+	return out
+}
+
+func isSnoozed(title, today string) bool {
+	v := model.MetaValue(title, "snooze")
+	return v != "" && v > today
+}
+
+func (m app) updateAgenda(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	entries := agendaEntries(m.items)
+	m.cursorAgenda = min(m.cursorAgenda, max(0, len(entries)-1))
+	switch msg.String() {
+	case "esc":
+		m.state = m.agendaBack
+	case "up", "k":
+		if m.cursorAgenda > 0 {
+			m.cursorAgenda--
+		}
+	case "down", "j":
+		if m.cursorAgenda < len(entries)-1 {
+			m.cursorAgenda++
+		}
+	case "enter":
+		if len(entries) > 0 {
+			m.state = viewMain
+			m.jumpTo(entries[m.cursorAgenda])
+		}
+	case "r":
+		if len(entries) > 0 {
+			m.openDatePopup(entries[m.cursorAgenda], "due", "Reschedule")
+		}
+	}
+	return m, nil
+}
+
+func agendaDayLabel(day time.Time, today time.Time) string {
+	label := i18n.DayMonth(day)
+	switch {
+	case day.Equal(today):
+		label += " · " + i18n.T("today")
+	case day.Equal(today.AddDate(0, 0, 1)):
+		label += " · " + i18n.T("tomorrow")
+	case day.Before(today):
+		label += " · " + i18n.T("overdue")
+	}
+	return label
+}
+
+func (m app) renderAgenda(height int, t theme.Theme) string {
+	entries := agendaEntries(m.items)
+	cursor := min(m.cursorAgenda, max(0, len(entries)-1))
+	today := model.StartOfDay(time.Now())
+
+	var lines []string
+	cursorLine := 0
+	var lastDay time.Time
+	for i, idx := range entries {
+		due, hasTime, _ := model.DueTime(m.items[idx].Title)
+		day := model.StartOfDay(due)
+		if i == 0 || !day.Equal(lastDay) {
+			style := lipgloss.NewStyle().Foreground(t.Accent).Bold(true)
+			if day.Before(today) {
+				style = style.Foreground(t.Error)
+			}
+			lines = append(lines, style.Render(agendaDayLabel(day, today)))
+			lastDay = day
+		}
+
+		marker := "  "
+		titleStyle := lipgloss.NewStyle().Foreground(t.Text)
+		if i == cursor {
+			marker = " ➤"
+			titleStyle = titleStyle.Foreground(t.Highlight).Bold(true)
+			cursorLine = len(lines)
+		}
+		clock := "     "
+		if hasTime {
+			clock = due.Format("15:04")
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(t.Highlight).Render(marker)+" "+
+			lipgloss.NewStyle().Foreground(t.Comment).Render(clock)+" "+
+			titleStyle.Render(model.DisplayTitle(m.items[idx].Title)))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(t.Comment).Render("  (Nothing scheduled)"))
+	}
+
+	start, end := ui.Paginator(cursorLine, height, len(lines))
+	return m.frame(height, t.Highlight).
+		Render(strings.Join(lines[start:end], "\n"))
+}
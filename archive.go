@@ -0,0 +1,282 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- ARCHIVE ---
+//
+// ":archive" moves finished tasks (with their subtrees, locked sections
+// excepted) out of the list into todo.archive.md next to it; ":archive view"
+// browses that file. With "archive_journal": true completing a task stamps a
+// hidden completed:<date>, and archived tasks are filed under month and day
+// headings ("# 2026-10" > "## 2026-10-16") in date order, which turns the
+// archive into a log of what got done. Tasks finished before that have no
+// date and go under "# undated" at the end. The archive is written under its write lock
+// (see lock.go) before the list, which is then saved right away.
+
+var (
+	journalMonth = regexp.MustCompile(`^\d{4}-\d{2}$`)
+	journalDay   = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+const journalUndated = "undated"
+
+// archivePath is todo.archive.md for todo.md.
+func archivePath(filename string) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + ".archive" + ext
+}
+
+// completedDay is when a task was finished, if it was stamped.
+func completedDay(it model.Item) (time.Time, bool) {
+	d, err := time.ParseInLocation(model.DateLayout, model.MetaValue(it.Title, "completed"), time.Local)
+	return d, err == nil
+}
+
+// journalHeading finds the heading titled name among the children of parent
+// (-1 for top level), inserting it in date order among its sibling headings,
+// before "undated", when missing, and returns its index.
+func journalHeading(archive []model.Item, parent int, name string, pattern *regexp.Regexp) ([]model.Item, int) {
+	level, start, end := 0, 0, len(archive)
+	if parent != -1 {
+		level, start, end = archive[parent].Level+1, parent+1, model.SubtreeEnd(archive, parent)
+	}
+	at := end
+	for i := start; i < end; i++ {
+		if archive[i].Level != level {
+			continue
+		}
+		title := model.DisplayTitle(archive[i].Title)
+		if title == name {
+			return archive, i
+		}
+		if ((pattern.MatchString(title) && title > name) || title == journalUndated) && at == end {
+			at = i
+		}
+	}
+	return slices.Insert(archive, at, model.Item{Title: name, Level: level, Heading: level + 1}), at
+}
+
+// fileInArchive adds a finished subtree to the archive: at the end, or under
+// its month and day headings in journal mode.
+func fileInArchive(archive, block []model.Item, journal bool) []model.Item {
+	block = slices.Clone(block)
+	base, at, level := block[0].Level, len(archive), 0
+	if day, ok := completedDay(block[0]); journal && ok {
+		var month, d int
+		archive, month = journalHeading(archive, -1, day.Format("2006-01"), journalMonth)
+		archive, d = journalHeading(archive, month, day.Format(model.DateLayout), journalDay)
+		at, level = model.SubtreeEnd(archive, d), 2
+	} else if journal {
+		var u int
+		archive, u = journalHeading(archive, -1, journalUndated, journalMonth)
+		at, level = model.SubtreeEnd(archive, u), 1
+	}
+	for i := range block {
+		block[i].Level += level - base
+	}
+	return slices.Insert(archive, at, block...)
+}
+
+// tidyJournal turns the month and day entries archives used to have as
+// plain tasks into headings, and files top-level tasks that would end up
+// inside a heading once saved (archived before journal mode) by their date.
+func tidyJournal(archive []model.Item) []model.Item {
+	for i, it := range archive {
+		title := model.DisplayTitle(it.Title)
+		if it.Heading == 0 && ((it.Level == 0 && (journalMonth.MatchString(title) || title == journalUndated)) ||
+			(it.Level == 1 && journalDay.MatchString(title))) {
+			archive[i].Heading, archive[i].Done, archive[i].State = it.Level+1, false, ""
+		}
+	}
+	first := slices.IndexFunc(archive, func(it model.Item) bool { return it.Heading > 0 })
+	if first == -1 {
+		return archive
+	}
+	for i := first; i < len(archive); {
+		if archive[i].Level > 0 || archive[i].Heading > 0 {
+			i++
+			continue
+		}
+		end := model.SubtreeEnd(archive, i)
+		block := slices.Clone(archive[i:end])
+		archive = fileInArchive(slices.Delete(archive, i, end), block, true)
+	}
+	return archive
+}
+
+// archiveDone moves every sweepable finished subtree into the archive.
+func archiveDone(items, archive []model.Item, journal bool) ([]model.Item, []model.Item, int) {
+	if journal {
+		archive = tidyJournal(archive)
+	}
+	moved := 0
+	for i := 0; i < len(items); {
+		if !sweepable(items, i) {
+			i++
+			continue
+		}
+		end := model.SubtreeEnd(items, i)
+		model.HandOverText(items, i, end)
+		archive = fileInArchive(archive, items[i:end], journal)
+		items = slices.Delete(items, i, end)
+		moved++
+	}
+	return items, archive, moved
+}
+
+func (m *app) archiveCommand(arg string) {
+	if m.workspace != nil {
+		m.status = "The archive works on one file: open it on its own"
+		return
+	}
+	path := archivePath(m.filename)
+	switch arg {
+	case "":
+		var items []model.Item
+		n := 0
+		err := withWriteLock(path, func() error {
+			archive, trash, err := storage.Load(path)
+			if err != nil {
+				return fmt.Errorf("can't read it: %w", err)
+			}
+			var moved []model.Item
+			items, moved, n = archiveDone(m.items, archive, m.config.ArchiveJournal)
+			if n == 0 {
+				return nil
+			}
+			return saveList(path, moved, trash, m.config.cloudSafe(path))
+		})
+		if err != nil {
+			m.showError("Can't write the archive", err.Error())
+			return
+		}
+		if n == 0 {
+			m.status = "Nothing finished to archive"
+			return
+		}
+		m.items = items
+		m.recalcVisible()
+		m.save()
+		// Archiwum jest już zapisane, więc listę zapisujemy od razu, nie za chwilę
+		if err := m.flushSave(); err != nil {
+			m.showError("Archived, but can't save the list", fmt.Sprintf(
+				"%v\nThe %d archived tasks are also still in %s until it is saved.", err, n, filepath.Base(m.filename)))
+			return
+		}
+		m.status = fmt.Sprintf("Archived %d finished tasks to %s", n, filepath.Base(path))
+	case "view":
+		archive, _, err := storage.Load(path)
+		if err != nil {
+			m.showError("Can't read the archive", err.Error())
+			return
+		}
+		if len(archive) == 0 {
+			m.status = "The archive is empty — :archive moves finished tasks there"
+			return
+		}
+		// Starsze miesiące zwinięte, ostatni rozwinięty
+		lastTop := -1
+		for i := range archive {
+			if archive[i].Level == 0 {
+				lastTop = i
+				archive[i].Collapsed = model.HasChildren(archive, i)
+			}
+		}
+		archive[lastTop].Collapsed = false
+		m.archive = archive
+		m.archiveVisible = model.Visible(archive, nil)
+		m.cursorArchive = 0
+		m.state = viewArchive
+	default:
+		m.status = "Usage: :archive [view]"
+	}
+}
+
+func (m app) updateArchive(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.archive, m.archiveVisible = nil, nil
+		m.state = viewMain
+	case "up", "k":
+		if m.cursorArchive > 0 {
+			m.cursorArchive--
+		}
+	case "down", "j":
+		if m.cursorArchive < len(m.archiveVisible)-1 {
+			m.cursorArchive++
+		}
+	case "v", " ", "enter":
+		if len(m.archiveVisible) == 0 {
+			break
+		}
+		// Zwijanie tylko w pamięci; plik archiwum zostaje nietknięty
+		if model.ToggleFold(m.archive, m.archiveVisible[m.cursorArchive].Index) {
+			m.archiveVisible = model.Visible(m.archive, nil)
+		}
+	}
+	return m, nil
+}
+
+func (m app) renderArchive(height int, t theme.Theme) string {
+	start, end := ui.Paginator(m.cursorArchive, height, len(m.archiveVisible))
+	guides := ui.TreeGuides(m.archiveVisible, start, end)
+	guide := lipgloss.NewStyle().Foreground(t.Comment)
+
+	var lines []string
+	for i := start; i < end; i++ {
+		it, g := m.archiveVisible[i].Data, guides[i-start]
+		marker := "  "
+		if i == m.cursorArchive {
+			marker = " ➤"
+		}
+		title := model.DisplayTitle(it.Title)
+		var row string
+		if it.Heading > 0 || (it.Level == 0 && journalMonth.MatchString(title)) || (it.Level == 1 && journalDay.MatchString(title)) {
+			fold := "▾"
+			if it.Collapsed {
+				fold = "▸"
+			}
+			if d, err := time.Parse(model.DateLayout, title); err == nil {
+				title += " · " + i18n.Weekday(d.Weekday(), false)
+			} else if d, err := time.Parse("2006-01", title); err == nil {
+				title += " · " + i18n.Month(d.Month(), false)
+			}
+			style := lipgloss.NewStyle().Foreground(t.Accent).Bold(true)
+			if i == m.cursorArchive {
+				style = style.Foreground(t.Highlight)
+			}
+			row = style.Render(fold + " " + title)
+		} else {
+			check, style := "[ ]", lipgloss.NewStyle().Foreground(t.Text)
+			switch {
+			case it.Collapsed:
+				check = "[+]"
+			case it.Done:
+				check, style = "[✔]", guide
+			}
+			if i == m.cursorArchive {
+				style = style.Foreground(t.Highlight).Bold(true)
+			}
+			row = guide.Render(check) + " " + style.Render(title)
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(t.Highlight).Render(marker)+guide.Render(g.Prefix+g.Connector)+row)
+	}
+
+	return m.frame(height, t.Highlight).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+)
+
+func TestArchiveJournal(t *testing.T) {
+	archive := []model.Item{{Title: "2026-10"}, {Title: "2026-10-20", Level: 1}, {Title: "later", Level: 2, Done: true}}
+	items := []model.Item{
+		{Title: "ship it completed:2026-10-03", Done: true},
+		{Title: "open"},
+		{Title: "Project"}, {Title: "step completed:2026-09-30", Level: 1, Done: true}, {Title: "detail", Level: 2, Done: true},
+		{Title: "unstamped", Done: true},
+		{Title: "keep lock:1", Done: true},
+	}
+	items, archive, n := archiveDone(items, archive, true)
+	if n != 3 {
+		t.Fatalf("archived %d", n)
+	}
+	var rest []string
+	for _, it := range items {
+		rest = append(rest, model.DisplayTitle(it.Title))
+	}
+	if strings.Join(rest, ",") != "open,Project,keep" {
+		t.Errorf("left in the list: %v", rest)
+	}
+
+	var got []string
+	for _, it := range archive {
+		got = append(got, strings.Repeat(" ", it.Level)+model.DisplayTitle(it.Title))
+	}
+	want := []string{
+		"2026-09", " 2026-09-30", "  step", "   detail",
+		"2026-10", " 2026-10-03", "  ship it", " 2026-10-20", "  later",
+		"undated", " unstamped",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("archive:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Nagłówki zapisują się jako nagłówki i tak samo wracają
+	path := filepath.Join(t.TempDir(), "todo.archive.md")
+	if err := storage.Save(path, archive, nil); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "# 2026-09\n## 2026-09-30\n- [x] step completed:2026-09-30\n  - [x] detail\n# 2026-10\n") ||
+		!strings.HasSuffix(string(data), "# undated\n- [x] unstamped\n") || strings.Contains(string(data), "[ ]") {
+		t.Errorf("archive file:\n%s", data)
+	}
+	loaded, _, _ := storage.Load(path)
+	if !reflect.DeepEqual(loaded, archive) {
+		t.Errorf("reloaded %+v\nwant %+v", loaded, archive)
+	}
+}
+
+func TestArchiveJournalTidiesOldEntries(t *testing.T) {
+	// Archiwum sprzed nagłówków: miesiące jako zadania i zadania bez daty na końcu
+	archive := []model.Item{{Title: "2026-09"}, {Title: "2026-09-30", Level: 1}, {Title: "step", Level: 2, Done: true}, {Title: "old", Done: true}}
+	items := []model.Item{{Title: "new completed:2026-10-01", Done: true}}
+	_, archive, _ = archiveDone(items, archive, true)
+	var got []string
+	for _, it := range archive {
+		got = append(got, fmt.Sprintf("%s%s:%d", strings.Repeat(" ", it.Level), model.DisplayTitle(it.Title), it.Heading))
+	}
+	want := "2026-09:1, 2026-09-30:2,  step:0,2026-10:1, 2026-10-01:2,  new:0,undated:1, old:0"
+	if strings.Join(got, ",") != want {
+		t.Errorf("archive = %s\nwant %s", strings.Join(got, ","), want)
+	}
+}
+
+func TestArchiveCommand(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	m := app{width: 80, height: 20, filename: filepath.Join(dir, "todo.md"), writer: &listWriter{}, items: []model.Item{
+		{Title: "done", Done: true}, {Title: "open"},
+	}}
+	m.recalcVisible()
+	m.archiveCommand("")
+	if len(m.items) != 1 {
+		t.Fatalf("items = %v", m.items)
+	}
+	archive, _, err := storage.Load(filepath.Join(dir, "todo.archive.md"))
+	if err != nil || len(archive) != 1 || archive[0].Title != "done" {
+		t.Fatalf("archive = %v, %v", archive, err)
+	}
+	// Lista jest zapisywana od razu po archiwum
+	if items, _, _ := storage.Load(m.filename); m.dirty || len(items) != 1 || items[0].Title != "open" {
+		t.Errorf("list saved as %v, dirty %v", items, m.dirty)
+	}
+
+	m.archiveCommand("view")
+	if m.state != viewArchive || len(m.archiveVisible) != 1 {
+		t.Errorf("archive view not opened: state %d", m.state)
+	}
+}
+
+func TestCompletedStamp(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+	items := []model.Item{{Title: "a"}}
+	setDone(items, 0, true, Config{ArchiveJournal: true}, now)
+	if model.MetaValue(items[0].Title, "completed") != "2026-10-16" {
+		t.Errorf("title = %q", items[0].Title)
+	}
+	setDone(items, 0, false, Config{ArchiveJournal: true}, now)
+	if items[0].Title != "a" {
+		t.Errorf("reopened title = %q", items[0].Title)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- ATTACHMENTS ---
+//
+// ":attach PATH" attaches a file to the selected task. The reference is kept
+// as written (file:// URL, ~/…, absolute, or relative to the list) on an
+// "attach: PATH" line of the task's note block, indented under it, so other
+// markdown editors leave it alone. The detail view lists them: Enter opens
+// one with the system handler, x detaches it.
+
+const attachPrefix = "attach: "
+
+func attachments(it model.Item) []string {
+	var out []string
+	for _, line := range it.Note {
+		if ref, ok := strings.CutPrefix(line, attachPrefix); ok && strings.TrimSpace(ref) != "" {
+			out = append(out, strings.TrimSpace(ref))
+		}
+	}
+	return out
+}
+
+// attach adds a reference to the note block, once.
+func attach(it *model.Item, ref string) bool {
+	if slices.Contains(attachments(*it), ref) {
+		return false
+	}
+	it.Note = append(slices.Clone(it.Note), attachPrefix+ref)
+	return true
+}
+
+// detach removes the n-th attachment, keeping the rest of the note.
+func detach(it *model.Item, n int) {
+	var note []string
+	for _, line := range it.Note {
+		if ref, ok := strings.CutPrefix(line, attachPrefix); ok && strings.TrimSpace(ref) != "" {
+			n--
+			if n == -1 {
+				continue
+			}
+		}
+		note = append(note, line)
+	}
+	it.Note = note
+}
+
+// attachmentPath resolves a reference against the list it belongs to.
+func attachmentPath(listFile, ref string) string {
+	if u, err := url.Parse(ref); err == nil && u.Scheme == "file" {
+		return filepath.FromSlash(u.Path)
+	}
+	ref = expandHome(ref)
+	if filepath.IsAbs(ref) {
+		return ref
+	}
+	return filepath.Join(filepath.Dir(listFile), ref)
+}
+
+func (m *app) attachCommand(ref string) {
+	if len(m.visibleItems) == 0 {
+		return
+	}
+	if ref == "" {
+		m.status = "Usage: :attach PATH (file://…, ~/…, absolute or relative to the list)"
+		return
+	}
+	idx := m.visibleItems[m.cursorMain].Index
+	if !attach(&m.items[idx], ref) {
+		m.status = "Already attached: " + ref
+		return
+	}
+	m.refreshItem(idx)
+	m.save()
+	m.status = "📎 Attached " + ref
+	if _, err := os.Stat(attachmentPath(m.filename, ref)); err != nil {
+		m.status += " (not found yet)"
+	}
+}
+
+func (m *app) openAttachment(ref string) {
+	path := attachmentPath(m.filename, ref)
+	if _, err := os.Stat(path); err != nil {
+		m.status = "Can't find " + path
+		return
+	}
+	if err := systemOpen(path); err != nil {
+		m.showError("Can't open "+filepath.Base(path), err.Error())
+		return
+	}
+	m.status = "Opened " + filepath.Base(path)
+}
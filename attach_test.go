@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestAttachmentPath(t *testing.T) {
+	list := filepath.Join("/home", "ann", "notes", "todo.md")
+	for ref, want := range map[string]string{
+		"specs/a.pdf":          filepath.Join("/home", "ann", "notes", "specs", "a.pdf"),
+		"/tmp/b.png":           "/tmp/b.png",
+		"file:///tmp/c%20d.md": "/tmp/c d.md",
+	} {
+		if got := attachmentPath(list, ref); got != want {
+			t.Errorf("attachmentPath(%q) = %q, want %q", ref, got, want)
+		}
+	}
+}
+
+func TestAttachments(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "spec.pdf"), nil, 0644)
+
+	var opened []string
+	defer func(f func(string) error) { systemOpen = f }(systemOpen)
+	systemOpen = func(target string) error {
+		opened = append(opened, target)
+		return nil
+	}
+
+	m := app{filename: filepath.Join(dir, "todo.md"), items: []model.Item{{Title: "review", Note: []string{"keep this line"}}}}
+	m.recalcVisible()
+	m.runCommand("attach spec.pdf")
+	m.runCommand("attach spec.pdf")
+	m.runCommand("attach missing.txt")
+	if got := attachments(m.items[0]); !slices.Equal(got, []string{"spec.pdf", "missing.txt"}) {
+		t.Fatalf("attachments = %q", got)
+	}
+
+	m.openDetail()
+	press := func(k string) {
+		t.Helper()
+		next, _ := m.updateDetail(keyMsg(k))
+		m = next.(app)
+	}
+	press("enter")
+	press("j")
+	press("enter")
+	if !slices.Equal(opened, []string{filepath.Join(dir, "spec.pdf")}) {
+		t.Errorf("opened = %q", opened)
+	}
+	press("x")
+	if want := []string{"keep this line", "attach: spec.pdf"}; !slices.Equal(m.items[0].Note, want) {
+		t.Errorf("note = %q, want %q", m.items[0].Note, want)
+	}
+}
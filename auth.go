@@ -0,0 +1,169 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// --- SERVE: AUTH & TLS ---
+
+const (
+	selfSignedCertFile = "serve-cert.pem"
+	selfSignedKeyFile  = "serve-key.pem"
+)
+
+type serveAuth struct {
+	token string
+	users map[string]string // basic auth: user -> password
+}
+
+func (a serveAuth) enabled() bool {
+	return a.token != "" || len(a.users) > 0
+}
+
+// loadUsers reads "name:password" lines (blank lines and # comments skipped).
+func loadUsers(path string, users map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, pass, ok := strings.Cut(line, ":")
+		if !ok || name == "" || pass == "" {
+			return fmt.Errorf("%s: expected name:password, got %q", path, line)
+		}
+		users[name] = pass
+	}
+	return nil
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// check accepts "Authorization: Bearer <token>", ?token=<token> (handy for
+// phone bookmarks) or basic auth, depending on what is configured. For basic
+// auth it also returns the user name.
+func (a serveAuth) check(r *http.Request) (string, bool) {
+	if a.token != "" {
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && secureEqual(bearer, a.token) {
+			return "", true
+		}
+		if t := r.URL.Query().Get("token"); t != "" && secureEqual(t, a.token) {
+			return "", true
+		}
+	}
+	if u, p, ok := r.BasicAuth(); ok {
+		if want, known := a.users[u]; known && secureEqual(p, want) {
+			return u, true
+		}
+	}
+	return "", false
+}
+
+func (a serveAuth) wrap(next http.Handler) http.Handler {
+	if !a.enabled() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := a.check(r)
+		if !ok {
+			if len(a.users) > 0 {
+				w.Header().Set("WWW-Authenticate", `Basic realm="todo"`)
+			}
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		if user != "" {
+			r = withUser(r, user)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLoopback reports whether addr only listens on localhost.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// selfSignedCert loads the generated certificate from the config dir, creating
+// it on first use so clients can pin a stable fingerprint.
+func selfSignedCert() (tls.Certificate, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	dir := filepath.Join(configDir, appName)
+	certPath := filepath.Join(dir, selfSignedCertFile)
+	keyPath := filepath.Join(dir, selfSignedKeyFile)
+
+	if cert, err := tls.LoadX509KeyPair(certPath, keyPath); err == nil {
+		if leaf, err := x509.ParseCertificate(cert.Certificate[0]); err == nil && time.Now().Before(leaf.NotAfter) {
+			return cert, nil
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	hostname, _ := os.Hostname()
+	tmpl := x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "todo serve"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if hostname != "" {
+		tmpl.DNSNames = append(tmpl.DNSNames, hostname)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(certPath, certPEM, 0644)
+	os.WriteFile(keyPath, keyPEM, 0600)
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
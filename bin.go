@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- BIN SIZE ---
+
+const defaultBinWarn = 100
+
+func (c Config) binWarn() int {
+	if c.BinWarn > 0 {
+		return c.BinWarn
+	}
+	return defaultBinWarn
+}
+
+// purgeOldest drops the oldest deleted subtrees (whole model.TrashBlock
+// units, as the bin shows and restores them) until at most keep items
+// remain. The bin is append-only, so the oldest entries are at the front.
+func purgeOldest(trash []model.Item, keep int) ([]model.Item, int) {
+	start := 0
+	for len(trash)-start > keep {
+		_, start = model.TrashBlock(trash, start)
+	}
+	return trash[start:], start
+}
+
+// snapTrashCursor keeps the bin cursor on the root of a deleted subtree,
+// which the bin treats as one unit.
+func (m *app) snapTrashCursor() {
+	if len(m.trash) == 0 {
+		m.cursorTrash = 0
+		return
+	}
+	m.cursorTrash, _ = model.TrashBlock(m.trash, min(m.cursorTrash, len(m.trash)-1))
+}
+
+// capBin enforces bin_limit, evicting the oldest deleted subtrees first.
+func (c Config) capBin(trash []model.Item) []model.Item {
+	if c.BinLimit > 0 && len(trash) > c.BinLimit {
+		trash, _ = purgeOldest(trash, c.BinLimit)
+	}
+	return trash
+}
+
+// binIndicator is the "Bin: N" footer segment, highlighted above the threshold.
+func (m app) binIndicator(t theme.Theme) string {
+	n := len(m.trash)
+	if n == 0 {
+		return ""
+	}
+	if n > m.config.binWarn() {
+		return lipgloss.NewStyle().Foreground(t.Error).Render(
+			fmt.Sprintf(" • ⚠ Bin: %d (:purge keeps newest %d)", n, m.config.binWarn()))
+	}
+	return lipgloss.NewStyle().Foreground(t.Comment).Render(fmt.Sprintf(" • Bin: %d", n))
+}
+
+func (m *app) purgeBin() {
+	var removed int
+	m.trash, removed = purgeOldest(m.trash, m.config.binWarn())
+	if m.cursorTrash >= len(m.trash) {
+		m.cursorTrash = max(0, len(m.trash)-1)
+	}
+	m.save()
+	m.status = fmt.Sprintf("Purged %d oldest items from the bin", removed)
+}
+
+// emptyBin purges everything; X in the bin asks first.
+func (m *app) emptyBin() {
+	n := len(m.trash)
+	m.trash = nil
+	m.cursorTrash = 0
+	m.save()
+	m.status = fmt.Sprintf("Purged all %d items from the bin", n)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+func TestBinTreatsSubtreesAsUnits(t *testing.T) {
+	m := app{width: 60, height: 20, state: viewTrash}
+	m.items, m.trash = model.DeleteSubtree([]model.Item{{Title: "a"}, {Title: "a1", Level: 1}, {Title: "keep"}}, nil, 0)
+	m.items, m.trash = model.DeleteSubtree(append(m.items, model.Item{Title: "b"}), m.trash, 1)
+	press := func(k string) {
+		t.Helper()
+		next, _ := m.updateTrash(keyMsg(k))
+		m = next.(app)
+	}
+
+	press("j")
+	if m.cursorTrash != 2 {
+		t.Fatalf("j moved to %d, want the next subtree at 2", m.cursorTrash)
+	}
+	press("k")
+	if m.cursorTrash != 0 {
+		t.Fatalf("k moved to %d", m.cursorTrash)
+	}
+	view := m.renderTrash(10, theme.Default)
+	if !strings.Contains(view, "➤") || !strings.Contains(view, "┃") {
+		t.Errorf("selection does not span the subtree:\n%s", view)
+	}
+
+	press("x")
+	if len(m.trash) != 1 || model.DisplayTitle(m.trash[0].Title) != "b" || m.cursorTrash != 0 {
+		t.Errorf("x purged %+v", m.trash)
+	}
+}
+
+func TestPurgeOldestByTrashBlock(t *testing.T) {
+	// Zadanie usunięte spod rodzica, potem głębsze spod innego: dwa bloki
+	trash := []model.Item{
+		{Title: "x from:p", Level: 1},
+		{Title: "y from:q", Level: 2},
+		{Title: "z"},
+	}
+	kept, removed := purgeOldest(trash, 2)
+	if removed != 1 || len(kept) != 2 || model.DisplayTitle(kept[0].Title) != "y" {
+		t.Errorf("purged %d, kept %+v", removed, kept)
+	}
+}
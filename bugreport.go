@@ -0,0 +1,281 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+)
+
+// --- BUG REPORT BUNDLE ---
+//
+// `todo bugreport [--structure] [-o dir] [file]` (or ":bugreport [structure]"
+// in the TUI) zips what an issue usually needs: build and terminal info, the
+// config with credentials, paths and hooks blanked, the themes.json files,
+// the tail of the activity log and, when asked for, the shape of the list
+// with every title replaced by a short hash. It prints the path of the zip;
+// nothing is sent anywhere.
+
+const bugReportActivityLines = 200
+
+// redactedConfigKeys hold credentials or reveal private paths, hosts and
+// commands. Every other key of the config (at any depth) must be listed in
+// safeConfigKeys; a test checks that each one is in one of the two.
+var redactedConfigKeys = map[string]bool{
+	"url": true, "username": true, "password": true, "idle_lock_hash": true, "workspaces": true,
+	"hooks": true, "file": true, "inbox": true,
+}
+
+// safeConfigKeys are copied into the report as they are.
+var safeConfigKeys = map[string]bool{
+	"selected_theme": true, "caldav": true, "interval_minutes": true, "conflict": true,
+	"lint": true, "max_age_months": true, "notify": true,
+	"fields": true, "name": true, "type": true, "values": true,
+	"pomodoro_minutes": true, "autosort": true, "bin_warn": true, "bin_limit": true, "delete_limit": true,
+	"keys": true, "holidays": true, "workdays_only": true, "cascade_complete": true, "daily_capacity": true,
+	"feeds": true, "section": true, "tag": true, "retain_days": true, "feed_minutes": true,
+	"cloud_save": true, "line_ending": true, "indent": true,
+	"header": true, "truncate": true, "home": true, "min_width": true, "format": true, "counts": true,
+	"footer": true, "archive_journal": true, "idle_lock_minutes": true,
+	"checkbox_states": true, "mark": true, "color": true, "space_cycles": true,
+	"completed": true, "mode": true, "hide_after_minutes": true, "score_weights": true,
+	"split_width": true, "split_ratio": true, "locale": true, "date_format": true, "week_start": true,
+	"compact": true, "line_numbers": true, "colors": true, "filters": true,
+	"perspectives": true, "filter": true, "sort": true, "fold": true,
+}
+
+// redactConfig is the config as JSON with the redactedConfigKeys blanked at
+// any depth.
+func redactConfig(cfg Config) ([]byte, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	var redact func(v any) any
+	redact = func(v any) any {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, sub := range v {
+				if redactedConfigKeys[k] {
+					v[k] = "<redacted>"
+				} else {
+					v[k] = redact(sub)
+				}
+			}
+		case []any:
+			for i := range v {
+				v[i] = redact(v[i])
+			}
+		}
+		return v
+	}
+	return json.MarshalIndent(redact(tree), "", "  ")
+}
+
+// anonymizedList keeps the structure of a list (levels, done and folded
+// state, which metadata keys and how many tags a task has) and replaces each
+// title with a hash, so equal titles stay recognisable.
+func anonymizedList(items []model.Item) string {
+	var b strings.Builder
+	for _, it := range items {
+		check := "[ ]"
+		if it.Done {
+			check = "[x]"
+		}
+		var meta []string
+		for _, tok := range strings.Fields(it.Title) {
+			if k, _, ok := strings.Cut(tok, ":"); ok && metaWord.MatchString(tok) {
+				meta = append(meta, k+":")
+			}
+		}
+		line := fmt.Sprintf("%s- %s %s", strings.Repeat("  ", it.Level), check, shortHash(linkName(it.Title)))
+		if n := len(model.Tags(it.Title)); n > 0 {
+			line += fmt.Sprintf(" #×%d", n)
+		}
+		if len(meta) > 0 {
+			line += " " + strings.Join(meta, " ")
+		}
+		if it.Collapsed {
+			line += " (folded)"
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+func bugReportInfo(filename string, now time.Time) string {
+	var b strings.Builder
+	version, revision := "(devel)", ""
+	if info, ok := debug.ReadBuildInfo(); ok {
+		version = info.Main.Version
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" || s.Key == "vcs.modified" {
+				revision += " " + s.Key + "=" + s.Value
+			}
+		}
+	}
+	fmt.Fprintf(&b, "todo %s%s\n", version, revision)
+	fmt.Fprintf(&b, "go %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "generated %s\n", now.Format(time.RFC3339))
+	for _, env := range []string{"TERM", "COLORTERM", "TERM_PROGRAM", "LANG", "NO_COLOR"} {
+		fmt.Fprintf(&b, "%s=%s\n", env, os.Getenv(env))
+	}
+	if filename != "" {
+		fmt.Fprintf(&b, "list %s (%s)\n", filepath.Ext(filename), shortHash(stateKey(filename)))
+		if st, err := os.Stat(filename); err == nil {
+			fmt.Fprintf(&b, "size %d bytes\n", st.Size())
+		}
+	}
+	return b.String()
+}
+
+// recentActivity is the tail of the activity log with titles, users and
+// files hashed.
+func recentActivity(n int) []byte {
+	path, err := activityPath()
+	if err != nil {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var lines []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e activityEntry
+		if json.Unmarshal(sc.Bytes(), &e) != nil {
+			continue
+		}
+		e.Title, e.User, e.File = shortHash(e.Title), shortHash(e.User), shortHash(e.File)
+		data, _ := json.Marshal(e)
+		lines = append(lines, string(data))
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// themeFiles returns the themes.json files in use, by name in the bundle.
+func themeFiles() map[string]string {
+	files := map[string]string{"themes/local.json": "themes.json"}
+	if dir, err := os.UserConfigDir(); err == nil {
+		files["themes/user.json"] = filepath.Join(dir, appName, "themes.json")
+	}
+	return files
+}
+
+// writeBugReport writes the bundle into dir and returns its path. items is
+// the list to include anonymized, nil to leave it out.
+func writeBugReport(dir, filename string, items []model.Item, now time.Time) (string, error) {
+	entries := map[string][]byte{"info.txt": []byte(bugReportInfo(filename, now))}
+
+	if cfg, err := loadConfig(); err != nil {
+		entries["config-error.txt"] = []byte(err.Error())
+	} else if data, err := redactConfig(cfg); err == nil {
+		entries["config.json"] = data
+	}
+	for name, path := range themeFiles() {
+		if data, err := os.ReadFile(path); err == nil {
+			entries[name] = data
+		}
+	}
+	if log := recentActivity(bugReportActivityLines); len(log) > 0 {
+		entries["activity.jsonl"] = log
+	}
+	if items != nil {
+		entries["structure.md"] = []byte(anonymizedList(items))
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return "", err
+		}
+		if _, err := w.Write(entries[name]); err != nil {
+			return "", err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, "todo-bugreport-"+now.Format("20060102-150405")+".zip")
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (m *app) bugReportCommand(arg string) {
+	var items []model.Item
+	switch arg {
+	case "":
+	case "structure":
+		items = append([]model.Item{}, m.items...)
+	default:
+		m.status = "Usage: :bugreport [structure]"
+		return
+	}
+	path, err := writeBugReport(os.TempDir(), m.filename, items, time.Now())
+	if err != nil {
+		m.showError("Can't write the bug report", err.Error())
+		return
+	}
+	m.status = "Bug report written to " + path
+}
+
+// runBugReport implements `todo bugreport [--structure] [-o dir] [file]`.
+func runBugReport(args []string) {
+	fs := flag.NewFlagSet("bugreport", flag.ExitOnError)
+	structure := fs.Bool("structure", false, "include the list structure with titles hashed")
+	dir := fs.String("o", os.TempDir(), "directory to write the zip to")
+	fs.Parse(args)
+
+	filename := "todo.md"
+	if fs.NArg() > 0 {
+		filename = fs.Arg(0)
+	}
+	var items []model.Item
+	if *structure {
+		var err error
+		if items, _, err = storage.Load(filename); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if items == nil {
+			items = []model.Item{}
+		}
+	}
+	path, err := writeBugReport(*dir, filename, items, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(path)
+}
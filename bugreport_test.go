@@ -0,0 +1,96 @@
+package main
+
+import (
+	"archive/zip"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+// Nowy klucz w config.json trzeba świadomie uznać za bezpieczny albo ukryć
+func TestBugReportClassifiesConfigKeys(t *testing.T) {
+	seen := make(map[reflect.Type]bool)
+	var walk func(ty reflect.Type)
+	walk = func(ty reflect.Type) {
+		for ty.Kind() == reflect.Pointer || ty.Kind() == reflect.Slice || ty.Kind() == reflect.Map {
+			ty = ty.Elem()
+		}
+		if ty.Kind() != reflect.Struct || seen[ty] {
+			return
+		}
+		seen[ty] = true
+		for i := range ty.NumField() {
+			f := ty.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			if redactedConfigKeys[name] == safeConfigKeys[name] {
+				t.Errorf("%s.%s (%q) must be in exactly one of redactedConfigKeys and safeConfigKeys", ty.Name(), f.Name, name)
+			}
+			walk(f.Type)
+		}
+	}
+	walk(reflect.TypeOf(Config{}))
+}
+
+func TestBugReport(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+
+	data, err := redactConfig(Config{
+		CalDAV:     &CalDAVConfig{URL: "https://dav.example.com/me", Username: "me", Password: "hunter2", Interval: 5},
+		Workspaces: []string{"~/work/secret-project/*.md"},
+		AutoSort:   "due",
+		Hooks:      map[string]string{"save": "curl -d @- https://hooks.example.com/token"},
+		File:       "~/secret-project/todo.md",
+		Inbox:      "~/secret-project/inbox.md",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"dav.example.com", "hunter2", `"me"`, "secret-project", "hooks.example.com"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("config leaks %s:\n%s", secret, data)
+		}
+	}
+	if !strings.Contains(string(data), `"interval_minutes": 5`) || !strings.Contains(string(data), `"autosort": "due"`) {
+		t.Errorf("harmless settings lost:\n%s", data)
+	}
+
+	items := []model.Item{
+		{Title: "call the bank #money due:2026-10-20 id:ab12"},
+		{Title: "call the bank", Level: 1, Done: true},
+	}
+	got := anonymizedList(items)
+	if strings.Contains(got, "bank") || strings.Contains(got, "money") {
+		t.Errorf("titles leak:\n%s", got)
+	}
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "  - [x] ") || !strings.Contains(lines[0], "#×1 due: id:") {
+		t.Errorf("structure lost:\n%s", got)
+	}
+	if h := shortHash("call the bank"); !strings.Contains(lines[0], h) || !strings.Contains(lines[1], h) {
+		t.Errorf("equal titles must hash alike:\n%s", got)
+	}
+
+	path, err := writeBugReport(t.TempDir(), "todo.md", items, time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	if strings.Join(names, " ") != "config.json info.txt structure.md" {
+		t.Errorf("bundle = %v", names)
+	}
+}
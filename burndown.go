@@ -0,0 +1,289 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- BURNDOWN ---
+//
+// Every save records how many tasks of the list are open and done that day
+// in history.json next to the config, one snapshot per file and day. The
+// stats view draws the open count as a bar chart over the last week ("w"),
+// the last 30 days ("m") or any range given as ":stats FROM [TO]" (dates as
+// in due:). A day without a snapshot carries the previous one over; bars
+// falling from the day before are green, rising ones red.
+
+const (
+	historyFile = "history.json"
+	historyKeep = 2 * 366 // days of snapshots kept per file
+	chartHeight = 8
+)
+
+type snapshot struct {
+	Open int `json:"open"`
+	Done int `json:"done"`
+}
+
+type statsRange struct {
+	name     string // "week", "month" or "" for a custom range
+	from, to time.Time
+}
+
+func historyPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, appName, historyFile), nil
+}
+
+func loadAllHistory() map[string]map[string]snapshot {
+	all := make(map[string]map[string]snapshot)
+	if path, err := historyPath(); err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			json.Unmarshal(data, &all)
+		}
+	}
+	return all
+}
+
+// loadHistory returns the snapshots of filename by ISO date.
+func loadHistory(filename string) map[string]snapshot {
+	return loadAllHistory()[stateKey(filename)]
+}
+
+func countSnapshot(items []model.Item) snapshot {
+	var s snapshot
+	for _, it := range items {
+		if it.Heading > 0 {
+			continue
+		}
+		if it.Done {
+			s.Done++
+		} else {
+			s.Open++
+		}
+	}
+	return s
+}
+
+// recordHistory stores today's counts of items, dropping snapshots older
+// than historyKeep days. Nothing is written when they didn't change.
+func recordHistory(filename string, items []model.Item, now time.Time) error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	all := loadAllHistory()
+	key, today := stateKey(filename), now.Format(model.DateLayout)
+	days := all[key]
+	s := countSnapshot(items)
+	if old, ok := days[today]; ok && old == s {
+		return nil
+	}
+	if days == nil {
+		days = make(map[string]snapshot)
+		all[key] = days
+	}
+	days[today] = s
+	oldest := now.AddDate(0, 0, -historyKeep).Format(model.DateLayout)
+	for day := range days {
+		if day < oldest {
+			delete(days, day)
+		}
+	}
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	os.MkdirAll(filepath.Dir(path), 0755)
+	return os.WriteFile(path, data, 0644)
+}
+
+func presetRange(name string, now time.Time) statsRange {
+	to := model.StartOfDay(now)
+	days := 7
+	if name == "month" {
+		days = 30
+	}
+	return statsRange{name, to.AddDate(0, 0, 1-days), to}
+}
+
+// parseStatsRange reads the :stats argument: "week", "month", or one or two
+// dates, the second one today when left out.
+func parseStatsRange(arg string, now time.Time) (statsRange, error) {
+	switch arg {
+	case "", "week":
+		return presetRange("week", now), nil
+	case "month":
+		return presetRange("month", now), nil
+	}
+	fields := strings.Fields(arg)
+	if len(fields) > 2 {
+		return statsRange{}, fmt.Errorf("expected FROM [TO], got %q", arg)
+	}
+	r := statsRange{to: model.StartOfDay(now)}
+	for i, f := range fields {
+		t, _, ok := model.ParseDate(f, now)
+		if !ok {
+			return statsRange{}, fmt.Errorf("unknown date %q", f)
+		}
+		if i == 0 {
+			r.from = model.StartOfDay(t)
+		} else {
+			r.to = model.StartOfDay(t)
+		}
+	}
+	if r.to.Before(r.from) {
+		r.from, r.to = r.to, r.from
+	}
+	return r, nil
+}
+
+// burndownSeries returns the snapshot of each day of r, carrying the last
+// known one over days without any; ok is false before the first snapshot.
+func burndownSeries(history map[string]snapshot, r statsRange) (series []snapshot, ok []bool) {
+	var last snapshot
+	known := false
+	// Dni przed zakresem też się liczą, żeby pierwszy słupek nie był pusty
+	first := ""
+	for day := range history {
+		if first == "" || day < first {
+			first = day
+		}
+	}
+	start := r.from
+	if t, err := time.ParseInLocation(model.DateLayout, first, time.Local); err == nil && t.Before(start) {
+		start = t
+	}
+	for day := start; !day.After(r.to); day = day.AddDate(0, 0, 1) {
+		if s, found := history[day.Format(model.DateLayout)]; found {
+			last, known = s, true
+		}
+		if !day.Before(r.from) {
+			series = append(series, last)
+			ok = append(ok, known)
+		}
+	}
+	return series, ok
+}
+
+// chartColumns picks which days get a column when they don't all fit in
+// width, and how wide a column is.
+func chartColumns(days, width int) (cols []int, colW int) {
+	if days == 0 {
+		return nil, 0
+	}
+	if days > width {
+		for i := range width {
+			cols = append(cols, i*days/width)
+		}
+		return cols, 1
+	}
+	for i := range days {
+		cols = append(cols, i)
+	}
+	return cols, min(4, width/days)
+}
+
+// renderBurndown draws the open counts of the selected range as bars.
+func (m app) renderBurndown(width int, t theme.Theme) []string {
+	now := time.Now()
+	r := m.statsRange
+	if r.to.IsZero() {
+		r = presetRange("week", now)
+	}
+	history := loadHistory(m.filename)
+	if history == nil {
+		history = make(map[string]snapshot)
+	}
+	if today := model.StartOfDay(now); !today.Before(r.from) && !today.After(r.to) {
+		history[today.Format(model.DateLayout)] = countSnapshot(m.items) // bieżący stan, nawet przed zapisem
+	}
+	series, known := burndownSeries(history, r)
+
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+	head := lipgloss.NewStyle().Foreground(t.Accent).Bold(true)
+	label := func(d time.Time) string { return fmt.Sprintf("%d %s", d.Day(), i18n.Month(d.Month(), true)) }
+	title := i18n.T("Custom")
+	switch r.name {
+	case "week":
+		title = i18n.T("Week")
+	case "month":
+		title = i18n.T("Month")
+	}
+	lines := []string{"  " + head.Render(i18n.T("Burndown")) + " " + dim.Render(title+" · "+label(r.from)+" – "+label(r.to))}
+
+	first := -1
+	peak := 1
+	for i, s := range series {
+		if known[i] {
+			if first == -1 {
+				first = i
+			}
+			peak = max(peak, s.Open)
+		}
+	}
+	if first == -1 {
+		return append(lines, "", dim.Render("  "+i18n.T("No history yet: a snapshot is taken whenever the list is saved")))
+	}
+	startS, endS := series[first], series[len(series)-1]
+	lines = append(lines, "  "+dim.Render(i18n.Tf("open %d → %d (%+d) · %+d done", startS.Open, endS.Open, endS.Open-startS.Open, endS.Done-startS.Done)), "")
+
+	axisW := len(fmt.Sprint(peak))
+	cols, colW := chartColumns(len(series), max(1, width-axisW-4))
+	barW := max(1, colW-1)
+	rise := lipgloss.NewStyle().Foreground(t.Error)
+	fall := lipgloss.NewStyle().Foreground(t.Special)
+	flat := lipgloss.NewStyle().Foreground(t.Accent)
+	for row := chartHeight - 1; row >= 0; row-- {
+		axis := strings.Repeat(" ", axisW)
+		switch row {
+		case chartHeight - 1:
+			axis = fmt.Sprintf("%*d", axisW, peak)
+		case 0:
+			axis = fmt.Sprintf("%*d", axisW, 0)
+		}
+		var b strings.Builder
+		b.WriteString("  " + dim.Render(axis+" │"))
+		for _, day := range cols {
+			cell := " "
+			if known[day] {
+				eighths := series[day].Open * chartHeight * 8 / peak
+				switch part := eighths - row*8; {
+				case part >= 8:
+					cell = "█"
+				case part > 0:
+					cell = string([]rune("▁▂▃▄▅▆▇")[part-1])
+				}
+			}
+			style := flat
+			if prev := day - 1; prev >= 0 && known[prev] {
+				switch {
+				case series[day].Open < series[prev].Open:
+					style = fall
+				case series[day].Open > series[prev].Open:
+					style = rise
+				}
+			}
+			b.WriteString(style.Render(strings.Repeat(cell, barW)) + strings.Repeat(" ", colW-barW))
+		}
+		lines = append(lines, b.String())
+	}
+	chartW := len(cols) * colW
+	lines = append(lines, "  "+dim.Render(strings.Repeat(" ", axisW)+" └"+strings.Repeat("─", chartW)))
+	from, to := label(r.from), label(r.to)
+	gap := max(1, chartW-len([]rune(from))-len([]rune(to)))
+	lines = append(lines, "  "+strings.Repeat(" ", axisW+2)+dim.Render(from+strings.Repeat(" ", gap)+to))
+	return lines
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestRecordHistory(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	f := filepath.Join(t.TempDir(), "todo.md")
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	items := []model.Item{{Title: "a"}, {Title: "b", Done: true}, {Title: "c", Level: 1}}
+
+	if err := recordHistory(f, items, now.AddDate(-3, 0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	recordHistory(f, items, now.AddDate(0, 0, -1))
+	items[0].Done = true
+	recordHistory(f, items, now)
+	h := loadHistory(f)
+	if len(h) != 2 || h["2026-10-15"] != (snapshot{2, 1}) || h["2026-10-16"] != (snapshot{1, 2}) {
+		t.Errorf("history = %v (snapshots older than two years must go)", h)
+	}
+
+	// Zapis listy przez listWriter też zostawia ślad
+	w := &listWriter{}
+	if _, err := w.write(f, items[:1], nil, 1); err != nil {
+		t.Fatal(err)
+	}
+	if s := loadHistory(f)[time.Now().Format(model.DateLayout)]; s != (snapshot{0, 1}) {
+		t.Errorf("snapshot after save = %v", s)
+	}
+}
+
+func TestBurndown(t *testing.T) {
+	now := time.Now()
+	day := func(n int) time.Time { return model.StartOfDay(now).AddDate(0, 0, n) }
+	history := map[string]snapshot{
+		day(-9).Format(model.DateLayout): {9, 0},
+		day(-4).Format(model.DateLayout): {6, 3},
+		day(-2).Format(model.DateLayout): {7, 3},
+	}
+	series, known := burndownSeries(history, presetRange("week", now))
+	var open []int
+	for i, s := range series {
+		if !known[i] {
+			t.Fatalf("day %d unknown though a snapshot precedes the range", i)
+		}
+		open = append(open, s.Open)
+	}
+	if fmt.Sprint(open) != "[9 9 6 6 7 7 7]" {
+		t.Errorf("week = %v", open)
+	}
+	if _, known := burndownSeries(map[string]snapshot{day(-1).Format(model.DateLayout): {1, 0}}, presetRange("week", now)); known[0] || !known[6] {
+		t.Errorf("known = %v", known)
+	}
+
+	if cols, w := chartColumns(7, 40); len(cols) != 7 || w != 4 {
+		t.Errorf("week columns = %v, %d", cols, w)
+	}
+	if cols, w := chartColumns(365, 60); len(cols) != 60 || w != 1 || cols[59] >= 365 {
+		t.Errorf("year columns = %d, %d", len(cols), w)
+	}
+
+	r, err := parseStatsRange("2026-09-01 2026-08-25", now)
+	if err != nil || r.from.Format(model.DateLayout) != "2026-08-25" || r.to.Format(model.DateLayout) != "2026-09-01" || r.name != "" {
+		t.Errorf("custom range = %+v, %v", r, err)
+	}
+	if _, err := parseStatsRange("someday", now); err == nil {
+		t.Error("a bad date must be refused")
+	}
+}
+
+func TestStatsView(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	f := filepath.Join(t.TempDir(), "todo.md")
+	os.WriteFile(f, nil, 0644)
+	yesterday := time.Now().AddDate(0, 0, -1)
+	recordHistory(f, []model.Item{{Title: "a"}, {Title: "b"}, {Title: "c"}}, yesterday)
+
+	m := app{width: 80, height: 30, filename: f, items: []model.Item{{Title: "a", Done: true}, {Title: "b"}}}
+	m.recalcVisible()
+	m.runCommand("stats")
+	view := m.View()
+	for _, want := range []string{"Burndown", "Week", "open 3 → 1 (-2) · +1 done", "█", "Pomodoros"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("stats view lacks %q:\n%s", want, view)
+		}
+	}
+	next, _ := m.Update(keyMsg("m"))
+	if m = next.(app); m.statsRange.name != "month" || !strings.Contains(m.View(), "Month") {
+		t.Errorf("m must switch to the month, got %+v", m.statsRange)
+	}
+	m.runCommand("stats 2026-01-01 2026-01-31")
+	if !strings.Contains(m.View(), "No history yet") {
+		t.Error("a range before the first snapshot has nothing to draw")
+	}
+}
@@ -0,0 +1,540 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- CALDAV SYNC ---
+//
+// Every task maps to one VTODO resource (UID = the item's id token), hierarchy
+// travels as RELATED-TO;RELTYPE=PARENT. The last synced state (href, etag and a
+// hash of the local fields) is kept per todo file, which lets us tell local
+// edits from remote ones on the next run.
+
+const caldavStateFile = "caldav-state.json"
+
+type CalDAVConfig struct {
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+	// Interval in minutes for automatic sync, 0 = manual only (S).
+	Interval int `json:"interval_minutes,omitempty"`
+	// Conflict decides who wins when both sides changed: "local" (default) or "remote".
+	Conflict string `json:"conflict,omitempty"`
+}
+
+type caldavEntry struct {
+	Href string `json:"href"`
+	ETag string `json:"etag"`
+	Hash string `json:"hash"`
+}
+
+type caldavRemoteItem struct {
+	Href string
+	ETag string
+	Todo vtodo
+}
+
+type caldavOp struct {
+	Delete bool
+	UID    string
+	Href   string
+	ETag   string // If-Match; empty means create (If-None-Match: *)
+	Body   []byte
+	Hash   string
+}
+
+type caldavMerge struct {
+	items []model.Item
+	trash []model.Item
+	ops   []caldavOp
+	state map[string]caldavEntry
+
+	pulled  int
+	removed int
+}
+
+// --- MESSAGES ---
+
+type caldavTickMsg struct{}
+
+type caldavFetchedMsg struct {
+	remote []caldavRemoteItem
+	err    error
+}
+
+type caldavPushedMsg struct {
+	updates map[string]caldavEntry
+	pushed  int
+	err     error
+}
+
+func (c *CalDAVConfig) enabled() bool {
+	return c != nil && c.URL != ""
+}
+
+func (c CalDAVConfig) baseURL() string {
+	if strings.HasSuffix(c.URL, "/") {
+		return c.URL
+	}
+	return c.URL + "/"
+}
+
+func (c CalDAVConfig) password() string {
+	if p := os.Getenv("TODO_CALDAV_PASSWORD"); p != "" {
+		return p
+	}
+	return c.Password
+}
+
+func (c CalDAVConfig) newRequest(method, target string, body []byte) (*http.Request, error) {
+	var rd io.Reader
+	if body != nil {
+		rd = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, target, rd)
+	if err != nil {
+		return nil, err
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.password())
+	}
+	return req, nil
+}
+
+// resolve turns an href from a multistatus response into an absolute URL.
+func (c CalDAVConfig) resolve(href string) string {
+	base, err := url.Parse(c.baseURL())
+	if err != nil {
+		return href
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return base.ResolveReference(ref).String()
+}
+
+var caldavHTTP = &http.Client{Timeout: 30 * time.Second}
+
+// --- LOCAL <-> VTODO ---
+
+func localVTODO(items []model.Item, idx int) vtodo {
+	t := vtodo{
+		UID:     model.ID(items[idx]),
+		Summary: model.DisplayTitle(items[idx].Title),
+		Done:    items[idx].Done,
+	}
+	if p := model.ParentIndex(items, idx); p != -1 && items[p].Heading == 0 {
+		t.Parent = model.ID(items[p])
+	}
+	return t
+}
+
+func (t vtodo) hash() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%t\x00%s", t.Summary, t.Done, t.Parent)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// applyRemote copies remote fields onto the item, keeping local hidden tokens.
+func applyRemote(it *model.Item, t vtodo) {
+	title := model.DisplayTitle(t.Summary)
+	for _, tok := range strings.Fields(it.Title) {
+		if k, _, ok := strings.Cut(tok, ":"); ok && model.HiddenMetaKeys[k] {
+			title += " " + tok
+		}
+	}
+	it.Title = strings.TrimSpace(title)
+	it.Done = t.Done
+}
+
+// mergeCalDAV reconciles local items with the remote collection. Items must
+// already carry ids. It returns the new local lists and the remote operations
+// still to be executed.
+func mergeCalDAV(items, trash []model.Item, remote []caldavRemoteItem, state map[string]caldavEntry, policy string) caldavMerge {
+	res := caldavMerge{state: make(map[string]caldavEntry, len(state))}
+	for k, v := range state {
+		res.state[k] = v
+	}
+
+	remoteByUID := make(map[string]caldavRemoteItem, len(remote))
+	for _, r := range remote {
+		remoteByUID[r.Todo.UID] = r
+	}
+
+	put := func(t vtodo, href, etag string) {
+		if href == "" {
+			href = url.PathEscape(t.UID) + ".ics"
+		}
+		res.ops = append(res.ops, caldavOp{UID: t.UID, Href: href, ETag: etag, Body: encodeVTODO(t, time.Now()), Hash: t.hash()})
+	}
+
+	// Zadania usunięte na serwerze idą do kosza najpierw i same: ich dzieci
+	// zostają (serwer mógł ich nie znać) i są wysyłane już pod nowym rodzicem
+	for i := 0; i < len(items); {
+		uid := model.ID(items[i])
+		_, inRemote := remoteByUID[uid]
+		if _, inState := state[uid]; items[i].Heading > 0 || inRemote || !inState {
+			i++
+			continue
+		}
+		for k, end := i+1, model.SubtreeEnd(items, i); k < end; k++ {
+			items[k].Level--
+		}
+		items, trash = model.DeleteSubtree(items, trash, i)
+		delete(res.state, uid)
+		res.removed++
+	}
+
+	seenLocal := make(map[string]bool)
+
+	for i := range items {
+		if items[i].Heading > 0 {
+			continue // sekcje nie są zadaniami
+		}
+		uid := model.ID(items[i])
+		seenLocal[uid] = true
+		local := localVTODO(items, i)
+		r, inRemote := remoteByUID[uid]
+		st, inState := state[uid]
+
+		switch {
+		case inRemote && local.hash() == r.Todo.hash():
+			res.state[uid] = caldavEntry{Href: r.Href, ETag: r.ETag, Hash: local.hash()}
+		case inRemote && inState:
+			localChanged := local.hash() != st.Hash
+			remoteChanged := r.ETag != st.ETag
+			if remoteChanged && (!localChanged || policy == "remote") {
+				applyRemote(&items[i], r.Todo)
+				res.state[uid] = caldavEntry{Href: r.Href, ETag: r.ETag, Hash: localVTODO(items, i).hash()}
+				res.pulled++
+			} else if localChanged {
+				put(local, r.Href, r.ETag)
+			}
+		case inRemote:
+			// Obie strony mają zadanie, ale brak historii – rozstrzyga polityka
+			if policy == "remote" {
+				applyRemote(&items[i], r.Todo)
+				res.state[uid] = caldavEntry{Href: r.Href, ETag: r.ETag, Hash: localVTODO(items, i).hash()}
+				res.pulled++
+			} else {
+				put(local, r.Href, r.ETag)
+			}
+		case inState:
+			// usunięte na serwerze – obsłużone wyżej
+		default:
+			put(local, "", "")
+		}
+	}
+
+	var fresh []caldavRemoteItem
+	for _, r := range remote {
+		uid := r.Todo.UID
+		if seenLocal[uid] {
+			continue
+		}
+		if st, ok := state[uid]; ok && !(policy == "remote" && st.ETag != r.ETag) {
+			res.ops = append(res.ops, caldavOp{Delete: true, UID: uid, Href: r.Href, ETag: r.ETag})
+			delete(res.state, uid)
+			continue
+		}
+		fresh = append(fresh, r)
+	}
+
+	// Nowe zadania z serwera: rodzic musi być wstawiony przed dziećmi
+	for len(fresh) > 0 {
+		pending := make(map[string]bool, len(fresh))
+		for _, r := range fresh {
+			pending[r.Todo.UID] = true
+		}
+
+		var rest []caldavRemoteItem
+		progress := false
+		for _, r := range fresh {
+			p := model.FindByID(items, r.Todo.Parent)
+			if p == -1 && pending[r.Todo.Parent] {
+				rest = append(rest, r)
+				continue
+			}
+			newItem := model.Item{Title: model.SetMeta(model.DisplayTitle(r.Todo.Summary), "id", r.Todo.UID), Done: r.Todo.Done}
+			idx := len(items)
+			if p == -1 {
+				items = append(items, newItem)
+			} else {
+				newItem.Level = items[p].Level + 1
+				idx = model.SubtreeEnd(items, p)
+				items = append(items[:idx], append([]model.Item{newItem}, items[idx:]...)...)
+			}
+			res.state[r.Todo.UID] = caldavEntry{Href: r.Href, ETag: r.ETag, Hash: localVTODO(items, idx).hash()}
+			res.pulled++
+			progress = true
+		}
+		if !progress {
+			// Cykl w RELATED-TO – reszta ląduje na najwyższym poziomie
+			for i := range rest {
+				rest[i].Todo.Parent = ""
+			}
+		}
+		fresh = rest
+	}
+
+	res.items = items
+	res.trash = trash
+	return res
+}
+
+// --- NETWORK ---
+
+const caldavQuery = `<?xml version="1.0" encoding="utf-8" ?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/><C:calendar-data/></D:prop>
+  <C:filter><C:comp-filter name="VCALENDAR"><C:comp-filter name="VTODO"/></C:comp-filter></C:filter>
+</C:calendar-query>`
+
+type davMultistatus struct {
+	Responses []struct {
+		Href     string `xml:"DAV: href"`
+		Propstat []struct {
+			Status string `xml:"DAV: status"`
+			Prop   struct {
+				ETag string `xml:"DAV: getetag"`
+				Data string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+func fetchCalDAV(cfg CalDAVConfig) tea.Cmd {
+	return func() tea.Msg {
+		remote, err := queryCalDAV(cfg)
+		return caldavFetchedMsg{remote: remote, err: err}
+	}
+}
+
+func queryCalDAV(cfg CalDAVConfig) ([]caldavRemoteItem, error) {
+	req, err := cfg.newRequest("REPORT", cfg.baseURL(), []byte(caldavQuery))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Depth", "1")
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+	resp, err := caldavHTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("caldav: REPORT returned %s", resp.Status)
+	}
+
+	var ms davMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("caldav: %w", err)
+	}
+
+	var result []caldavRemoteItem
+	for _, r := range ms.Responses {
+		for _, ps := range r.Propstat {
+			if ps.Prop.Data == "" || (ps.Status != "" && !strings.Contains(ps.Status, " 200")) {
+				continue
+			}
+			todos, err := decodeVTODOs(ps.Prop.Data)
+			if err != nil || len(todos) == 0 || todos[0].UID == "" {
+				continue
+			}
+			result = append(result, caldavRemoteItem{Href: r.Href, ETag: ps.Prop.ETag, Todo: todos[0]})
+		}
+	}
+	return result, nil
+}
+
+func pushCalDAV(cfg CalDAVConfig, ops []caldavOp) tea.Cmd {
+	return func() tea.Msg {
+		msg := caldavPushedMsg{updates: make(map[string]caldavEntry)}
+		var errs []error
+		for _, op := range ops {
+			etag, err := execCalDAVOp(cfg, op)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if !op.Delete {
+				msg.updates[op.UID] = caldavEntry{Href: op.Href, ETag: etag, Hash: op.Hash}
+			}
+			msg.pushed++
+		}
+		msg.err = errors.Join(errs...)
+		return msg
+	}
+}
+
+func execCalDAVOp(cfg CalDAVConfig, op caldavOp) (string, error) {
+	method := http.MethodPut
+	var body []byte
+	if op.Delete {
+		method = http.MethodDelete
+	} else {
+		body = op.Body
+	}
+	req, err := cfg.newRequest(method, cfg.resolve(op.Href), body)
+	if err != nil {
+		return "", err
+	}
+	if op.ETag != "" {
+		req.Header.Set("If-Match", op.ETag)
+	} else if !op.Delete {
+		req.Header.Set("If-None-Match", "*")
+	}
+	if !op.Delete {
+		req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+	}
+
+	resp, err := caldavHTTP.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode == http.StatusNotFound && op.Delete {
+		return "", nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("caldav: %s %s: %s", method, op.Href, resp.Status)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// --- SYNC STATE ---
+
+func caldavStatePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, appName, caldavStateFile), nil
+}
+
+func stateKey(filename string) string {
+	if abs, err := filepath.Abs(filename); err == nil {
+		return abs
+	}
+	return filename
+}
+
+func loadCalDAVState(filename string) map[string]caldavEntry {
+	all := make(map[string]map[string]caldavEntry)
+	if path, err := caldavStatePath(); err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			json.Unmarshal(data, &all)
+		}
+	}
+	if st := all[stateKey(filename)]; st != nil {
+		return st
+	}
+	return make(map[string]caldavEntry)
+}
+
+func saveCalDAVState(filename string, state map[string]caldavEntry) {
+	path, err := caldavStatePath()
+	if err != nil {
+		return
+	}
+	all := make(map[string]map[string]caldavEntry)
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &all)
+	}
+	all[stateKey(filename)] = state
+	data, _ := json.MarshalIndent(all, "", "  ")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, data, 0600)
+}
+
+// --- MODEL INTEGRATION ---
+
+func caldavTick(cfg *CalDAVConfig) tea.Cmd {
+	if !cfg.enabled() || cfg.Interval <= 0 {
+		return nil
+	}
+	return tea.Tick(time.Duration(cfg.Interval)*time.Minute, func(time.Time) tea.Msg {
+		return caldavTickMsg{}
+	})
+}
+
+func (m *app) startSync() tea.Cmd {
+	if !m.config.CalDAV.enabled() {
+		m.status = "CalDAV is not configured"
+		return nil
+	}
+	if m.syncing || m.readOnly {
+		return nil
+	}
+	m.syncing = true
+	m.status = "Syncing…"
+	return fetchCalDAV(*m.config.CalDAV)
+}
+
+func (m *app) handleCalDAVFetched(msg caldavFetchedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.syncing = false
+		m.status = "Sync failed: " + msg.err.Error()
+		return nil
+	}
+	if m.inputMode || m.fieldEditing || m.propOpen || m.dateOpen {
+		m.syncing = false
+		m.status = "Sync skipped while editing"
+		return nil
+	}
+
+	model.EnsureIDs(m.items)
+	state := loadCalDAVState(m.filename)
+	res := mergeCalDAV(m.items, m.trash, msg.remote, state, m.config.CalDAV.Conflict)
+
+	m.items = res.items
+	m.trash = res.trash
+	m.recalcVisible()
+	if m.state == viewDetail {
+		m.state = viewMain
+	}
+	m.save()
+	saveCalDAVState(m.filename, res.state)
+
+	if len(res.ops) == 0 {
+		m.syncing = false
+		m.status = fmt.Sprintf("Synced (%d pulled, %d removed)", res.pulled, res.removed)
+		return nil
+	}
+	return pushCalDAV(*m.config.CalDAV, res.ops)
+}
+
+func (m *app) handleCalDAVPushed(msg caldavPushedMsg) {
+	m.syncing = false
+	state := loadCalDAVState(m.filename)
+	for uid, e := range msg.updates {
+		state[uid] = e
+	}
+	saveCalDAVState(m.filename, state)
+
+	if msg.err != nil {
+		m.status = "Sync incomplete: " + msg.err.Error()
+		return
+	}
+	m.status = fmt.Sprintf("Synced (%d pushed)", msg.pushed)
+}
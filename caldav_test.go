@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestCalDAVRemoteDeleteKeepsLocalChildren(t *testing.T) {
+	items := []model.Item{
+		{Title: "trip id:p"},
+		{Title: "tickets id:c", Level: 1},
+		{Title: "seat id:g", Level: 2},
+		{Title: "mail id:m"},
+	}
+	state := map[string]caldavEntry{
+		"p": {Href: "p.ics", ETag: "1", Hash: localVTODO(items, 0).hash()},
+		"m": {Href: "m.ics", ETag: "1", Hash: localVTODO(items, 3).hash()},
+	}
+	remote := []caldavRemoteItem{{Href: "m.ics", ETag: "1", Todo: localVTODO(items, 3)}}
+
+	res := mergeCalDAV(items, nil, remote, state, "")
+	if res.removed != 1 || len(res.trash) != 1 || model.ID(res.trash[0]) != "p" {
+		t.Fatalf("removed %d, trash %v", res.removed, flat(res.trash))
+	}
+	want := []string{"0:tickets id:c:false", "1:seat id:g:false", "0:mail id:m:false"}
+	if got := flat(res.items); len(got) != len(want) {
+		t.Fatalf("items %q", got)
+	} else {
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("item %d = %q, want %q", i, got[i], want[i])
+			}
+		}
+	}
+	if _, ok := res.state["p"]; ok {
+		t.Error("the deleted task must leave the sync state")
+	}
+	// Dzieci, których serwer nie znał, trafiają na niego pod nowym rodzicem
+	pushed := map[string]bool{}
+	for _, op := range res.ops {
+		pushed[op.UID] = !op.Delete
+	}
+	if !pushed["c"] || !pushed["g"] || len(res.ops) != 2 {
+		t.Errorf("ops %+v", res.ops)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- MONTH CALENDAR ---
+//
+// :calendar shows a month grid with the number of open tasks due on each day
+// (red when overdue) and a total per week, so overloaded weeks stand out.
+// It navigates like the date picker; Enter opens that day in the agenda.
+
+// dueCounts returns the number of open tasks per due day.
+func dueCounts(items []model.Item) map[time.Time]int {
+	counts := make(map[time.Time]int)
+	for _, it := range items {
+		if due, _, ok := model.DueTime(it.Title); ok && !it.Done {
+			counts[model.StartOfDay(due)]++
+		}
+	}
+	return counts
+}
+
+func (m *app) openCalendar() {
+	m.calendar = ui.NewDatePicker(time.Now(), true)
+	m.calendar.Calendar = m.config.calendar()
+	m.state = viewCalendar
+}
+
+func (m app) updateCalendar(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.state = viewMain
+	case "enter":
+		// Kursor agendy na pierwszym zadaniu tego dnia (lub następnym)
+		entries := agendaEntries(m.items)
+		m.cursorAgenda = max(0, len(entries)-1)
+		for i, idx := range entries {
+			due, _, _ := model.DueTime(m.items[idx].Title)
+			if !model.StartOfDay(due).Before(m.calendar.Date) {
+				m.cursorAgenda = i
+				break
+			}
+		}
+		m.state = viewAgenda
+		m.agendaBack = viewCalendar
+	default:
+		m.calendar.Update(msg.String())
+		m.calendar.Set = true
+	}
+	return m, nil
+}
+
+func (m app) renderCalendar(height int, t theme.Theme) string {
+	sel := m.calendar.Date
+	today := model.StartOfDay(time.Now())
+	counts := dueCounts(m.items)
+
+	// 7 dni + kolumna sumy tygodnia
+	cellW := max(4, (m.width-4)/8)
+	cellH := max(1, min(3, (height-3)/6))
+
+	cell := func(s string, style lipgloss.Style) string {
+		return style.Width(cellW).Height(cellH).Render(s)
+	}
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+
+	var rows []string
+	rows = append(rows, lipgloss.PlaceHorizontal(m.width-4, lipgloss.Center,
+		lipgloss.NewStyle().Foreground(t.Accent).Bold(true).Render(i18n.MonthYear(sel))))
+	var head []string
+	for _, d := range i18n.Weekdays() {
+		head = append(head, dim.Width(cellW).Render(i18n.Weekday(d, true)))
+	}
+	head = append(head, dim.Width(cellW).Render(i18n.T("Week")))
+	rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, head...))
+
+	first := time.Date(sel.Year(), sel.Month(), 1, 0, 0, 0, 0, sel.Location())
+	day := first.AddDate(0, 0, -i18n.WeekdayColumn(first.Weekday()))
+	for week := 0; week < 6; week++ {
+		var cells []string
+		total := 0
+		for wd := 0; wd < 7; wd++ {
+			n := counts[day]
+			total += n
+			style := lipgloss.NewStyle().Foreground(t.Text)
+			if day.Month() != sel.Month() || !m.calendar.Calendar.IsWorkday(day) {
+				style = style.Foreground(t.Comment)
+			}
+			if day.Equal(today) {
+				style = style.Foreground(t.Special).Bold(true)
+			}
+			label := fmt.Sprintf("%2d", day.Day())
+			if n > 0 {
+				countStyle := lipgloss.NewStyle().Foreground(t.Accent)
+				if day.Before(today) {
+					countStyle = countStyle.Foreground(t.Error)
+				}
+				label += " " + countStyle.Render(fmt.Sprintf("%d%s", n, strings.Repeat("•", min(n, cellW-6))))
+			}
+			if day.Equal(sel) {
+				style = style.Foreground(t.Base).Background(t.Highlight)
+			}
+			cells = append(cells, cell(label, style))
+			day = day.AddDate(0, 0, 1)
+		}
+		weekStyle := dim
+		if total > 0 {
+			weekStyle = lipgloss.NewStyle().Foreground(t.Accent).Bold(true)
+		}
+		cells = append(cells, cell(fmt.Sprintf("Σ %d", total), weekStyle))
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+	}
+
+	return m.frame(height, t.Highlight).
+		Render(strings.Join(rows, "\n"))
+}
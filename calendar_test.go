@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestCalendarDrillsIntoAgenda(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	day := func(n int) string { return time.Now().AddDate(0, 0, n).Format(model.DateLayout) }
+	m := app{items: []model.Item{
+		{Title: "a due:" + day(1)},
+		{Title: "b due:" + day(1)},
+		{Title: "c due:" + day(1), Done: true},
+		{Title: "d due:" + day(3)},
+	}}
+	m.recalcVisible()
+
+	if n := dueCounts(m.items)[model.StartOfDay(time.Now().AddDate(0, 0, 1))]; n != 2 {
+		t.Errorf("open tasks tomorrow = %d, want 2", n)
+	}
+
+	m.openCalendar()
+	for _, k := range []string{"l", "l", "enter"} {
+		next, _ := m.Update(keyMsg(k))
+		m = next.(app)
+	}
+	entries := agendaEntries(m.items)
+	if m.state != viewAgenda || m.items[entries[m.cursorAgenda]].Title != "d due:"+day(3) {
+		t.Fatalf("state = %v, cursor on %d", m.state, m.cursorAgenda)
+	}
+	next, _ := m.Update(keyMsg("esc"))
+	if next.(app).state != viewCalendar {
+		t.Error("Esc should return to the calendar")
+	}
+}
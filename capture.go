@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- QUICK CAPTURE (todo capture) ---
+//
+// `todo capture` asks for one task on a single line below the prompt, adds
+// it to the inbox and exits: meant for a global hotkey or a drop-down
+// terminal, to note something down without leaving what you were doing.
+// The inbox is "inbox" in config.json (~ allowed), else the usual list;
+// --file overrides both. Enter adds, Esc or an empty line cancels. The
+// title is read as `todo add` reads it, so "due:fri" and a pasted list of
+// several lines work too.
+
+type capture struct {
+	inbox   string
+	input   []rune
+	width   int
+	theme   theme.Theme
+	done    bool
+	aborted bool
+}
+
+// inboxFile is the list todo capture adds to.
+func (c Config) inboxFile() string {
+	if c.Inbox != "" {
+		return expandHome(c.Inbox)
+	}
+	return defaultFile()
+}
+
+func newCapture(inbox string, t theme.Theme) capture {
+	return capture{inbox: inbox, theme: t}
+}
+
+func (c capture) Init() tea.Cmd { return nil }
+
+func (c capture) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		c.width = msg.Width
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			c.aborted = true
+			return c, tea.Quit
+		case "enter":
+			c.done = strings.TrimSpace(string(c.input)) != ""
+			c.aborted = !c.done
+			return c, tea.Quit
+		case "backspace", "ctrl+h":
+			if len(c.input) > 0 {
+				c.input = c.input[:len(c.input)-1]
+			}
+		case "ctrl+w":
+			end := len(c.input)
+			for end > 0 && unicode.IsSpace(c.input[end-1]) {
+				end--
+			}
+			for end > 0 && !unicode.IsSpace(c.input[end-1]) {
+				end--
+			}
+			c.input = c.input[:end]
+		case "ctrl+u":
+			c.input = nil
+		default:
+			if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+				// Wklejony tekst zachowuje nowe linie: każda to osobne zadanie
+				c.input = append(c.input, []rune(strings.ReplaceAll(string(msg.Runes), "\r\n", "\n"))...)
+			}
+		}
+	}
+	return c, nil
+}
+
+func (c capture) View() string {
+	if c.done || c.aborted {
+		return ""
+	}
+	t := c.theme
+	prompt := lipgloss.NewStyle().Foreground(t.Highlight).Bold(true).Render("➤ ") +
+		lipgloss.NewStyle().Foreground(t.Comment).Render("Add to "+abbreviateHome(c.inbox)+": ")
+	text := strings.ReplaceAll(string(c.input), "\n", " ⏎ ")
+	if room := c.width - lipgloss.Width(prompt) - 1; c.width > 0 && ansi.StringWidth(text) > room {
+		// Długi tytuł przewijamy, żeby kursor był zawsze widoczny
+		text = ansi.TruncateLeft(text, ansi.StringWidth(text)-max(1, room)+1, "…")
+	}
+	cursor := lipgloss.NewStyle().Foreground(t.Base).Background(t.Highlight).Render(" ")
+	hint := lipgloss.NewStyle().Foreground(t.Comment).Render("  Enter:Add • Esc:Cancel")
+	line := prompt + lipgloss.NewStyle().Foreground(t.Text).Render(text) + cursor
+	if c.width > 0 && lipgloss.Width(line+hint) > c.width {
+		return line
+	}
+	return line + hint
+}
+
+func runCapture(args []string) {
+	fs := flag.NewFlagSet("capture", flag.ExitOnError)
+	file := fs.String("file", "", `the list to add to (default: "inbox" in config.json, else the usual one)`)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: todo capture [--file inbox.md]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	inbox := *file
+	if inbox == "" {
+		inbox = cfg.inboxFile()
+	}
+	if cfg.Colors != "" {
+		profile, _ := colorProfile(cfg.Colors)
+		lipgloss.SetColorProfile(profile)
+	}
+	loadThemes()
+	t, _ := themeByName(cfg.SelectedTheme)
+
+	final, err := tea.NewProgram(newCapture(inbox, shownTheme(t))).Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	c := final.(capture)
+	if !c.done {
+		return
+	}
+	added, err := addTasks(inbox, string(c.input), cfg, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: could not add to %s: %v\n", inbox, err)
+		os.Exit(1)
+	}
+	printAdded(added, inbox)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestCapture(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	loadThemes()
+	c := newCapture("inbox.md", themes[0])
+	press := func(msg tea.Msg) tea.Cmd {
+		t.Helper()
+		next, cmd := c.Update(msg)
+		c = next.(capture)
+		return cmd
+	}
+	press(tea.WindowSizeMsg{Width: 40, Height: 10})
+
+	if press(keyMsg("enter")); !c.aborted || c.done {
+		t.Fatal("enter on an empty line must cancel")
+	}
+	c.aborted = false
+	for _, k := range []string{"c", "a", "l", "l", " ", "b", "o", "b"} {
+		press(keyMsg(k))
+	}
+	press(keyMsg("ctrl+w"))
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("mom\r\nbuy milk"), Paste: true})
+	press(keyMsg("backspace"))
+	if got := string(c.input); got != "call mom\nbuy mil" {
+		t.Fatalf("input = %q", got)
+	}
+	view := ansi.Strip(c.View())
+	if !strings.Contains(view, "Add to inbox.md") || !strings.Contains(view, "mom ⏎ buy mil") {
+		t.Errorf("view = %q", view)
+	}
+
+	for range 40 {
+		press(keyMsg("x"))
+	}
+	if w := lipgloss.Width(c.View()); w > 40 {
+		t.Errorf("a long title makes the prompt %d columns wide", w)
+	}
+	if !strings.HasSuffix(strings.TrimSuffix(ansi.Strip(c.View()), " "), "xxx") {
+		t.Errorf("the end of a long title must stay in view: %q", ansi.Strip(c.View()))
+	}
+
+	if cmd := press(keyMsg("enter")); !c.done || cmd == nil || c.View() != "" {
+		t.Error("enter must finish the capture")
+	}
+}
+
+func TestInboxFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if got := (Config{}).inboxFile(); got != "todo.md" {
+		t.Errorf("default inbox = %q", got)
+	}
+	t.Setenv("HOME", "/home/ala")
+	if got := (Config{Inbox: "~/inbox.md"}).inboxFile(); got != filepath.Join("/home/ala", "inbox.md") {
+		t.Errorf("inbox = %q", got)
+	}
+}
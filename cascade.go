@@ -0,0 +1,165 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- COMPLETION CASCADE ---
+//
+// cascade_complete in config.json:
+//
+//	"off"  (default) space only toggles the selected task
+//	"down" a parent takes its whole subtree along
+//	"up"   completing the last open subtask offers to complete the parent
+//	"both" down and up
+
+func (c Config) cascadeDown() bool {
+	return c.CascadeComplete == "down" || c.CascadeComplete == "both"
+}
+
+func (c Config) cascadeUp() bool {
+	return c.CascadeComplete == "up" || c.CascadeComplete == "both"
+}
+
+// setDone completes or reopens items[idx]. Completing a recurring task moves
+// its due date instead; with cascade the descendants follow the parent, each
+// completed as if checked off on its own.
+// It returns the next occurrence when the task recurred.
+func setDone(items []model.Item, idx int, done bool, cfg Config, now time.Time) (time.Time, bool) {
+	if next, ok := markDone(&items[idx], done, cfg, now); ok {
+		return next, true
+	}
+	if cfg.cascadeDown() {
+		for i := idx + 1; i < model.SubtreeEnd(items, idx); i++ {
+			// Już zrobione zachowują swoją datę ukończenia
+			if items[i].Heading > 0 || (done && items[i].Done) {
+				continue
+			}
+			markDone(&items[i], done, cfg, now)
+		}
+	}
+	return time.Time{}, false
+}
+
+// markDone completes or reopens one task, stamping completed:<date> in
+// archive journal mode. A recurring task moves to its next occurrence.
+func markDone(it *model.Item, done bool, cfg Config, now time.Time) (time.Time, bool) {
+	if done {
+		it.State = ""
+		if next, ok := completeRecurring(it, cfg, now); ok {
+			return next, true
+		}
+	}
+	it.Done = done
+	if cfg.ArchiveJournal {
+		stamp := ""
+		if done {
+			stamp = now.Format(model.DateLayout)
+		}
+		it.Title = model.SetMeta(it.Title, "completed", stamp)
+	}
+	return time.Time{}, false
+}
+
+// siblingsDone reports whether every child of parent is done.
+func siblingsDone(items []model.Item, parent int) bool {
+	for i := parent + 1; i < model.SubtreeEnd(items, parent); i++ {
+		if items[i].Level == items[parent].Level+1 && !items[i].Done {
+			return false
+		}
+	}
+	return true
+}
+
+// toggleDone handles space on items[idx]. With space_cycles an open task
+// first goes through the checkbox states. A task still waiting for others
+// is only completed after confirmation.
+func (m *app) toggleDone(idx int) {
+	if m.items[idx].Heading > 0 {
+		m.status = "A section heading can't be checked off"
+		return
+	}
+	if m.config.SpaceCycles && !m.items[idx].Done {
+		if _, ok := m.config.nextState(m.items[idx].State); ok {
+			m.cycleState(idx)
+			return
+		}
+	}
+	if m.confirmBlocked(idx) {
+		return
+	}
+	m.flipDone(idx)
+}
+
+func (m *app) flipDone(idx int) {
+	done := !m.items[idx].Done
+	if next, ok := setDone(m.items, idx, done, m.config, time.Now()); ok {
+		m.status = "↻ " + i18n.T("Next") + ": " + i18n.LongDate(next)
+	}
+	m.noteDone(idx, time.Now())
+	if m.items[idx].Done {
+		m.offerParent(idx)
+		m.fireHook("complete", idx)
+	}
+	if m.config.cascadeDown() && model.HasChildren(m.items, idx) {
+		m.recalcVisible()
+	} else {
+		m.refreshItem(idx)
+	}
+	m.save()
+}
+
+// offerParent asks about completing the parent of a just finished task once
+// all its siblings are done. The parent is remembered by id, because an
+// autosort on save may move it.
+func (m *app) offerParent(idx int) {
+	m.cascadeID = ""
+	if !m.config.cascadeUp() {
+		return
+	}
+	p := model.ParentIndex(m.items, idx)
+	if p == -1 || m.items[p].Done || m.items[p].Heading > 0 || !siblingsDone(m.items, p) {
+		return
+	}
+	if model.ID(m.items[p]) == "" {
+		m.items[p].Title = model.SetMeta(m.items[p].Title, "id", model.NewID())
+		m.refreshItem(p)
+	}
+	m.cascadeID = model.ID(m.items[p])
+}
+
+// updateCascadePrompt answers the prompt; any other key dismisses it and is
+// handled as usual.
+func (m *app) updateCascadePrompt(msg tea.KeyMsg) bool {
+	id := m.cascadeID
+	m.cascadeID = ""
+	switch msg.String() {
+	case "y", "enter":
+		if p := model.FindByID(m.items, id); p != -1 {
+			setDone(m.items, p, true, m.config, time.Now())
+			m.noteDone(p, time.Now())
+			m.offerParent(p)
+			m.recalcVisible()
+			m.save()
+		}
+		return true
+	case "n", "esc":
+		return true
+	}
+	return false
+}
+
+func (m app) renderCascadePrompt(t theme.Theme) string {
+	p := model.FindByID(m.items, m.cascadeID)
+	if p == -1 {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(t.Highlight).Render(
+		"All subtasks done — complete “" + model.DisplayTitle(m.items[p].Title) + "”? (y/n)")
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func cascadeApp(mode string) app {
+	m := app{config: Config{CascadeComplete: mode}, items: []model.Item{
+		{Title: "project"},
+		{Title: "phase", Level: 1},
+		{Title: "step 1", Level: 2, Done: true},
+		{Title: "step 2", Level: 2},
+		{Title: "other"},
+	}}
+	m.recalcVisible()
+	return m
+}
+
+func TestCascadeDown(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := cascadeApp("down")
+	m.toggleDone(0)
+	for i, it := range m.items[:4] {
+		if !it.Done {
+			t.Errorf("item %d not completed with its parent", i)
+		}
+	}
+	m.toggleDone(0)
+	if m.items[2].Done || m.items[4].Done {
+		t.Error("reopening the parent must reopen the subtree only")
+	}
+
+	m = cascadeApp("off")
+	m.toggleDone(0)
+	if m.items[3].Done {
+		t.Error("cascade off still changed a child")
+	}
+}
+
+func TestCascadeCompletesEachChild(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+	cfg := Config{CascadeComplete: "down", ArchiveJournal: true}
+	items := []model.Item{
+		{Title: "trip"},
+		{Title: "pack", Level: 1},
+		{Title: "water plants recur:weekly due:2026-10-16", Level: 1},
+		{Title: "book completed:2026-10-01", Level: 1, Done: true},
+	}
+	setDone(items, 0, true, cfg, now)
+	if model.MetaValue(items[1].Title, "completed") != "2026-10-16" || !items[1].Done {
+		t.Errorf("cascaded child = %+v", items[1])
+	}
+	// Powtarzalne dziecko przechodzi na następny termin zamiast się kończyć
+	if items[2].Done || model.MetaValue(items[2].Title, "due") != "2026-10-23" {
+		t.Errorf("recurring child = %+v", items[2])
+	}
+	if model.MetaValue(items[3].Title, "completed") != "2026-10-01" {
+		t.Errorf("a child done earlier keeps its day: %q", items[3].Title)
+	}
+}
+
+func TestCascadeUpPrompt(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := cascadeApp("up")
+	m.cursorMain = 3
+	m.toggleDone(3)
+	if m.cascadeID == "" || model.FindByID(m.items, m.cascadeID) != 1 {
+		t.Fatalf("no prompt for the parent, cascadeID = %q", m.cascadeID)
+	}
+	// "y" completes the parent and offers the grandparent, whose only child is now done
+	m.updateCascadePrompt(keyMsg("y"))
+	if !m.items[1].Done || model.FindByID(m.items, m.cascadeID) != 0 {
+		t.Fatalf("parent done = %v, next prompt = %q", m.items[1].Done, m.cascadeID)
+	}
+	if m.updateCascadePrompt(keyMsg("j")) || m.cascadeID != "" || m.items[0].Done {
+		t.Error("other keys must dismiss the prompt and pass through")
+	}
+}
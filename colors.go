@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- COLOR PROFILES ---
+//
+// The terminal's color support is detected (COLORTERM, TERM) and the theme
+// is shown through its ansi256/ansi16 palette when truecolor is missing.
+// "colors" in config.json overrides the detection for terminals that
+// misreport: "truecolor", "256", "16" or "none".
+
+func colorProfile(name string) (termenv.Profile, error) {
+	switch name {
+	case "", "auto":
+		return lipgloss.ColorProfile(), nil
+	case "truecolor":
+		return termenv.TrueColor, nil
+	case "256":
+		return termenv.ANSI256, nil
+	case "16":
+		return termenv.ANSI, nil
+	case "none":
+		return termenv.Ascii, nil
+	}
+	return lipgloss.ColorProfile(), fmt.Errorf("unknown colors %q (want auto, truecolor, 256, 16 or none)", name)
+}
+
+// shownTheme is t in the colors the terminal can show. It follows the
+// current profile, so exports rendered in truecolor keep the real colors.
+func shownTheme(t theme.Theme) theme.Theme {
+	return t.ForProfile(lipgloss.ColorProfile())
+}
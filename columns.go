@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- COLUMN VIEW ---
+//
+// "c" switches the list to a table: the due date, priority and tags of each
+// task move out of its title into aligned columns on the right, and titles
+// are cut to one line. Windows too narrow for the columns keep the plain
+// list.
+
+const (
+	dueColumn  = 10 // 2006-01-02
+	priColumn  = 3
+	tagsColumn = 16
+	// columnsWidth also counts a space before each column
+	columnsWidth = dueColumn + priColumn + tagsColumn + 3
+)
+
+// showColumns reports whether the list is drawn as a table.
+func (m *app) showColumns() bool {
+	return m.columns && m.width-columnsWidth >= 40
+}
+
+func (m *app) toggleColumns() {
+	m.columns = !m.columns
+	m.status = "Columns off"
+	if m.columns {
+		m.status = "Columns on"
+	}
+}
+
+// columnTitle is title without the due date, priority and tags.
+func columnTitle(title string) string {
+	title = model.StripMeta(title, map[string]bool{"due": true, "pri": true})
+	for _, tag := range model.Tags(title) {
+		title = model.SetTag(title, tag, false)
+	}
+	return title
+}
+
+// columnCells are the texts of the columns of it.
+func columnCells(it model.Item) []string {
+	due := ""
+	if d, hasTime, ok := model.DueTime(it.Title); ok {
+		due = i18n.Date(d, time.Now())
+		if hasTime && model.StartOfDay(d).Equal(model.StartOfDay(time.Now())) {
+			due = d.Format("15:04")
+		}
+	}
+	tags := ""
+	if t := model.Tags(it.Title); len(t) > 0 {
+		tags = "#" + strings.Join(t, " #")
+	}
+	return []string{due, model.MetaValue(it.Title, "pri"), tags}
+}
+
+// renderColumns styles the cells of it, each padded to its column.
+func renderColumns(it model.Item, t theme.Theme) string {
+	cells := columnCells(it)
+	dueStyle := lipgloss.NewStyle().Foreground(t.Accent)
+	if d, _, ok := model.DueTime(it.Title); ok && !it.Done && d.Before(model.StartOfDay(time.Now())) {
+		dueStyle = dueStyle.Foreground(t.Error)
+	}
+	cell := func(s string, width int, style lipgloss.Style) string {
+		s = ansi.Truncate(s, width, "…")
+		return " " + style.Render(s) + strings.Repeat(" ", width-ansi.StringWidth(s))
+	}
+	return cell(cells[0], dueColumn, dueStyle) +
+		cell(cells[1], priColumn, lipgloss.NewStyle().Foreground(t.Highlight).Bold(true)) +
+		cell(cells[2], tagsColumn, lipgloss.NewStyle().Foreground(t.Comment))
+}
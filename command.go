@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- COMMAND LINE (:) ---
+
+func (m *app) updateCommand(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		line := strings.TrimSpace(m.cmdBuf)
+		m.cmdMode = false
+		m.cmdBuf = ""
+		return m.runCommand(line)
+	case tea.KeyEsc:
+		m.cmdMode = false
+		m.cmdBuf = ""
+	case tea.KeyBackspace, tea.KeyDelete:
+		if len(m.cmdBuf) > 0 {
+			runes := []rune(m.cmdBuf)
+			m.cmdBuf = string(runes[:len(runes)-1])
+		} else {
+			m.cmdMode = false
+		}
+	case tea.KeySpace:
+		m.cmdBuf += " "
+	case tea.KeyRunes:
+		m.cmdBuf += string(msg.Runes)
+	}
+	return nil
+}
+
+func (m *app) runCommand(line string) tea.Cmd {
+	if isSubstitute(line) {
+		m.openReplace(line)
+		return nil
+	}
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	switch name {
+	case "":
+		return nil
+	case "lint":
+		m.openLint()
+	case "stats":
+		r, err := parseStatsRange(arg, time.Now())
+		if err != nil {
+			m.status = "Bad range: " + err.Error()
+			break
+		}
+		m.statsRange = r
+		m.state = viewStats
+	case "report":
+		m.exportReport()
+	case "agenda":
+		m.state = viewAgenda
+		m.agendaBack = viewMain
+		m.cursorAgenda = 0
+	case "calendar", "cal":
+		m.openCalendar()
+	case "plan":
+		m.openPlan(arg)
+	case "import":
+		m.importFile(arg)
+	case "archive":
+		m.archiveCommand(arg)
+	case "group":
+		m.openGroups(arg)
+	case "deps":
+		m.openDeps()
+	case "due":
+		m.dueCommand(arg)
+	case "attach":
+		m.attachCommand(arg)
+	case "bugreport":
+		m.bugReportCommand(arg)
+	case "review":
+		m.openReview()
+	case "smart":
+		m.state = viewSmart
+		m.cursorSmart = 0
+	case "template", "templates":
+		m.templateCommand(arg)
+	case "sort":
+		if arg == "" {
+			arg = "title"
+		}
+		if !m.sortItems(arg) {
+			m.status = "Unknown sort key: " + arg + " (title, due, pri, done)"
+			break
+		}
+		m.sortKey = arg
+		m.save()
+	case "purge":
+		m.purgeBin()
+	case "export":
+		m.exportScreenshot(arg)
+	case "clean":
+		var swept int
+		m.items, m.trash, swept = cleanDone(m.items, m.trash)
+		m.recalcVisible()
+		m.save()
+		m.status = fmt.Sprintf("Moved %d finished tasks to the bin", swept)
+	case "filter":
+		m.setFilter(arg)
+	case "view":
+		m.viewCommand(arg)
+	case "filters":
+		m.filtersCommand(arg)
+	case "snoozed":
+		m.showSnoozed = !m.showSnoozed
+		m.recalcVisible()
+	case "numbers":
+		m.cycleNumbers()
+	case "plugins":
+		m.loadPlugins()
+		m.status = m.pluginList()
+	case "q", "quit":
+		m.quitting = true
+		return tea.Quit
+	default:
+		if n, err := strconv.Atoi(name); err == nil && arg == "" {
+			m.state = viewMain
+			m.jumpToLine(n)
+			break
+		}
+		m.loadPlugins()
+		if c, ok := m.plugin(name); ok {
+			return m.runPlugin(c, arg)
+		}
+		m.status = "Unknown command: " + name
+	}
+	return nil
+}
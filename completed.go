@@ -0,0 +1,143 @@
+package main
+
+import (
+	"slices"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- COMPLETED ITEMS ---
+//
+// What the list does with finished tasks:
+//
+//	"completed": {"mode": "strike"}                          struck through in place (default)
+//	"completed": {"mode": "bottom"}                          moved below their open siblings
+//	"completed": {"mode": "hide", "hide_after_minutes": 5}   hidden once that long done
+//
+// Only the display changes; the file keeps its order. Hidden tasks take
+// their subtree along, so a finished task with open subtasks stays. Tasks
+// finished before the app started have no completion time and hide at once.
+
+type CompletedConfig struct {
+	Mode      string `json:"mode,omitempty"`
+	HideAfter int    `json:"hide_after_minutes,omitempty"`
+}
+
+func (c *CompletedConfig) mode() string {
+	if c == nil {
+		return "strike"
+	}
+	switch c.Mode {
+	case "bottom", "hide":
+		return c.Mode
+	}
+	return "strike"
+}
+
+func (c *CompletedConfig) hideAfter() time.Duration {
+	if c == nil || c.HideAfter <= 0 {
+		return 0
+	}
+	return time.Duration(c.HideAfter) * time.Minute
+}
+
+// noteDone remembers when items[idx] was finished, for the hide mode.
+func (m *app) noteDone(idx int, now time.Time) {
+	if m.config.Completed.mode() != "hide" {
+		return
+	}
+	if m.doneAt == nil {
+		m.doneAt = map[string]time.Time{}
+	}
+	for i := idx; i < model.SubtreeEnd(m.items, idx); i++ {
+		if key := sessionKey(m.items[i]); m.items[i].Done {
+			if _, ok := m.doneAt[key]; !ok || i == idx {
+				m.doneAt[key] = now
+			}
+		} else {
+			delete(m.doneAt, key)
+		}
+	}
+}
+
+// expireDone drops completion times past the delay and reports whether any
+// task is due to disappear.
+func (m *app) expireDone(now time.Time) bool {
+	expired := false
+	for key, at := range m.doneAt {
+		if now.Sub(at) >= m.config.Completed.hideAfter() {
+			delete(m.doneAt, key)
+			expired = true
+		}
+	}
+	return expired
+}
+
+// arrangeDone applies the completed mode to a freshly built visible list.
+func (m *app) arrangeDone(visible []model.VisibleItem, now time.Time) []model.VisibleItem {
+	switch m.config.Completed.mode() {
+	case "bottom":
+		return doneLast(visible)
+	case "hide":
+		var out []model.VisibleItem
+		skipLevel := -1
+		for _, v := range visible {
+			if skipLevel != -1 && v.Data.Level > skipLevel {
+				continue
+			}
+			skipLevel = -1
+			if m.hideDone(v.Index, now) {
+				skipLevel = v.Data.Level
+				continue
+			}
+			out = append(out, v)
+		}
+		return out
+	}
+	return visible
+}
+
+// hideDone reports whether the finished subtree at items[idx] is hidden.
+func (m *app) hideDone(idx int, now time.Time) bool {
+	for i := idx; i < model.SubtreeEnd(m.items, idx); i++ {
+		if !m.items[i].Done {
+			return false
+		}
+	}
+	at, ok := m.doneAt[sessionKey(m.items[idx])]
+	return !ok || now.Sub(at) >= m.config.Completed.hideAfter()
+}
+
+// doneLast moves finished siblings (with what is shown of their subtrees)
+// below the open ones at every level, keeping the order within each part.
+func doneLast(visible []model.VisibleItem) []model.VisibleItem {
+	type block struct {
+		items []model.VisibleItem
+		done  bool
+	}
+	var blocks []block
+	for i := 0; i < len(visible); {
+		end := i + 1
+		for end < len(visible) && visible[end].Data.Level > visible[i].Data.Level {
+			end++
+		}
+		items := append([]model.VisibleItem{visible[i]}, doneLast(visible[i+1:end])...)
+		blocks = append(blocks, block{items, visible[i].Data.Done})
+		i = end
+	}
+	slices.SortStableFunc(blocks, func(a, b block) int {
+		switch {
+		case a.done == b.done:
+			return 0
+		case b.done:
+			return -1
+		}
+		return 1
+	})
+	out := make([]model.VisibleItem, 0, len(visible))
+	for _, b := range blocks {
+		out = append(out, b.items...)
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestCompletedBottom(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{config: Config{Completed: &CompletedConfig{Mode: "bottom"}}, items: []model.Item{
+		{Title: "a", Done: true},
+		{Title: "a1", Level: 1},
+		{Title: "b"},
+		{Title: "b1", Level: 1, Done: true},
+		{Title: "b2", Level: 1},
+		{Title: "c"},
+	}}
+	m.recalcVisible()
+	if got, want := m.shownTitles(), "b b2 b1 c a a1"; strings.Join(got, " ") != want {
+		t.Fatalf("shown %q, want %q", got, want)
+	}
+
+	// completing c moves it below b, the cursor follows the task
+	m.cursorMain = 3
+	m.toggleDone(5)
+	if got, want := m.shownTitles(), "b b2 b1 a a1 c"; strings.Join(got, " ") != want {
+		t.Errorf("shown %q, want %q", got, want)
+	}
+	if model.VisiblePos(m.visibleItems, 5) != 5 || model.VisiblePos(m.visibleItems, 3) != 2 {
+		t.Error("VisiblePos lost items in the rearranged list")
+	}
+	if m.items[0].Title != "a" {
+		t.Error("the file order changed")
+	}
+}
+
+func TestCompletedHide(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{config: Config{Completed: &CompletedConfig{Mode: "hide", HideAfter: 5}}, items: []model.Item{
+		{Title: "old", Done: true},
+		{Title: "parent", Done: true},
+		{Title: "open child", Level: 1},
+		{Title: "task"},
+	}}
+	m.recalcVisible()
+	if got, want := m.shownTitles(), "parent open child task"; strings.Join(got, " ") != want {
+		t.Fatalf("shown %q, want %q", got, want)
+	}
+
+	m.toggleDone(3)
+	if got := m.shownTitles(); strings.Join(got, " ") != "parent open child task" {
+		t.Fatalf("just finished task hidden too early: %q", got)
+	}
+	m.doneAt[sessionKey(m.items[3])] = time.Now().Add(-6 * time.Minute)
+	if !m.expireDone(time.Now()) {
+		t.Fatal("nothing expired")
+	}
+	m.recalcVisible()
+	if got := m.shownTitles(); strings.Join(got, " ") != "parent open child" {
+		t.Errorf("shown %q after the delay", got)
+	}
+}
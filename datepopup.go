@@ -0,0 +1,80 @@
+package main
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- DATE POPUP ---
+//
+// A stand-alone picker writing a single date token (due, snooze) of one item.
+
+type datePopup struct {
+	picker ui.DatePicker
+	idx    int
+	key    string
+	title  string
+	clock  string // keeps the HH:MM part of due values
+}
+
+func (m *app) openDatePopup(idx int, key, title string) {
+	p := datePopup{idx: idx, key: key, title: title}
+	v := model.MetaValue(m.items[idx].Title, key)
+	if key == "due" {
+		due, hasTime, ok := model.DueTime(m.items[idx].Title)
+		p.picker = ui.NewDatePicker(due, ok)
+		if hasTime {
+			p.clock = due.Format("15:04")
+		}
+	} else {
+		d, err := time.ParseInLocation(model.DateLayout, v, time.Local)
+		p.picker = ui.NewDatePicker(d, err == nil)
+		if err != nil && key == "snooze" {
+			// Domyślnie odkładamy na jutro
+			p.picker = ui.NewDatePicker(m.config.nextDay(time.Now()), true)
+		}
+	}
+	p.picker.Calendar = m.config.calendar()
+	m.datePopup = p
+	m.dateOpen = true
+}
+
+func (m *app) updateDatePopup(key string) {
+	p := &m.datePopup
+	switch key {
+	case "esc":
+		m.dateOpen = false
+	case "enter":
+		m.dateOpen = false
+		if p.idx >= len(m.items) {
+			return
+		}
+		value := ""
+		if p.picker.Set {
+			value = model.LocalDue(p.picker.Date, p.clock)
+		}
+		m.items[p.idx].Title = model.SetMeta(m.items[p.idx].Title, p.key, value)
+		m.refreshItem(p.idx)
+		if p.key == "due" {
+			m.warnCapacity(p.idx)
+		}
+		m.save()
+	default:
+		p.picker.Update(key)
+	}
+}
+
+func (m app) renderDatePopup(t theme.Theme) string {
+	p := m.datePopup
+	head := lipgloss.NewStyle().Foreground(t.Highlight).Bold(true).Render(p.title) + " " +
+		lipgloss.NewStyle().Foreground(t.Text).Render(p.picker.Label())
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Accent).
+		Padding(0, 1).
+		Render(head + "\n" + p.picker.View(true, t))
+}
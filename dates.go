@@ -0,0 +1,57 @@
+package main
+
+import (
+	"time"
+
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- DATE ENTRY ---
+//
+// Dates can be typed the way they are said (see model.ParseDate): in a title
+// as due:tomorrow or snooze:next-week, in a date field, or with ":due next
+// friday 9:00" for the selected task. They are stored as ISO dates.
+
+// expandDates rewrites the natural-language due and snooze values of title.
+func expandDates(title string, now time.Time) string {
+	if v := model.MetaValue(title, "due"); v != "" {
+		if _, _, ok := model.DueTime(title); !ok {
+			if t, hasTime, ok := model.ParseDate(v, now); ok {
+				title = model.SetMeta(title, "due", model.FormatDue(t, hasTime))
+			}
+		}
+	}
+	if v := model.MetaValue(title, "snooze"); v != "" {
+		if t, _, ok := model.ParseDate(v, now); ok {
+			title = model.SetMeta(title, "snooze", t.Format(model.DateLayout))
+		}
+	}
+	return title
+}
+
+// dueCommand sets the due date of the selected task; no date clears it.
+func (m *app) dueCommand(arg string) {
+	if m.state != viewMain || len(m.visibleItems) == 0 {
+		m.status = "Select a task first"
+		return
+	}
+	idx := m.visibleItems[m.cursorMain].Index
+	value := ""
+	if arg != "" {
+		t, hasTime, ok := model.ParseDate(arg, time.Now())
+		if !ok {
+			m.status = "Unknown date: " + arg
+			return
+		}
+		value = model.FormatDue(t, hasTime)
+		m.status = "Due " + i18n.LongDate(t)
+		if hasTime {
+			m.status += t.Format(" 15:04")
+		}
+	}
+	m.items[idx].Title = model.SetMeta(m.items[idx].Title, "due", value)
+	m.refreshItem(idx)
+	m.warnCapacity(idx)
+	m.save()
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestNaturalDateEntry(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+	if got := expandDates("pay rent due:eom snooze:next-week #home", now); got != "pay rent due:2026-10-31 snooze:2026-10-19 #home" {
+		t.Errorf("expandDates = %q", got)
+	}
+	if got := expandDates("keep due:2026-11-02 due-ish:tomorrow", now); got != "keep due:2026-11-02 due-ish:tomorrow" {
+		t.Errorf("ISO dates and other keys must stay: %q", got)
+	}
+
+	m := app{items: []model.Item{{Title: "call"}}}
+	m.recalcVisible()
+	m.runCommand("due in 2 weeks")
+	if _, _, ok := model.DueTime(m.items[0].Title); !ok {
+		t.Fatalf(":due did not set a date: %q", m.items[0].Title)
+	}
+	m.runCommand("due someday")
+	if m.status != "Unknown date: someday" {
+		t.Errorf("status = %q", m.status)
+	}
+	m.runCommand("due")
+	if model.MetaValue(m.items[0].Title, "due") != "" {
+		t.Errorf(":due without a date kept %q", m.items[0].Title)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+)
+
+// --- DEMO MODE ---
+//
+// todo --demo opens a made-up list that is never saved, to try the keys or
+// take screenshots. --export-svg FILE or --export-ansi FILE renders a single
+// frame to the file instead of starting the app; --size, --theme and --view
+// set it up. Dates are shown relative to today (tomorrow, 2 days ago), so a
+// frame comes out the same whenever it is made. Without --demo the frame
+// shows a list file, todo.md by default.
+
+const demoFile = "demo.md"
+
+// demoList is the dataset of --demo, dated around now.
+func demoList(now time.Time) (items, trash []model.Item) {
+	day := func(n int) string { return now.AddDate(0, 0, n).Format(model.DateLayout) }
+	items = []model.Item{
+		{Title: "Launch the website #work pri:A due:" + day(1)},
+		{Title: "Write the landing page copy", Level: 1, Done: true},
+		{Title: "Pick a hosting plan #research", Level: 1, Done: true},
+		{Title: "Set up the domain and TLS", Level: 1, State: "~"},
+		{Title: "Announce it on the mailing list due:" + day(2), Level: 1},
+		{Title: "Home #home"},
+		{Title: "Pay the electricity bill due:" + day(-2), Level: 1},
+		{Title: "Fix the leaking tap star:1", Level: 1},
+		{Title: "Plan the garden", Level: 1, Note: []string{"Tomatoes by the south wall, herbs in pots."}},
+		{Title: "Buy seeds", Level: 2},
+		{Title: "Build the raised bed", Level: 2},
+		{Title: "Reading #reading"},
+		{Title: "The Pragmatic Programmer", Level: 1, Done: true},
+		{Title: "Designing Data-Intensive Applications due:" + day(0), Level: 1},
+		{Title: "Call mum on Sunday pri:B"},
+		{Title: "Renew the passport due:" + day(14)},
+	}
+	trash = []model.Item{{Title: "Old meeting notes"}}
+	return items, trash
+}
+
+// demoModel is the app on items with nothing saved or locked, under the
+// default settings plus relative dates.
+func demoModel(filename string, items, trash []model.Item, themeName string) app {
+	config := Config{SelectedTheme: themeName, DateFormat: "relative", Notify: "off"}
+	config.applyLocale()
+	t, cursor := themeByName(themeName)
+	m := app{
+		items:       items,
+		trash:       trash,
+		filename:    filename,
+		activeTheme: t,
+		cursorTheme: cursor,
+		config:      config,
+		lastInput:   time.Now(),
+		reminders:   newReminders(),
+		rows:        newRenderCache(),
+		writer:      &listWriter{},
+		state:       viewMain,
+		demo:        true,
+	}
+	m.keys, _ = parseKeymap(nil)
+	m.recalcVisible()
+	return m
+}
+
+func runDemo(args []string) {
+	fs := flag.NewFlagSet("demo", flag.ExitOnError)
+	demo := fs.Bool("demo", false, "use a made-up list instead of a file")
+	svg := fs.String("export-svg", "", "render one frame to this SVG file and exit")
+	dump := fs.String("export-ansi", "", "render one frame with ANSI colors to this file and exit")
+	size := fs.String("size", "100x30", "frame size in columns x rows")
+	themeName := fs.String("theme", "", "theme name (default: the first theme)")
+	view := fs.String("view", "main", "view to show, by keymap name: main, today, agenda, calendar, stats…")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: todo --demo [--theme NAME] [--view NAME]")
+		fmt.Fprintln(fs.Output(), "       todo [--demo] --export-svg FILE|--export-ansi FILE [--size 100x30] [--theme NAME] [--view NAME] [todo.md]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var width, height int
+	if _, err := fmt.Sscanf(*size, "%dx%d", &width, &height); err != nil || width < 20 || height < 8 {
+		fmt.Fprintf(os.Stderr, "Error: bad --size %q (e.g. 100x30)\n", *size)
+		os.Exit(2)
+	}
+	state, ok := appState(-1), false
+	for _, v := range viewKeymaps {
+		if v.name == *view {
+			state, ok = v.state, true
+		}
+	}
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown view %q\n", *view)
+		os.Exit(2)
+	}
+
+	loadThemes()
+	filename := demoFile
+	var items, trash []model.Item
+	if *demo {
+		items, trash = demoList(time.Now())
+	} else {
+		if fs.NArg() > 0 {
+			filename = fs.Arg(0)
+		} else {
+			filename = "todo.md"
+		}
+		var err error
+		if items, trash, err = storage.Load(filename); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	m := demoModel(filename, items, trash, *themeName)
+	if *demo {
+		// Sama nazwa zamiast ścieżki, która zależy od katalogu
+		m.config.Header = &HeaderConfig{Format: "// {mode} {name}"}
+	}
+	m.state = state
+
+	if *svg == "" && *dump == "" {
+		if !*demo {
+			fs.Usage()
+			os.Exit(2)
+		}
+		if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	m.width, m.height = width, height
+	frame := m.renderFrame()
+	for path, data := range map[string]string{*svg: ansiToSVG(frame, m.activeTheme), *dump: frame + "\n"} {
+		if path == "" {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// isDemoArg reports whether the command line asks for the demo or a frame
+// export.
+func isDemoArg(arg string) bool {
+	name, ok := strings.CutPrefix(arg, "-")
+	name = strings.TrimPrefix(name, "-")
+	return ok && (strings.HasPrefix(name, "demo") || strings.HasPrefix(name, "export-"))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/i18n"
+)
+
+func TestDemo(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	defer i18n.SetDateFormat("iso")
+	loadThemes()
+	items, trash := demoList(time.Now())
+	m := demoModel(demoFile, items, trash, "")
+	m.width, m.height = 100, 30
+
+	frame := ansi.Strip(m.renderFrame())
+	for _, want := range []string{"due:tomorrow", "due:2 days ago", "1 overdue", "Bin: 1"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("frame lacks %q:\n%s", want, frame)
+		}
+	}
+	if frame != ansi.Strip(m.renderFrame()) {
+		t.Error("frames differ")
+	}
+
+	next, _ := m.updateMain(keyMsg(" "))
+	if m = next.(app); !m.items[0].Done || m.dirty {
+		t.Errorf("the demo must change in memory only: done %v, dirty %v", m.items[0].Done, m.dirty)
+	}
+
+	for arg, want := range map[string]bool{"--demo": true, "-demo": true, "--export-svg=a.svg": true, "demo.md": false, "export": false, "--size": false} {
+		if isDemoArg(arg) != want {
+			t.Errorf("isDemoArg(%q) = %v", arg, !want)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- DEPENDENCIES ---
+//
+// blocked:<id>[,<id>...] makes a task wait for others. While any of them is
+// open the task is drawn dimmed with a ⛔ marker, and completing it asks for
+// confirmation first. "w" picks the task under the cursor, Enter on another
+// one adds it as a blocker (or removes it when it already is one); "W" shows
+// the whole chain: what the task waits for, transitively, and what waits for
+// it.
+
+// blockers returns the tasks items[idx] waits for, open or not. Ids that
+// resolve to nothing are left to lint.
+func blockers(items []model.Item, idx int) []int {
+	var out []int
+	for _, id := range model.MetaValues(items[idx].Title, "blocked") {
+		if b := model.FindByID(items, id); b != -1 && b != idx {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// openBlockers returns the blockers of items[idx] that aren't done yet.
+func openBlockers(items []model.Item, idx int) []int {
+	var out []int
+	for _, b := range blockers(items, idx) {
+		if !items[b].Done {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+func isBlocked(items []model.Item, idx int) bool {
+	return !items[idx].Done && len(openBlockers(items, idx)) > 0
+}
+
+// dependents returns the tasks waiting for items[idx].
+func dependents(items []model.Item, idx int) []int {
+	id := model.ID(items[idx])
+	if id == "" {
+		return nil
+	}
+	var out []int
+	for i, it := range items {
+		if i != idx && slices.Contains(model.MetaValues(it.Title, "blocked"), id) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// waitsFor reports whether items[from] depends on items[to], directly or
+// through other tasks.
+func waitsFor(items []model.Item, from, to int) bool {
+	seen := map[int]bool{}
+	stack := []int{from}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, b := range blockers(items, i) {
+			if b == to {
+				return true
+			}
+			if !seen[b] {
+				seen[b] = true
+				stack = append(stack, b)
+			}
+		}
+	}
+	return false
+}
+
+// toggleBlocker makes items[idx] wait for items[blocker], or stops it
+// waiting when it already does. Both tasks get an id when needed.
+func toggleBlocker(items []model.Item, idx, blocker int) (added bool, err error) {
+	switch {
+	case idx == blocker:
+		return false, fmt.Errorf("a task can't wait for itself")
+	case waitsFor(items, blocker, idx):
+		return false, fmt.Errorf("“%s” already waits for this task", model.DisplayTitle(items[blocker].Title))
+	}
+	id := model.ID(items[blocker])
+	if id == "" {
+		id = model.NewID()
+		items[blocker].Title = model.SetMeta(items[blocker].Title, "id", id)
+	}
+	if model.ID(items[idx]) == "" {
+		items[idx].Title = model.SetMeta(items[idx].Title, "id", model.NewID())
+	}
+	ids := model.MetaValues(items[idx].Title, "blocked")
+	if i := slices.Index(ids, id); i != -1 {
+		ids = slices.Delete(ids, i, i+1)
+	} else {
+		ids = append(ids, id)
+		added = true
+	}
+	items[idx].Title = model.SetMeta(items[idx].Title, "blocked", strings.Join(ids, ","))
+	return added, nil
+}
+
+// --- PICKING A BLOCKER ---
+
+func (m *app) startBlockPick(idx int) {
+	m.blockPick = true
+	m.blockFrom = idx
+}
+
+// updateBlockPick handles a key while picking a blocker. Like move mode,
+// navigation keys return false and are handled by updateMain.
+func (m *app) updateBlockPick(key string, target int) bool {
+	switch key {
+	case "up", "k", "down", "j", "v":
+		return false
+	case "esc":
+		m.blockPick = false
+	case "enter":
+		if target == -1 {
+			return true
+		}
+		added, err := toggleBlocker(m.items, m.blockFrom, target)
+		if err != nil {
+			m.status = err.Error()
+			return true
+		}
+		m.blockPick = false
+		from, title := model.ID(m.items[m.blockFrom]), model.DisplayTitle(m.items[target].Title)
+		m.recalcVisible()
+		m.save()
+		if added {
+			m.status = "⛔ Now waits for “" + title + "”"
+		} else {
+			m.status = "No longer waits for “" + title + "”"
+		}
+		m.jumpTo(model.FindByID(m.items, from))
+	}
+	return true
+}
+
+// --- COMPLETING A BLOCKED TASK ---
+
+// confirmBlocked holds off completing a task with open blockers and asks
+// first. The task is remembered by id, because an autosort may move it.
+func (m *app) confirmBlocked(idx int) bool {
+	if m.items[idx].Done || len(openBlockers(m.items, idx)) == 0 {
+		return false
+	}
+	if model.ID(m.items[idx]) == "" {
+		m.items[idx].Title = model.SetMeta(m.items[idx].Title, "id", model.NewID())
+		m.refreshItem(idx)
+	}
+	m.blockedDoneID = model.ID(m.items[idx])
+	return true
+}
+
+// updateBlockedPrompt answers the prompt; any other key dismisses it and is
+// handled as usual.
+func (m *app) updateBlockedPrompt(msg tea.KeyMsg) bool {
+	id := m.blockedDoneID
+	m.blockedDoneID = ""
+	switch msg.String() {
+	case "y", "enter":
+		if idx := model.FindByID(m.items, id); idx != -1 {
+			m.flipDone(idx)
+		}
+		return true
+	case "n", "esc":
+		return true
+	}
+	return false
+}
+
+func (m app) renderBlockedPrompt(t theme.Theme) string {
+	idx := model.FindByID(m.items, m.blockedDoneID)
+	if idx == -1 {
+		return ""
+	}
+	open := openBlockers(m.items, idx)
+	if len(open) == 0 {
+		return ""
+	}
+	what := "“" + model.DisplayTitle(m.items[open[0]].Title) + "”"
+	if len(open) > 1 {
+		what += fmt.Sprintf(" and %d more", len(open)-1)
+	}
+	return lipgloss.NewStyle().Foreground(t.Error).Render("⛔ Still waits for " + what + " — complete anyway? (y/n)")
+}
+
+// --- DEPENDENCY CHAIN VIEW ---
+
+type depRow struct {
+	idx   int
+	depth int  // 0 for the task itself
+	up    bool // a blocker rather than a dependent
+}
+
+// depChain lists what items[idx] waits for, nearest first and each blocker
+// followed by its own blockers, then the task itself, then what waits for it
+// in the same shape. Tasks reached twice are shown once.
+func depChain(items []model.Item, idx int) []depRow {
+	var walk func(i, depth int, up bool, seen map[int]bool) []depRow
+	walk = func(i, depth int, up bool, seen map[int]bool) []depRow {
+		next := dependents(items, i)
+		if up {
+			next = blockers(items, i)
+		}
+		var rows []depRow
+		for _, n := range next {
+			if seen[n] {
+				continue
+			}
+			seen[n] = true
+			rows = append(rows, depRow{idx: n, depth: depth, up: up})
+			rows = append(rows, walk(n, depth+1, up, seen)...)
+		}
+		return rows
+	}
+	rows := walk(idx, 1, true, map[int]bool{idx: true})
+	rows = append(rows, depRow{idx: idx})
+	return append(rows, walk(idx, 1, false, map[int]bool{idx: true})...)
+}
+
+func (m *app) openDeps() {
+	if len(m.visibleItems) == 0 {
+		return
+	}
+	idx := m.visibleItems[m.cursorMain].Index
+	chain := depChain(m.items, idx)
+	if len(chain) == 1 {
+		m.status = "“" + model.DisplayTitle(m.items[idx].Title) + "” has no dependencies — w sets one"
+		return
+	}
+	// Lista wyliczana na bieżąco z id, więc zostaje aktualna po odhaczeniu
+	if model.ID(m.items[idx]) == "" {
+		m.items[idx].Title = model.SetMeta(m.items[idx].Title, "id", model.NewID())
+		m.refreshItem(idx)
+		m.save()
+	}
+	m.depsID = model.ID(m.items[idx])
+	m.cursorDeps = slices.IndexFunc(chain, func(r depRow) bool { return r.depth == 0 })
+	m.state = viewDeps
+}
+
+func (m app) depRows() []depRow {
+	idx := model.FindByID(m.items, m.depsID)
+	if idx == -1 {
+		return nil
+	}
+	return depChain(m.items, idx)
+}
+
+func (m app) updateDeps(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows := m.depRows()
+	m.cursorDeps = min(m.cursorDeps, max(0, len(rows)-1))
+	switch msg.String() {
+	case "esc":
+		m.state = viewMain
+	case "up", "k":
+		if m.cursorDeps > 0 {
+			m.cursorDeps--
+		}
+	case "down", "j":
+		if m.cursorDeps < len(rows)-1 {
+			m.cursorDeps++
+		}
+	case "enter":
+		if len(rows) > 0 {
+			m.state = viewMain
+			m.jumpTo(rows[m.cursorDeps].idx)
+		}
+	case " ":
+		if len(rows) > 0 {
+			m.toggleDone(rows[m.cursorDeps].idx)
+		}
+	}
+	return m, nil
+}
+
+func (m app) renderDeps(height int, t theme.Theme) string {
+	rows := m.depRows()
+	cursor := min(m.cursorDeps, max(0, len(rows)-1))
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+	heading := lipgloss.NewStyle().Foreground(t.Accent).Bold(true)
+
+	var lines []string
+	cursorLine := 0
+	for i, r := range rows {
+		switch {
+		case i == 0 && r.up:
+			lines = append(lines, heading.Render("Waits for"))
+		case r.depth == 0:
+			if i > 0 {
+				lines = append(lines, "")
+			}
+		case i > 0 && rows[i-1].depth == 0:
+			lines = append(lines, "", heading.Render("Blocks"))
+		}
+
+		it := m.items[r.idx]
+		marker := "  "
+		if i == cursor {
+			marker = " ➤"
+			cursorLine = len(lines)
+		}
+		check, title, style := "[ ]", model.DisplayTitle(it.Title), lipgloss.NewStyle().Foreground(t.Text)
+		switch {
+		case it.Done:
+			check, style = "[✔]", dim.Strikethrough(true)
+		case isBlocked(m.items, r.idx):
+			title, style = "⛔ "+title, dim
+		}
+		if r.depth == 0 {
+			style = style.Bold(true)
+		}
+		if i == cursor {
+			style = style.Foreground(t.Highlight).Bold(true)
+		}
+		indent := strings.Repeat("  ", max(0, r.depth-1))
+		if r.depth > 0 {
+			indent = "  " + indent
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(t.Highlight).Render(marker)+" "+indent+dim.Render(check)+" "+style.Render(title))
+	}
+
+	start, end := ui.Paginator(cursorLine, height, len(lines))
+	return m.frame(height, t.Highlight).
+		Render(strings.Join(lines[start:end], "\n"))
+}
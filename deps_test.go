@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestBlockPickAndComplete(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{items: []model.Item{
+		{Title: "deploy"},
+		{Title: "review"},
+		{Title: "write docs"},
+	}}
+	m.recalcVisible()
+	press := func(k string) {
+		t.Helper()
+		next, _ := m.updateMain(keyMsg(k))
+		m = next.(app)
+	}
+
+	// deploy waits for review, review waits for docs
+	press("w")
+	press("enter")
+	if !m.blockPick || m.status == "" {
+		t.Fatal("a task was made to wait for itself")
+	}
+	press("j")
+	press("enter")
+	m.cursorMain = 1
+	press("w")
+	press("j")
+	press("enter")
+	if !isBlocked(m.items, 0) || !isBlocked(m.items, 1) || isBlocked(m.items, 2) {
+		t.Fatalf("items = %+v", m.items)
+	}
+
+	// docs waiting for deploy would close the loop
+	m.cursorMain = 2
+	press("w")
+	press("k")
+	press("k")
+	press("enter")
+	if !m.blockPick || model.MetaValue(m.items[2].Title, "blocked") != "" {
+		t.Fatal("a dependency cycle was accepted")
+	}
+	press("esc")
+
+	m.toggleDone(0)
+	if m.items[0].Done || m.blockedDoneID == "" {
+		t.Fatal("a blocked task was completed without asking")
+	}
+	m.updateBlockedPrompt(keyMsg("n"))
+	if m.items[0].Done {
+		t.Fatal("n still completed the task")
+	}
+	m.toggleDone(0)
+	m.updateBlockedPrompt(keyMsg("y"))
+	if !m.items[0].Done {
+		t.Fatal("y didn't complete the task")
+	}
+
+	m.toggleDone(2)
+	if !m.items[2].Done || isBlocked(m.items, 1) {
+		t.Error("finishing the blocker must unblock its dependent")
+	}
+}
+
+func TestDepChain(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	items := []model.Item{
+		{Title: "release id:r blocked:t,d"},
+		{Title: "tests id:t blocked:f"},
+		{Title: "fixtures id:f"},
+		{Title: "docs id:d blocked:f"},
+		{Title: "announce blocked:r"},
+	}
+	var got []string
+	for _, r := range depChain(items, 0) {
+		got = append(got, model.MetaValue(items[r.idx].Title, "id")+":"+string(rune('0'+r.depth)))
+	}
+	// fixtures shows up once, under the first blocker reaching it
+	want := []string{"t:1", "f:2", "d:1", "r:0", ":1"}
+	if len(got) != len(want) {
+		t.Fatalf("chain = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("chain = %v, want %v", got, want)
+		}
+	}
+}
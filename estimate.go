@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- ESTIMATES ---
+//
+// "~30m" or "~1h30m" typed in a title is stored as est:30m, the estimate
+// :plan works with. A parent shows "Σ 2h" for the open work below it, the
+// Today view sums what it lists against daily_capacity, and setting a due
+// date or an estimate that pushes a day over the capacity warns in the
+// footer. Tasks without an estimate count as 30 minutes, as in :plan.
+
+// estimateWord parses a "~45m" shorthand.
+func estimateWord(word string) (time.Duration, bool) {
+	v, ok := strings.CutPrefix(word, "~")
+	if !ok {
+		return 0, false
+	}
+	d, err := time.ParseDuration(v)
+	return d, err == nil && d > 0
+}
+
+// expandEstimate rewrites a "~2h" word of title as est:2h.
+func expandEstimate(title string) string {
+	words := strings.Fields(title)
+	for i, w := range words {
+		if d, ok := estimateWord(w); ok {
+			rest := strings.Join(append(words[:i:i], words[i+1:]...), " ")
+			return model.SetMeta(rest, "est", formatEstimate(d))
+		}
+	}
+	return title
+}
+
+// estimateTotal is the open work estimated under items[idx]: the sum over
+// its subtasks, or its own estimate when none of them has one. Finished
+// tasks count nothing.
+func estimateTotal(items []model.Item, idx int) time.Duration {
+	if items[idx].Done {
+		return 0
+	}
+	var sum time.Duration
+	end := model.SubtreeEnd(items, idx)
+	for i := idx + 1; i < end; i = model.SubtreeEnd(items, i) {
+		sum += estimateTotal(items, i)
+	}
+	if d, ok := taskEstimate(items[idx].Title); ok && sum == 0 {
+		sum = d
+	}
+	return sum
+}
+
+// estimateLabel is the "Σ" sum shown after parent titles.
+func estimateLabel(items []model.Item, idx int) string {
+	if !model.HasChildren(items, idx) {
+		return ""
+	}
+	if d := estimateTotal(items, idx); d > 0 {
+		return " Σ " + formatEstimate(d)
+	}
+	return ""
+}
+
+// workEstimate is how long a task takes for capacity: its open subtree, or
+// the default estimate when nothing is written down.
+func workEstimate(items []model.Item, idx int) (d time.Duration, estimated bool) {
+	if d = estimateTotal(items, idx); d > 0 {
+		return d, true
+	}
+	return defaultEstimate, false
+}
+
+// todayLoad sums the Today entries, counting a subtask listed together with
+// its parent once. unestimated is how many were counted at the default.
+func todayLoad(items []model.Item, entries []todayEntry) (total time.Duration, unestimated int) {
+	listed := make(map[int]bool, len(entries))
+	for _, e := range entries {
+		listed[e.idx] = true
+	}
+	for _, e := range entries {
+		inside := false
+		for p := model.ParentIndex(items, e.idx); p != -1 && !inside; p = model.ParentIndex(items, p) {
+			inside = listed[p]
+		}
+		if inside {
+			continue
+		}
+		d, ok := workEstimate(items, e.idx)
+		total += d
+		if !ok {
+			unestimated++
+		}
+	}
+	return total, unestimated
+}
+
+// dayLoad is the work planned for day: the Today view for today, the open
+// tasks due that day otherwise.
+func dayLoad(items []model.Item, day, now time.Time) time.Duration {
+	if day.Equal(model.StartOfDay(now)) {
+		total, _ := todayLoad(items, todayEntries(items, now))
+		return total
+	}
+	return dueLoad(items)[day]
+}
+
+// warnCapacity puts a warning in the footer when the day items[idx] is due
+// on holds more than daily_capacity.
+func (m *app) warnCapacity(idx int) {
+	if idx < 0 || idx >= len(m.items) || m.items[idx].Done {
+		return
+	}
+	now := time.Now()
+	due, _, ok := model.DueTime(m.items[idx].Title)
+	if !ok {
+		if !isStarred(m.items[idx]) {
+			return
+		}
+		due = now
+	}
+	day := model.StartOfDay(due)
+	if day.Before(model.StartOfDay(now)) {
+		day = model.StartOfDay(now) // zaległe są w planie na dziś
+	}
+	capacity := m.config.dailyCapacity()
+	if load := dayLoad(m.items, day, now); load > capacity {
+		m.status = fmt.Sprintf("⚠ %s planned for %s, over the %s capacity",
+			formatEstimate(load), agendaDayLabel(day, model.StartOfDay(now)), formatEstimate(capacity))
+	}
+}
+
+// renderTodayLoad is the Today view's summary line: a bar of the planned
+// work against daily_capacity.
+func (m app) renderTodayLoad(entries []todayEntry, t theme.Theme) string {
+	total, unestimated := todayLoad(m.items, entries)
+	capacity := m.config.dailyCapacity()
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+	barW := 20
+	filled := min(barW, int(float64(barW)*float64(total)/float64(capacity)))
+	bar := lipgloss.NewStyle().Foreground(t.Special)
+	if total > capacity {
+		bar = bar.Foreground(t.Error)
+	}
+	line := "  " + bar.Render(strings.Repeat("█", filled)) + dim.Render(strings.Repeat("░", barW-filled)) +
+		" " + lipgloss.NewStyle().Foreground(t.Text).Render(formatEstimate(total)+" / "+formatEstimate(capacity))
+	if total > capacity {
+		line += lipgloss.NewStyle().Foreground(t.Error).Bold(true).Render("  ⚠ " + i18n.Tf("%s over capacity", formatEstimate(total-capacity)))
+	}
+	if unestimated > 0 {
+		line += dim.Render("  " + i18n.Tf("%d without estimate", unestimated))
+	}
+	return line
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestExpandEstimate(t *testing.T) {
+	for in, want := range map[string]string{
+		"write the report ~90m #work": "write the report #work est:1h30m",
+		"call ~2h":                    "call est:2h",
+		"about ~ done ~soon":          "about ~ done ~soon",
+		"swap est:1h ~15m":            "swap est:15m",
+	} {
+		if got := expandEstimate(in); got != want {
+			t.Errorf("expandEstimate(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if d, ok := taskEstimate("left as typed ~45m"); !ok || d != 45*time.Minute {
+		t.Errorf("a ~45m word must count as the estimate, got %v %v", d, ok)
+	}
+}
+
+func TestEstimateTotals(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	today := time.Now().Format(model.DateLayout)
+	m := app{width: 100, height: 20, config: Config{DailyCapacity: "4h"}, items: []model.Item{
+		{Title: "Release est:9h"},
+		{Title: "changelog est:1h", Level: 1},
+		{Title: "docs", Level: 1},
+		{Title: "pages est:2h", Level: 2},
+		{Title: "tag est:30m", Level: 2, Done: true},
+		{Title: "shipped est:5h", Level: 1, Done: true},
+		{Title: "Errands"},
+		{Title: "bank", Level: 1},
+	}}
+	m.recalcVisible()
+	for idx, want := range map[int]time.Duration{0: 3 * time.Hour, 2: 2 * time.Hour, 4: 0, 6: 0, 1: time.Hour} {
+		if got := estimateTotal(m.items, idx); got != want {
+			t.Errorf("estimateTotal(%d) = %v, want %v", idx, got, want)
+		}
+	}
+	if got := m.itemContent(0); !strings.HasSuffix(got, " Σ 3h") {
+		t.Errorf("parent row = %q", got)
+	}
+	if got := m.itemContent(1) + m.itemContent(6); strings.Contains(got, "Σ") {
+		t.Errorf("leaves and unestimated parents show no sum: %q", got)
+	}
+
+	// Today: Release z podzadaniami (3h) raz, changelog już w nim; bank bez szacunku
+	m.items[0].Title += " due:" + today
+	m.items[1].Title += " due:" + today
+	m.items[7].Title += " star:1"
+	total, unestimated := todayLoad(m.items, todayEntries(m.items, time.Now()))
+	if total != 3*time.Hour+defaultEstimate || unestimated != 1 {
+		t.Errorf("todayLoad = %v, %d", total, unestimated)
+	}
+	m.state = viewToday
+	if view := m.View(); !strings.Contains(view, "3h30m / 4h") || strings.Contains(view, "over capacity") || !strings.Contains(view, "1 without estimate") {
+		t.Errorf("today view:\n%s", view)
+	}
+
+	// Szacunek wpisany przy edycji: pełny dzień to jeszcze nie przekroczenie
+	m.state = viewMain
+	m.jumpTo(7)
+	m.inputMode, m.editMode, m.inputBuf = true, true, "bank ~1h star:1"
+	m.handleInputConfirm()
+	if m.items[7].Title != "bank star:1 est:1h" {
+		t.Errorf("title = %q", m.items[7].Title)
+	}
+	if m.status != "" {
+		t.Errorf("a full day is no warning yet, got %q", m.status)
+	}
+	m.inputMode, m.editMode, m.inputBuf = true, true, "bank ~2h star:1"
+	m.handleInputConfirm()
+	if !strings.HasPrefix(m.status, "⚠ 5h planned for") || !strings.HasSuffix(m.status, "over the 4h capacity") {
+		t.Errorf("status = %q", m.status)
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- SCREENSHOT EXPORT ---
+
+const (
+	svgFontSize   = 14
+	svgCellWidth  = 8.4 // 0.6em – typowa szerokość znaku fontu monospace
+	svgLineHeight = 18
+)
+
+// renderFrame renders the current view in truecolor regardless of the
+// terminal, so exports look the same everywhere.
+func (m app) renderFrame() string {
+	prev := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	defer lipgloss.SetColorProfile(prev)
+	return m.View()
+}
+
+func (m *app) exportScreenshot(path string) {
+	if path == "" {
+		path = strings.TrimSuffix(filepath.Base(m.filename), filepath.Ext(m.filename)) + ".svg"
+	}
+	frame := m.renderFrame()
+
+	data := frame + "\n"
+	if strings.EqualFold(filepath.Ext(path), ".svg") {
+		data = ansiToSVG(frame, m.activeTheme)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		m.status = "Export failed: " + err.Error()
+		return
+	}
+	m.status = "Screenshot saved to " + path
+}
+
+// --- ANSI PARSING ---
+
+type cellStyle struct {
+	fg, bg                          string
+	bold, italic, underline, strike bool
+}
+
+type cell struct {
+	text  string
+	width int
+	style cellStyle
+}
+
+var ansiBasic = []string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+func ansi256(n int) string {
+	switch {
+	case n < 16:
+		return ansiBasic[n]
+	case n < 232:
+		n -= 16
+		levels := []int{0, 95, 135, 175, 215, 255}
+		return fmt.Sprintf("#%02x%02x%02x", levels[n/36], levels[(n/6)%6], levels[n%6])
+	default:
+		v := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", v, v, v)
+	}
+}
+
+// applySGR updates the style with the parameters of an "ESC[...m" sequence.
+func applySGR(st *cellStyle, params string) {
+	parts := strings.Split(params, ";")
+	if params == "" {
+		parts = []string{"0"}
+	}
+	for i := 0; i < len(parts); i++ {
+		n, _ := strconv.Atoi(parts[i])
+		switch {
+		case n == 0:
+			*st = cellStyle{}
+		case n == 1:
+			st.bold = true
+		case n == 3:
+			st.italic = true
+		case n == 4:
+			st.underline = true
+		case n == 9:
+			st.strike = true
+		case n == 22:
+			st.bold = false
+		case n == 23:
+			st.italic = false
+		case n == 24:
+			st.underline = false
+		case n == 29:
+			st.strike = false
+		case n >= 30 && n <= 37:
+			st.fg = ansiBasic[n-30]
+		case n >= 90 && n <= 97:
+			st.fg = ansiBasic[n-90+8]
+		case n >= 40 && n <= 47:
+			st.bg = ansiBasic[n-40]
+		case n >= 100 && n <= 107:
+			st.bg = ansiBasic[n-100+8]
+		case n == 39:
+			st.fg = ""
+		case n == 49:
+			st.bg = ""
+		case n == 38 || n == 48:
+			var color string
+			if i+4 < len(parts) && parts[i+1] == "2" {
+				r, _ := strconv.Atoi(parts[i+2])
+				g, _ := strconv.Atoi(parts[i+3])
+				b, _ := strconv.Atoi(parts[i+4])
+				color = fmt.Sprintf("#%02x%02x%02x", r, g, b)
+				i += 4
+			} else if i+2 < len(parts) && parts[i+1] == "5" {
+				c, _ := strconv.Atoi(parts[i+2])
+				color = ansi256(c)
+				i += 2
+			}
+			if n == 38 {
+				st.fg = color
+			} else {
+				st.bg = color
+			}
+		}
+	}
+}
+
+// parseANSILine splits a styled line into cells, dropping control sequences.
+func parseANSILine(line string) []cell {
+	var cells []cell
+	var st cellStyle
+	for i := 0; i < len(line); {
+		if line[i] == 0x1b && i+1 < len(line) {
+			switch line[i+1] {
+			case '[':
+				j := i + 2
+				for j < len(line) && (line[j] < 0x40 || line[j] > 0x7e) {
+					j++
+				}
+				if j < len(line) && line[j] == 'm' {
+					applySGR(&st, line[i+2:j])
+				}
+				i = j + 1
+			case ']':
+				// OSC: do BEL albo ST
+				j := i + 2
+				for j < len(line) && line[j] != 0x07 && !(line[j] == 0x1b && j+1 < len(line) && line[j+1] == '\\') {
+					j++
+				}
+				if j < len(line) && line[j] == 0x1b {
+					j++
+				}
+				i = j + 1
+			default:
+				i += 2
+			}
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(line[i:])
+		text := line[i : i+size]
+		cells = append(cells, cell{text: text, width: ansi.StringWidth(text), style: st})
+		i += size
+	}
+	return cells
+}
+
+// --- SVG ---
+
+func ansiToSVG(frame string, t theme.Theme) string {
+	lines := strings.Split(frame, "\n")
+	cols := 0
+	parsed := make([][]cell, len(lines))
+	for i, l := range lines {
+		parsed[i] = parseANSILine(l)
+		w := 0
+		for _, c := range parsed[i] {
+			w += c.width
+		}
+		cols = max(cols, w)
+	}
+
+	width := float64(cols)*svgCellWidth + 20
+	height := len(lines)*svgLineHeight + 20
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%d" viewBox="0 0 %.0f %d">`+"\n", width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", string(t.Base))
+	fmt.Fprintf(&b, `<g font-family="Menlo, Consolas, 'DejaVu Sans Mono', monospace" font-size="%d" xml:space="preserve">`+"\n", svgFontSize)
+
+	for row, cells := range parsed {
+		y := 10 + row*svgLineHeight
+		col := 0
+		for i := 0; i < len(cells); {
+			// Grupujemy sąsiednie komórki o tym samym stylu
+			j := i
+			var text strings.Builder
+			w := 0
+			for j < len(cells) && cells[j].style == cells[i].style {
+				text.WriteString(cells[j].text)
+				w += cells[j].width
+				j++
+			}
+			st := cells[i].style
+			x := 10 + float64(col)*svgCellWidth
+			if st.bg != "" {
+				fmt.Fprintf(&b, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s"/>`+"\n", x, y, float64(w)*svgCellWidth, svgLineHeight, st.bg)
+			}
+			if s := text.String(); strings.TrimSpace(s) != "" {
+				fg := st.fg
+				if fg == "" {
+					fg = string(t.Text)
+				}
+				attrs := fmt.Sprintf(`fill="%s"`, fg)
+				if st.bold {
+					attrs += ` font-weight="bold"`
+				}
+				if st.italic {
+					attrs += ` font-style="italic"`
+				}
+				if st.underline || st.strike {
+					deco := []string{}
+					if st.underline {
+						deco = append(deco, "underline")
+					}
+					if st.strike {
+						deco = append(deco, "line-through")
+					}
+					attrs += fmt.Sprintf(` text-decoration="%s"`, strings.Join(deco, " "))
+				}
+				fmt.Fprintf(&b, `<text x="%.1f" y="%d" textLength="%.1f" lengthAdjust="spacingAndGlyphs" %s>%s</text>`+"\n",
+					x, y+svgLineHeight-4, float64(w)*svgCellWidth, attrs, html.EscapeString(s))
+			}
+			col += w
+			i = j
+		}
+	}
+	b.WriteString("</g>\n</svg>\n")
+	return b.String()
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+)
+
+// --- FEED SUBSCRIPTIONS ---
+//
+// `todo serve` and `todo remind` poll the RSS/Atom feeds listed under "feeds"
+// in config.json every feed_minutes (default 30) and add a task per new entry,
+// "Title https://link #tag", under the feed's section (default "Feeds").
+// Entries are recognised by a hidden feed: token, so each one is added once.
+// With retain_days, entries older than that are moved to the bin and older
+// entries still in the feed are not added again.
+
+const (
+	defaultFeedSection = "Feeds"
+	defaultFeedMinutes = 30
+	maxFeedSize        = 5 << 20
+)
+
+type FeedConfig struct {
+	URL string `json:"url"`
+	// Section is the top-level task new entries go under (default "Feeds")
+	Section string `json:"section,omitempty"`
+	// Tag is added to every task of this feed
+	Tag string `json:"tag,omitempty"`
+	// RetainDays moves entries older than this to the bin (0 = keep)
+	RetainDays int `json:"retain_days,omitempty"`
+}
+
+func (c Config) feedInterval() time.Duration {
+	if c.FeedMinutes > 0 {
+		return time.Duration(c.FeedMinutes) * time.Minute
+	}
+	return defaultFeedMinutes * time.Minute
+}
+
+type feedEntry struct {
+	id, title, link string
+	published       time.Time
+}
+
+// feedDoc decodes RSS 2.0 (channel>item), RSS 1.0 (item) and Atom (entry).
+type feedDoc struct {
+	Channel []feedXMLItem `xml:"channel>item"`
+	Items   []feedXMLItem `xml:"item"`
+	Entries []struct {
+		ID        string `xml:"id"`
+		Title     string `xml:"title"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+		Links     []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+	} `xml:"entry"`
+}
+
+type feedXMLItem struct {
+	Title   string `xml:"title"`
+	Link    string `xml:"link"`
+	GUID    string `xml:"guid"`
+	PubDate string `xml:"pubDate"`
+	Date    string `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+var feedDateLayouts = []string{time.RFC1123Z, time.RFC1123, time.RFC3339, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST"}
+
+func parseFeedDate(s string) time.Time {
+	for _, layout := range feedDateLayouts {
+		if t, err := time.Parse(layout, strings.TrimSpace(s)); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func parseFeed(data []byte) ([]feedEntry, error) {
+	var doc feedDoc
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	var out []feedEntry
+	for _, it := range append(doc.Channel, doc.Items...) {
+		e := feedEntry{id: it.GUID, title: it.Title, link: strings.TrimSpace(it.Link), published: parseFeedDate(it.PubDate)}
+		if e.published.IsZero() {
+			e.published = parseFeedDate(it.Date)
+		}
+		out = append(out, e)
+	}
+	for _, it := range doc.Entries {
+		e := feedEntry{id: it.ID, title: it.Title, published: parseFeedDate(it.Published)}
+		if e.published.IsZero() {
+			e.published = parseFeedDate(it.Updated)
+		}
+		for _, l := range it.Links {
+			if l.Rel == "" || l.Rel == "alternate" {
+				e.link = l.Href
+				break
+			}
+		}
+		out = append(out, e)
+	}
+	for i := range out {
+		if out[i].id == "" {
+			out[i].id = out[i].link
+		}
+	}
+	return out, nil
+}
+
+var feedHTTP = &http.Client{Timeout: 30 * time.Second}
+
+func fetchFeed(ctx context.Context, url string) ([]feedEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", appName)
+	resp, err := feedHTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := parseFeed(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+	return entries, nil
+}
+
+func shortHash(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:4])
+}
+
+// applyFeed adds the new entries of one feed and moves expired ones to the
+// bin. It reports how many tasks were added and removed.
+func applyFeed(items, trash []model.Item, feed FeedConfig, entries []feedEntry, now time.Time) ([]model.Item, []model.Item, int, int) {
+	prefix := shortHash(feed.URL) + "."
+	var cutoff time.Time
+	if feed.RetainDays > 0 {
+		cutoff = model.StartOfDay(now).AddDate(0, 0, -feed.RetainDays)
+	}
+
+	removed := 0
+	if !cutoff.IsZero() {
+		for i := 0; i < len(items); {
+			created, err := time.ParseInLocation(model.DateLayout, model.MetaValue(items[i].Title, "created"), time.Local)
+			if strings.HasPrefix(model.MetaValue(items[i].Title, "feed"), prefix) && err == nil && created.Before(cutoff) {
+				items, trash = model.DeleteSubtree(items, trash, i)
+				removed++
+				continue
+			}
+			i++
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, list := range [][]model.Item{items, trash} {
+		for _, it := range list {
+			seen[model.MetaValue(it.Title, "feed")] = true
+		}
+	}
+	var added []model.Item
+	for _, e := range entries {
+		key := prefix + shortHash(e.id)
+		if e.id == "" || seen[key] || (!cutoff.IsZero() && !e.published.IsZero() && e.published.Before(cutoff)) {
+			continue
+		}
+		seen[key] = true
+		title := bookmarkTitle(bookmark{title: e.title, url: e.link})
+		if feed.Tag != "" {
+			title = model.SetTag(title, feed.Tag, true)
+		}
+		title = model.SetMeta(title, "feed", key)
+		title = model.SetMeta(title, "created", now.Format(model.DateLayout))
+		added = append(added, model.Item{Title: title, Level: 1})
+	}
+	if len(added) > 0 {
+		section := feed.Section
+		if section == "" {
+			section = defaultFeedSection
+		}
+		items = appendToSection(items, section, added)
+	}
+	return items, trash, len(added), removed
+}
+
+// fetchFeeds downloads every configured feed; failed ones are logged and skipped.
+func fetchFeeds(ctx context.Context, feeds []FeedConfig) map[string][]feedEntry {
+	out := make(map[string][]feedEntry)
+	for _, f := range feeds {
+		entries, err := fetchFeed(ctx, f.URL)
+		if err != nil {
+			log.Printf("feed: %v", err)
+			continue
+		}
+		out[f.URL] = entries
+	}
+	return out
+}
+
+// applyFeeds runs applyFeed for every fetched feed.
+func applyFeeds(items, trash []model.Item, feeds []FeedConfig, fetched map[string][]feedEntry, now time.Time) ([]model.Item, []model.Item, bool) {
+	changed := false
+	for _, f := range feeds {
+		entries, ok := fetched[f.URL]
+		if !ok {
+			continue
+		}
+		var added, removed int
+		items, trash, added, removed = applyFeed(items, trash, f, entries, now)
+		if added+removed > 0 {
+			log.Printf("feed %s: %d new, %d expired", f.URL, added, removed)
+			changed = true
+		}
+	}
+	return items, trash, changed
+}
+
+// updateFeeds polls the feeds once for `todo remind`, which has no server
+// lock; the file is read under the write lock only once the feeds are in.
+func updateFeeds(filename string, cfg Config) error {
+	fetched := fetchFeeds(context.Background(), cfg.Feeds)
+	return withWriteLock(filename, func() error {
+		items, trash, err := storage.Load(filename)
+		if err != nil {
+			return err
+		}
+		items, trash, changed := applyFeeds(items, trash, cfg.Feeds, fetched, time.Now())
+		if !changed {
+			return nil
+		}
+		return saveList(filename, items, cfg.capBin(trash), cfg.cloudSafe(filename))
+	})
+}
+
+// pollFeeds keeps the served file subscribed until ctx ends.
+func (s *apiServer) pollFeeds(ctx context.Context) {
+	for {
+		fetched := fetchFeeds(ctx, s.config.Feeds)
+		err := s.withFile(func(items, trash []model.Item) ([]model.Item, []model.Item, bool) {
+			return applyFeeds(items, trash, s.config.Feeds, fetched, time.Now())
+		})
+		if err != nil {
+			log.Printf("feed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.config.feedInterval()):
+		}
+	}
+}
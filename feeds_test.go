@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+const rssFeed = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Blog</title>
+<item><title>New post</title><link>https://blog.example/new</link><guid>post-2</guid><pubDate>Thu, 15 Oct 2026 08:00:00 +0000</pubDate></item>
+<item><title>Old post</title><link>https://blog.example/old</link><guid>post-1</guid><pubDate>Mon, 01 Jun 2026 08:00:00 +0000</pubDate></item>
+</channel></rss>`
+
+const atomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom"><title>Releases</title>
+<entry><id>tag:x,2026:v2</id><title>v2 &amp; more</title><updated>2026-10-14T10:00:00Z</updated>
+<link rel="replies" href="https://x.example/v2#comments"/><link href="https://x.example/v2"/></entry>
+</feed>`
+
+func TestParseFeed(t *testing.T) {
+	entries, err := parseFeed([]byte(rssFeed))
+	if err != nil || len(entries) != 2 || entries[0].id != "post-2" || entries[0].link != "https://blog.example/new" || entries[0].published.IsZero() {
+		t.Fatalf("rss = %+v, %v", entries, err)
+	}
+	entries, err = parseFeed([]byte(atomFeed))
+	if err != nil || len(entries) != 1 || entries[0].title != "v2 & more" || entries[0].link != "https://x.example/v2" {
+		t.Fatalf("atom = %+v, %v", entries, err)
+	}
+}
+
+func TestApplyFeed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(rssFeed))
+	}))
+	defer srv.Close()
+	feed := FeedConfig{URL: srv.URL, Section: "Blogs", Tag: "blog", RetainDays: 30}
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+
+	fetched := fetchFeeds(context.Background(), []FeedConfig{feed})
+	items, trash, changed := applyFeeds([]model.Item{{Title: "Work"}}, nil, []FeedConfig{feed}, fetched, now)
+	// the June post is past retain_days and is skipped
+	if !changed || len(items) != 3 || items[1].Title != "Blogs" || !strings.HasPrefix(items[2].Title, "New post https://blog.example/new #blog feed:") {
+		t.Fatalf("items = %+v", items)
+	}
+	if _, _, changed = applyFeeds(items, trash, []FeedConfig{feed}, fetched, now); changed {
+		t.Error("the same entry was added twice")
+	}
+
+	later := now.AddDate(0, 0, 40)
+	items, trash, _, removed := applyFeed(items, trash, feed, nil, later)
+	if removed != 1 || len(items) != 2 || len(trash) != 1 {
+		t.Errorf("expired: removed = %d, items = %+v", removed, items)
+	}
+	if _, _, added, _ := applyFeed(items, trash, feed, fetched[srv.URL], now); added != 0 {
+		t.Error("an entry in the bin came back")
+	}
+}
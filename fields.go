@@ -0,0 +1,328 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- CUSTOM FIELDS ---
+//
+// Fields are declared in config.json and stored as key:value tokens in the
+// title, like every other piece of metadata. Whitespace and "%" in a value
+// are stored percent-encoded ("in progress" -> "in%20progress"), whatever
+// the field's type, so every value reads back exactly as it was set.
+
+type FieldDef struct {
+	Name string `json:"name"`
+	// Type: "text" (default), "number", "date", "choice" or "bool"
+	Type   string   `json:"type,omitempty"`
+	Values []string `json:"values,omitempty"`
+}
+
+// visibleMetaKeys are the built-in tokens shown in titles; with
+// model.HiddenMetaKeys they make up reservedMetaKeys.
+var visibleMetaKeys = []string{"due", "blocked", "pri", "snooze", "recur", "est"}
+
+// reservedMetaKeys cannot be redefined as custom fields.
+var reservedMetaKeys = func() map[string]bool {
+	keys := maps.Clone(model.HiddenMetaKeys)
+	for _, k := range visibleMetaKeys {
+		keys[k] = true
+	}
+	return keys
+}()
+
+// customFields returns the usable field definitions, silently dropping
+// nameless, reserved or duplicate entries.
+func (c Config) customFields() []FieldDef {
+	var out []FieldDef
+	seen := make(map[string]bool)
+	for _, f := range c.Fields {
+		f.Name = strings.ToLower(strings.TrimSpace(f.Name))
+		if f.Name == "" || strings.ContainsAny(f.Name, ": #") || reservedMetaKeys[f.Name] || seen[f.Name] {
+			continue
+		}
+		if f.Type == "choice" && len(f.Values) == 0 {
+			continue
+		}
+		seen[f.Name] = true
+		out = append(out, f)
+	}
+	return out
+}
+
+// normalize validates a user supplied value and returns its stored form.
+func (f FieldDef) normalize(v string) (string, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return "", nil
+	}
+	switch f.Type {
+	case "number":
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return "", fmt.Errorf("%s: %q is not a number", f.Name, v)
+		}
+	case "date":
+		t, _, ok := model.ParseDate(v, time.Now())
+		if !ok {
+			return "", fmt.Errorf("%s: expected a date like 2006-01-02 or next friday", f.Name)
+		}
+		return t.Format(model.DateLayout), nil
+	case "choice":
+		for _, allowed := range f.Values {
+			if strings.EqualFold(allowed, v) {
+				return allowed, nil
+			}
+		}
+		return "", fmt.Errorf("%s: must be one of %s", f.Name, strings.Join(f.Values, ", "))
+	case "bool":
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return "", fmt.Errorf("%s: expected true/false", f.Name)
+		}
+		return strconv.FormatBool(b), nil
+	}
+	return strings.Join(strings.Fields(v), " "), nil
+}
+
+// escapeFieldValue turns a value into a single title token.
+func escapeFieldValue(v string) string {
+	var b strings.Builder
+	for _, r := range v {
+		if r != '%' && !unicode.IsSpace(r) {
+			b.WriteRune(r)
+			continue
+		}
+		// Tokeny rozdziela spacja, więc kodujemy ją (i sam %) bajt po bajcie
+		for _, c := range []byte(string(r)) {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// unescapeFieldValue undoes escapeFieldValue; a value typed into the title
+// by hand that isn't valid percent-encoding is shown as it is.
+func unescapeFieldValue(v string) string {
+	if u, err := url.PathUnescape(v); err == nil {
+		return u
+	}
+	return v
+}
+
+// fieldValue reads a custom field from the title in its display form.
+func fieldValue(title string, f FieldDef) string {
+	return unescapeFieldValue(model.MetaValue(title, f.Name))
+}
+
+// --- DETAIL VIEW ---
+
+func (m *app) openDetail() {
+	if len(m.visibleItems) == 0 {
+		return
+	}
+	m.detailIdx = m.visibleItems[m.cursorMain].Index
+	m.cursorDetail = 0
+	m.state = viewDetail
+}
+
+func (m *app) setField(f FieldDef, value string) {
+	v, err := f.normalize(value)
+	if err != nil {
+		m.status = err.Error()
+		return
+	}
+	m.items[m.detailIdx].Title = model.SetMeta(m.items[m.detailIdx].Title, f.Name, escapeFieldValue(v))
+	m.refreshItem(m.detailIdx)
+	m.save()
+}
+
+func (m app) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	fields := m.config.customFields()
+	// Pod polami kursor przechodzi na listę załączników
+	atts := attachments(m.items[m.detailIdx])
+	m.cursorDetail = min(m.cursorDetail, max(0, len(fields)+len(atts)-1))
+	onField := m.cursorDetail < len(fields)
+	var cur FieldDef
+	value := ""
+	if onField {
+		cur = fields[m.cursorDetail]
+		value = fieldValue(m.items[m.detailIdx].Title, cur)
+	}
+
+	switch msg.String() {
+	case "esc", "i":
+		m.state = viewMain
+	case "up", "k":
+		if m.cursorDetail > 0 {
+			m.cursorDetail--
+		}
+	case "down", "j":
+		if m.cursorDetail < len(fields)+len(atts)-1 {
+			m.cursorDetail++
+		}
+	case "enter", " ", "right", "l", "left", "h":
+		if !onField {
+			if k := msg.String(); (k == "enter" || k == " ") && len(atts) > 0 {
+				m.openAttachment(atts[m.cursorDetail-len(fields)])
+			}
+			break
+		}
+		switch cur.Type {
+		case "bool":
+			m.setField(cur, strconv.FormatBool(value != "true"))
+		case "choice":
+			// Cykliczne przełączanie, z pustą wartością na końcu listy
+			options := append(slices.Clone(cur.Values), "")
+			pos := slices.Index(options, value)
+			step := 1
+			if k := msg.String(); k == "left" || k == "h" {
+				step = len(options) - 1
+			}
+			m.setField(cur, options[(pos+step)%len(options)])
+		default:
+			if msg.String() == "enter" {
+				m.fieldEditing = true
+				m.fieldBuf = value
+			}
+		}
+	case "x":
+		if onField {
+			m.setField(cur, "")
+		} else if len(atts) > 0 {
+			detach(&m.items[m.detailIdx], m.cursorDetail-len(fields))
+			m.refreshItem(m.detailIdx)
+			m.save()
+		}
+	}
+	return m, nil
+}
+
+func (m *app) updateFieldEdit(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.fieldEditing = false
+		m.setField(m.config.customFields()[m.cursorDetail], m.fieldBuf)
+		m.fieldBuf = ""
+	case tea.KeyEsc:
+		m.fieldEditing = false
+		m.fieldBuf = ""
+	case tea.KeyBackspace, tea.KeyDelete:
+		if len(m.fieldBuf) > 0 {
+			runes := []rune(m.fieldBuf)
+			m.fieldBuf = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.fieldBuf += " "
+	case tea.KeyRunes:
+		m.fieldBuf += string(msg.Runes)
+	}
+}
+
+// detailInfo lists the facts about a task shown in the detail view and the
+// detail pane, as label/value pairs; empty values are skipped there.
+func (m app) detailInfo(it model.Item) [][2]string {
+	due := ""
+	if t, hasTime, ok := model.DueTime(it.Title); ok {
+		due = i18n.LongDate(t)
+		if hasTime {
+			due += t.Format(" 15:04 MST")
+		}
+	}
+	info := [][2]string{
+		{"Status", m.config.stateName(it)},
+		{"Due", due},
+		{"Created", model.MetaValue(it.Title, "created")},
+		{"Completed", model.MetaValue(it.Title, "completed")},
+		{"Added by", model.MetaValue(it.Title, "by")},
+		{"Tags", strings.Join(model.Tags(it.Title), ", ")},
+		{"Blocked", strings.Join(model.MetaValues(it.Title, "blocked"), ", ")},
+	}
+	if n := pomoCount(it); n > 0 {
+		info = append(info, [2]string{"Pomodoros", strconv.Itoa(n)})
+	}
+	if d := spentTime(it.Title); d > 0 {
+		info = append(info, [2]string{"Spent", model.FormatDuration(d)})
+	}
+	return append(info, [2]string{"ID", model.ID(it)})
+}
+
+func (m app) renderDetail(height int, t theme.Theme) string {
+	it := m.items[m.detailIdx]
+	label := lipgloss.NewStyle().Foreground(t.Comment).Width(10)
+	text := lipgloss.NewStyle().Foreground(t.Text)
+
+	var s strings.Builder
+	row := func(name, value string) {
+		if value == "" {
+			return
+		}
+		s.WriteString("  " + label.Render(i18n.T(name)) + text.Render(value) + "\n")
+	}
+
+	title := lipgloss.NewStyle().Foreground(t.Highlight).Bold(true).Width(m.width - 6).Render(model.DisplayTitle(it.Title))
+	s.WriteString("  " + title + "\n\n")
+	for _, r := range m.detailInfo(it) {
+		row(r[0], r[1])
+	}
+
+	fields := m.config.customFields()
+	if len(fields) > 0 {
+		s.WriteString("\n  " + lipgloss.NewStyle().Foreground(t.Accent).Render(i18n.T("Fields")) + "\n")
+	}
+	for i, f := range fields {
+		cursor := "  "
+		if i == m.cursorDetail {
+			cursor = " ➤"
+		}
+		value := fieldValue(it.Title, f)
+		valueStyle := text
+		if i == m.cursorDetail && m.fieldEditing {
+			value = m.fieldBuf + "█"
+			valueStyle = lipgloss.NewStyle().Foreground(t.Base).Background(t.Highlight)
+		} else if value == "" {
+			value = "—"
+			valueStyle = lipgloss.NewStyle().Foreground(t.Comment)
+		}
+		hint := ""
+		if f.Type == "choice" {
+			hint = "  (" + strings.Join(f.Values, " / ") + ")"
+		} else if f.Type != "" && f.Type != "text" {
+			hint = "  (" + f.Type + ")"
+		}
+		s.WriteString(lipgloss.NewStyle().Foreground(t.Highlight).Render(cursor) + " " +
+			label.Render(f.Name) + valueStyle.Render(value) +
+			lipgloss.NewStyle().Foreground(t.Comment).Render(hint) + "\n")
+	}
+
+	if atts := attachments(it); len(atts) > 0 {
+		s.WriteString("\n  " + lipgloss.NewStyle().Foreground(t.Accent).Render(i18n.T("Attachments")) + "\n")
+		for i, ref := range atts {
+			cursor, style := "  ", text
+			if len(fields)+i == m.cursorDetail {
+				cursor, style = " ➤", style.Foreground(t.Highlight).Bold(true)
+			}
+			missing := ""
+			if _, err := os.Stat(attachmentPath(m.filename, ref)); err != nil {
+				missing = lipgloss.NewStyle().Foreground(t.Error).Render("  (missing)")
+			}
+			s.WriteString(lipgloss.NewStyle().Foreground(t.Highlight).Render(cursor) + " 📎 " + style.Render(ref) + missing + "\n")
+		}
+	}
+
+	return m.frame(height, t.Highlight).
+		Render(s.String())
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestFieldValuesRoundTrip(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	fields := []FieldDef{
+		{Name: "note"},
+		{Name: "stage", Type: "choice", Values: []string{"in progress", "done"}},
+	}
+	m := app{items: []model.Item{{Title: "task #x"}}}
+	for _, c := range []struct {
+		f         FieldDef
+		set, want string
+	}{
+		{fields[0], "ask  snake_case 100%", "ask snake_case 100%"},
+		{fields[0], "a%20b", "a%20b"},
+		{fields[1], "In Progress", "in progress"},
+	} {
+		m.setField(c.f, c.set)
+		title := m.items[0].Title
+		if got := fieldValue(title, c.f); got != c.want {
+			t.Errorf("%s = %q, want %q (title %q)", c.f.Name, got, c.want, title)
+		}
+		if n := len(strings.Fields(title)); n != 3 {
+			t.Errorf("the value must stay one token: %q", title)
+		}
+		m.setField(c.f, "")
+	}
+}
+
+func TestReservedFieldNames(t *testing.T) {
+	cfg := Config{Fields: []FieldDef{{Name: "ws"}, {Name: "due"}, {Name: "id"}, {Name: "owner"}}}
+	if got := cfg.customFields(); len(got) != 1 || got[0].Name != "owner" {
+		t.Errorf("fields = %+v", got)
+	}
+}
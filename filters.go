@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- SAVED FILTERS ---
+//
+// ":filters save this week" stores the active :filter expression under a
+// name in "filters" of config.json; "F" (or ":filters") lists them and
+// Enter applies one, x deletes it. ":filter @this week" applies one by name.
+
+// setFilter applies a :filter expression, or clears the filter when it is
+// empty.
+func (m *app) setFilter(text string) {
+	if name, ok := strings.CutPrefix(text, "@"); ok {
+		saved, found := m.config.Filters[strings.TrimSpace(name)]
+		if !found {
+			m.status = "No saved filter " + name
+			return
+		}
+		text = saved
+	}
+	if text == "" {
+		m.filter, m.filterText, m.perspective = nil, "", ""
+		m.recalcVisible()
+		return
+	}
+	q, err := parseQuery(text)
+	if err != nil {
+		m.status = "Bad filter: " + err.Error()
+		return
+	}
+	m.filter, m.filterText, m.perspective = q, text, ""
+	m.cursorMain = 0
+	m.recalcVisible()
+	m.status = fmt.Sprintf("%d items shown", len(m.visibleItems))
+}
+
+func (m *app) filtersCommand(arg string) {
+	sub, name, _ := strings.Cut(arg, " ")
+	name = strings.TrimSpace(name)
+	switch {
+	case sub == "":
+		m.openFilters()
+	case sub == "save" && name != "":
+		if m.filter == nil {
+			m.status = "No filter to save — set one with :filter"
+			return
+		}
+		if m.config.Filters == nil {
+			m.config.Filters = make(map[string]string)
+		}
+		m.config.Filters[name] = m.filterText
+		m.status = "Saved filter “" + name + "”"
+		m.saveLayout()
+	default:
+		m.status = "Usage: :filters [save <name>]"
+	}
+}
+
+func (m app) filterNames() []string {
+	return slices.Sorted(maps.Keys(m.config.Filters))
+}
+
+func (m *app) openFilters() {
+	if len(m.config.Filters) == 0 {
+		m.status = "No saved filters yet — :filters save <name> keeps the active one"
+		return
+	}
+	m.cursorFilter = 0
+	if i := slices.IndexFunc(m.filterNames(), func(n string) bool { return m.config.Filters[n] == m.filterText }); i != -1 {
+		m.cursorFilter = i
+	}
+	m.state = viewFilters
+}
+
+func (m app) updateFilters(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	names := m.filterNames()
+	switch msg.String() {
+	case "esc":
+		m.state = viewMain
+	case "up", "k":
+		m.cursorFilter = max(0, m.cursorFilter-1)
+	case "down", "j":
+		m.cursorFilter = min(len(names)-1, m.cursorFilter+1)
+	case "enter":
+		if len(names) == 0 {
+			break
+		}
+		m.state = viewMain
+		m.setFilter(m.config.Filters[names[m.cursorFilter]])
+	case "x":
+		if len(names) == 0 {
+			break
+		}
+		name := names[m.cursorFilter]
+		delete(m.config.Filters, name)
+		m.cursorFilter = max(0, min(m.cursorFilter, len(names)-2))
+		m.status = "Deleted filter “" + name + "”"
+		m.saveLayout()
+		if len(m.config.Filters) == 0 {
+			m.state = viewMain
+		}
+	}
+	return m, nil
+}
+
+func (m app) renderFilters(height int, t theme.Theme) string {
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+	names := m.filterNames()
+	nameW := 0
+	for _, n := range names {
+		nameW = max(nameW, lipgloss.Width(n))
+	}
+	var lines []string
+	for i, name := range names {
+		marker := "  "
+		nameStyle := lipgloss.NewStyle().Foreground(t.Text)
+		if i == m.cursorFilter {
+			marker = " ➤"
+			nameStyle = nameStyle.Foreground(t.Highlight).Bold(true)
+		}
+		expr := m.config.Filters[name]
+		if expr == m.filterText {
+			expr += "  ✔"
+		}
+		lines = append(lines, ansi.Truncate(lipgloss.NewStyle().Foreground(t.Highlight).Render(marker)+" "+
+			nameStyle.Render(name+strings.Repeat(" ", nameW-lipgloss.Width(name)))+"  "+dim.Render(expr), m.width-4, "…"))
+	}
+	start, end := ui.Paginator(m.cursorFilter, height, len(lines))
+	return m.frame(height, t.Highlight).
+		Render(strings.Join(lines[start:end], "\n"))
+}
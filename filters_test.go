@@ -0,0 +1,114 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestQueryComparisons(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	items := []model.Item{
+		{Title: "soon due:2026-03-12 pri:B est:2h #work"},
+		{Title: "later due:2026-04-01 pri:A est:30m"},
+		{Title: "no dates star:1 size:12"},
+		{Title: "done one due:2026-03-11 #work size:4", Done: true},
+	}
+	match := func(expr string) []string {
+		t.Helper()
+		q, err := parseQuery(expr)
+		if err != nil {
+			t.Fatalf("%q: %v", expr, err)
+		}
+		var got []string
+		for _, it := range items {
+			if q(it, now) {
+				got = append(got, strings.Fields(it.Title)[0])
+			}
+		}
+		return got
+	}
+	for expr, want := range map[string][]string{
+		"due<7d":                          {"soon", "done"},
+		"due < 7d and #work and not done": {"soon"},
+		"due>=2026-04-01":                 {"later"},
+		"due!=1w":                         {"soon", "later", "no", "done"},
+		"pri>=B":                          {"soon", "later"},
+		"priority<A":                      {"soon", "no", "done"},
+		"pri>=B or starred":               {"soon", "later", "no"},
+		"est>1h":                          {"soon"},
+		"estimate<=30m":                   {"later"},
+		"size>5":                          {"no"},
+		"(size=4) OR pri=a":               {"later", "done"},
+	} {
+		if got := match(expr); !slices.Equal(got, want) {
+			t.Errorf("%q = %q, want %q", expr, got, want)
+		}
+	}
+	for _, bad := range []string{"due<xyz", "est>abc", "due<"} {
+		if _, err := parseQuery(bad); err == nil {
+			t.Errorf("%q must be refused", bad)
+		}
+	}
+}
+
+func TestSavedFilters(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{width: 80, height: 20, items: []model.Item{
+		{Title: "work #work"},
+		{Title: "home #home"},
+	}}
+	m.recalcVisible()
+
+	m.runCommand("filters save nothing")
+	if len(m.config.Filters) != 0 {
+		t.Fatal("saved a filter without one set")
+	}
+	m.runCommand("filter #work")
+	m.runCommand("filters save job")
+	m.runCommand("filter #home")
+	m.runCommand("filters save house")
+	if len(m.visibleItems) != 1 || m.visibleItems[0].Index != 1 {
+		t.Fatalf("visible = %+v", m.visibleItems)
+	}
+	if cfg, _ := loadConfig(); cfg.Filters["job"] != "#work" || cfg.Filters["house"] != "#home" {
+		t.Errorf("config.json filters = %v", cfg.Filters)
+	}
+
+	m.runCommand("filter @job")
+	if m.filterText != "#work" || len(m.visibleItems) != 1 || m.visibleItems[0].Index != 0 {
+		t.Fatalf("@job gave %q, %+v", m.filterText, m.visibleItems)
+	}
+	m.runCommand("filter @nope")
+	if m.filterText != "#work" {
+		t.Error("an unknown name must keep the filter")
+	}
+
+	next, _ := m.Update(keyMsg("F"))
+	m = next.(app)
+	if m.state != viewFilters || m.filterNames()[m.cursorFilter] != "job" {
+		t.Fatalf("picker at %d in state %v", m.cursorFilter, m.state)
+	}
+	next, _ = m.Update(keyMsg("k"))
+	m = next.(app)
+	next, _ = m.Update(keyMsg("enter"))
+	m = next.(app)
+	if m.state != viewMain || m.filterText != "#home" {
+		t.Fatalf("Enter applied %q", m.filterText)
+	}
+
+	next, _ = m.Update(keyMsg("F"))
+	m = next.(app)
+	next, _ = m.Update(keyMsg("x"))
+	m = next.(app)
+	if _, ok := m.config.Filters["house"]; ok || len(m.config.Filters) != 1 {
+		t.Errorf("x left %v", m.config.Filters)
+	}
+	m.runCommand("filter")
+	if m.filter != nil || len(m.visibleItems) != 2 {
+		t.Error(":filter without an expression must clear it")
+	}
+}
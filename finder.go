@@ -0,0 +1,303 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- FUZZY FINDER ---
+//
+// ctrl+p opens a search over every task of the list, folded, finished or
+// outside the zoom alike. Typed letters must appear in the title in order
+// ("wrep" finds "Write the report"); runs of letters and word starts rank
+// higher. Enter jumps to the task, unfolding its parents and leaving a zoom
+// or filter that hides it.
+//
+// alt+c makes the search case-sensitive and alt+r takes the query as a
+// regular expression (Go syntax, matched anywhere in the title, earlier
+// matches first); both stay on until toggled off again. While a regex
+// doesn't compile, the error shows in the footer and the last good results
+// stay listed.
+
+const finderResults = 10
+
+type finder struct {
+	query     string
+	cursor    int
+	matchCase bool
+	regex     bool
+	last      []finderMatch // the matches of the last valid regex
+}
+
+type finderMatch struct {
+	idx   int
+	score int
+	pos   []int // byte offsets of the matched runes in the title
+}
+
+// fuzzyMatch scores query as a subsequence of s, case aside unless
+// matchCase; ok is false when it isn't one. Each matched letter counts, more
+// at the start of a word or right after the previous one; the best
+// placement wins.
+func fuzzyMatch(query, s string, matchCase bool) (score int, pos []int, ok bool) {
+	if query == "" {
+		return 0, nil, true
+	}
+	fold := unicode.ToLower
+	if matchCase {
+		fold = func(r rune) rune { return r }
+	}
+	var q []rune
+	for _, r := range query {
+		q = append(q, fold(r))
+	}
+	var runes []rune
+	var offsets []int
+	for i, r := range s {
+		runes = append(runes, fold(r))
+		offsets = append(offsets, i)
+	}
+	// best[j][i]: najlepszy wynik dla q[:j+1] z q[j] na pozycji i (-1: brak)
+	best := make([][]int, len(q))
+	from := make([][]int, len(q))
+	for j := range q {
+		best[j], from[j] = make([]int, len(runes)), make([]int, len(runes))
+		for i, r := range runes {
+			best[j][i] = -1
+			if r != q[j] {
+				continue
+			}
+			gain := 1
+			if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+				gain += 2
+			}
+			if j == 0 {
+				best[j][i] = gain
+				continue
+			}
+			for k := j - 1; k < i; k++ {
+				if best[j-1][k] < 0 {
+					continue
+				}
+				v := best[j-1][k] + gain
+				if k == i-1 {
+					v += 3
+				}
+				if v > best[j][i] {
+					best[j][i], from[j][i] = v, k
+				}
+			}
+		}
+	}
+	last := len(q) - 1
+	end := -1
+	for i, v := range best[last] {
+		if v > score {
+			score, end = v, i
+		}
+	}
+	if end == -1 {
+		return 0, nil, false
+	}
+	pos = make([]int, len(q))
+	for j := last; j >= 0; j-- {
+		pos[j] = offsets[end]
+		end = from[j][end]
+	}
+	return score, pos, true
+}
+
+// regexMatch scores a regex match by how early it starts.
+func regexMatch(re *regexp.Regexp, s string) (score int, pos []int, ok bool) {
+	loc := re.FindStringIndex(s)
+	if loc == nil {
+		return 0, nil, false
+	}
+	for i := range s[loc[0]:loc[1]] {
+		pos = append(pos, loc[0]+i)
+	}
+	return -loc[0], pos, true
+}
+
+// finderMatches returns the best matches of the finder's query among items,
+// best first; ties go to open tasks, then to shorter titles, then file
+// order. The error is that of a regex that doesn't compile.
+func finderMatches(items []model.Item, f finder) ([]finderMatch, error) {
+	match := func(s string) (int, []int, bool) { return fuzzyMatch(f.query, s, f.matchCase) }
+	if f.regex {
+		pattern := f.query
+		if !f.matchCase {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		match = func(s string) (int, []int, bool) { return regexMatch(re, s) }
+	}
+	var out []finderMatch
+	for i, it := range items {
+		if score, pos, ok := match(model.DisplayTitle(it.Title)); ok {
+			out = append(out, finderMatch{i, score, pos})
+		}
+	}
+	slices.SortStableFunc(out, func(a, b finderMatch) int {
+		if a.score != b.score {
+			return b.score - a.score
+		}
+		if items[a.idx].Done != items[b.idx].Done {
+			if items[a.idx].Done {
+				return 1
+			}
+			return -1
+		}
+		return len(items[a.idx].Title) - len(items[b.idx].Title)
+	})
+	return out, nil
+}
+
+// shownMatches are the matches listed: while the regex is broken, the last
+// ones it found.
+func (m app) shownMatches() ([]finderMatch, error) {
+	matches, err := finderMatches(m.items, m.finder)
+	if err != nil {
+		return m.finder.last, err
+	}
+	return matches, nil
+}
+
+func (m *app) openFinder() {
+	m.finder = finder{matchCase: m.finder.matchCase, regex: m.finder.regex}
+	m.finderOpen = true
+}
+
+func (m *app) updateFinder(msg tea.KeyMsg) {
+	f := &m.finder
+	matches, _ := m.shownMatches()
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.finderOpen = false
+	case "up", "ctrl+p", "ctrl+k":
+		f.cursor = max(0, f.cursor-1)
+	case "down", "ctrl+n", "ctrl+j":
+		f.cursor = min(min(len(matches), finderResults)-1, f.cursor+1)
+	case "enter":
+		m.finderOpen = false
+		if f.cursor < len(matches) {
+			m.revealTask(matches[f.cursor].idx)
+		}
+	case "alt+c":
+		f.matchCase = !f.matchCase
+		f.cursor = 0
+	case "alt+r":
+		f.regex = !f.regex
+		f.cursor = 0
+	case "backspace":
+		if r := []rune(f.query); len(r) > 0 {
+			f.query = string(r[:len(r)-1])
+			f.cursor = 0
+		}
+	default:
+		if (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) && !msg.Alt {
+			f.query += string(msg.Runes)
+			f.cursor = 0
+		}
+	}
+	if !m.finderOpen {
+		return
+	}
+	if matches, err := m.shownMatches(); err != nil {
+		m.status = "Bad regex: " + err.Error()
+	} else {
+		f.last = matches
+	}
+}
+
+// revealTask puts the cursor on items[idx] in the list, clearing the filter
+// when it hides the task.
+func (m *app) revealTask(idx int) {
+	m.state = viewMain
+	m.jumpTo(idx)
+	if !m.shownInList(idx) && m.filter != nil {
+		m.filter, m.filterText = nil, ""
+		m.jumpTo(idx)
+	}
+	if !m.shownInList(idx) {
+		m.status = "The task is hidden (snoozed or finished)"
+	}
+}
+
+func (m app) shownInList(idx int) bool {
+	return len(m.visibleItems) > 0 && m.visibleItems[m.cursorMain].Index == idx
+}
+
+func (m app) renderFinder(t theme.Theme) string {
+	width := max(20, min(70, m.width-8))
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+	text := lipgloss.NewStyle().Foreground(t.Text)
+	hit := lipgloss.NewStyle().Foreground(t.Highlight).Bold(true)
+
+	prompt := "› "
+	if m.finder.regex {
+		prompt = "/ "
+	}
+	line := lipgloss.NewStyle().Foreground(t.Accent).Render(prompt) + text.Render(m.finder.query+"█")
+	var modes []string
+	if m.finder.matchCase {
+		modes = append(modes, "Aa")
+	}
+	if m.finder.regex {
+		modes = append(modes, ".*")
+	}
+	if len(modes) > 0 {
+		modes := hit.Render(strings.Join(modes, " "))
+		line += strings.Repeat(" ", max(1, width-lipgloss.Width(line)-lipgloss.Width(modes))) + modes
+	}
+	lines := []string{line, ""}
+	matches, err := m.shownMatches()
+	for n, match := range matches[:min(len(matches), finderResults)] {
+		title := model.DisplayTitle(m.items[match.idx].Title)
+		style := text
+		if m.items[match.idx].Done {
+			style = dim.Strikethrough(true)
+		}
+		marker := "  "
+		if n == m.finder.cursor {
+			marker = hit.Render("➤ ")
+			style = style.Bold(true)
+		}
+		var b strings.Builder
+		for i, r := range title {
+			if slices.Contains(match.pos, i) {
+				b.WriteString(hit.Render(string(r)))
+			} else {
+				b.WriteString(style.Render(string(r)))
+			}
+		}
+		line := marker + b.String()
+		if path := ancestorTitles(m.items, match.idx); len(path) > 1 {
+			line += dim.Render("  ‹ " + strings.Join(path[:len(path)-1], " > "))
+		}
+		lines = append(lines, ansi.Truncate(line, width, "…"))
+	}
+	if err != nil {
+		lines = append(lines, lipgloss.NewStyle().Foreground(t.Error).Render(ansi.Truncate(i18n.T("Bad regex")+": "+err.Error(), width, "…")))
+	} else if len(matches) == 0 {
+		lines = append(lines, dim.Render(i18n.T("No matching tasks")))
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Accent).
+		Padding(0, 1).
+		Width(width + 2).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	if _, _, ok := fuzzyMatch("wrep", "Write the report", false); !ok {
+		t.Error("subsequence not matched")
+	}
+	if _, _, ok := fuzzyMatch("repw", "Write the report", false); ok {
+		t.Error("letters out of order matched")
+	}
+	word, _, _ := fuzzyMatch("rep", "Write the report", false)
+	mid, _, _ := fuzzyMatch("rep", "Prepare slides", false)
+	if word <= mid {
+		t.Errorf("word start %d must beat a match inside a word %d", word, mid)
+	}
+	if _, pos, _ := fuzzyMatch("ŻÓ", "x żółw", false); len(pos) != 2 || pos[0] != 2 || pos[1] != 4 {
+		t.Errorf("positions = %v", pos)
+	}
+}
+
+func TestFinder(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{width: 100, height: 20, items: []model.Item{
+		{Title: "Home", Collapsed: true},
+		{Title: "Garden", Level: 1, Collapsed: true},
+		{Title: "Water the plants", Level: 2},
+		{Title: "Work"},
+		{Title: "Write the report"},
+	}}
+	m.recalcVisible()
+
+	next, _ := m.Update(keyMsg("ctrl+p"))
+	m = next.(app)
+	if !m.finderOpen {
+		t.Fatal("ctrl+p must open the finder")
+	}
+	for _, k := range []string{"w", "t", "p", "l"} {
+		next, _ = m.Update(keyMsg(k))
+		m = next.(app)
+	}
+	if m.finder.query != "wtpl" {
+		t.Errorf("query = %q", m.finder.query)
+	}
+	matches, _ := finderMatches(m.items, m.finder)
+	if len(matches) != 1 || matches[0].idx != 2 {
+		t.Errorf("matches = %+v", matches)
+	}
+	if view := m.View(); !strings.Contains(view, "‹ Home > Garden") {
+		t.Error("finder lacks the breadcrumbs")
+	}
+
+	next, _ = m.Update(keyMsg("enter"))
+	m = next.(app)
+	if m.finderOpen || m.visibleItems[m.cursorMain].Index != 2 {
+		t.Errorf("enter must reveal the task, cursor on %+v", m.visibleItems[m.cursorMain])
+	}
+	if m.items[0].Collapsed || m.items[1].Collapsed {
+		t.Error("ancestors stay folded")
+	}
+
+	next, _ = m.Update(keyMsg("ctrl+p"))
+	next, _ = next.(app).Update(keyMsg("esc"))
+	if m = next.(app); m.finderOpen || m.visibleItems[m.cursorMain].Index != 2 {
+		t.Error("esc must close the finder and stay put")
+	}
+}
+
+func TestFinderModes(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{width: 100, height: 20, items: []model.Item{
+		{Title: "Call Bob at 10"},
+		{Title: "call the bank"},
+		{Title: "Order 42 boxes"},
+	}}
+	m.recalcVisible()
+	alt := func(r rune) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}, Alt: true} }
+	titles := func() string {
+		matches, _ := m.shownMatches()
+		var out []string
+		for _, match := range matches {
+			out = append(out, m.items[match.idx].Title)
+		}
+		return strings.Join(out, "|")
+	}
+	typed := func(s string) {
+		for _, r := range s {
+			next, _ := m.Update(keyMsg(string(r)))
+			m = next.(app)
+		}
+	}
+
+	next, _ := m.Update(keyMsg("ctrl+p"))
+	m = next.(app)
+	typed("Ca")
+	if got := titles(); got != "call the bank|Call Bob at 10" {
+		t.Errorf("case-insensitive = %q", got)
+	}
+	next, _ = m.Update(alt('c'))
+	if m = next.(app); !m.finder.matchCase || m.finder.query != "Ca" || titles() != "Call Bob at 10" {
+		t.Errorf("alt+c: %+v, %q", m.finder, titles())
+	}
+
+	next, _ = m.Update(keyMsg("esc"))
+	next, _ = next.(app).Update(keyMsg("ctrl+p"))
+	next, _ = next.(app).Update(alt('r'))
+	m = next.(app)
+	if !m.finder.matchCase || !m.finder.regex {
+		t.Fatalf("modes must survive reopening: %+v", m.finder)
+	}
+	typed(`\d+ b`)
+	if got := titles(); got != "Order 42 boxes" {
+		t.Errorf(`regex \d+ b = %q`, got)
+	}
+	matches, _ := m.shownMatches()
+	if want := []int{6, 7, 8, 9}; !slices.Equal(matches[0].pos, want) {
+		t.Errorf("highlight = %v, want %v", matches[0].pos, want)
+	}
+
+	// Błędny wzorzec: komunikat w stopce, ostatnie wyniki zostają
+	typed("(")
+	if m.status == "" || !strings.HasPrefix(m.status, "Bad regex: ") || titles() != "Order 42 boxes" {
+		t.Errorf("status %q, shown %q", m.status, titles())
+	}
+	if view := m.View(); !strings.Contains(view, "Bad regex") || !strings.Contains(view, "Aa .*") {
+		t.Errorf("finder view:\n%s", view)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+)
+
+// --- GLOBAL SEARCH (todo grep) ---
+//
+// `todo grep PATTERN` searches every list registered under "workspaces" in
+// config.json (paths or globs, ~ allowed), or, without that setting, every
+// list the TUI has opened before. Each hit prints its file, status and
+// section path; --open asks which hit to open and starts the TUI there.
+
+type grepHit struct {
+	file string
+	idx  int
+	done bool
+	path []string // ancestors and the task itself
+}
+
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~"+string(filepath.Separator))
+	if !ok && path != "~" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	if !ok {
+		return home
+	}
+	return filepath.Join(home, rest)
+}
+
+// workspaceFiles lists the files to search, each once. Patterns matching
+// nothing are reported to warn when it isn't nil.
+func workspaceFiles(cfg Config, warn func(pattern string)) []string {
+	var files []string
+	if len(cfg.Workspaces) == 0 {
+		for file := range loadSessions() {
+			files = append(files, file)
+		}
+		slices.Sort(files)
+	}
+	for _, pattern := range cfg.Workspaces {
+		matches, err := filepath.Glob(expandHome(pattern))
+		if (err != nil || len(matches) == 0) && warn != nil {
+			warn(pattern)
+		}
+		files = append(files, matches...)
+	}
+
+	seen := map[string]bool{}
+	var out []string
+	for _, f := range files {
+		if key := stateKey(f); !seen[key] {
+			seen[key] = true
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// grepItems returns the items whose line matches re.
+func grepItems(file string, items []model.Item, re *regexp.Regexp) []grepHit {
+	var hits []grepHit
+	for i, it := range items {
+		if it.Heading == 0 && re.MatchString(it.Title) {
+			hits = append(hits, grepHit{file: file, idx: i, done: it.Done, path: ancestorTitles(items, i)})
+		}
+	}
+	return hits
+}
+
+func (h grepHit) String() string {
+	status := "[ ]"
+	if h.done {
+		status = "[x]"
+	}
+	return abbreviateHome(h.file) + "  " + status + " " + strings.Join(h.path, " > ")
+}
+
+func runGrep(args []string) {
+	fs := flag.NewFlagSet("grep", flag.ExitOnError)
+	ignoreCase := fs.Bool("i", false, "ignore case")
+	open := fs.Bool("open", false, "open the TUI at a chosen hit")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: todo grep [-i] [--open] PATTERN [file.md ...]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	pattern := fs.Arg(0)
+	if *ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	files := fs.Args()[1:]
+	if len(files) == 0 {
+		cfg, err := loadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		files = workspaceFiles(cfg, func(pattern string) {
+			fmt.Fprintf(os.Stderr, "todo grep: no file matches %s\n", pattern)
+		})
+	}
+
+	var hits []grepHit
+	for _, file := range files {
+		items, _, err := storage.Load(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "todo grep: %v\n", err)
+			continue
+		}
+		hits = append(hits, grepItems(file, items, re)...)
+	}
+	if len(hits) == 0 {
+		os.Exit(1)
+	}
+
+	if !*open {
+		for _, h := range hits {
+			fmt.Println(h)
+		}
+		return
+	}
+	choice := 0
+	if len(hits) > 1 {
+		for i, h := range hits {
+			fmt.Printf("%3d  %s\n", i+1, h)
+		}
+		fmt.Printf("Open which? [1-%d]: ", len(hits))
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		n, err := strconv.Atoi(strings.TrimSpace(line))
+		if err != nil || n < 1 || n > len(hits) {
+			os.Exit(1)
+		}
+		choice = n - 1
+	}
+
+	h := hits[choice]
+	m := initialModel(h.file)
+	m.state = viewMain
+	if h.idx < len(m.items) {
+		m.jumpTo(h.idx)
+	}
+	runTUI(h.file, m)
+}
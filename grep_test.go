@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestGrepItems(t *testing.T) {
+	items := []model.Item{
+		{Title: "Project"}, {Title: "Backend", Level: 1}, {Title: "fix token refresh #auth", Level: 2, Done: true}, {Title: "Token docs"},
+	}
+	hits := grepItems("todo.md", items, regexp.MustCompile("(?i)token"))
+	if len(hits) != 2 {
+		t.Fatalf("hits = %v", hits)
+	}
+	if got, want := hits[0].String(), "todo.md  [x] Project > Backend > fix token refresh #auth"; got != want {
+		t.Errorf("hit = %q, want %q", got, want)
+	}
+	if hits[1].idx != 3 || hits[1].done {
+		t.Errorf("second hit = %+v", hits[1])
+	}
+}
+
+func TestWorkspaceFiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	for _, name := range []string{"a.md", "b.md", "notes.txt"} {
+		os.WriteFile(filepath.Join(dir, name), nil, 0644)
+	}
+	files := workspaceFiles(Config{Workspaces: []string{"~/*.md", filepath.Join(dir, "a.md")}}, nil)
+	want := []string{filepath.Join(dir, "a.md"), filepath.Join(dir, "b.md")}
+	if !slices.Equal(files, want) {
+		t.Errorf("files = %v, want %v", files, want)
+	}
+}
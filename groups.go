@@ -0,0 +1,293 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- GROUPED VIEW ---
+//
+// ":group tag|assignee|due" shows the list regrouped by #tag, by @assignee
+// or by due bucket (overdue, today, this week, later). Tasks keep their file
+// order inside a group and appear in every group they belong to; the file
+// itself is never reordered. Group headers fold with Enter/Space/v, Tab
+// switches the grouping.
+
+var groupModes = []string{"tag", "assignee", "due"}
+
+const (
+	noTagGroup      = "(untagged)"
+	noAssigneeGroup = "(unassigned)"
+	noDueGroup      = "No date"
+)
+
+var dueBuckets = []string{"Overdue", "Today", "This week", "Later", noDueGroup}
+
+var assigneeToken = regexp.MustCompile(`(?:^|\s)@([\p{L}\p{N}_.-]+)`)
+
+// assignees returns the @name mentions of a title.
+func assignees(title string) []string {
+	var out []string
+	for _, m := range assigneeToken.FindAllStringSubmatch(title, -1) {
+		out = append(out, m[1])
+	}
+	return out
+}
+
+func dueBucket(title string, now time.Time) string {
+	due, _, ok := model.DueTime(title)
+	if !ok {
+		return noDueGroup
+	}
+	today := model.StartOfDay(now)
+	switch day := model.StartOfDay(due); {
+	case day.Before(today):
+		return "Overdue"
+	case day.Equal(today):
+		return "Today"
+	case day.Before(today.AddDate(0, 0, 7)):
+		return "This week"
+	}
+	return "Later"
+}
+
+// groupKeys lists the groups an item belongs to.
+func groupKeys(it model.Item, by string, now time.Time) []string {
+	switch by {
+	case "assignee":
+		if names := assignees(it.Title); len(names) > 0 {
+			for i, n := range names {
+				names[i] = "@" + n
+			}
+			return names
+		}
+		return []string{noAssigneeGroup}
+	case "due":
+		return []string{dueBucket(it.Title, now)}
+	}
+	if tags := model.Tags(it.Title); len(tags) > 0 {
+		for i, t := range tags {
+			tags[i] = "#" + t
+		}
+		return tags
+	}
+	return []string{noTagGroup}
+}
+
+type taskGroup struct {
+	name  string
+	items []int
+}
+
+// groupItems buckets the picked items. Named groups come in alphabetical
+// order (due buckets in time order) with the catch-all group last.
+func groupItems(items []model.Item, pick []bool, by string, now time.Time) []taskGroup {
+	members := map[string][]int{}
+	for i, it := range items {
+		if !pick[i] {
+			continue
+		}
+		for _, k := range groupKeys(it, by, now) {
+			if l := members[k]; len(l) == 0 || l[len(l)-1] != i {
+				members[k] = append(l, i)
+			}
+		}
+	}
+
+	var names []string
+	if by == "due" {
+		names = dueBuckets
+	} else {
+		for k := range members {
+			if k != noTagGroup && k != noAssigneeGroup {
+				names = append(names, k)
+			}
+		}
+		slices.SortFunc(names, func(a, b string) int { return strings.Compare(strings.ToLower(a), strings.ToLower(b)) })
+		// Grupa „bez tagu/osoby” na końcu
+		names = append(names, groupKeys(model.Item{}, by, now)...)
+	}
+
+	var out []taskGroup
+	for _, k := range names {
+		if len(members[k]) > 0 {
+			out = append(out, taskGroup{name: k, items: members[k]})
+		}
+	}
+	return out
+}
+
+// groupRow is one line of the grouped view; idx is -1 for a group header.
+type groupRow struct {
+	group string
+	idx   int
+}
+
+// groupPick selects the items the main list would show: the filter matches
+// when a filter is on, everything but snoozed subtrees otherwise.
+func (m *app) groupPick(now time.Time) []bool {
+	if m.filter != nil {
+		match, _ := queryMatches(m.items, m.filter, now)
+		return match
+	}
+	pick := make([]bool, len(m.items))
+	hide, skipLevel := m.hidden(now), -1
+	for i, it := range m.items {
+		if skipLevel != -1 && it.Level > skipLevel {
+			continue
+		}
+		skipLevel = -1
+		if hide(it) {
+			skipLevel = it.Level
+			continue
+		}
+		pick[i] = true
+	}
+	return pick
+}
+
+func (m *app) groupRows(now time.Time) ([]groupRow, []taskGroup) {
+	groups := groupItems(m.items, m.groupPick(now), m.groupBy, now)
+	var rows []groupRow
+	for _, g := range groups {
+		rows = append(rows, groupRow{group: g.name, idx: -1})
+		if m.groupFolded[m.groupBy+"\x00"+g.name] {
+			continue
+		}
+		for _, idx := range g.items {
+			rows = append(rows, groupRow{group: g.name, idx: idx})
+		}
+	}
+	return rows, groups
+}
+
+func (m *app) openGroups(by string) {
+	if by == "" {
+		by = m.groupBy
+	}
+	if by == "" {
+		by = groupModes[0]
+	}
+	if !slices.Contains(groupModes, by) {
+		m.status = "Usage: :group [" + strings.Join(groupModes, "|") + "]"
+		return
+	}
+	if m.groupFolded == nil {
+		m.groupFolded = map[string]bool{}
+	}
+	m.groupBy = by
+	m.cursorGroup = 0
+	m.state = viewGroups
+}
+
+func (m app) updateGroups(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows, _ := m.groupRows(time.Now())
+	m.cursorGroup = min(m.cursorGroup, max(0, len(rows)-1))
+	foldGroup := func(name string) {
+		key := m.groupBy + "\x00" + name
+		m.groupFolded[key] = !m.groupFolded[key]
+		// Kursor zostaje na nagłówku zwijanej grupy
+		newRows, _ := m.groupRows(time.Now())
+		m.cursorGroup = slices.Index(newRows, groupRow{group: name, idx: -1})
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.state = viewMain
+	case "up", "k":
+		if m.cursorGroup > 0 {
+			m.cursorGroup--
+		}
+	case "down", "j":
+		if m.cursorGroup < len(rows)-1 {
+			m.cursorGroup++
+		}
+	case "tab":
+		i := slices.Index(groupModes, m.groupBy)
+		m.groupBy = groupModes[(i+1)%len(groupModes)]
+		m.cursorGroup = 0
+	case "v":
+		if len(rows) > 0 {
+			foldGroup(rows[m.cursorGroup].group)
+		}
+	case "enter", " ":
+		if len(rows) == 0 {
+			break
+		}
+		r := rows[m.cursorGroup]
+		switch {
+		case r.idx == -1:
+			foldGroup(r.group)
+		case msg.String() == "enter":
+			m.state = viewMain
+			m.jumpTo(r.idx)
+		default:
+			m.toggleDone(r.idx)
+		}
+	}
+	return m, nil
+}
+
+func (m app) renderGroups(height int, t theme.Theme) string {
+	rows, groups := m.groupRows(time.Now())
+	cursor := min(m.cursorGroup, max(0, len(rows)-1))
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+	sizes := map[string]int{}
+	for _, g := range groups {
+		sizes[g.name] = len(g.items)
+	}
+
+	var lines []string
+	for i, r := range rows {
+		marker := "  "
+		if i == cursor {
+			marker = " ➤"
+		}
+		markerStr := lipgloss.NewStyle().Foreground(t.Highlight).Render(marker)
+		if r.idx == -1 {
+			fold := "▾"
+			if m.groupFolded[m.groupBy+"\x00"+r.group] {
+				fold = "▸"
+			}
+			style := lipgloss.NewStyle().Foreground(t.Accent).Bold(true)
+			if r.group == "Overdue" {
+				style = style.Foreground(t.Error)
+			}
+			if i == cursor {
+				style = style.Foreground(t.Highlight)
+			}
+			lines = append(lines, markerStr+" "+style.Render(fold+" "+r.group)+dim.Render(fmt.Sprintf("  (%d)", sizes[r.group])))
+			continue
+		}
+
+		it := m.items[r.idx]
+		check, titleStyle := "[ ]", lipgloss.NewStyle().Foreground(t.Text)
+		if it.Done {
+			check, titleStyle = "[✔]", dim.Strikethrough(true)
+		}
+		if i == cursor {
+			titleStyle = titleStyle.Foreground(t.Highlight).Bold(true)
+		}
+		line := markerStr + "   " + dim.Render(check) + " " + titleStyle.Render(model.DisplayTitle(it.Title))
+		if p := model.ParentIndex(m.items, r.idx); p != -1 {
+			line += dim.Render("  ‹ " + model.DisplayTitle(m.items[p].Title))
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, dim.Render("  (No tasks)"))
+	}
+
+	start, end := ui.Paginator(cursor, height, len(lines))
+	return m.frame(height, t.Highlight).
+		Render(strings.Join(lines[start:end], "\n"))
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+func TestGroupItems(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.Local) // Wednesday
+	items := []model.Item{
+		{Title: "a #work @ann due:2026-10-13"},
+		{Title: "b #home due:2026-10-14"},
+		{Title: "c #work #home @bob due:2026-10-18"},
+		{Title: "d due:2026-11-02"},
+		{Title: "e mail@example.com"},
+	}
+	pick := []bool{true, true, true, true, true}
+	names := func(by string) map[string][]int {
+		out := map[string][]int{}
+		for _, g := range groupItems(items, pick, by, now) {
+			out[g.name] = g.items
+		}
+		return out
+	}
+
+	if got, want := names("tag"), map[string][]int{"#home": {1, 2}, "#work": {0, 2}, noTagGroup: {3, 4}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("by tag = %v", got)
+	}
+	if got, want := names("assignee"), map[string][]int{"@ann": {0}, "@bob": {2}, noAssigneeGroup: {1, 3, 4}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("by assignee = %v", got)
+	}
+	var order []string
+	for _, g := range groupItems(items, pick, "due", now) {
+		order = append(order, g.name)
+	}
+	if want := []string{"Overdue", "Today", "This week", "Later", noDueGroup}; !reflect.DeepEqual(order, want) {
+		t.Errorf("due buckets = %v", order)
+	}
+	if g := groupItems(items, pick, "tag", now); g[len(g)-1].name != noTagGroup {
+		t.Error("untagged group must come last")
+	}
+}
+
+func TestGroupsView(t *testing.T) {
+	m := app{width: 60, height: 20, items: []model.Item{
+		{Title: "p #x"}, {Title: "q", Level: 1}, {Title: "r #x"},
+	}}
+	m.recalcVisible()
+	m.openGroups("tag")
+	press := func(k string) {
+		t.Helper()
+		next, _ := m.updateGroups(keyMsg(k))
+		m = next.(app)
+	}
+
+	press("enter") // fold #x
+	if view := m.renderGroups(10, theme.Default); strings.Contains(view, "[ ] p") || !strings.Contains(view, "▸ #x") {
+		t.Errorf("folded group still shows its tasks:\n%s", view)
+	}
+	press("enter") // unfold
+	press("j")
+	press(" ")
+	if !m.items[0].Done {
+		t.Fatal("space didn't complete the task")
+	}
+	if titles := []string{m.items[0].Title, m.items[1].Title, m.items[2].Title}; titles[0] != "p #x" || titles[2] != "r #x" {
+		t.Errorf("grouping changed the file order: %v", titles)
+	}
+	press("j")
+	press("enter")
+	if m.state != viewMain || m.visibleItems[m.cursorMain].Index != 2 {
+		t.Errorf("enter should jump to r, got state %d cursor %d", m.state, m.cursorMain)
+	}
+}
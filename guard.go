@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// --- DELETE RATE GUARD ---
+//
+// Protects the list from runaway automation: once more than N items were
+// deleted within a minute, further deletes need an explicit force flag, and
+// the first forced one snapshots the file.
+
+const (
+	defaultDeleteLimit = 20
+	deleteWindow       = time.Minute
+	snapshotDir        = "snapshots"
+)
+
+func (c Config) deleteLimit() int {
+	if c.DeleteLimit > 0 {
+		return c.DeleteLimit
+	}
+	return defaultDeleteLimit
+}
+
+type deleteGuard struct {
+	limit    int
+	recent   []time.Time
+	snapshot time.Time
+}
+
+func (g *deleteGuard) prune(now time.Time) {
+	keep := g.recent[:0]
+	for _, t := range g.recent {
+		if now.Sub(t) < deleteWindow {
+			keep = append(keep, t)
+		}
+	}
+	g.recent = keep
+}
+
+// allow reports whether n more deletes fit into the current window.
+func (g *deleteGuard) allow(n int, now time.Time) bool {
+	g.prune(now)
+	return len(g.recent)+n <= g.limit
+}
+
+func (g *deleteGuard) record(n int, now time.Time) {
+	for range n {
+		g.recent = append(g.recent, now)
+	}
+}
+
+// needsSnapshot is true once per burst of forced deletes.
+func (g *deleteGuard) needsSnapshot(now time.Time) bool {
+	return now.Sub(g.snapshot) >= deleteWindow
+}
+
+// snapshotFile copies the list into the config dir and returns the copy's path.
+func snapshotFile(filename string, now time.Time) (string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(configDir, appName, snapshotDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	ext := filepath.Ext(filename)
+	name := fmt.Sprintf("%s.%s%s", filepath.Base(filename[:len(filename)-len(ext)]), now.Format("20060102-150405"), ext)
+	path := filepath.Join(dir, name)
+	return path, os.WriteFile(path, data, 0644)
+}
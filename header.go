@@ -0,0 +1,357 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- HEADER PATH ---
+//
+// The file path in the header is shortened to fit, dropping whole directory
+// names where it can. Config:
+//
+//	"header": {"truncate": "middle", "home": true, "min_width": 60}
+//
+// truncate puts the ellipsis at the "head" (default), "middle" or "tail" of
+// the path, home shows the home directory as ~, and below min_width columns
+// the path is hidden.
+
+type HeaderConfig struct {
+	Truncate string `json:"truncate,omitempty"`
+	Home     bool   `json:"home,omitempty"`
+	MinWidth int    `json:"min_width,omitempty"`
+	Format   string `json:"format,omitempty"` // see HEADER AND FOOTER FORMATS
+	Counts   *bool  `json:"counts,omitempty"` // see COUNT BADGES
+}
+
+func (c *HeaderConfig) truncation() string {
+	if c == nil {
+		return "head"
+	}
+	switch c.Truncate {
+	case "middle", "tail":
+		return c.Truncate
+	}
+	return "head"
+}
+
+// displayPath is the path as shown in a header width columns wide, with
+// room columns left for it.
+func (c *HeaderConfig) displayPath(path string, width, room int) string {
+	if c != nil && c.MinWidth > 0 && width < c.MinWidth {
+		return ""
+	}
+	if c != nil && c.Home {
+		path = abbreviateHome(path)
+	}
+	return truncatePath(path, room, c.truncation())
+}
+
+func abbreviateHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	return abbreviate(path, home)
+}
+
+// abbreviate writes home as ~ in path. A Windows home is matched as Windows
+// does, regardless of case and of which slash the path uses.
+func abbreviate(path, home string) string {
+	sep := string(filepath.Separator)
+	same := func(a, b string) bool { return a == b }
+	if windowsVolume(home) != "" {
+		sep = `\`
+		same = func(a, b string) bool {
+			return strings.EqualFold(strings.ReplaceAll(a, "/", sep), strings.ReplaceAll(b, "/", sep))
+		}
+	}
+	if same(path, home) {
+		return "~"
+	}
+	if len(path) > len(home) && same(path[:len(home)+1], home+sep) {
+		return "~" + sep + path[len(home)+1:]
+	}
+	return path
+}
+
+// windowsVolume returns the drive ("C:") or the share (\\server\share) a
+// Windows path starts with. Such paths are read the Windows way on any
+// system, as a list opened from one may be shown elsewhere.
+func windowsVolume(path string) string {
+	if len(path) >= 3 && path[1] == ':' && (path[2] == '\\' || path[2] == '/') {
+		if c := path[0] | 0x20; c >= 'a' && c <= 'z' {
+			return path[:2]
+		}
+	}
+	if rest, ok := strings.CutPrefix(path, `\\`); ok {
+		server, share, _ := strings.Cut(rest, `\`)
+		share, _, _ = strings.Cut(share, `\`)
+		if server != "" && share != "" {
+			return `\\` + server + `\` + share
+		}
+	}
+	return ""
+}
+
+// splitPath cuts path at its separators into the names truncatePath may
+// drop. A drive or a share stays whole as the first name, and rooted tells
+// whether the first name is a root ("" for "/") rather than a directory.
+func splitPath(path string) (parts []string, sep string, rooted bool) {
+	sep = string(filepath.Separator)
+	vol := windowsVolume(path)
+	if vol == "" {
+		return strings.Split(path, sep), sep, strings.HasPrefix(path, sep)
+	}
+	sep = `\`
+	rest := strings.TrimPrefix(strings.ReplaceAll(path[len(vol):], "/", sep), sep)
+	return append([]string{vol}, strings.Split(rest, sep)...), sep, true
+}
+
+// truncatePath shortens path to width columns, cutting at separators so that
+// only whole directory names are dropped; a single name that still doesn't
+// fit is cut mid-word.
+func truncatePath(path string, width int, mode string) string {
+	if width <= 0 {
+		return ""
+	}
+	if lipgloss.Width(path) <= width {
+		return path
+	}
+	parts, sep, rooted := splitPath(path)
+	fits := func(s string) bool { return lipgloss.Width(s) <= width }
+
+	switch mode {
+	case "tail":
+		best := ""
+		for k := 1; k < len(parts); k++ {
+			if k == 1 && rooted {
+				continue // samo "/" albo "C:" nic nie mówi
+			}
+			if s := strings.Join(parts[:k], sep) + sep + "…"; fits(s) {
+				best = s
+			}
+		}
+		if best != "" {
+			return best
+		}
+		return ansi.Truncate(path, width, "…")
+	case "middle":
+		// Nazwa pliku zostaje zawsze, potem na zmianę katalogi od końca i od początku
+		join := func(f, b int) string {
+			return strings.Join(parts[:f], sep) + sep + "…" + sep + strings.Join(parts[len(parts)-b:], sep)
+		}
+		if len(parts) < 3 || !fits(join(1, 1)) {
+			tail := (width - 1) / 2
+			return ansi.Truncate(path, width-tail-1, "") + "…" + ansi.TruncateLeft(path, lipgloss.Width(path)-tail, "")
+		}
+		front, back := 1, 1
+		for front+back < len(parts)-1 {
+			switch {
+			case back <= front && fits(join(front, back+1)):
+				back++
+			case fits(join(front+1, back)):
+				front++
+			case fits(join(front, back+1)):
+				back++
+			default:
+				return join(front, back)
+			}
+		}
+		return join(front, back)
+	}
+
+	for k := len(parts) - 1; k > 0; k-- {
+		if s := "…" + sep + strings.Join(parts[len(parts)-k:], sep); fits(s) {
+			return s
+		}
+	}
+	return ansi.TruncateLeft(path, lipgloss.Width(path)-width+1, "…")
+}
+
+// --- COUNT BADGES ---
+//
+// Next to the header: "12 open · 2 overdue · 3 due today · 5 done", counted
+// afresh on every redraw so they follow each change. Overdue and due today
+// are left out at zero, all of them when the window is too narrow;
+// "header": {"counts": false} turns them off.
+
+const minBadgeWidth = 70
+
+type taskCounts struct {
+	open, done, overdue, dueToday int
+}
+
+func countTasks(items []model.Item, now time.Time) taskCounts {
+	var c taskCounts
+	today := model.StartOfDay(now)
+	for _, it := range items {
+		if it.Heading > 0 {
+			continue
+		}
+		if it.Done {
+			c.done++
+			continue
+		}
+		c.open++
+		if due, _, ok := model.DueTime(it.Title); ok {
+			switch d := model.StartOfDay(due); {
+			case d.Before(today):
+				c.overdue++
+			case d.Equal(today):
+				c.dueToday++
+			}
+		}
+	}
+	return c
+}
+
+func (c *HeaderConfig) counts() bool {
+	return c == nil || c.Counts == nil || *c.Counts
+}
+
+// renderBadges draws the counts, or "" when they are off or don't fit.
+func (m app) renderBadges(t theme.Theme) string {
+	if !m.config.Header.counts() || m.width < minBadgeWidth {
+		return ""
+	}
+	c := countTasks(m.items, time.Now())
+	badge := func(color lipgloss.TerminalColor, text string) string {
+		return lipgloss.NewStyle().Foreground(color).Bold(true).Render(text)
+	}
+	parts := []string{badge(t.Accent, i18n.Tf("%d open", c.open))}
+	if c.overdue > 0 {
+		parts = append(parts, badge(t.Error, i18n.Tf("%d overdue", c.overdue)))
+	}
+	if c.dueToday > 0 {
+		parts = append(parts, badge(t.Highlight, i18n.Tf("%d due today", c.dueToday)))
+	}
+	parts = append(parts, badge(t.Special, i18n.Tf("%d done", c.done)))
+	return strings.Join(parts, lipgloss.NewStyle().Foreground(t.Comment).Render(" · "))
+}
+
+// --- HEADER AND FOOTER FORMATS ---
+//
+// "format" replaces the built-in header or footer text:
+//
+//	"header": {"format": "{mode} {name} · {open} open, {done} done{filter}"},
+//	"footer": {"format": "{help} │ {clock}"}
+//
+// Placeholders: {mode} (TODO, BIN…), {file} (the path, shortened to fit as
+// above), {name} (file name), {open}, {done}, {total}, {overdue}, {due}
+// (due today), {filter} (" [filter: …]" when one is set), {sort} (the last
+// :sort key), {view} (the active perspective), {zoom}, {pomodoro},
+// {clock}, {date} and {help} (the key hints). Unknown ones are left as
+// typed. The read-only marker and prompts still show.
+
+type FooterConfig struct {
+	Format string `json:"format,omitempty"`
+}
+
+func (c *HeaderConfig) format() string {
+	if c == nil {
+		return ""
+	}
+	return c.Format
+}
+
+func (c *FooterConfig) format() string {
+	if c == nil {
+		return ""
+	}
+	return c.Format
+}
+
+// formatFields are the values of the placeholders, {file} aside.
+func (m app) formatFields(modeName, help string) map[string]string {
+	now := time.Now()
+	c := countTasks(m.items, now)
+	filter := ""
+	if m.filter != nil {
+		filter = " [filter: " + m.filterText + "]"
+	}
+	return map[string]string{
+		"mode":     modeName,
+		"name":     filepath.Base(m.filename),
+		"open":     strconv.Itoa(c.open),
+		"done":     strconv.Itoa(c.done),
+		"overdue":  strconv.Itoa(c.overdue),
+		"due":      strconv.Itoa(c.dueToday),
+		"total":    strconv.Itoa(len(m.items)),
+		"filter":   filter,
+		"sort":     m.sortKey,
+		"view":     m.perspective,
+		"zoom":     m.zoomTitle(),
+		"pomodoro": strings.TrimSpace(m.pomodoroHeader()),
+		"clock":    now.Format("15:04"),
+		"date":     now.Format(model.DateLayout),
+		"help":     help,
+	}
+}
+
+// expandFormat fills the placeholders of format from fields.
+func expandFormat(format string, fields map[string]string) string {
+	var pairs []string
+	for k, v := range fields {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(format)
+}
+
+// formatHeader expands the header format for a header width columns wide,
+// giving {file} what room the rest leaves.
+func (m app) formatHeader(format, modeName, path string, width int) string {
+	fields := m.formatFields(modeName, "")
+	fields["file"] = ""
+	room := width - lipgloss.Width(expandFormat(format, fields)) - 2
+	fields["file"] = m.config.Header.displayPath(path, m.width, room)
+	return expandFormat(format, fields)
+}
+
+// --- BREADCRUMB ---
+
+// breadcrumb is the ancestor chain of the item under the cursor, e.g.
+// "Project > Backend > Auth > fix token refresh", shown under the header for
+// nested items. Outer ancestors give way first when it doesn't fit.
+func (m *app) breadcrumb(width int) string {
+	if m.state != viewMain || len(m.visibleItems) == 0 || width <= 0 {
+		return ""
+	}
+	idx := m.visibleItems[m.cursorMain].Index
+	if m.items[idx].Level == 0 {
+		return ""
+	}
+	chain := ancestorTitles(m.items, idx)
+	if len(chain) < 2 {
+		return ""
+	}
+	for dropped := 0; dropped < len(chain)-1; dropped++ {
+		s := strings.Join(chain[dropped:], " > ")
+		if dropped > 0 {
+			s = "… > " + s
+		}
+		if lipgloss.Width(s) <= width {
+			return s
+		}
+	}
+	return ansi.Truncate("… > "+chain[len(chain)-1], width, "…")
+}
+
+// ancestorTitles is the chain of display titles from the top-level ancestor
+// down to items[idx].
+func ancestorTitles(items []model.Item, idx int) []string {
+	var chain []string
+	for i := idx; i != -1; i = model.ParentIndex(items, i) {
+		chain = append([]string{model.DisplayTitle(items[i].Title)}, chain...)
+	}
+	return chain
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+func TestTruncatePath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := filepath.FromSlash("/home/ann/projects/website/todo.md")
+	tests := []struct {
+		mode  string
+		width int
+		want  string
+	}{
+		{"head", 100, "/home/ann/projects/website/todo.md"},
+		{"head", 22, "…/website/todo.md"},
+		{"tail", 22, "/home/ann/projects/…"},
+		{"middle", 22, "/…/website/todo.md"},
+		{"middle", 25, "/home/…/website/todo.md"},
+		{"head", 5, "…o.md"},
+		{"tail", 5, "/hom…"},
+		{"head", 0, ""},
+	}
+	for _, tt := range tests {
+		got := truncatePath(path, tt.width, tt.mode)
+		if want := filepath.FromSlash(tt.want); got != want {
+			t.Errorf("truncatePath(%s, %d) = %q, want %q", tt.mode, tt.width, got, want)
+		}
+		if lipgloss.Width(got) > tt.width {
+			t.Errorf("truncatePath(%s, %d) is %d wide", tt.mode, tt.width, lipgloss.Width(got))
+		}
+	}
+}
+
+func TestWindowsPaths(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	tests := []struct {
+		path, mode string
+		width      int
+		want       string
+	}{
+		{`C:\Users\ann\projects\website\todo.md`, "head", 22, `…\website\todo.md`},
+		{`C:\Users\ann\projects\website\todo.md`, "tail", 22, `C:\Users\ann\…`},
+		{`C:\Users\ann\projects\website\todo.md`, "middle", 22, `C:\…\website\todo.md`},
+		{`C:/Users/ann/projects/website/todo.md`, "middle", 22, `C:\…\website\todo.md`},
+		{`C:\todo-lists-for-everything.md`, "tail", 20, `C:\todo-lists-for-e…`},
+		{`\\nas\home\ann\lists\todo.md`, "middle", 24, `\\nas\home\ann\…\todo.md`},
+	}
+	for _, tt := range tests {
+		if got := truncatePath(tt.path, tt.width, tt.mode); got != tt.want {
+			t.Errorf("truncatePath(%s, %s, %d) = %q, want %q", tt.path, tt.mode, tt.width, got, tt.want)
+		}
+	}
+
+	home := `C:\Users\Ann`
+	for path, want := range map[string]string{
+		`c:\users\ann\todo.md`:   `~\todo.md`,
+		`C:/Users/Ann/x/todo.md`: `~\x/todo.md`,
+		`C:\Users\Anna\todo.md`:  `C:\Users\Anna\todo.md`,
+		`C:\Users\ann`:           "~",
+	} {
+		if got := abbreviate(path, home); got != want {
+			t.Errorf("abbreviate(%s) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestHeaderHidesPathWhenNarrow(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	c := &HeaderConfig{MinWidth: 60}
+	if got := c.displayPath("/tmp/todo.md", 59, 40); got != "" {
+		t.Errorf("narrow header shows %q", got)
+	}
+	if got := c.displayPath("/tmp/todo.md", 80, 40); got != "/tmp/todo.md" {
+		t.Errorf("wide header shows %q", got)
+	}
+}
+
+func TestBreadcrumb(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{items: []model.Item{
+		{Title: "Project"}, {Title: "Backend", Level: 1}, {Title: "Auth created:2026-01-02", Level: 2}, {Title: "fix token refresh", Level: 3},
+	}}
+	m.recalcVisible()
+	if got := m.breadcrumb(80); got != "" {
+		t.Errorf("top-level item has breadcrumb %q", got)
+	}
+	m.cursorMain = 3
+	if got, want := m.breadcrumb(80), "Project > Backend > Auth > fix token refresh"; got != want {
+		t.Errorf("breadcrumb = %q, want %q", got, want)
+	}
+	if got, want := m.breadcrumb(35), "… > Auth > fix token refresh"; got != want {
+		t.Errorf("narrow breadcrumb = %q, want %q", got, want)
+	}
+	if got := m.breadcrumb(10); lipgloss.Width(got) > 10 {
+		t.Errorf("breadcrumb %q wider than 10", got)
+	}
+}
+
+func TestHeaderFooterFormat(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{width: 80, height: 10, filename: "/tmp/work/todo.md", activeTheme: theme.Default, items: []model.Item{
+		{Title: "one"}, {Title: "two", Done: true}, {Title: "three"},
+	}}
+	m.config.Header = &HeaderConfig{Format: "{mode} {name}: {open} open, {done} done{filter} {unknown}"}
+	m.config.Footer = &FooterConfig{Format: "{total} tasks │ {clock}"}
+	m.recalcVisible()
+	m.runCommand("filter open")
+	m.runCommand("sort title")
+	m.status = ""
+	out := ansi.Strip(m.View())
+	for _, want := range []string{"TODO todo.md: 2 open, 1 done [filter: open] {unknown}", "3 tasks │ " + time.Now().Format("15:04")} {
+		if !strings.Contains(out, want) {
+			t.Errorf("view lacks %q:\n%s", want, out)
+		}
+	}
+	if m.sortKey != "title" {
+		t.Errorf("sortKey = %q", m.sortKey)
+	}
+
+	m.width = 20
+	if got := ansi.Strip(m.formatHeader("[{file}]", "TODO", "/tmp/work/todo.md", m.width)); lipgloss.Width(got) > 18 || !strings.HasSuffix(got, "todo.md]") {
+		t.Errorf("{file} does not fit: %q", got)
+	}
+
+	m.inputMode = true
+	if out := ansi.Strip(m.View()); !strings.Contains(out, "Confirm") {
+		t.Errorf("the edit hints must win over the footer format:\n%s", out)
+	}
+}
+
+func TestCountBadges(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	now := time.Now()
+	day := func(n int) string { return now.AddDate(0, 0, n).Format(model.DateLayout) }
+	m := app{width: 100, height: 10, filename: "todo.md", activeTheme: theme.Default, items: []model.Item{
+		{Title: "late due:" + day(-1)}, {Title: "now due:" + day(0)}, {Title: "later due:" + day(2)},
+		{Title: "old due:" + day(-3), Done: true}, {Title: "plain"},
+	}}
+	m.recalcVisible()
+	if c := countTasks(m.items, now); c != (taskCounts{open: 4, done: 1, overdue: 1, dueToday: 1}) {
+		t.Errorf("counts = %+v", c)
+	}
+	header := strings.Split(ansi.Strip(m.View()), "\n")[1]
+	if !strings.Contains(header, "4 open · 1 overdue · 1 due today · 1 done") || lipgloss.Width(header) > m.width {
+		t.Errorf("header = %q", header)
+	}
+
+	// Liczniki idą za zmianami
+	next, _ := m.updateMain(keyMsg(" "))
+	m = next.(app)
+	if header := ansi.Strip(m.View()); !strings.Contains(header, "3 open · 1 due today · 2 done") {
+		t.Errorf("after completing the overdue task:\n%s", header)
+	}
+
+	off := false
+	m.config.Header = &HeaderConfig{Counts: &off}
+	if header := ansi.Strip(m.View()); strings.Contains(header, "open") {
+		t.Errorf("counts: false still shows them:\n%s", header)
+	}
+	m.config.Header, m.width = nil, 50
+	if header := ansi.Strip(m.View()); strings.Contains(header, "open") {
+		t.Errorf("a narrow window shows the counts:\n%s", header)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- HOOKS ---
+//
+// "hooks" in config.json runs a shell command when something happens:
+//
+//	"hooks": {"complete": "jq -r .task.title >> ~/journal.txt", "save": "git commit -qam todo"}
+//
+// Events: "add" (a task typed or pasted in the app, POSTed to serve or
+// given to todo add), "complete" and "save" (the file was written). The
+// command gets {"event", "file", "time", "task"} as JSON on stdin, the task
+// in the shape the REST API uses ("save" has none), and TODO_EVENT and
+// TODO_FILE in its environment. Hooks run in the background for at most
+// hookTimeout; a failure shows in the footer, or in the log of serve.
+
+const hookTimeout = 30 * time.Second
+
+type hookPayload struct {
+	Event string    `json:"event"`
+	File  string    `json:"file"`
+	Time  time.Time `json:"time"`
+	Task  *apiTask  `json:"task,omitempty"`
+}
+
+type hookCall struct {
+	command string
+	payload hookPayload
+}
+
+type hookDoneMsg struct {
+	event string
+	err   error
+}
+
+func newHookCall(hooks map[string]string, event, filename string, task *apiTask) (hookCall, bool) {
+	command := hooks[event]
+	if command == "" {
+		return hookCall{}, false
+	}
+	if abs, err := filepath.Abs(filename); err == nil {
+		filename = abs
+	}
+	return hookCall{command, hookPayload{event, filename, time.Now(), task}}, true
+}
+
+// run executes the hook, returning its first line of output with an error.
+func (h hookCall) run() error {
+	data, err := json.Marshal(h.payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.command)
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", h.command)
+	}
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Env = append(os.Environ(), "TODO_EVENT="+h.payload.Event, "TODO_FILE="+h.payload.File)
+	out, err := cmd.CombinedOutput()
+	if line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n"); err != nil && line != "" {
+		err = fmt.Errorf("%w: %s", err, line)
+	}
+	return err
+}
+
+// fireHook queues the hook of event, started after the current update.
+func (m *app) fireHook(event string, idx int) {
+	if m.demo {
+		return
+	}
+	var task *apiTask
+	if idx >= 0 {
+		t := toAPITask(m.items, idx)
+		task = &t
+	}
+	if h, ok := newHookCall(m.config.Hooks, event, m.filename, task); ok {
+		m.hooks = append(m.hooks, h)
+	}
+}
+
+func (m *app) startHooks() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, h := range m.hooks {
+		cmds = append(cmds, func() tea.Msg { return hookDoneMsg{h.payload.Event, h.run()} })
+	}
+	m.hooks = nil
+	return tea.Batch(cmds...)
+}
+
+// fireHook runs the hook of event for serve, logging failures.
+func (s *apiServer) fireHook(event string, task *apiTask) {
+	if h, ok := newHookCall(s.config.Hooks, event, s.filename, task); ok {
+		go func() {
+			if err := h.run(); err != nil {
+				log.Printf("hook %s: %v", event, err)
+			}
+		}()
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks run through sh")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	out := filepath.Join(dir, "event.json")
+	m := app{filename: filepath.Join(dir, "todo.md"), items: []model.Item{{Title: "write the report id:r1"}}}
+	m.config.Hooks = map[string]string{"complete": `cat > "` + out + `"; echo "$TODO_EVENT" >> "` + out + `.env"`, "save": "echo broken >&2; exit 3"}
+	m.recalcVisible()
+
+	next, _ := m.Update(keyMsg(" "))
+	m = next.(app)
+	if len(m.hooks) != 0 {
+		t.Fatal("queued hooks must be started by Update")
+	}
+	m.fireHook("complete", 0)
+	msg := m.startHooks()()
+	if done := msg.(hookDoneMsg); done.err != nil {
+		t.Fatal(done.err)
+	}
+	var p hookPayload
+	data, _ := os.ReadFile(out)
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatalf("%v: %s", err, data)
+	}
+	if p.Event != "complete" || p.Task == nil || p.Task.ID != "r1" || !p.Task.Done || p.File != m.filename {
+		t.Errorf("payload = %+v", p)
+	}
+	if env, _ := os.ReadFile(out + ".env"); string(env) != "complete\n" {
+		t.Errorf("TODO_EVENT = %q", env)
+	}
+
+	m.fireHook("save", -1)
+	next, _ = m.Update(m.startHooks()())
+	if m = next.(app); m.status != "Hook save failed: exit status 3: broken" {
+		t.Errorf("status = %q", m.status)
+	}
+	m.fireHook("add", 0)
+	if len(m.hooks) != 0 {
+		t.Error("an event without a hook queued one")
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// --- ICALENDAR (VTODO) ---
+
+type vtodo struct {
+	UID     string
+	Summary string
+	Done    bool
+	Parent  string // UID from RELATED-TO;RELTYPE=PARENT
+}
+
+func icalEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+func icalUnescape(s string) string {
+	r := strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+	return r.Replace(s)
+}
+
+// icalFold splits content lines longer than 75 octets (RFC 5545 3.1).
+func icalFold(line string) string {
+	if len(line) <= 75 {
+		return line
+	}
+	var sb strings.Builder
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		// Nie tniemy w środku znaku UTF-8
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		sb.WriteString(line[:cut])
+		sb.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+	sb.WriteString(line)
+	return sb.String()
+}
+
+func encodeVTODO(t vtodo, stamp time.Time) []byte {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//" + appName + "//EN",
+		"BEGIN:VTODO",
+		"UID:" + t.UID,
+		"DTSTAMP:" + stamp.UTC().Format("20060102T150405Z"),
+		"SUMMARY:" + icalEscape(t.Summary),
+	}
+	if t.Done {
+		lines = append(lines, "STATUS:COMPLETED", "PERCENT-COMPLETE:100")
+	} else {
+		lines = append(lines, "STATUS:NEEDS-ACTION")
+	}
+	if t.Parent != "" {
+		lines = append(lines, "RELATED-TO;RELTYPE=PARENT:"+t.Parent)
+	}
+	lines = append(lines, "END:VTODO", "END:VCALENDAR")
+
+	var sb strings.Builder
+	for _, l := range lines {
+		sb.WriteString(icalFold(l))
+		sb.WriteString("\r\n")
+	}
+	return []byte(sb.String())
+}
+
+// decodeVTODOs extracts every VTODO from a calendar object.
+func decodeVTODOs(data string) ([]vtodo, error) {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	// Unfolding: linia zaczynająca się od spacji/taba jest kontynuacją
+	data = strings.ReplaceAll(data, "\n ", "")
+	data = strings.ReplaceAll(data, "\n\t", "")
+
+	var result []vtodo
+	var cur *vtodo
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		nameParams, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, params, _ := strings.Cut(nameParams, ";")
+		name = strings.ToUpper(name)
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VTODO"):
+			cur = &vtodo{}
+		case name == "END" && strings.EqualFold(value, "VTODO"):
+			if cur == nil {
+				return nil, fmt.Errorf("ical: END:VTODO without BEGIN")
+			}
+			result = append(result, *cur)
+			cur = nil
+		case cur == nil:
+			continue
+		case name == "UID":
+			cur.UID = value
+		case name == "SUMMARY":
+			cur.Summary = icalUnescape(value)
+		case name == "STATUS":
+			cur.Done = strings.EqualFold(value, "COMPLETED")
+		case name == "RELATED-TO":
+			relType := "PARENT"
+			for _, p := range strings.Split(params, ";") {
+				if k, v, ok := strings.Cut(p, "="); ok && strings.EqualFold(k, "RELTYPE") {
+					relType = strings.ToUpper(v)
+				}
+			}
+			if relType == "PARENT" {
+				cur.Parent = value
+			}
+		}
+	}
+	if cur != nil {
+		return nil, fmt.Errorf("ical: unterminated VTODO")
+	}
+	return result, nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+	"github.com/pawello85/todo/internal/theme"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// --- IDLE LOCK ---
+//
+// With idle_lock_minutes set, the list is hidden behind a lock splash after
+// that long without a keypress. If idle_lock_hash holds the bcrypt hash of a
+// passphrase (`todo idle-lock-hash` prints one), unlocking requires it;
+// otherwise any key brings the list back. A value that isn't a bcrypt hash,
+// such as the SHA-256 earlier versions took, keeps the list locked and says
+// so: only ctrl+c gets past it.
+
+const idleCheckInterval = 15 * time.Second
+
+type idleTickMsg struct{}
+
+func idleTick() tea.Cmd {
+	return tea.Tick(idleCheckInterval, func(time.Time) tea.Msg {
+		return idleTickMsg{}
+	})
+}
+
+func (c Config) idleTimeout() time.Duration {
+	return time.Duration(c.IdleLockMinutes) * time.Minute
+}
+
+func (m *app) checkIdle(now time.Time) tea.Cmd {
+	if m.config.idleTimeout() <= 0 {
+		return nil
+	}
+	if !m.idleLocked && now.Sub(m.lastInput) >= m.config.idleTimeout() {
+		m.idleLocked = true
+		m.unlockBuf, m.unlockErr = "", false
+	}
+	return idleTick()
+}
+
+func (m *app) updateIdleLock(msg tea.KeyMsg) tea.Cmd {
+	if msg.String() == "ctrl+c" {
+		m.quitting = true
+		return tea.Quit
+	}
+	if m.config.IdleLockHash == "" {
+		m.unlock()
+		return nil
+	}
+	switch msg.Type {
+	case tea.KeyEnter:
+		if bcrypt.CompareHashAndPassword([]byte(m.config.IdleLockHash), []byte(m.unlockBuf)) == nil {
+			m.unlock()
+			return nil
+		}
+		m.unlockBuf, m.unlockErr = "", true
+	case tea.KeyEsc:
+		m.unlockBuf = ""
+	case tea.KeyBackspace:
+		if r := []rune(m.unlockBuf); len(r) > 0 {
+			m.unlockBuf = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		m.unlockBuf += " "
+	case tea.KeyRunes:
+		m.unlockBuf += string(msg.Runes)
+	}
+	return nil
+}
+
+func (m *app) unlock() {
+	m.idleLocked = false
+	m.unlockBuf, m.unlockErr = "", false
+	m.lastInput = time.Now()
+}
+
+func (m app) renderIdleLock(t theme.Theme) string {
+	lines := []string{lipgloss.NewStyle().Foreground(t.Highlight).Bold(true).Render("🔒 Locked")}
+	if m.config.IdleLockHash == "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(t.Comment).Render("press any key"))
+	} else if _, err := bcrypt.Cost([]byte(m.config.IdleLockHash)); err != nil {
+		lines = append(lines, lipgloss.NewStyle().Foreground(t.Error).Render("idle_lock_hash isn't a bcrypt hash: set it with todo idle-lock-hash"),
+			lipgloss.NewStyle().Foreground(t.Comment).Render("ctrl+c quits"))
+	} else {
+		lines = append(lines, lipgloss.NewStyle().Foreground(t.Text).Render("Passphrase: "+strings.Repeat("•", len([]rune(m.unlockBuf)))+"█"))
+		if m.unlockErr {
+			lines = append(lines, lipgloss.NewStyle().Foreground(t.Error).Render("Wrong passphrase"))
+		}
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center, lines...))
+}
+
+// --- todo idle-lock-hash ---
+
+// runIdleLockHash prints the bcrypt hash of a passphrase for idle_lock_hash.
+// On a terminal the passphrase is asked for twice without echo, otherwise
+// it is the first line of stdin.
+func runIdleLockHash(args []string) {
+	fs := flag.NewFlagSet("idle-lock-hash", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: todo idle-lock-hash  (prints the value for idle_lock_hash in config.json)")
+	}
+	fs.Parse(args)
+
+	pass, err := readPassphrase()
+	if err == nil && pass == "" {
+		err = errors.New("empty passphrase")
+	}
+	var hash []byte
+	if err == nil {
+		hash, err = bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(hash))
+}
+
+func readPassphrase() (string, error) {
+	fd := os.Stdin.Fd()
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if line == "" && err != nil {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+	var read [2]string
+	for i, prompt := range []string{"Passphrase: ", "Again: "} {
+		fmt.Fprint(os.Stderr, prompt)
+		b, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		read[i] = string(b)
+	}
+	if read[0] != read[1] {
+		return "", errors.New("the passphrases differ")
+	}
+	return read[0], nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestIdleLock(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	hash, err := bcrypt.GenerateFromPassword([]byte("open sesame"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := app{
+		items:       []model.Item{{Title: "secret plans"}},
+		config:      Config{IdleLockMinutes: 5, IdleLockHash: string(hash)},
+		activeTheme: theme.Default,
+		width:       60, height: 20,
+		lastInput: time.Now().Add(-4 * time.Minute),
+	}
+	m.recalcVisible()
+
+	if m.checkIdle(time.Now()); m.idleLocked {
+		t.Fatal("locked before the timeout")
+	}
+	m.checkIdle(time.Now().Add(time.Minute))
+	if !m.idleLocked {
+		t.Fatal("not locked after the timeout")
+	}
+	if strings.Contains(m.View(), "secret plans") {
+		t.Error("list visible while locked")
+	}
+
+	enter := func(s string) {
+		for _, r := range s {
+			next, _ := m.Update(keyMsg(string(r)))
+			m = next.(app)
+		}
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = next.(app)
+	}
+	enter("wrong")
+	if !m.idleLocked || !m.unlockErr {
+		t.Fatal("wrong passphrase unlocked")
+	}
+	enter("open sesame")
+	if m.idleLocked {
+		t.Fatal("right passphrase rejected")
+	}
+
+	// Stary skrót SHA-256 nie otwiera blokady
+	m.config.IdleLockHash = "41ef4bb0b23661e66301aac36066912dac037827b4ae63a7b1165a5aa93ed4eb" // sha256("open sesame")
+	m.checkIdle(time.Now().Add(time.Hour))
+	enter("open sesame")
+	if !m.idleLocked || !strings.Contains(m.View(), "todo idle-lock-hash") {
+		t.Error("an unsalted SHA-256 must not unlock")
+	}
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+)
+
+// --- READING LIST IMPORT ---
+//
+// `todo import bookmarks.html` (or `:import <file>`) adds links from a
+// Netscape bookmark export (browsers, old Pocket exports) or a Pocket or
+// Instapaper CSV as tasks "Title https://…" under a top-level "Reading"
+// section. Links already anywhere in the file, bin included, are skipped, so
+// importing the same export again only adds what is new.
+
+const readingSection = "Reading"
+
+type bookmark struct {
+	title string
+	url   string
+	tags  []string
+	done  bool // archived in Pocket/Instapaper
+}
+
+var (
+	bookmarkLink = regexp.MustCompile(`(?is)<a\s([^>]*)>(.*?)</a>`)
+	bookmarkAttr = regexp.MustCompile(`(?is)([a-z_]+)\s*=\s*"([^"]*)"`)
+	htmlTag      = regexp.MustCompile(`<[^>]*>`)
+	urlToken     = regexp.MustCompile(`https?://\S+`)
+)
+
+// parseBookmarks reads either export format, telling them apart by content.
+func parseBookmarks(data []byte) ([]bookmark, error) {
+	head := bytes.TrimSpace(data[:min(len(data), 512)])
+	if bytes.HasPrefix(head, []byte("<")) {
+		return parseBookmarkHTML(data), nil
+	}
+	return parseBookmarkCSV(data)
+}
+
+func parseBookmarkHTML(data []byte) []bookmark {
+	var out []bookmark
+	for _, m := range bookmarkLink.FindAllSubmatch(data, -1) {
+		attrs := map[string]string{}
+		for _, a := range bookmarkAttr.FindAllSubmatch(m[1], -1) {
+			attrs[strings.ToLower(string(a[1]))] = html.UnescapeString(string(a[2]))
+		}
+		b := bookmark{
+			url:   attrs["href"],
+			title: html.UnescapeString(htmlTag.ReplaceAllString(string(m[2]), "")),
+		}
+		if tags := attrs["tags"]; tags != "" {
+			b.tags = strings.Split(tags, ",")
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// parseBookmarkCSV reads Pocket (title,url,time_added,tags,status) and
+// Instapaper (URL,Title,Selection,Folder,Timestamp) exports by header name.
+func parseBookmarkCSV(data []byte) ([]bookmark, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	col := map[string]int{}
+	for i, name := range rows[0] {
+		col[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := col["url"]; !ok {
+		return nil, fmt.Errorf("no url column in the CSV header")
+	}
+	field := func(row []string, name string) string {
+		if i, ok := col[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var out []bookmark
+	for _, row := range rows[1:] {
+		b := bookmark{url: field(row, "url"), title: field(row, "title")}
+		if tags := field(row, "tags"); tags != "" {
+			b.tags = strings.FieldsFunc(tags, func(r rune) bool { return r == '|' || r == ',' })
+		}
+		b.done = strings.EqualFold(field(row, "status"), "archive") || strings.EqualFold(field(row, "folder"), "archive")
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+// bookmarkTitle is the task line of a bookmark.
+func bookmarkTitle(b bookmark) string {
+	title := strings.Join(strings.Fields(b.title), " ")
+	switch {
+	case title == "" || title == b.url:
+		title = b.url
+	case b.url != "":
+		title += " " + b.url
+	}
+	for _, tag := range b.tags {
+		if tag = strings.Join(strings.Fields(tag), "-"); tag != "" {
+			title = model.SetTag(title, tag, true)
+		}
+	}
+	return title
+}
+
+// importBookmarks adds the new links at the end of the reading section,
+// creating it if needed, and returns how many were added.
+func importBookmarks(items, trash []model.Item, marks []bookmark) ([]model.Item, int) {
+	seen := map[string]bool{}
+	for _, list := range [][]model.Item{items, trash} {
+		for _, it := range list {
+			for _, u := range urlToken.FindAllString(it.Title, -1) {
+				seen[u] = true
+			}
+		}
+	}
+
+	var added []model.Item
+	for _, b := range marks {
+		if !urlToken.MatchString(b.url) || seen[b.url] {
+			continue
+		}
+		seen[b.url] = true
+		added = append(added, model.Item{Title: bookmarkTitle(b), Done: b.done, Level: 1})
+	}
+	if len(added) == 0 {
+		return items, 0
+	}
+
+	return appendToSection(items, readingSection, added), len(added)
+}
+
+// appendToSection adds children at the end of the top-level task titled
+// name, creating it at the end of the list if needed.
+func appendToSection(items []model.Item, name string, children []model.Item) []model.Item {
+	section := -1
+	for i, it := range items {
+		if it.Level == 0 && model.DisplayTitle(it.Title) == name {
+			section = i
+			break
+		}
+	}
+	if section == -1 {
+		items = append(items, model.Item{Title: name})
+		section = len(items) - 1
+	}
+	return slices.Insert(items, model.SubtreeEnd(items, section), children...)
+}
+
+func readBookmarks(path string) ([]bookmark, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	marks, err := parseBookmarks(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return marks, nil
+}
+
+func (m *app) importFile(arg string) {
+	if arg == "" {
+		m.status = "Usage: :import <bookmarks.html|export.csv|notes.md [section]|todoist.zip>"
+		return
+	}
+	path, section, _ := strings.Cut(arg, " ")
+	if isMarkdownFile(path) || isTodoistExport(path) {
+		var tasks []importedTask
+		var err error
+		if isMarkdownFile(path) {
+			tasks, err = readMarkdownTasks(path, strings.TrimSpace(section))
+		} else {
+			tasks, err = readTodoist(path, time.Now())
+		}
+		if err != nil {
+			m.status = "Import failed: " + err.Error()
+			return
+		}
+		var n int
+		m.items, n = importTasks(m.items, m.trash, tasks)
+		m.recalcVisible()
+		if n > 0 {
+			m.save()
+		}
+		m.status = fmt.Sprintf("Imported %d new tasks from %s", n, filepath.Base(path))
+		return
+	}
+	marks, err := readBookmarks(path)
+	if err != nil {
+		m.status = "Import failed: " + err.Error()
+		return
+	}
+	var n int
+	m.items, n = importBookmarks(m.items, m.trash, marks)
+	m.recalcVisible()
+	if n > 0 {
+		m.save()
+	}
+	m.status = fmt.Sprintf("Imported %d new links into %s", n, readingSection)
+}
+
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	section := fs.String("section", "", "only import the tasks under this heading of a markdown file")
+	from := fs.String("from", "", `source format: "todoist" for a Todoist export, or the Todoist API without a file`)
+	token := fs.String("token", os.Getenv("TODOIST_API_TOKEN"), "Todoist API token")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: todo import <bookmarks.html|export.csv|notes.md|todoist.zip> [--section NAME] [todo.md]")
+		fmt.Fprintln(fs.Output(), "       todo import --from todoist [--token TOKEN] [todo.md]")
+		fs.PrintDefaults()
+	}
+	// Flagi mogą stać po nazwach plików
+	var files []string
+	for fs.Parse(args); fs.NArg() > 0; fs.Parse(args) {
+		files = append(files, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	todoist := strings.EqualFold(*from, "todoist")
+	if *from != "" && !todoist {
+		fmt.Fprintf(os.Stderr, "Error: unknown source %q\n", *from)
+		os.Exit(2)
+	}
+	source, filename := "", "todo.md"
+	switch {
+	case todoist && len(files) == 1 && strings.EqualFold(filepath.Ext(files[0]), ".md"):
+		filename = files[0]
+	case len(files) > 1:
+		source, filename = files[0], files[1]
+	case len(files) == 1:
+		source = files[0]
+	}
+	if source == "" && !todoist {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	var (
+		tasks []importedTask
+		marks []bookmark
+		err   error
+	)
+	switch {
+	case source == "":
+		if *token == "" {
+			fmt.Fprintln(os.Stderr, "Error: set TODOIST_API_TOKEN or pass --token")
+			os.Exit(2)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		tasks, err = fetchTodoist(ctx, *token, time.Now())
+	case todoist || isTodoistExport(source):
+		tasks, err = readTodoist(source, time.Now())
+	case isMarkdownFile(source):
+		tasks, err = readMarkdownTasks(source, *section)
+	default:
+		marks, err = readBookmarks(source)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	var n int
+	err = withWriteLock(filename, func() error {
+		items, trash, err := storage.Load(filename)
+		if err != nil {
+			return err
+		}
+		if marks != nil {
+			items, n = importBookmarks(items, trash, marks)
+		} else {
+			items, n = importTasks(items, trash, tasks)
+		}
+		if n == 0 {
+			return nil
+		}
+		return saveList(filename, items, trash, config.cloudSafe(filename))
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	switch {
+	case marks != nil:
+		fmt.Printf("Imported %d new links (%d skipped)\n", n, len(marks)-n)
+	case source == "":
+		fmt.Printf("Imported %d new tasks from Todoist\n", n)
+	default:
+		fmt.Printf("Imported %d new tasks from %s\n", n, filepath.Base(source))
+	}
+}
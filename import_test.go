@@ -0,0 +1,71 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+const netscapeExport = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<DL><p>
+    <DT><H3>Go</H3>
+    <DL><p>
+        <DT><A HREF="https://go.dev/blog/" ADD_DATE="1700000000" TAGS="go,to read">The Go Blog &amp; news</A>
+        <DT><A HREF="place:sort=8">Recent</A>
+    </DL><p>
+    <DT><A HREF="https://example.com/">https://example.com/</A>
+</DL><p>`
+
+func TestParseBookmarks(t *testing.T) {
+	marks, err := parseBookmarks([]byte(netscapeExport))
+	if err != nil || len(marks) != 3 {
+		t.Fatalf("marks = %+v, %v", marks, err)
+	}
+	if got := bookmarkTitle(marks[0]); got != "The Go Blog & news https://go.dev/blog/ #go #to-read" {
+		t.Errorf("title = %q", got)
+	}
+	if got := bookmarkTitle(marks[2]); got != "https://example.com/" {
+		t.Errorf("title = %q", got)
+	}
+
+	pocket := "title,url,time_added,tags,status\n\"A, B\",https://a.example/,1700000000,x|y,archive\n"
+	marks, _ = parseBookmarks([]byte(pocket))
+	want := []bookmark{{title: "A, B", url: "https://a.example/", tags: []string{"x", "y"}, done: true}}
+	if !reflect.DeepEqual(marks, want) {
+		t.Errorf("pocket = %+v", marks)
+	}
+	instapaper := "URL,Title,Selection,Folder,Timestamp\nhttps://b.example/,B,,Unread,1700000000\n"
+	marks, _ = parseBookmarks([]byte(instapaper))
+	if len(marks) != 1 || marks[0].title != "B" || marks[0].done {
+		t.Errorf("instapaper = %+v", marks)
+	}
+	if _, err := parseBookmarks([]byte("name,link\na,b\n")); err == nil {
+		t.Error("CSV without a url column accepted")
+	}
+}
+
+func TestImportBookmarksDeduplicates(t *testing.T) {
+	items := []model.Item{{Title: "Reading"}, {Title: "Old https://a.example/", Level: 1}, {Title: "Work"}}
+	trash := []model.Item{{Title: "Gone https://gone.example/"}}
+	marks := []bookmark{
+		{title: "A", url: "https://a.example/"},
+		{title: "Gone", url: "https://gone.example/"},
+		{title: "New", url: "https://new.example/"},
+		{title: "New again", url: "https://new.example/"},
+		{title: "Local", url: "place:recent"},
+	}
+	got, n := importBookmarks(items, trash, marks)
+	if n != 1 || len(got) != 4 || got[2].Title != "New https://new.example/" || got[2].Level != 1 {
+		t.Fatalf("n = %d, items = %+v", n, got)
+	}
+	if _, n = importBookmarks(got, trash, marks); n != 0 {
+		t.Errorf("second import added %d", n)
+	}
+
+	got, _ = importBookmarks([]model.Item{{Title: "Work"}}, nil, marks[2:3])
+	if len(got) != 3 || got[1].Title != "Reading" || got[2].Level != 1 {
+		t.Errorf("new section: %+v", got)
+	}
+}
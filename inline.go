@@ -0,0 +1,42 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- INLINE MODE ---
+//
+// `todo --inline [file]` (and `todo all --inline`) runs without the
+// alternate screen, the way gum and fzf do: the list is drawn in place below
+// the prompt, in the compact layout and at most inlineRows rows tall. On
+// quit the list is printed where it was drawn, as folded and filtered, so it
+// stays in the scrollback after the quick edit.
+
+const inlineRows = 12
+
+// cutInlineFlag takes --inline out of the command line.
+func cutInlineFlag(args []string) ([]string, bool) {
+	i := slices.IndexFunc(args, func(a string) bool { return a == "--inline" || a == "-inline" })
+	if i == -1 {
+		return args, false
+	}
+	return slices.Delete(slices.Clone(args), i, i+1), true
+}
+
+// finalList is the list as left on screen after an inline session: the
+// rows of the visible tasks without the header, the footer or the cursor.
+func (m app) finalList(t theme.Theme) string {
+	m.cursorMain = -1
+	m.inputMode = false
+	m.width += 2 // jak w układzie kompaktowym, bez ramki
+	guides := ui.TreeGuides(m.visibleItems, 0, len(m.visibleItems))
+	var lines []string
+	for i := range m.visibleItems {
+		lines = append(lines, m.itemRows(i, guides[i], t)...)
+	}
+	return strings.Join(lines, "\n")
+}
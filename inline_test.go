@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+func TestInlineMode(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if args, ok := cutInlineFlag([]string{"list.md", "--inline"}); !ok || len(args) != 1 || args[0] != "list.md" {
+		t.Errorf("cutInlineFlag = %q %v", args, ok)
+	}
+	if _, ok := cutInlineFlag([]string{"list.md"}); ok {
+		t.Error("no flag, no inline mode")
+	}
+
+	m := app{inline: true, demo: true, activeTheme: theme.Default, items: []model.Item{
+		{Title: "groceries"}, {Title: "milk", Level: 1, Done: true}, {Title: "call mum"},
+	}}
+	m.recalcVisible()
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 60, Height: 40})
+	m = next.(app)
+	view := ansi.Strip(m.View())
+	if lines := strings.Count(view, "\n") + 1; lines != inlineRows || strings.Contains(view, "╭") {
+		t.Errorf("inline view is %d lines:\n%s", lines, view)
+	}
+
+	final := ansi.Strip(m.finalList(theme.Default))
+	if strings.Count(final, "\n") != 2 || strings.Contains(final, "➤") || strings.Contains(final, "TODO") {
+		t.Errorf("final list:\n%s", final)
+	}
+	for _, title := range []string{"groceries", "milk", "call mum"} {
+		if !strings.Contains(final, title) {
+			t.Errorf("final list lacks %q:\n%s", title, final)
+		}
+	}
+}
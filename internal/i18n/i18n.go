@@ -0,0 +1,195 @@
+// Package i18n translates UI strings and formats dates for the configured
+// locale, date format and first day of the week.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// --- CATALOGS ---
+//
+// Messages are looked up by their English text, so anything without a
+// translation simply stays English. Each locale also names the days (Sunday
+// first) and months.
+
+//go:embed locales/*.json
+var localesFS embed.FS
+
+type catalog struct {
+	Messages    map[string]string `json:"messages"`
+	Days        []string          `json:"days"`
+	DaysShort   []string          `json:"days_short"`
+	Months      []string          `json:"months"`
+	MonthsShort []string          `json:"months_short"`
+}
+
+func load(name string) (catalog, bool) {
+	var c catalog
+	data, err := localesFS.ReadFile("locales/" + name + ".json")
+	if err != nil || json.Unmarshal(data, &c) != nil || len(c.Days) != 7 || len(c.DaysShort) != 7 || len(c.Months) != 12 || len(c.MonthsShort) != 12 {
+		return c, false
+	}
+	return c, true
+}
+
+var english, _ = load("en")
+
+var (
+	current    = english
+	weekStart  = time.Monday
+	dateFormat = "iso"
+)
+
+// SetLocale switches the language: "en" (also for ""), "pl", or "auto" for
+// the one in LC_ALL, LC_MESSAGES or LANG. An unknown locale leaves English
+// and is an error.
+func SetLocale(name string) error {
+	current = english
+	auto := name == "auto"
+	if auto {
+		for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+			if name = os.Getenv(env); name != "" {
+				break
+			}
+		}
+	}
+	// pl_PL.UTF-8 → pl
+	lang, _, _ := strings.Cut(strings.ToLower(name), ".")
+	lang, _, _ = strings.Cut(lang, "_")
+	if lang == "" || lang == "c" || lang == "posix" {
+		return nil
+	}
+	c, ok := load(lang)
+	switch {
+	case ok:
+		current = c
+	case !auto:
+		return fmt.Errorf("unknown locale %q (want en or pl)", name)
+	}
+	return nil
+}
+
+// SetWeekStart sets the first day of the week: "monday" (the default) or
+// "sunday".
+func SetWeekStart(name string) error {
+	switch strings.ToLower(name) {
+	case "", "monday":
+		weekStart = time.Monday
+	case "sunday":
+		weekStart = time.Sunday
+	default:
+		weekStart = time.Monday
+		return fmt.Errorf("unknown week_start %q (want monday or sunday)", name)
+	}
+	return nil
+}
+
+// SetDateFormat sets how Date writes dates: "iso" (2006-01-02, the
+// default), "dmy" (02.01.2006) or "relative" (today, in 3 days).
+func SetDateFormat(name string) error {
+	switch strings.ToLower(name) {
+	case "", "iso":
+		dateFormat = "iso"
+	case "dmy", "relative":
+		dateFormat = strings.ToLower(name)
+	default:
+		dateFormat = "iso"
+		return fmt.Errorf("unknown date_format %q (want iso, dmy or relative)", name)
+	}
+	return nil
+}
+
+// --- MESSAGES ---
+
+// T translates msg.
+func T(msg string) string {
+	if s, ok := current.Messages[msg]; ok && s != "" {
+		return s
+	}
+	return msg
+}
+
+// Tf translates format and fills it in like fmt.Sprintf.
+func Tf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// --- DATES ---
+
+func Weekday(d time.Weekday, short bool) string {
+	if short {
+		return current.DaysShort[d]
+	}
+	return current.Days[d]
+}
+
+func Month(m time.Month, short bool) string {
+	if short {
+		return current.MonthsShort[m-1]
+	}
+	return current.Months[m-1]
+}
+
+// WeekStart is the first day of the week.
+func WeekStart() time.Weekday {
+	return weekStart
+}
+
+// WeekdayColumn is the column of d in a week starting on WeekStart.
+func WeekdayColumn(d time.Weekday) int {
+	return (int(d) - int(weekStart) + 7) % 7
+}
+
+// Weekdays are the days of the week in column order.
+func Weekdays() []time.Weekday {
+	days := make([]time.Weekday, 7)
+	for i := range days {
+		days[i] = (weekStart + time.Weekday(i)) % 7
+	}
+	return days
+}
+
+// LongDate is "Mon, 2 Jan 2006" in the current language.
+func LongDate(t time.Time) string {
+	return fmt.Sprintf("%s, %d %s %d", Weekday(t.Weekday(), true), t.Day(), Month(t.Month(), true), t.Year())
+}
+
+// DayMonth is "Mon, 2 Jan" in the current language.
+func DayMonth(t time.Time) string {
+	return fmt.Sprintf("%s, %d %s", Weekday(t.Weekday(), true), t.Day(), Month(t.Month(), true))
+}
+
+// MonthYear is "January 2006" in the current language.
+func MonthYear(t time.Time) string {
+	return Month(t.Month(), false) + " " + fmt.Sprint(t.Year())
+}
+
+// Date writes the day of t in the configured date format; relative dates
+// count calendar days from now.
+func Date(t, now time.Time) string {
+	switch dateFormat {
+	case "dmy":
+		return t.Format("02.01.2006")
+	case "relative":
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		switch n := int(day.Sub(today).Hours() / 24); {
+		case n == 0:
+			return T("today")
+		case n == 1:
+			return T("tomorrow")
+		case n == -1:
+			return T("yesterday")
+		case n > 0:
+			return Tf("in %d days", n)
+		default:
+			return Tf("%d days ago", -n)
+		}
+	}
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,61 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocale(t *testing.T) {
+	defer SetLocale("en")
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	if got := LongDate(day); got != "Fri, 16 Oct 2026" {
+		t.Errorf("LongDate = %q", got)
+	}
+
+	t.Setenv("LANG", "pl_PL.UTF-8")
+	if err := SetLocale("auto"); err != nil {
+		t.Fatal(err)
+	}
+	if got := LongDate(day); got != "pt, 16 paź 2026" {
+		t.Errorf("Polish LongDate = %q", got)
+	}
+	if T("Quit") != "Wyjdź" || T("no such message") != "no such message" {
+		t.Errorf("T = %q, %q", T("Quit"), T("no such message"))
+	}
+
+	if SetLocale("xx") == nil || T("Quit") != "Quit" {
+		t.Error("unknown locale must fail and fall back to English")
+	}
+}
+
+func TestDateFormats(t *testing.T) {
+	defer SetDateFormat("iso")
+	now := time.Date(2026, 10, 16, 22, 0, 0, 0, time.Local)
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	for format, want := range map[string]string{"iso": "2026-10-19", "dmy": "19.10.2026", "relative": "in 3 days"} {
+		if err := SetDateFormat(format); err != nil {
+			t.Fatal(err)
+		}
+		if got := Date(day, now); got != want {
+			t.Errorf("%s: Date = %q, want %q", format, got, want)
+		}
+	}
+	SetDateFormat("relative")
+	if got := Date(now.AddDate(0, 0, -1), now); got != "yesterday" {
+		t.Errorf("relative yesterday = %q", got)
+	}
+	if SetDateFormat("us") == nil {
+		t.Error("unknown format accepted")
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	defer SetWeekStart("monday")
+	if WeekdayColumn(time.Sunday) != 6 || Weekdays()[0] != time.Monday {
+		t.Error("weeks must start on Monday by default")
+	}
+	SetWeekStart("sunday")
+	if WeekdayColumn(time.Sunday) != 0 || WeekdayColumn(time.Saturday) != 6 || Weekdays()[0] != time.Sunday {
+		t.Errorf("sunday start: columns %v", Weekdays())
+	}
+}
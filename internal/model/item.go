@@ -0,0 +1,33 @@
+// Package model holds the task tree – items with their inline metadata – and
+// the pure operations on it shared by the TUI, the CLI commands and serve.
+package model
+
+// --- DATA MODEL ---
+
+// Item is one checklist line or section heading; metadata lives inside Title.
+type Item struct {
+	Title     string
+	Done      bool
+	Level     int
+	Collapsed bool
+	// State is the mark of a custom checkbox state such as "~" in "- [~]",
+	// "" for a plain open or done task
+	State string
+	// Note holds the lines indented under the checklist line, without that
+	// indentation (attachments live there, see attach.go in the app)
+	Note []string
+	// Heading is the depth of a markdown heading ("#" is 1) standing for a
+	// section, 0 for a task. A section is the parent of what follows it.
+	Heading int
+	// Before holds the lines of the file the loader didn't understand (prose,
+	// blank lines, comments, front matter) found right above the item, and
+	// After those at the end of the file, which are written back at the end
+	// whatever item keeps them. See HandOverText.
+	Before, After []string
+}
+
+// VisibleItem is an item currently on screen, with its index in the list.
+type VisibleItem struct {
+	Index int
+	Data  Item
+}
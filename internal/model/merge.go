@@ -0,0 +1,105 @@
+package model
+
+import "slices"
+
+// --- THREE-WAY MERGE ---
+
+// Merge combines two lists changed independently from base, item by item
+// (the way diff3 does lines): a stretch changed on one side only takes that
+// side, and one changed on both keeps ours followed by what theirs added.
+// Items compare with SameItem.
+func Merge(base, ours, theirs []Item) []Item {
+	mo, mt := matchItems(base, ours), matchItems(base, theirs)
+	var out []Item
+	b, o, t := 0, 0, 0
+	for {
+		// Następny element bazy, który obie strony zostawiły bez zmian
+		i := b
+		for i < len(base) && (mo[i] == -1 || mt[i] == -1) {
+			i++
+		}
+		oEnd, tEnd := len(ours), len(theirs)
+		if i < len(base) {
+			oEnd, tEnd = mo[i], mt[i]
+		}
+		out = append(out, mergeChunk(base[b:i], ours[o:oEnd], theirs[t:tEnd])...)
+		if i == len(base) {
+			return out
+		}
+		out = append(out, ours[oEnd])
+		b, o, t = i+1, oEnd+1, tEnd+1
+	}
+}
+
+func mergeChunk(base, ours, theirs []Item) []Item {
+	switch {
+	case slices.EqualFunc(ours, base, SameItem):
+		return theirs
+	case slices.EqualFunc(theirs, base, SameItem), slices.EqualFunc(ours, theirs, SameItem):
+		return ours
+	}
+	out := slices.Clone(ours)
+	for _, it := range theirs {
+		if !slices.ContainsFunc(ours, func(x Item) bool { return SameItem(x, it) }) &&
+			!slices.ContainsFunc(base, func(x Item) bool { return SameItem(x, it) }) {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// matchItems pairs items of a with equal ones of b along their longest
+// common subsequence: match[i] is the index in b of a[i], or -1.
+func matchItems(a, b []Item) []int {
+	match := make([]int, len(a))
+	for i := range match {
+		match[i] = -1
+	}
+	// Zwykle zmienia się kilka elementów: wspólny początek i koniec odcinamy
+	pre := 0
+	for pre < len(a) && pre < len(b) && SameItem(a[pre], b[pre]) {
+		match[pre] = pre
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && SameItem(a[len(a)-1-suf], b[len(b)-1-suf]) {
+		match[len(a)-1-suf] = len(b) - 1 - suf
+		suf++
+	}
+	a, b = a[pre:len(a)-suf], b[pre:len(b)-suf]
+	if len(a) == 0 || len(b) == 0 {
+		return match
+	}
+
+	// lcs[i][j] to długość LCS dla a[i:] i b[j:]
+	w := len(b) + 1
+	lcs := make([]int32, (len(a)+1)*w)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if SameItem(a[i], b[j]) {
+				lcs[i*w+j] = lcs[(i+1)*w+j+1] + 1
+			} else {
+				lcs[i*w+j] = max(lcs[(i+1)*w+j], lcs[i*w+j+1])
+			}
+		}
+	}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case SameItem(a[i], b[j]):
+			match[pre+i] = pre + j
+			i++
+			j++
+		case lcs[(i+1)*w+j] >= lcs[i*w+j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return match
+}
+
+// SameItem tells whether x and y are written the same; folds don't count.
+func SameItem(x, y Item) bool {
+	return x.Title == y.Title && x.Done == y.Done && x.Level == y.Level && x.State == y.State && x.Heading == y.Heading &&
+		slices.Equal(x.Note, y.Note) && slices.Equal(x.Before, y.Before) && slices.Equal(x.After, y.After)
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	base := tree(0, "a", 1, "a1", 0, "b", 0, "c")
+	for _, c := range []struct {
+		name         string
+		ours, theirs []Item
+		want         []string
+	}{
+		{"theirs appended",
+			tree(0, "a", 1, "a1", 0, "b!", 0, "c"),
+			tree(0, "a", 1, "a1", 0, "b", 0, "c", 0, "new"),
+			[]string{"a", "a1", "b!", "c", "new"}},
+		{"both edited apart",
+			tree(0, "a", 1, "a1", 0, "c"),
+			tree(0, "a", 1, "a1", 1, "a2", 0, "b", 0, "c"),
+			[]string{"a", "a1", "a2", "c"}},
+		{"same edit",
+			tree(0, "a", 0, "b", 0, "c"),
+			tree(0, "a", 0, "b", 0, "c"),
+			[]string{"a", "b", "c"}},
+		{"conflict keeps both",
+			tree(0, "a", 1, "a1", 0, "b mine", 0, "c"),
+			tree(0, "a", 1, "a1", 0, "b theirs", 0, "c"),
+			[]string{"a", "a1", "b mine", "b theirs", "c"}},
+	} {
+		if got := titles(Merge(base, c.ours, c.theirs)); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: %q, want %q", c.name, got, c.want)
+		}
+	}
+	if got := titles(Merge(nil, tree(0, "x"), tree(0, "y"))); !reflect.DeepEqual(got, []string{"x", "y"}) {
+		t.Errorf("from nothing: %q", got)
+	}
+}
@@ -0,0 +1,261 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// --- INLINE METADATA ---
+//
+// Metadata lives inside the title as "key:value" tokens (e.g. "id:3f9a02c1"),
+// so the file stays plain markdown and survives editing in other tools.
+
+// HiddenMetaKeys are bookkeeping tokens which are never rendered in the UI.
+var HiddenMetaKeys = map[string]bool{
+	"id":        true,
+	"created":   true,
+	"pomo":      true,
+	"spent":     true,
+	"timer":     true,
+	"lock":      true,
+	"star":      true,
+	"feed":      true,
+	"from":      true,
+	"after":     true,
+	"by":        true,
+	"completed": true,
+	"reviewed":  true,
+	"ws":        true,
+	"anchor":    true,
+}
+
+const (
+	DateLayout     = "2006-01-02"
+	DateTimeLayout = "2006-01-02T15:04"
+	// DateTimeZoneLayout is how timed due dates are written: with the UTC
+	// offset in force on that day, so the file means the same instant on
+	// every machine and across DST changes.
+	DateTimeZoneLayout = "2006-01-02T15:04Z07:00"
+)
+
+func MetaValue(title, key string) string {
+	prefix := key + ":"
+	for _, tok := range strings.Fields(title) {
+		if strings.HasPrefix(tok, prefix) && len(tok) > len(prefix) {
+			return tok[len(prefix):]
+		}
+	}
+	return ""
+}
+
+// SetMeta replaces (or appends) the key:value token. An empty value removes it.
+func SetMeta(title, key, value string) string {
+	prefix := key + ":"
+	var out []string
+	replaced := false
+	for _, tok := range strings.Fields(title) {
+		if strings.HasPrefix(tok, prefix) && len(tok) > len(prefix) {
+			if value != "" && !replaced {
+				out = append(out, prefix+value)
+				replaced = true
+			}
+			continue
+		}
+		out = append(out, tok)
+	}
+	if value != "" && !replaced {
+		out = append(out, prefix+value)
+	}
+	return strings.Join(out, " ")
+}
+
+// StripMeta removes the given keys from the title.
+func StripMeta(title string, keys map[string]bool) string {
+	var out []string
+	for _, tok := range strings.Fields(title) {
+		if k, _, ok := strings.Cut(tok, ":"); ok && keys[k] {
+			continue
+		}
+		out = append(out, tok)
+	}
+	return strings.Join(out, " ")
+}
+
+// RowStyleWords are the "%red"-style words that color or emphasize the row
+// of a task instead of being shown.
+var RowStyleWords = map[string]bool{
+	"red": true, "orange": true, "yellow": true, "green": true, "blue": true, "purple": true, "gray": true,
+	"bold": true, "italic": true, "underline": true,
+}
+
+// RowStyle returns the row style words of a title, without the "%".
+func RowStyle(title string) []string {
+	var out []string
+	for _, tok := range strings.Fields(title) {
+		if w, ok := strings.CutPrefix(tok, "%"); ok && RowStyleWords[w] {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// DisplayTitle is the title as shown to the user, with a timed due date in
+// local time and without row style words.
+func DisplayTitle(title string) string {
+	title = StripMeta(title, HiddenMetaKeys)
+	if len(RowStyle(title)) > 0 {
+		var out []string
+		for _, tok := range strings.Fields(title) {
+			if w, ok := strings.CutPrefix(tok, "%"); !ok || !RowStyleWords[w] {
+				out = append(out, tok)
+			}
+		}
+		title = strings.Join(out, " ")
+	}
+	if due, hasTime, ok := DueTime(title); ok && hasTime {
+		title = SetMeta(title, "due", due.Format(DateTimeLayout))
+	}
+	return title
+}
+
+// Tags returns the #tags of a title in order of appearance (duplicates kept).
+func Tags(title string) []string {
+	var tags []string
+	for _, tok := range strings.Fields(title) {
+		if len(tok) > 1 && tok[0] == '#' {
+			tags = append(tags, tok[1:])
+		}
+	}
+	return tags
+}
+
+// SetTag adds or removes a #tag token.
+func SetTag(title, tag string, on bool) string {
+	var out []string
+	found := false
+	for _, tok := range strings.Fields(title) {
+		if tok == "#"+tag {
+			if !on || found {
+				continue
+			}
+			found = true
+		}
+		out = append(out, tok)
+	}
+	if on && !found {
+		out = append(out, "#"+tag)
+	}
+	return strings.Join(out, " ")
+}
+
+// MetaValues returns every value of the key, splitting comma-separated lists.
+func MetaValues(title, key string) []string {
+	prefix := key + ":"
+	var out []string
+	for _, tok := range strings.Fields(title) {
+		if strings.HasPrefix(tok, prefix) {
+			for _, v := range strings.Split(tok[len(prefix):], ",") {
+				if v != "" {
+					out = append(out, v)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// DueTime parses the due:YYYY-MM-DD[THH:MM[±hh:mm|Z]] token into local time.
+// Times without an offset (older files) are taken as local. hasTime is false
+// for date-only values, which are due at the start of that day wherever you are.
+func DueTime(title string) (due time.Time, hasTime bool, ok bool) {
+	v := MetaValue(title, "due")
+	if v == "" {
+		return time.Time{}, false, false
+	}
+	if t, err := time.Parse(DateTimeZoneLayout, v); err == nil {
+		return t.In(time.Local), true, true
+	}
+	if t, err := time.ParseInLocation(DateTimeLayout, v, time.Local); err == nil {
+		return t, true, true
+	}
+	if t, err := time.ParseInLocation(DateLayout, v, time.Local); err == nil {
+		return t, false, true
+	}
+	return time.Time{}, false, false
+}
+
+// FormatDue is the due:value for t: a floating date, or the time with the
+// offset of t's zone on that day.
+func FormatDue(t time.Time, hasTime bool) string {
+	if !hasTime {
+		return t.Format(DateLayout)
+	}
+	return t.Format(DateTimeZoneLayout)
+}
+
+// LocalDue is the due time of a title at the given wall clock ("15:04") on day
+// in the local zone, as a due:value. An empty clock gives a date-only value.
+func LocalDue(day time.Time, clock string) string {
+	if clock == "" {
+		return day.Format(DateLayout)
+	}
+	c, err := time.Parse("15:04", clock)
+	if err != nil {
+		return day.Format(DateLayout)
+	}
+	t := time.Date(day.Year(), day.Month(), day.Day(), c.Hour(), c.Minute(), 0, 0, time.Local)
+	return FormatDue(t, true)
+}
+
+// FormatDuration renders durations as "1h 05m" / "12m".
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := int(d.Hours())
+	mins := int(d.Minutes()) % 60
+	if h > 0 {
+		return fmt.Sprintf("%dh %02dm", h, mins)
+	}
+	return fmt.Sprintf("%dm", mins)
+}
+
+func NewID() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func ID(it Item) string {
+	return MetaValue(it.Title, "id")
+}
+
+// EnsureIDs gives every item a stable id. Returns true if anything changed.
+func EnsureIDs(items []Item) bool {
+	changed := false
+	for i := range items {
+		if ID(items[i]) == "" {
+			items[i].Title = SetMeta(items[i].Title, "id", NewID())
+			changed = true
+		}
+	}
+	return changed
+}
+
+func FindByID(items []Item, id string) int {
+	if id == "" {
+		return -1
+	}
+	for i, it := range items {
+		if ID(it) == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func StartOfDay(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, t.Location())
+}
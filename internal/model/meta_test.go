@@ -0,0 +1,157 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSetMeta(t *testing.T) {
+	tests := []struct {
+		title, key, value, want string
+	}{
+		{"Buy milk", "due", "2026-01-02", "Buy milk due:2026-01-02"},
+		{"Buy milk due:2026-01-02 #home", "due", "2026-02-03", "Buy milk due:2026-02-03 #home"},
+		{"Buy milk due:2026-01-02 #home", "due", "", "Buy milk #home"},
+		{"Buy milk", "due", "", "Buy milk"},
+	}
+	for _, tt := range tests {
+		if got := SetMeta(tt.title, tt.key, tt.value); got != tt.want {
+			t.Errorf("SetMeta(%q, %q, %q) = %q, want %q", tt.title, tt.key, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMetaValueAndDisplayTitle(t *testing.T) {
+	title := "Write report #work id:abc created:2026-01-01 pri:A blocked:x,y"
+	if got := MetaValue(title, "pri"); got != "A" {
+		t.Errorf("pri = %q", got)
+	}
+	if got := MetaValue(title, "due"); got != "" {
+		t.Errorf("missing key = %q", got)
+	}
+	if got := MetaValues(title, "blocked"); !reflect.DeepEqual(got, []string{"x", "y"}) {
+		t.Errorf("blocked = %v", got)
+	}
+	if got := DisplayTitle(title); got != "Write report #work pri:A blocked:x,y" {
+		t.Errorf("DisplayTitle = %q", got)
+	}
+}
+
+func TestRowStyle(t *testing.T) {
+	title := "Fix build %red %bold 50% %d"
+	if got := RowStyle(title); !reflect.DeepEqual(got, []string{"red", "bold"}) {
+		t.Errorf("RowStyle = %v", got)
+	}
+	if got := DisplayTitle(title); got != "Fix build 50% %d" {
+		t.Errorf("DisplayTitle = %q", got)
+	}
+}
+
+func TestTags(t *testing.T) {
+	title := "Plan #work trip #home"
+	if got := Tags(title); !reflect.DeepEqual(got, []string{"work", "home"}) {
+		t.Errorf("Tags = %v", got)
+	}
+	if got := SetTag(title, "home", false); got != "Plan #work trip" {
+		t.Errorf("remove tag = %q", got)
+	}
+	if got := SetTag(title, "urgent", true); got != "Plan #work trip #home #urgent" {
+		t.Errorf("add tag = %q", got)
+	}
+	if got := SetTag(title, "work", true); got != title {
+		t.Errorf("adding an existing tag changed the title: %q", got)
+	}
+}
+
+func TestDueTime(t *testing.T) {
+	due, hasTime, ok := DueTime("x due:2026-03-04T09:30")
+	if !ok || !hasTime || due.Hour() != 9 || due.Minute() != 30 {
+		t.Errorf("with time: %v %v %v", due, hasTime, ok)
+	}
+	due, hasTime, ok = DueTime("x due:2026-03-04")
+	if !ok || hasTime || !due.Equal(time.Date(2026, 3, 4, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("date only: %v %v %v", due, hasTime, ok)
+	}
+	if _, _, ok := DueTime("x due:tomorrow"); ok {
+		t.Error("invalid date parsed")
+	}
+}
+
+// inZone runs fn with time.Local set to the named zone.
+func inZone(t *testing.T, name string, fn func()) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("no tzdata for %s: %v", name, err)
+	}
+	saved := time.Local
+	time.Local = loc
+	defer func() { time.Local = saved }()
+	fn()
+}
+
+func TestDueTimeZones(t *testing.T) {
+	title := "call due:2026-01-30T14:00+01:00"
+	inZone(t, "America/New_York", func() {
+		due, hasTime, ok := DueTime(title)
+		if !ok || !hasTime || due.Hour() != 8 || due.Location() != time.Local {
+			t.Errorf("New York sees %v", due)
+		}
+		if got := DisplayTitle(title); got != "call due:2026-01-30T08:00" {
+			t.Errorf("DisplayTitle = %q", got)
+		}
+		// Daty bez godziny są "pływające": ten sam dzień w każdej strefie
+		if due, _, _ := DueTime("x due:2026-01-30"); due.Day() != 30 {
+			t.Errorf("date-only due moved to %v", due)
+		}
+	})
+}
+
+func TestLocalDueAcrossDST(t *testing.T) {
+	inZone(t, "Europe/Warsaw", func() {
+		if got := LocalDue(time.Date(2026, 3, 28, 0, 0, 0, 0, time.Local), "09:00"); got != "2026-03-28T09:00+01:00" {
+			t.Errorf("before DST = %q", got)
+		}
+		if got := LocalDue(time.Date(2026, 3, 30, 0, 0, 0, 0, time.Local), "09:00"); got != "2026-03-30T09:00+02:00" {
+			t.Errorf("after DST = %q", got)
+		}
+		// Następne wystąpienie zachowuje godzinę zegarową mimo zmiany czasu
+		due, _, _ := DueTime("x due:2026-03-28T09:00+01:00")
+		next, _, _ := NextOccurrence(due, time.Time{}, "daily", Calendar{}, false, due)
+		next, _, _ = NextOccurrence(next, time.Time{}, "daily", Calendar{}, false, next)
+		if got := FormatDue(next, true); got != "2026-03-30T09:00+02:00" {
+			t.Errorf("daily across DST = %q", got)
+		}
+	})
+	if got := LocalDue(time.Date(2026, 3, 30, 0, 0, 0, 0, time.Local), ""); got != "2026-03-30" {
+		t.Errorf("no clock = %q", got)
+	}
+}
+
+func TestIDs(t *testing.T) {
+	items := []Item{{Title: "a id:12345678"}, {Title: "b"}}
+	if !EnsureIDs(items) {
+		t.Fatal("EnsureIDs reported no change")
+	}
+	if EnsureIDs(items) {
+		t.Error("second EnsureIDs changed ids")
+	}
+	if ID(items[0]) != "12345678" || len(ID(items[1])) != 8 {
+		t.Errorf("ids = %q, %q", ID(items[0]), ID(items[1]))
+	}
+	if FindByID(items, ID(items[1])) != 1 || FindByID(items, "nope") != -1 || FindByID(items, "") != -1 {
+		t.Error("FindByID")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		12 * time.Minute: "12m",
+		65 * time.Minute: "1h 05m",
+		2 * time.Hour:    "2h 00m",
+	} {
+		if got := FormatDuration(d); got != want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
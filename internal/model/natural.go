@@ -0,0 +1,219 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- NATURAL-LANGUAGE DATES ---
+//
+// ParseDate understands what people type for a due date, in English:
+//
+//	today, tomorrow (tmr), yesterday
+//	friday, fri, this friday, next friday   the coming one, never today
+//	next week / month / year                its first day (weeks start on Monday)
+//	in 3 days, in a week, in 2 months, 3d, +2w, 1m, 1y
+//	eow, eom, eoy, end of month             Friday, or the last day of the month or year
+//	oct 20, 20 oct, 20.10, 20.10.2026, 2026-10-20
+//
+// Words may be joined with "-" or "_" (due:next-friday) and a phrase may end
+// with a time: "tomorrow 9:30", "fri at 14:00".
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var monthNames = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "sept": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+// ParseDate reads s as a date relative to now, in now's location. hasTime
+// is set when s ends with a time of day.
+func ParseDate(s string, now time.Time) (t time.Time, hasTime bool, ok bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{DateTimeZoneLayout, DateTimeLayout} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, true, true
+		}
+	}
+	if t, err := time.ParseInLocation(DateLayout, s, now.Location()); err == nil {
+		return t, false, true
+	}
+
+	words := strings.Fields(strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(s)))
+	var clock time.Time
+	if n := len(words); n > 1 {
+		if c, err := time.Parse("15:04", words[n-1]); err == nil {
+			clock, hasTime = c, true
+			words = words[:n-1]
+			if words[len(words)-1] == "at" {
+				words = words[:len(words)-1]
+			}
+		}
+	}
+	day, ok := parseDay(words, StartOfDay(now))
+	if !ok {
+		return time.Time{}, false, false
+	}
+	if hasTime {
+		day = time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, day.Location())
+	}
+	return day, hasTime, true
+}
+
+func parseDay(words []string, today time.Time) (time.Time, bool) {
+	switch len(words) {
+	case 1:
+		return parseWord(words[0], today)
+	case 2:
+		switch words[0] {
+		case "this":
+			if wd, ok := weekdayNames[words[1]]; ok {
+				return nextWeekday(today, wd), true
+			}
+		case "next":
+			if wd, ok := weekdayNames[words[1]]; ok {
+				return nextWeekday(today, wd), true
+			}
+			switch words[1] {
+			case "week":
+				return nextWeekday(today, time.Monday), true
+			case "month":
+				return time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location()), true
+			case "year":
+				return time.Date(today.Year()+1, time.January, 1, 0, 0, 0, 0, today.Location()), true
+			}
+		}
+		// "oct 20" i "20 oct"
+		if m, ok := monthNames[monthKey(words[0])]; ok {
+			return nextMonthDay(today, m, words[1])
+		}
+		if m, ok := monthNames[monthKey(words[1])]; ok {
+			return nextMonthDay(today, m, words[0])
+		}
+	case 3:
+		if words[0] == "end" && words[1] == "of" {
+			return parseWord("eo"+words[2][:1], today)
+		}
+		if words[0] == "in" {
+			n, err := strconv.Atoi(words[1])
+			if words[1] == "a" || words[1] == "an" {
+				n, err = 1, nil
+			}
+			if err == nil && n >= 0 {
+				return addUnits(today, n, strings.TrimSuffix(words[2], "s"))
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseWord(w string, today time.Time) (time.Time, bool) {
+	switch w {
+	case "today", "tod", "now":
+		return today, true
+	case "tomorrow", "tmr", "tom":
+		return today.AddDate(0, 0, 1), true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "eow":
+		if today.Weekday() == time.Friday {
+			return today, true
+		}
+		return nextWeekday(today, time.Friday), true
+	case "eom":
+		return time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, today.Location()), true
+	case "eoy":
+		return time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, today.Location()), true
+	}
+	if wd, ok := weekdayNames[w]; ok {
+		return nextWeekday(today, wd), true
+	}
+	// 3d, +2w, 1m, 1y
+	if n, err := strconv.Atoi(strings.TrimPrefix(w[:len(w)-1], "+")); err == nil && n >= 0 && len(w) > 1 {
+		units := map[byte]string{'d': "day", 'w': "week", 'm': "month", 'y': "year"}
+		if unit, ok := units[w[len(w)-1]]; ok {
+			return addUnits(today, n, unit)
+		}
+	}
+	// 20.10, 20.10. i 20.10.2026
+	if parts := strings.Split(strings.TrimSuffix(w, "."), "."); len(parts) == 2 || len(parts) == 3 {
+		d, err1 := strconv.Atoi(parts[0])
+		m, err2 := strconv.Atoi(parts[1])
+		if err1 != nil || err2 != nil || m < 1 || m > 12 {
+			return time.Time{}, false
+		}
+		if len(parts) == 2 {
+			return nextMonthDay(today, time.Month(m), parts[0])
+		}
+		y, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return time.Time{}, false
+		}
+		return validDate(y, time.Month(m), d, today.Location())
+	}
+	return time.Time{}, false
+}
+
+// nextWeekday is the first wd after today.
+func nextWeekday(today time.Time, wd time.Weekday) time.Time {
+	days := (int(wd) - int(today.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+// nextMonthDay is the day in month m from today on, this year or the next.
+func nextMonthDay(today time.Time, m time.Month, day string) (time.Time, bool) {
+	d, err := strconv.Atoi(day)
+	if err != nil {
+		return time.Time{}, false
+	}
+	t, ok := validDate(today.Year(), m, d, today.Location())
+	if ok && t.Before(today) {
+		t, ok = validDate(today.Year()+1, m, d, today.Location())
+	}
+	return t, ok
+}
+
+func validDate(y int, m time.Month, d int, loc *time.Location) (time.Time, bool) {
+	t := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	return t, t.Day() == d && t.Month() == m
+}
+
+// addUnits moves today by n days, weeks, months or years; months and years
+// stop at the end of a shorter month (Jan 31 + 1 month = Feb 28).
+func addUnits(today time.Time, n int, unit string) (time.Time, bool) {
+	switch unit {
+	case "day":
+		return today.AddDate(0, 0, n), true
+	case "week":
+		return today.AddDate(0, 0, 7*n), true
+	case "month", "year":
+		months := n
+		if unit == "year" {
+			months = 12 * n
+		}
+		first := time.Date(today.Year(), today.Month()+time.Month(months), 1, 0, 0, 0, 0, today.Location())
+		last := first.AddDate(0, 1, -1).Day()
+		return time.Date(first.Year(), first.Month(), min(today.Day(), last), 0, 0, 0, 0, today.Location()), true
+	}
+	return time.Time{}, false
+}
+
+func monthKey(w string) string {
+	if len(w) > 3 && w != "sept" {
+		return w[:3]
+	}
+	return w
+}
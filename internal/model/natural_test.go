@@ -0,0 +1,58 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2026, 10, 16, 18, 30, 0, 0, time.Local) // piątek
+	for in, want := range map[string]string{
+		"today":          "2026-10-16",
+		"Tomorrow":       "2026-10-17",
+		"tmr":            "2026-10-17",
+		"yesterday":      "2026-10-15",
+		"friday":         "2026-10-23",
+		"mon":            "2026-10-19",
+		"next-friday":    "2026-10-23",
+		"this wednesday": "2026-10-21",
+		"next week":      "2026-10-19",
+		"next month":     "2026-11-01",
+		"next year":      "2027-01-01",
+		"in 2 weeks":     "2026-10-30",
+		"in_a_week":      "2026-10-23",
+		"in 3 days":      "2026-10-19",
+		"3d":             "2026-10-19",
+		"+2w":            "2026-10-30",
+		"1m":             "2026-11-16",
+		"1y":             "2027-10-16",
+		"eow":            "2026-10-16",
+		"eom":            "2026-10-31",
+		"end of month":   "2026-10-31",
+		"eoy":            "2026-12-31",
+		"oct 20":         "2026-10-20",
+		"20 october":     "2026-10-20",
+		"oct 1":          "2027-10-01",
+		"20.10":          "2026-10-20",
+		"1.2.2027":       "2027-02-01",
+		"2026-12-24":     "2026-12-24",
+	} {
+		got, hasTime, ok := ParseDate(in, now)
+		if !ok || hasTime || got.Format(DateLayout) != want {
+			t.Errorf("ParseDate(%q) = %s, %v, %v; want %s", in, got.Format(DateLayout), hasTime, ok, want)
+		}
+	}
+
+	if got, hasTime, ok := ParseDate("fri at 9:30", now); !ok || !hasTime || got.Format(DateTimeLayout) != "2026-10-23T09:30" {
+		t.Errorf("fri at 9:30 = %v, %v, %v", got, hasTime, ok)
+	}
+	jan31 := time.Date(2027, 1, 31, 0, 0, 0, 0, time.Local)
+	if got, _, _ := ParseDate("in 1 month", jan31); got.Format(DateLayout) != "2027-02-28" {
+		t.Errorf("Jan 31 + 1 month = %s", got.Format(DateLayout))
+	}
+	for _, bad := range []string{"", "someday", "31.2", "in many days", "next fortnight", "13.13.2026"} {
+		if _, _, ok := ParseDate(bad, now); ok {
+			t.Errorf("ParseDate(%q) accepted", bad)
+		}
+	}
+}
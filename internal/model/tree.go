@@ -0,0 +1,295 @@
+package model
+
+import "slices"
+
+// --- TREE HELPERS ---
+
+// SubtreeEnd returns the index just past the last descendant of items[idx].
+func SubtreeEnd(items []Item, idx int) int {
+	end := idx + 1
+	for end < len(items) && items[end].Level > items[idx].Level {
+		end++
+	}
+	return end
+}
+
+// ParentIndex returns the index of the parent of items[idx], or -1 for top-level items.
+func ParentIndex(items []Item, idx int) int {
+	for i := idx - 1; i >= 0; i-- {
+		if items[i].Level < items[idx].Level {
+			return i
+		}
+	}
+	return -1
+}
+
+// IndentSubtree moves items[idx] (with children) one level deeper, under its
+// previous sibling. Returns false if there is no sibling to nest under.
+func IndentSubtree(items []Item, idx int) bool {
+	if idx == 0 || items[idx-1].Level < items[idx].Level {
+		return false
+	}
+	end := SubtreeEnd(items, idx)
+	for k := idx; k < end; k++ {
+		items[k].Level++
+	}
+	return true
+}
+
+// OutdentSubtree moves items[idx] (with children) one level up.
+func OutdentSubtree(items []Item, idx int) bool {
+	if items[idx].Level == 0 {
+		return false
+	}
+	end := SubtreeEnd(items, idx)
+	for k := idx; k < end; k++ {
+		items[k].Level--
+	}
+	return true
+}
+
+// DeleteSubtree moves items[idx] together with its children to the trash.
+// The root remembers where it was (see RestoreSubtree): from: is the id of
+// its parent ("-" at the top level) and after: the id of the sibling before
+// it. Both neighbours get an id if they had none.
+func DeleteSubtree(items, trash []Item, idx int) ([]Item, []Item) {
+	end := SubtreeEnd(items, idx)
+	HandOverText(items, idx, end)
+	deleted := make([]Item, end-idx)
+	copy(deleted, items[idx:end])
+
+	from := "-"
+	if p := ParentIndex(items, idx); p != -1 {
+		from = ensureID(items, p)
+	}
+	deleted[0].Title = SetMeta(deleted[0].Title, "from", from)
+	if prev := prevSibling(items, idx); prev != -1 {
+		deleted[0].Title = SetMeta(deleted[0].Title, "after", ensureID(items, prev))
+	}
+
+	trash = append(trash, deleted...)
+	items = append(items[:idx], items[end:]...)
+	return items, trash
+}
+
+// HandOverText passes the loose lines of the file kept by items[idx:end] to
+// the items staying around them, so taking a subtree out of the list leaves
+// the prose where it was. With nothing left around them they stay put.
+func HandOverText(items []Item, idx, end int) {
+	if idx == 0 && end == len(items) {
+		return
+	}
+	var before, after []string
+	for k := idx; k < end; k++ {
+		before = append(before, items[k].Before...)
+		after = append(after, items[k].After...)
+		items[k].Before, items[k].After = nil, nil
+	}
+	if end < len(items) {
+		items[end].Before = append(before, items[end].Before...)
+	} else {
+		after = append(before, after...)
+	}
+	if len(after) > 0 {
+		last := len(items) - 1
+		if end == len(items) {
+			last = idx - 1
+		}
+		items[last].After = append(items[last].After, after...)
+	}
+}
+
+func ensureID(items []Item, idx int) string {
+	if ID(items[idx]) == "" {
+		items[idx].Title = SetMeta(items[idx].Title, "id", NewID())
+	}
+	return ID(items[idx])
+}
+
+// prevSibling returns the sibling right before items[idx], or -1.
+func prevSibling(items []Item, idx int) int {
+	for i := idx - 1; i >= 0 && items[i].Level >= items[idx].Level; i-- {
+		if items[i].Level == items[idx].Level {
+			return i
+		}
+	}
+	return -1
+}
+
+// MoveSubtree re-parents the subtree at from as the last child of parent
+// (-1 for the top level, at the end of the list), shifting the levels of the
+// whole subtree. It returns the new items and the new index of the moved
+// item; ok is false when parent lies inside the moved subtree.
+func MoveSubtree(items []Item, from, parent int) (moved []Item, idx int, ok bool) {
+	end := SubtreeEnd(items, from)
+	if parent >= from && parent < end {
+		return items, from, false
+	}
+	block := slices.Clone(items[from:end])
+	rest := slices.Delete(slices.Clone(items), from, end)
+
+	level, at := 0, len(rest)
+	if parent != -1 {
+		if parent >= end {
+			parent -= end - from
+		}
+		level, at = rest[parent].Level+1, SubtreeEnd(rest, parent)
+		rest[parent].Collapsed = false
+	}
+	delta := level - block[0].Level
+	for k := range block {
+		block[k].Level += delta
+	}
+	return slices.Insert(rest, at, block...), at, true
+}
+
+// DuplicateSubtree inserts a copy of the subtree at idx right after it, with
+// every task reopened, and returns the index of the copy.
+func DuplicateSubtree(items []Item, idx int) ([]Item, int) {
+	end := SubtreeEnd(items, idx)
+	block := slices.Clone(items[idx:end])
+	for k := range block {
+		block[k].Done = false
+		block[k].Before, block[k].After = nil, nil
+	}
+	return slices.Insert(items, end, block...), end
+}
+
+// TrashBlock returns the bounds of the deleted subtree containing trash[idx]:
+// a root marked with from: (or, for entries deleted before that existed, any
+// shallower line) and the deeper lines following it.
+func TrashBlock(trash []Item, idx int) (start, end int) {
+	start = idx
+	for start > 0 && MetaValue(trash[start].Title, "from") == "" && trash[start-1].Level < trash[start].Level {
+		start--
+	}
+	end = start + 1
+	for end < len(trash) && trash[end].Level > trash[start].Level && MetaValue(trash[end].Title, "from") == "" {
+		end++
+	}
+	return start, end
+}
+
+// RestoreSubtree moves the deleted subtree containing trash[idx] back where
+// it was: under its old parent, after its old previous sibling. If the
+// sibling is gone it becomes the parent's last child; if the parent is gone
+// (or the entry predates from:) it goes to the end of the list at the top
+// level. It returns the index of the restored root.
+func RestoreSubtree(items, trash []Item, idx int) ([]Item, []Item, int) {
+	start, end := TrashBlock(trash, idx)
+	block := slices.Clone(trash[start:end])
+	trash = slices.Delete(trash, start, end)
+
+	root := block[0].Title
+	from, after := MetaValue(root, "from"), MetaValue(root, "after")
+	block[0].Title = SetMeta(SetMeta(root, "from", ""), "after", "")
+
+	parent, at := -1, len(items)
+	if from != "" && from != "-" {
+		parent = FindByID(items, from)
+	}
+	switch {
+	case parent == -1 && from != "-":
+		after = "-" // rodzic zniknął: na koniec listy
+	case parent != -1:
+		at = SubtreeEnd(items, parent)
+	}
+	switch prev := FindByID(items, after); {
+	case after == "":
+		at = parent + 1
+	case prev != -1 && ParentIndex(items, prev) == parent:
+		at = SubtreeEnd(items, prev)
+	}
+
+	level := 0
+	if parent != -1 {
+		level = items[parent].Level + 1
+		items[parent].Collapsed = false
+	}
+	delta := level - block[0].Level
+	for k := range block {
+		block[k].Level += delta
+	}
+	return slices.Insert(items, at, block...), trash, at
+}
+
+// HasChildren reports whether items[idx] has at least one descendant.
+func HasChildren(items []Item, idx int) bool {
+	return idx+1 < len(items) && items[idx+1].Level > items[idx].Level
+}
+
+// ToggleFold collapses or expands items[idx]. Leaves cannot be folded.
+func ToggleFold(items []Item, idx int) bool {
+	if !HasChildren(items, idx) {
+		return false
+	}
+	items[idx].Collapsed = !items[idx].Collapsed
+	return true
+}
+
+// Visible lists the items not hidden inside a collapsed parent. hide (may be
+// nil) removes an item together with its whole subtree.
+func Visible(items []Item, hide func(Item) bool) []VisibleItem {
+	visible := []VisibleItem{}
+	skipLevel := -1
+	for i, it := range items {
+		if skipLevel != -1 {
+			if it.Level > skipLevel {
+				continue
+			}
+			skipLevel = -1
+		}
+		if hide != nil && hide(it) {
+			skipLevel = it.Level
+			continue
+		}
+		visible = append(visible, VisibleItem{Index: i, Data: it})
+		if it.Collapsed {
+			skipLevel = it.Level
+		}
+	}
+	return visible
+}
+
+// RefoldVisible updates visible after the item shown at visible[pos] was
+// folded or unfolded. Only that item's subtree is walked, so a toggle costs
+// the size of the subtree rather than of the whole list.
+func RefoldVisible(items []Item, visible []VisibleItem, pos int, hide func(Item) bool) []VisibleItem {
+	idx := visible[pos].Index
+	visible[pos].Data = items[idx]
+	subEnd := SubtreeEnd(items, idx)
+	end := pos + 1
+	for end < len(visible) && visible[end].Index < subEnd {
+		end++
+	}
+	var shown []VisibleItem
+	if !items[idx].Collapsed {
+		shown = Visible(items[idx+1:subEnd], hide)
+		for i := range shown {
+			shown[i].Index += idx + 1
+		}
+	}
+	return slices.Replace(visible, pos+1, end, shown...)
+}
+
+// VisiblePos returns the position of items[idx] in visible, or -1 when it is
+// hidden. visible is normally ordered by index, so this is a binary search;
+// a list shown in another order is scanned instead.
+func VisiblePos(visible []VisibleItem, idx int) int {
+	pos, ok := slices.BinarySearchFunc(visible, idx, func(v VisibleItem, idx int) int { return v.Index - idx })
+	if !ok {
+		return slices.IndexFunc(visible, func(v VisibleItem) bool { return v.Index == idx })
+	}
+	return pos
+}
+
+// VisibleMatching lists the items with keep[i] set, ignoring folds.
+func VisibleMatching(items []Item, keep []bool) []VisibleItem {
+	visible := []VisibleItem{}
+	for i, it := range items {
+		if keep[i] {
+			visible = append(visible, VisibleItem{Index: i, Data: it})
+		}
+	}
+	return visible
+}
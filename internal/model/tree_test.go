@@ -0,0 +1,315 @@
+package model
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// tree builds items from "level:title" pairs.
+func tree(spec ...any) []Item {
+	var items []Item
+	for i := 0; i < len(spec); i += 2 {
+		items = append(items, Item{Level: spec[i].(int), Title: spec[i+1].(string)})
+	}
+	return items
+}
+
+func titles(items []Item) []string {
+	out := []string{}
+	for _, it := range items {
+		out = append(out, it.Title)
+	}
+	return out
+}
+
+func TestSubtreeEndAndParent(t *testing.T) {
+	items := tree(0, "a", 1, "a1", 2, "a1x", 1, "a2", 0, "b")
+	if got := SubtreeEnd(items, 0); got != 4 {
+		t.Errorf("SubtreeEnd(a) = %d, want 4", got)
+	}
+	if got := SubtreeEnd(items, 1); got != 3 {
+		t.Errorf("SubtreeEnd(a1) = %d, want 3", got)
+	}
+	if got := SubtreeEnd(items, 4); got != 5 {
+		t.Errorf("SubtreeEnd(b) = %d, want 5", got)
+	}
+	for idx, want := range []int{-1, 0, 1, 0, -1} {
+		if got := ParentIndex(items, idx); got != want {
+			t.Errorf("ParentIndex(%d) = %d, want %d", idx, got, want)
+		}
+	}
+}
+
+func shown(items []Item) []string {
+	out := []string{}
+	for _, it := range items {
+		out = append(out, DisplayTitle(it.Title))
+	}
+	return out
+}
+
+func TestDeleteAndRestoreSubtree(t *testing.T) {
+	items := tree(0, "a", 1, "a1", 2, "a1x", 1, "a2", 0, "b")
+	items, trash := DeleteSubtree(items, nil, 1)
+	if want := []string{"a", "a2", "b"}; !reflect.DeepEqual(shown(items), want) {
+		t.Fatalf("items = %v, want %v", shown(items), want)
+	}
+	if want := []string{"a1", "a1x"}; !reflect.DeepEqual(shown(trash), want) {
+		t.Fatalf("trash = %v, want %v", shown(trash), want)
+	}
+
+	// a subtree goes back to its old place, children included
+	items, trash, idx := RestoreSubtree(items, trash, 1)
+	if want := []string{"a", "a1", "a1x", "a2", "b"}; !reflect.DeepEqual(shown(items), want) {
+		t.Errorf("items after restore = %v, want %v", shown(items), want)
+	}
+	if idx != 1 || items[1].Level != 1 || items[2].Level != 2 || MetaValue(items[1].Title, "from") != "" {
+		t.Errorf("restored root = %d %+v", idx, items[1])
+	}
+	if len(trash) != 0 {
+		t.Errorf("trash after restore has %d items, want 0", len(trash))
+	}
+}
+
+func TestRestoreSubtreeFallbacks(t *testing.T) {
+	items := tree(0, "a", 1, "a1", 1, "a2", 1, "a3", 0, "b")
+	items, trash := DeleteSubtree(items, nil, 2)  // a2, after a1
+	items, trash = DeleteSubtree(items, trash, 1) // a1, first child
+	items, trash = DeleteSubtree(items, trash, 2) // b, top level after a
+
+	items, trash, _ = RestoreSubtree(items, trash, 1) // a1 first again
+	if want := []string{"a", "a1", "a3"}; !reflect.DeepEqual(shown(items), want) {
+		t.Errorf("first child: %v", shown(items))
+	}
+	items = items[:2] // a3 gone, a2 still follows a1
+	items, trash, _ = RestoreSubtree(items, trash, 0)
+	if want := []string{"a", "a1", "a2"}; !reflect.DeepEqual(shown(items), want) {
+		t.Errorf("after sibling: %v", shown(items))
+	}
+
+	// the parent is gone: end of the list, top level
+	items = tree(0, "p", 1, "child", 2, "grandchild", 0, "q")
+	items, trash = DeleteSubtree(items, nil, 1)
+	items, trash = DeleteSubtree(items, trash, 0)
+	trash = trash[:2] // p purged
+	items, trash, idx := RestoreSubtree(items, trash, 1)
+	if want := []string{"q", "child", "grandchild"}; idx != 1 || !reflect.DeepEqual(shown(items), want) || items[1].Level != 0 || items[2].Level != 1 {
+		t.Errorf("parent gone: %+v idx %d", items, idx)
+	}
+
+	// entries deleted before from: existed are blocks by level
+	trash = []Item{{Title: "x", Level: 1}, {Title: "legacy", Level: 2}, {Title: "y", Level: 1}}
+	if s, e := TrashBlock(trash, 1); s != 0 || e != 2 {
+		t.Errorf("legacy block = %d..%d", s, e)
+	}
+	items, _, idx = RestoreSubtree(items, trash, 1)
+	if idx != 3 || items[3].Title != "x" || items[3].Level != 0 || items[4].Level != 1 {
+		t.Errorf("legacy restore: %+v", items)
+	}
+}
+
+func TestIndentOutdent(t *testing.T) {
+	items := tree(0, "a", 0, "b", 1, "b1")
+	if IndentSubtree(items, 0) {
+		t.Error("first item must not indent")
+	}
+	if !IndentSubtree(items, 1) {
+		t.Fatal("b should indent under a")
+	}
+	if items[1].Level != 1 || items[2].Level != 2 {
+		t.Errorf("levels after indent = %d,%d, want 1,2", items[1].Level, items[2].Level)
+	}
+	if IndentSubtree(items, 1) {
+		t.Error("b has no sibling above to nest under anymore")
+	}
+
+	if !OutdentSubtree(items, 1) {
+		t.Fatal("b should outdent")
+	}
+	if items[1].Level != 0 || items[2].Level != 1 {
+		t.Errorf("levels after outdent = %d,%d, want 0,1", items[1].Level, items[2].Level)
+	}
+	if OutdentSubtree(items, 0) {
+		t.Error("top-level item must not outdent")
+	}
+}
+
+func TestToggleFoldAndVisible(t *testing.T) {
+	items := tree(0, "a", 1, "a1", 2, "a1x", 0, "b", 1, "b1")
+	indices := func(v []VisibleItem) []int {
+		out := []int{}
+		for _, it := range v {
+			out = append(out, it.Index)
+		}
+		return out
+	}
+
+	if got := indices(Visible(items, nil)); !reflect.DeepEqual(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("unfolded = %v", got)
+	}
+	if ToggleFold(items, 2) {
+		t.Error("leaf must not fold")
+	}
+	if !ToggleFold(items, 1) {
+		t.Fatal("a1 should fold")
+	}
+	if got := indices(Visible(items, nil)); !reflect.DeepEqual(got, []int{0, 1, 3, 4}) {
+		t.Errorf("a1 folded = %v", got)
+	}
+
+	hideB := func(it Item) bool { return it.Title == "b" }
+	if got := indices(Visible(items, hideB)); !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Errorf("b hidden = %v", got)
+	}
+
+	keep := []bool{true, false, true, false, true}
+	if got := indices(VisibleMatching(items, keep)); !reflect.DeepEqual(got, []int{0, 2, 4}) {
+		t.Errorf("matching ignores folds = %v", got)
+	}
+}
+
+// bigTree builds n items with nesting up to four levels deep.
+func bigTree(n int, r *rand.Rand) []Item {
+	items := make([]Item, n)
+	level := 0
+	for i := range items {
+		level = max(0, min(level+r.Intn(3)-1, 3))
+		if i == 0 {
+			level = 0
+		}
+		items[i] = Item{Title: fmt.Sprintf("task %d", i), Level: level}
+	}
+	return items
+}
+
+func TestRefoldVisibleMatchesRebuild(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	items := bigTree(500, r)
+	hide := func(it Item) bool { return strings.HasSuffix(it.Title, "7") }
+	visible := Visible(items, hide)
+	for range 2000 {
+		pos := r.Intn(len(visible))
+		if !ToggleFold(items, visible[pos].Index) {
+			continue
+		}
+		visible = RefoldVisible(items, visible, pos, hide)
+		if want := Visible(items, hide); !reflect.DeepEqual(visible, want) {
+			t.Fatalf("incremental list diverged after folding %d", pos)
+		}
+	}
+}
+
+func TestVisiblePos(t *testing.T) {
+	items := tree(0, "a", 1, "a1", 0, "b")
+	items[0].Collapsed = true
+	visible := Visible(items, nil)
+	if got := VisiblePos(visible, 2); got != 1 {
+		t.Errorf("VisiblePos(b) = %d, want 1", got)
+	}
+	if got := VisiblePos(visible, 1); got != -1 {
+		t.Errorf("VisiblePos(folded a1) = %d, want -1", got)
+	}
+}
+
+// The fold benchmarks toggle one small subtree in a 10k-item tree.
+
+func benchFold(b *testing.B, refold bool) {
+	items := bigTree(10000, rand.New(rand.NewSource(1)))
+	idx := 5000
+	for !HasChildren(items, idx) {
+		idx++
+	}
+	visible := Visible(items, nil)
+	pos := VisiblePos(visible, idx)
+	for b.Loop() {
+		ToggleFold(items, idx)
+		if refold {
+			visible = RefoldVisible(items, visible, pos, nil)
+		} else {
+			visible = Visible(items, nil)
+		}
+	}
+}
+
+func BenchmarkFoldRebuild10k(b *testing.B)     { benchFold(b, false) }
+func BenchmarkFoldIncremental10k(b *testing.B) { benchFold(b, true) }
+
+func TestMoveSubtree(t *testing.T) {
+	items := tree(0, "a", 1, "a1", 2, "a1x", 0, "b", 1, "b1", 0, "c")
+	levels := func(items []Item) []int {
+		out := []int{}
+		for _, it := range items {
+			out = append(out, it.Level)
+		}
+		return out
+	}
+
+	got, idx, ok := MoveSubtree(items, 1, 3) // a1 and its child under b
+	if !ok || idx != 3 {
+		t.Fatalf("ok = %v, idx = %d", ok, idx)
+	}
+	if want := []string{"a", "b", "b1", "a1", "a1x", "c"}; !reflect.DeepEqual(titles(got), want) {
+		t.Errorf("titles = %v, want %v", titles(got), want)
+	}
+	if want := []int{0, 0, 1, 1, 2, 0}; !reflect.DeepEqual(levels(got), want) {
+		t.Errorf("levels = %v, want %v", levels(got), want)
+	}
+
+	got, idx, _ = MoveSubtree(items, 4, -1) // b1 to the top level
+	if idx != 5 || got[5].Title != "b1" || got[5].Level != 0 {
+		t.Errorf("top level: idx = %d, %+v", idx, got)
+	}
+
+	got, idx, _ = MoveSubtree(items, 5, 1) // c under a1, ahead of b
+	if idx != 3 || got[3].Title != "c" || got[3].Level != 2 {
+		t.Errorf("upwards: idx = %d, %v %v", idx, titles(got), levels(got))
+	}
+
+	if _, _, ok := MoveSubtree(items, 0, 2); ok {
+		t.Error("moved a subtree into itself")
+	}
+	if items[1].Title != "a1" {
+		t.Error("MoveSubtree modified its input")
+	}
+}
+
+func TestDuplicateSubtree(t *testing.T) {
+	items := tree(0, "pack", 1, "socks", 1, "charger", 0, "other")
+	items[1].Done = true
+	got, idx := DuplicateSubtree(items, 0)
+	if idx != 3 {
+		t.Fatalf("idx = %d", idx)
+	}
+	if want := []string{"pack", "socks", "charger", "pack", "socks", "charger", "other"}; !reflect.DeepEqual(titles(got), want) {
+		t.Errorf("titles = %v, want %v", titles(got), want)
+	}
+	if !got[1].Done || got[4].Done || got[4].Level != 1 {
+		t.Errorf("copy = %+v", got[3:6])
+	}
+}
+
+func TestHandOverText(t *testing.T) {
+	items := tree(0, "a", 0, "b", 1, "b1", 0, "c")
+	items[1].Before = []string{"", "About b:"}
+	items[2].Before = []string{""}
+	items[3].After = []string{"", "The end."}
+
+	// The prose above b stays above c
+	items, trash := DeleteSubtree(items, nil, 1)
+	if want := []string{"", "About b:", ""}; !reflect.DeepEqual(items[1].Before, want) || trash[0].Before != nil || trash[1].Before != nil {
+		t.Errorf("c.Before = %q, trash %+v", items[1].Before, trash)
+	}
+	// ...and with nothing after it, the end of the file goes to a
+	items, _ = DeleteSubtree(items, trash, 1)
+	if want := []string{"", "About b:", "", "", "The end."}; !reflect.DeepEqual(items[0].After, want) {
+		t.Errorf("a.After = %q", items[0].After)
+	}
+
+	items, at := DuplicateSubtree(items, 0)
+	if items[at].After != nil {
+		t.Error("a copy must not repeat the text")
+	}
+}
@@ -0,0 +1,153 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- WORKDAYS & RECURRENCE ---
+
+// Calendar tells working days from weekends and holidays. Holidays are
+// "2006-01-02" dates, or "01-02" for a holiday on the same day every year.
+type Calendar struct {
+	Holidays []string
+}
+
+func (c Calendar) IsWorkday(t time.Time) bool {
+	if wd := t.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		return false
+	}
+	day, monthDay := t.Format(DateLayout), t.Format("01-02")
+	for _, h := range c.Holidays {
+		if h == day || h == monthDay {
+			return false
+		}
+	}
+	return true
+}
+
+// RollForward returns t, or the first workday after it.
+func (c Calendar) RollForward(t time.Time) time.Time {
+	// Rok wystarczy nawet przy absurdalnie długiej liście świąt
+	for i := 0; i < 366 && !c.IsWorkday(t); i++ {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// NextWorkday returns the first workday strictly after t, keeping the clock.
+func (c Calendar) NextWorkday(t time.Time) time.Time {
+	return c.RollForward(t.AddDate(0, 0, 1))
+}
+
+// AddWorkdays moves t by n workdays (n may be negative).
+func (c Calendar) AddWorkdays(t time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if c.IsWorkday(t) {
+			n--
+		}
+	}
+	return t
+}
+
+// ValidRecurrence reports whether rule is a recur: value NextOccurrence knows:
+// daily, weekday, weekly, monthly, yearly, <N>d or <N>w.
+func ValidRecurrence(rule string) bool {
+	_, ok := recurrence(rule)
+	return ok
+}
+
+// recurrence returns the nth occurrence of rule counted from start (the 0th).
+// Monthly and yearly keep the day of start, clamped to shorter months.
+func recurrence(rule string) (func(start time.Time, n int, c Calendar) time.Time, bool) {
+	switch strings.ToLower(rule) {
+	case "daily":
+		return func(t time.Time, n int, _ Calendar) time.Time { return t.AddDate(0, 0, n) }, true
+	case "weekday", "weekdays", "workday", "workdays":
+		return func(t time.Time, n int, c Calendar) time.Time { return c.AddWorkdays(t, n) }, true
+	case "weekly":
+		return func(t time.Time, n int, _ Calendar) time.Time { return t.AddDate(0, 0, 7*n) }, true
+	case "monthly":
+		return func(t time.Time, n int, _ Calendar) time.Time { return addMonths(t, n) }, true
+	case "yearly":
+		return func(t time.Time, n int, _ Calendar) time.Time { return addMonths(t, 12*n) }, true
+	}
+	if len(rule) < 2 {
+		return nil, false
+	}
+	k, err := strconv.Atoi(rule[:len(rule)-1])
+	if err != nil || k <= 0 {
+		return nil, false
+	}
+	switch rule[len(rule)-1] {
+	case 'd':
+		return func(t time.Time, n int, _ Calendar) time.Time { return t.AddDate(0, 0, k*n) }, true
+	case 'w':
+		return func(t time.Time, n int, _ Calendar) time.Time { return t.AddDate(0, 0, 7*k*n) }, true
+	}
+	return nil, false
+}
+
+// addMonths moves t by n months, to the last day of the month when t's day
+// doesn't exist there (Jan 31 + 1 is Feb 28), keeping the clock.
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), last)-1)
+}
+
+// NextOccurrence returns the first occurrence of rule after due that is also
+// after today, so an overdue task doesn't come back already overdue. With
+// workdaysOnly every result is rolled forward to a workday.
+//
+// Occurrences are counted from anchor, the unrolled date the series started
+// on, so a date rolled past a holiday or clamped to a short month doesn't
+// shift the ones after it. A zero anchor, or one due isn't an occurrence of
+// (the date was moved by hand), starts the series over from due. The anchor
+// to keep is returned along with the date, zero when the date itself will do.
+func NextOccurrence(due, anchor time.Time, rule string, cal Calendar, workdaysOnly bool, now time.Time) (next, start time.Time, ok bool) {
+	nth, ok := recurrence(rule)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	at := func(start time.Time, n int) time.Time {
+		t := nth(start, n, cal)
+		if workdaysOnly {
+			t = cal.RollForward(t)
+		}
+		return t
+	}
+	start = due
+	n := 0
+	if !anchor.IsZero() {
+		// Kotwica z datą, ale z godziną terminu
+		a := time.Date(anchor.Year(), anchor.Month(), anchor.Day(), due.Hour(), due.Minute(), 0, 0, due.Location())
+		day := StartOfDay(due)
+		for n = 0; n < 100000 && StartOfDay(at(a, n)).Before(day); n++ {
+		}
+		if StartOfDay(at(a, n)).Equal(day) {
+			start = a
+		} else {
+			n = 0
+		}
+	}
+	today := StartOfDay(now)
+	for limit := n + 100000; n < limit; n++ {
+		next = at(start, n)
+		if StartOfDay(next).After(StartOfDay(due)) && StartOfDay(next).After(today) {
+			// Kotwica potrzebna tylko, gdy data jest przesunięta albo przycięta do końca miesiąca
+			byMonth := strings.EqualFold(rule, "monthly") || strings.EqualFold(rule, "yearly")
+			if nth(start, n, cal).Equal(next) && (!byMonth || next.Day() == start.Day()) {
+				start = time.Time{}
+			}
+			return next, start, true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, _ := time.ParseInLocation(DateLayout, s, time.Local)
+	return t
+}
+
+func TestCalendar(t *testing.T) {
+	cal := Calendar{Holidays: []string{"2026-01-02", "12-25"}}
+	tests := []struct {
+		name      string
+		got, want time.Time
+	}{
+		{"friday holiday skips weekend", cal.NextWorkday(day("2026-01-01")), day("2026-01-05")},
+		{"yearly holiday", cal.NextWorkday(day("2030-12-24")), day("2030-12-26")},
+		{"roll saturday", cal.RollForward(day("2026-01-10")), day("2026-01-12")},
+		{"roll workday", cal.RollForward(day("2026-01-12")), day("2026-01-12")},
+		{"add 3", cal.AddWorkdays(day("2026-01-01"), 3), day("2026-01-07")},
+		{"subtract 1", cal.AddWorkdays(day("2026-01-05"), -1), day("2026-01-01")},
+	}
+	for _, tt := range tests {
+		if !tt.got.Equal(tt.want) {
+			t.Errorf("%s: got %s, want %s", tt.name, tt.got.Format(DateLayout), tt.want.Format(DateLayout))
+		}
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	cal := Calendar{Holidays: []string{"2026-01-12"}}
+	now := day("2026-01-08") // czwartek
+	tests := []struct {
+		due, rule    string
+		workdaysOnly bool
+		want         string
+	}{
+		{"2026-01-08", "daily", false, "2026-01-09"},
+		{"2026-01-09", "daily", false, "2026-01-10"},
+		{"2026-01-09", "daily", true, "2026-01-13"},
+		{"2026-01-09", "weekday", false, "2026-01-13"},
+		{"2026-01-01", "weekly", false, "2026-01-15"},
+		{"2025-12-10", "monthly", true, "2026-01-13"},
+		{"2026-01-08", "3d", false, "2026-01-11"},
+		{"2026-01-08", "3d", true, "2026-01-13"},
+		{"2026-01-08", "2w", false, "2026-01-22"},
+	}
+	for _, tt := range tests {
+		got, _, ok := NextOccurrence(day(tt.due), time.Time{}, tt.rule, cal, tt.workdaysOnly, now)
+		if !ok || got.Format(DateLayout) != tt.want {
+			t.Errorf("NextOccurrence(%s, %s, %v) = %s, %v; want %s", tt.due, tt.rule, tt.workdaysOnly, got.Format(DateLayout), ok, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "d", "0d", "fortnightly", "3x"} {
+		if ValidRecurrence(bad) {
+			t.Errorf("ValidRecurrence(%q) = true", bad)
+		}
+	}
+}
+
+// series completes a task n times in a row, each on its due date.
+func series(t *testing.T, due, rule string, cal Calendar, workdaysOnly bool, n int) []string {
+	t.Helper()
+	d, anchor := day(due), time.Time{}
+	var got []string
+	for range n {
+		next, a, ok := NextOccurrence(d, anchor, rule, cal, workdaysOnly, d)
+		if !ok {
+			t.Fatalf("no occurrence after %s", d.Format(DateLayout))
+		}
+		got = append(got, next.Format(DateLayout))
+		d, anchor = next, a
+	}
+	return got
+}
+
+func TestRecurrenceKeepsItsDay(t *testing.T) {
+	// Święto w piątek przesuwa jedno wystąpienie, a nie całą serię
+	cal := Calendar{Holidays: []string{"2026-01-09"}}
+	if got := series(t, "2026-01-02", "weekly", cal, true, 3); !slices.Equal(got, []string{"2026-01-12", "2026-01-16", "2026-01-23"}) {
+		t.Errorf("weekly over a holiday = %v", got)
+	}
+	if got := series(t, "2026-01-31", "monthly", Calendar{}, false, 3); !slices.Equal(got, []string{"2026-02-28", "2026-03-31", "2026-04-30"}) {
+		t.Errorf("monthly from the 31st = %v", got)
+	}
+	if got := series(t, "2028-02-29", "yearly", Calendar{}, false, 4); !slices.Equal(got, []string{"2029-02-28", "2030-02-28", "2031-02-28", "2032-02-29"}) {
+		t.Errorf("yearly from Feb 29 = %v", got)
+	}
+
+	// Termin przesunięty ręcznie zaczyna serię od nowa
+	next, anchor, _ := NextOccurrence(day("2026-01-14"), day("2026-01-02"), "weekly", cal, true, day("2026-01-14"))
+	if next.Format(DateLayout) != "2026-01-21" || !anchor.IsZero() {
+		t.Errorf("moved by hand = %s, anchor %s", next.Format(DateLayout), anchor.Format(DateLayout))
+	}
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- CLOUD-SYNCED FOLDERS ---
+//
+// Sync clients (iCloud Drive, Google Drive, Dropbox, OneDrive) watch files by
+// path and may hold them open while uploading. A temp file renamed over the
+// list looks like a delete plus a new file to them, which loses version
+// history and can end in "conflicted copies", and writes can fail while the
+// client has the file busy. SaveWriteThrough rewrites the file in place and
+// retries those transient errors instead.
+
+// cloudMarkers are path segments (or runs of segments) of synced folders.
+// A segment also matches with a suffix, e.g. "OneDrive - Contoso".
+var cloudMarkers = [][]string{
+	{"Library", "Mobile Documents"},
+	{"Library", "CloudStorage"},
+	{"iCloud Drive"}, {"iCloudDrive"},
+	{"Google Drive"}, {"GoogleDrive"}, {"My Drive"},
+	{"Dropbox"}, {"OneDrive"},
+}
+
+// InCloudFolder guesses from the path whether a sync client manages the file.
+func InCloudFolder(filename string) bool {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		abs = filename
+	}
+	segs := strings.Split(filepath.ToSlash(abs), "/")
+	for _, marker := range cloudMarkers {
+		for i := 0; i+len(marker) <= len(segs); i++ {
+			if matchSegments(segs[i:i+len(marker)], marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchSegments(segs, marker []string) bool {
+	for k, want := range marker {
+		if segs[k] != want && !strings.HasPrefix(segs[k], want+" ") {
+			return false
+		}
+	}
+	return true
+}
+
+const (
+	writeRetries = 5
+	retryDelay   = 50 * time.Millisecond
+)
+
+// retrySleep is replaced in tests.
+var retrySleep = time.Sleep
+
+// SaveWriteThrough writes the lists into the existing file, flushes it to
+// disk and retries while the file is busy. The new content is written before
+// the file is truncated to its length, so it never appears empty.
+func SaveWriteThrough(filename string, items []model.Item, trash []model.Item) error {
+	var buf bytes.Buffer
+	encode(&buf, items, trash, fileIndent(filename))
+	delay := retryDelay
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = writeInPlace(filename, buf.Bytes())
+		if err == nil || !isTransient(err) || attempt == writeRetries {
+			return err
+		}
+		retrySleep(delay)
+		delay *= 2
+	}
+}
+
+func writeInPlace(filename string, data []byte) error {
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Truncate(int64(len(data)))
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func isTransient(err error) bool {
+	for _, t := range transientErrors {
+		if errors.Is(err, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// conflictSuffix matches what sync clients append to the name of a
+// conflicting copy: "todo (conflicted copy 2026-01-02).md" (Dropbox),
+// "todo.sync-conflict-….md" (Syncthing), "todo 2.md" (iCloud) and
+// "todo (1).md" (Google Drive).
+var conflictSuffix = regexp.MustCompile(`(?i)conflict|^ \d+$|^ \(\d+\)$`)
+
+// ConflictCopies lists the conflict copies of filename in its directory.
+func ConflictCopies(filename string) ([]string, error) {
+	dir, base := filepath.Split(filename)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	entries, err := os.ReadDir(filepath.Clean(dir + "."))
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || name == base || filepath.Ext(name) != ext || !strings.HasPrefix(name, stem) {
+			continue
+		}
+		if conflictSuffix.MatchString(strings.TrimSuffix(name[len(stem):], ext)) {
+			out = append(out, filepath.Join(dir, name))
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"syscall"
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestInCloudFolder(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/Users/ann/Library/Mobile Documents/com~apple~CloudDocs/todo.md":        true,
+		"/Users/ann/Library/CloudStorage/GoogleDrive-ann@x.com/My Drive/todo.md": true,
+		"/home/ann/Dropbox/todo.md":                           true,
+		"/home/ann/Dropbox (Personal)/todo.md":                true,
+		"/c/Users/ann/OneDrive - Contoso/todo.md":             true,
+		"/home/ann/notes/todo.md":                             false,
+		"/home/ann/DropboxBackup/todo.md":                     false,
+		"/Users/ann/Library/Application Support/todo/todo.md": false,
+	} {
+		if got := InCloudFolder(path); got != want {
+			t.Errorf("InCloudFolder(%q) = %v", path, got)
+		}
+	}
+}
+
+func TestSaveWriteThrough(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.md")
+	long := []model.Item{{Title: "a long first title"}, {Title: "second", Level: 1}}
+	short := []model.Item{{Title: "b"}}
+	if err := SaveWriteThrough(path, long, nil); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.Stat(path)
+	if err := SaveWriteThrough(path, short, []model.Item{{Title: "gone"}}); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := os.Stat(path)
+	if !os.SameFile(before, after) {
+		t.Error("the file was replaced instead of rewritten")
+	}
+	items, trash, err := Load(path)
+	if err != nil || !reflect.DeepEqual(items, short) || len(trash) != 1 {
+		t.Errorf("items = %+v, trash = %+v, %v", items, trash, err)
+	}
+}
+
+func TestTransientErrors(t *testing.T) {
+	if len(transientErrors) == 0 {
+		t.Skip("no transient errors on this platform")
+	}
+	wrapped := &os.PathError{Op: "open", Path: "todo.md", Err: transientErrors[0]}
+	if !isTransient(fmt.Errorf("save: %w", wrapped)) {
+		t.Error("busy file not treated as transient")
+	}
+	if isTransient(&os.PathError{Op: "open", Path: "todo.md", Err: syscall.ENOENT}) {
+		t.Error("missing directory treated as transient")
+	}
+}
+
+func TestConflictCopies(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{
+		"todo.md", "todo (conflicted copy 2026-01-02).md", "todo.sync-conflict-20260102-101010-ABC.md",
+		"todo 2.md", "todo (1).md", "todo-old.md", "todo 2.txt", "todos.md", "other.md",
+	}
+	for _, n := range names {
+		os.WriteFile(filepath.Join(dir, n), nil, 0644)
+	}
+	got, err := ConflictCopies(filepath.Join(dir, "todo.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var base []string
+	for _, p := range got {
+		base = append(base, filepath.Base(p))
+	}
+	want := []string{"todo (1).md", "todo (conflicted copy 2026-01-02).md", "todo 2.md", "todo.sync-conflict-20260102-101010-ABC.md"}
+	if !reflect.DeepEqual(base, want) {
+		t.Errorf("conflicts = %q, want %q", base, want)
+	}
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// --- INDENTATION ---
+//
+// A task's level comes from how far it is indented compared with the tasks
+// above it, not from a fixed width, so lists indented with two or four
+// spaces or with tabs all load as the same tree. When tabs and spaces are
+// mixed a tab counts as four columns. Saving keeps the indentation the file
+// already has (two spaces in a new one) unless config.json sets it:
+//
+//	"indent": "tab"
+//
+// or a number of spaces such as "4", which is then also what a tab counts
+// for on load. "auto" is the default.
+
+var indentUnit string // "" keeps the file's own
+
+// SetIndent picks the indentation files are saved with: "auto" (also for
+// ""), "tab" or a number of spaces from 1 to 8. Anything else leaves "auto"
+// and is an error.
+func SetIndent(name string) error {
+	indentUnit = ""
+	switch name {
+	case "", "auto":
+	case "tab":
+		indentUnit = "\t"
+	default:
+		n, err := strconv.Atoi(name)
+		if err != nil || n < 1 || n > 8 {
+			return fmt.Errorf("unknown indent %q (want auto, tab or 1 to 8 spaces)", name)
+		}
+		indentUnit = strings.Repeat(" ", n)
+	}
+	return nil
+}
+
+// indentColumn measures the indentation line starts with.
+func indentColumn(line string) (col int, prefix string) {
+	tab := 4
+	if indentUnit != "" && indentUnit != "\t" {
+		tab = len(indentUnit)
+	}
+	prefix = line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	for _, r := range prefix {
+		if r == '\t' {
+			col += tab
+		} else {
+			col++
+		}
+	}
+	return col, prefix
+}
+
+// cutNote strips the indentation of a note line under a task indented with
+// prefix: the prefix and two spaces or a tab more.
+func cutNote(line, prefix string) (string, bool) {
+	rest, ok := strings.CutPrefix(line, prefix)
+	if !ok {
+		return "", false
+	}
+	if note, ok := strings.CutPrefix(rest, "  "); ok {
+		return note, true
+	}
+	return strings.CutPrefix(rest, "\t")
+}
+
+// fileIndent is the indentation filename is saved with: the configured one,
+// or that of the first nested task already in the file, or two spaces.
+func fileIndent(filename string) string {
+	if indentUnit != "" {
+		return indentUnit
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return "  "
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Split(scanLines)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimLeft(line, " \t")
+		if !strings.HasPrefix(trimmed, "- [") || trimmed == line {
+			continue
+		}
+		switch prefix := line[:len(line)-len(trimmed)]; {
+		case strings.Trim(prefix, "\t") == "":
+			return "\t"
+		case strings.Trim(prefix, " ") == "":
+			return prefix
+		}
+	}
+	return "  "
+}
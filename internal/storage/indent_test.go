@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestIndentStyles(t *testing.T) {
+	t.Cleanup(func() { SetIndent("") })
+	twoSpaces := "- [ ] a\n  - [ ] b\n    note\n    - [x] c\n  - [ ] d\n- [ ] e\n"
+	files := map[string]string{
+		"two":  twoSpaces,
+		"four": "- [ ] a\n    - [ ] b\n      note\n        - [x] c\n    - [ ] d\n- [ ] e\n",
+		"tabs": "- [ ] a\n\t- [ ] b\n\t\tnote\n\t\t- [x] c\n\t- [ ] d\n- [ ] e\n",
+	}
+	want := []model.Item{
+		{Title: "a"}, {Title: "b", Level: 1, Note: []string{"note"}}, {Title: "c", Level: 2, Done: true}, {Title: "d", Level: 1}, {Title: "e"},
+	}
+	for name, file := range files {
+		path := filepath.Join(t.TempDir(), name+".md")
+		os.WriteFile(path, []byte(file), 0644)
+		items, trash, err := Load(path)
+		if err != nil || !reflect.DeepEqual(items, want) {
+			t.Fatalf("%s: %+v %v", name, items, err)
+		}
+		// Zapis zachowuje wcięcia pliku
+		if err := Save(path, items, trash); err != nil {
+			t.Fatal(err)
+		}
+		if data, _ := os.ReadFile(path); string(data) != file {
+			t.Errorf("%s saved as %q", name, data)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "todo.md")
+	os.WriteFile(path, []byte(files["tabs"]), 0644)
+	if err := SetIndent("2"); err != nil {
+		t.Fatal(err)
+	}
+	items, trash, _ := Load(path)
+	Save(path, items, trash)
+	if data, _ := os.ReadFile(path); string(data) != twoSpaces {
+		t.Errorf("indent 2 saved %q", data)
+	}
+	if err := SetIndent("wide"); err == nil || indentUnit != "" {
+		t.Error("a bad indent must be refused")
+	}
+}
+
+func TestIndentMixedAndUneven(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.md")
+	// Tab to 4 kolumny; zbyt głębokie wcięcie to nadal jeden poziom
+	os.WriteFile(path, []byte("- [ ] a\n\t- [ ] b\n    - [ ] c\n        - [ ] d\n  - [ ] e\n- [ ] f\n      - [ ] g\n"), 0644)
+	items, _, _ := Load(path)
+	var got []string
+	for _, it := range items {
+		got = append(got, strings.Repeat(".", it.Level)+it.Title)
+	}
+	if want := "a .b .c ..d .e f .g"; strings.Join(got, " ") != want {
+		t.Errorf("levels = %q", strings.Join(got, " "))
+	}
+}
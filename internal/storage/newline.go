@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// --- LINE ENDINGS ---
+//
+// Files are read whatever their line endings ("\n", "\r\n" or a lone "\r"
+// from classic Mac OS) and with or without a UTF-8 byte order mark, and
+// written with the line ending of the platform unless config.json says
+// otherwise:
+//
+//	"line_ending": "lf"
+//
+// "crlf" is the other choice, "native" the default. The byte order mark is
+// not written back.
+
+const byteOrderMark = "\uFEFF"
+
+var lineEnding = nativeLineEnding
+
+// SetLineEnding picks the line ending files are saved with: "native" (also
+// for ""), "lf" or "crlf". Anything else leaves the native one and is an
+// error.
+func SetLineEnding(name string) error {
+	switch strings.ToLower(name) {
+	case "", "native":
+		lineEnding = nativeLineEnding
+	case "lf":
+		lineEnding = "\n"
+	case "crlf":
+		lineEnding = "\r\n"
+	default:
+		lineEnding = nativeLineEnding
+		return fmt.Errorf("unknown line_ending %q (want native, lf or crlf)", name)
+	}
+	return nil
+}
+
+// scanLines is bufio.ScanLines that also ends a line at a lone "\r".
+func scanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	i := bytes.IndexAny(data, "\r\n")
+	switch {
+	case i == -1 && atEOF:
+		return len(data), data, nil
+	case i == -1:
+		return 0, nil, nil
+	case data[i] == '\n':
+		return i + 1, data[:i], nil
+	case i+1 < len(data) && data[i+1] == '\n':
+		return i + 2, data[:i], nil
+	case i+1 == len(data) && !atEOF:
+		return 0, nil, nil // "\n" może przyjść w następnym kawałku
+	}
+	return i + 1, data[:i], nil
+}
+
+// lineWriter writes each "\n" as the configured line ending.
+type lineWriter struct{ w io.Writer }
+
+func (lw lineWriter) Write(p []byte) (int, error) {
+	if lineEnding == "\n" {
+		return lw.w.Write(p)
+	}
+	if _, err := lw.w.Write(bytes.ReplaceAll(p, []byte("\n"), []byte(lineEnding))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
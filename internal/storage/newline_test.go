@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLineEndingSamples(t *testing.T) {
+	t.Cleanup(func() { SetLineEnding("") })
+	linux, _ := os.ReadFile(filepath.Join("testdata", "linux.md"))
+	want, wantTrash, err := Load(filepath.Join("testdata", "linux.md"))
+	if err != nil || len(want) != 4 || len(wantTrash) != 1 {
+		t.Fatalf("linux.md: %+v %+v %v", want, wantTrash, err)
+	}
+
+	for _, sample := range []string{"macos.md", "windows.md"} {
+		items, trash, err := Load(filepath.Join("testdata", sample))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(items, want) || !reflect.DeepEqual(trash, wantTrash) {
+			t.Errorf("%s = %+v %+v\nwant %+v %+v", sample, items, trash, want, wantTrash)
+		}
+		for _, ending := range []string{"lf", "crlf"} {
+			SetLineEnding(ending)
+			path := filepath.Join(t.TempDir(), sample)
+			if err := Save(path, items, trash); err != nil {
+				t.Fatal(err)
+			}
+			out := string(linux)
+			if ending == "crlf" {
+				out = strings.ReplaceAll(out, "\n", "\r\n")
+			}
+			if data, _ := os.ReadFile(path); string(data) != out {
+				t.Errorf("%s saved with %s = %q", sample, ending, data)
+			}
+		}
+	}
+}
+
+func TestClassicMacLineEndings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.md")
+	os.WriteFile(path, []byte("- [ ] a\r  note\r\r- [x] b"), 0644)
+	items, _, err := Load(path)
+	if err != nil || len(items) != 2 || items[0].Note[0] != "note" || !reflect.DeepEqual(items[1].Before, []string{""}) || !items[1].Done {
+		t.Errorf("items = %+v, %v", items, err)
+	}
+}
+
+func TestSetLineEnding(t *testing.T) {
+	t.Cleanup(func() { SetLineEnding("") })
+	if err := SetLineEnding("CRLF"); err != nil || lineEnding != "\r\n" {
+		t.Errorf("crlf: %q %v", lineEnding, err)
+	}
+	if err := SetLineEnding("cr"); err == nil || lineEnding != nativeLineEnding {
+		t.Errorf("cr: %q %v", lineEnding, err)
+	}
+}
@@ -0,0 +1,5 @@
+//go:build windows
+
+package storage
+
+const nativeLineEnding = "\r\n"
@@ -0,0 +1,282 @@
+// Package storage reads and writes the markdown checklist format.
+package storage
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- IO (LOADER) ---
+//
+// "#" to "######" headings divide the list into sections: a heading is an
+// item with Heading set, and the tasks below it (and deeper headings) are its
+// children, one level further in than their indentation says. In the bin a
+// heading is written as "- [D] ## Title".
+//
+// Lines that are neither tasks, notes nor headings (prose, blank lines,
+// comments, front matter) are kept as they are: each run goes with the item
+// below it (Item.Before) and the run closing the file with the last item
+// (Item.After), to be written back in the same place. Front matter, fenced
+// code and <!-- --> comments are kept whole, even where they hold lines
+// that look like tasks. A file without a single task or heading has no item
+// to keep its text on.
+
+// verbatimBlock returns the line closing the block trimmed opens, when it
+// opens front matter (on the first line), a code fence or a comment.
+func verbatimBlock(trimmed string, first bool) string {
+	switch {
+	case first && trimmed == "---":
+		return "---"
+	case strings.HasPrefix(trimmed, "```"), strings.HasPrefix(trimmed, "~~~"):
+		return trimmed[:3]
+	case strings.HasPrefix(trimmed, "<!--") && !strings.Contains(trimmed[4:], "-->"):
+		return "-->"
+	}
+	return ""
+}
+
+// closesBlock reports whether trimmed ends the block closed by closer.
+func closesBlock(closer, trimmed string) bool {
+	switch closer {
+	case "-->":
+		return strings.Contains(trimmed, "-->")
+	case "---":
+		return trimmed == "---" || trimmed == "..."
+	}
+	return strings.HasPrefix(trimmed, closer)
+}
+
+// parseHeading reads a markdown heading line.
+func parseHeading(line string) (depth int, title string, ok bool) {
+	hashes := len(line) - len(strings.TrimLeft(line, "#"))
+	if hashes == 0 || hashes > 6 || len(line) == hashes || line[hashes] != ' ' {
+		return 0, "", false
+	}
+	title = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(line[hashes:]), "#"))
+	return hashes, title, title != ""
+}
+
+// Load reads the active items and the bin. A missing file is an empty list;
+// any other failure is returned, so callers never mistake an unreadable file
+// for an empty one and overwrite it.
+func Load(filename string) ([]model.Item, []model.Item, error) {
+	active, trash, _, err := LoadLines(filename)
+	return active, trash, err
+}
+
+// LoadLines is Load that also tells the line of the file (1-based) each
+// active item was read from, for messages pointing into the file.
+func LoadLines(filename string) ([]model.Item, []model.Item, []int, error) {
+	file, err := os.Open(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []model.Item{}, []model.Item{}, nil, nil
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer file.Close()
+
+	var active []model.Item
+	var trash []model.Item
+	var lines []int      // lines of the active items
+	var last *model.Item // the item a note line belongs to
+	lastPrefix := ""     // its indentation in the file
+	var indents []int    // columns of the tasks the next one may nest under
+	var headings []int   // depths of the headings the next task is under
+	var loose []string   // lines kept for the next item
+	closer := ""         // the line ending the verbatim block we're in
+	keep := func(it *model.Item) {
+		it.Before, loose = loose, nil
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Split(scanLines)
+	for n, first := 1, true; scanner.Scan(); n, first = n+1, false {
+		line := scanner.Text()
+		if first {
+			line = strings.TrimPrefix(line, byteOrderMark)
+		}
+		trimmed := strings.TrimSpace(line)
+
+		if closer != "" {
+			loose = append(loose, line)
+			if closesBlock(closer, trimmed) {
+				closer = ""
+			}
+			continue
+		}
+
+		if last != nil && !strings.HasPrefix(trimmed, "- [") {
+			// Notatka: wiersze wcięte głębiej niż "- " zadania nad nimi
+			if rest, ok := cutNote(line, lastPrefix); ok && trimmed != "" {
+				last.Note = append(last.Note, rest)
+				continue
+			}
+			last = nil
+		}
+
+		if closer = verbatimBlock(trimmed, first); closer != "" {
+			loose = append(loose, line)
+			continue
+		}
+
+		if depth, title, ok := parseHeading(line); ok {
+			for len(headings) > 0 && headings[len(headings)-1] >= depth {
+				headings = headings[:len(headings)-1]
+			}
+			heading := model.Item{Title: title, Level: len(headings), Heading: depth}
+			keep(&heading)
+			active = append(active, heading)
+			lines = append(lines, n)
+			headings = append(headings, depth)
+			indents = nil
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- [") {
+			isDone := strings.Contains(line, "- [x]")
+			isTrash := strings.Contains(line, "- [D]")
+
+			parts := strings.SplitN(line, "]", 2)
+			if len(parts) > 1 {
+				// Poziom to liczba płycej wciętych zadań nad linią
+				col, prefix := indentColumn(line)
+				for len(indents) > 0 && indents[len(indents)-1] >= col {
+					indents = indents[:len(indents)-1]
+				}
+				newItem := model.Item{Title: strings.TrimSpace(parts[1]), Done: isDone, Level: len(indents)}
+				indents = append(indents, col)
+				lastPrefix = prefix
+				// Inne znaczniki niż " ", "x" i "D" to stany użytkownika, np. "[~]"
+				if mark := strings.TrimPrefix(strings.TrimLeft(parts[0], " \t"), "- ["); !isDone && !isTrash && len([]rune(mark)) == 1 && mark != " " {
+					newItem.State = mark
+				}
+				keep(&newItem)
+
+				if isTrash {
+					if depth, title, ok := parseHeading(newItem.Title); ok {
+						newItem.Title, newItem.Heading = title, depth
+					}
+					trash = append(trash, newItem)
+					last = &trash[len(trash)-1]
+				} else {
+					newItem.Level += len(headings)
+					active = append(active, newItem)
+					lines = append(lines, n)
+					last = &active[len(active)-1]
+				}
+				continue
+			}
+		}
+		loose = append(loose, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	switch {
+	case len(active) > 0:
+		active[len(active)-1].After = loose
+	case len(trash) > 0:
+		trash[len(trash)-1].After = loose
+	}
+	return active, trash, lines, nil
+}
+
+// Save writes the lists atomically: a failed write leaves the old file intact.
+func Save(filename string, items []model.Item, trash []model.Item) error {
+	// Zapis atomowy: plik tymczasowy + rename, żeby czytelnicy nie widzieli połowy pliku
+	file, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tmpName := file.Name()
+	writer := bufio.NewWriter(file)
+	encode(writer, items, trash, fileIndent(filename))
+
+	err = writer.Flush()
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if info, err := os.Stat(filename); err == nil {
+		os.Chmod(tmpName, info.Mode())
+	} else {
+		os.Chmod(tmpName, 0644)
+	}
+	if err := os.Rename(tmpName, filename); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
+
+// encode writes the markdown of both lists, indenting each level by indent.
+func encode(w io.Writer, items []model.Item, trash []model.Item, indent string) {
+	noteIndent := "  "
+	if indent == "\t" {
+		noteIndent = "\t"
+	}
+	w = lineWriter{w}
+	var headings []int // levels of the headings the item is under
+	for _, item := range items {
+		for len(headings) > 0 && headings[len(headings)-1] >= item.Level {
+			headings = headings[:len(headings)-1]
+		}
+		writeLines(w, item.Before)
+		if item.Heading > 0 {
+			fmt.Fprintf(w, "%s %s\n", strings.Repeat("#", item.Heading), item.Title)
+			headings = append(headings, item.Level)
+			continue
+		}
+		level := item.Level - len(headings)
+		status := " "
+		if item.Done {
+			status = "x"
+		} else if item.State != "" {
+			status = item.State
+		}
+		prefix := strings.Repeat(indent, level)
+		fmt.Fprintf(w, "%s- [%s] %s\n", prefix, status, item.Title)
+		encodeNote(w, item, prefix+noteIndent)
+	}
+
+	for _, item := range trash {
+		prefix := strings.Repeat(indent, item.Level)
+		title := item.Title
+		if item.Heading > 0 {
+			title = strings.Repeat("#", item.Heading) + " " + title
+		}
+		writeLines(w, item.Before)
+		fmt.Fprintf(w, "%s- [D] %s\n", prefix, title)
+		encodeNote(w, item, prefix+noteIndent)
+	}
+
+	for _, list := range [][]model.Item{items, trash} {
+		for _, item := range list {
+			writeLines(w, item.After)
+		}
+	}
+}
+
+func writeLines(w io.Writer, lines []string) {
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
+
+func encodeNote(w io.Writer, item model.Item, prefix string) {
+	for _, line := range item.Note {
+		fmt.Fprintf(w, "%s%s\n", prefix, line)
+	}
+}
@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.md")
+	items := []model.Item{
+		{Title: "Project #work"},
+		{Title: "Write spec due:2026-01-02", Level: 1, Done: true},
+		{Title: "Review", Level: 1},
+	}
+	trash := []model.Item{{Title: "Old idea"}, {Title: "detail", Level: 1}}
+
+	if err := Save(path, items, trash); err != nil {
+		t.Fatal(err)
+	}
+	gotItems, gotTrash, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotItems, items) {
+		t.Errorf("items = %+v, want %+v", gotItems, items)
+	}
+	if !reflect.DeepEqual(gotTrash, trash) {
+		t.Errorf("trash = %+v, want %+v", gotTrash, trash)
+	}
+
+	data, _ := os.ReadFile(path)
+	want := "- [ ] Project #work\n  - [x] Write spec due:2026-01-02\n  - [ ] Review\n- [D] Old idea\n  - [D] detail\n"
+	if string(data) != want {
+		t.Errorf("file =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	items, trash, err := Load(filepath.Join(t.TempDir(), "missing.md"))
+	if err != nil || len(items) != 0 || len(trash) != 0 {
+		t.Errorf("got %d items, %d in trash, err %v", len(items), len(trash), err)
+	}
+}
+
+func TestLoadUnreadableFile(t *testing.T) {
+	// Katalog da się otworzyć, ale nie przeczytać
+	if _, _, err := Load(t.TempDir()); err == nil {
+		t.Error("unreadable file loaded as an empty list")
+	}
+}
+
+func TestSaveReportsErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing-dir", "todo.md")
+	if err := Save(path, []model.Item{{Title: "x"}}, nil); err == nil {
+		t.Error("save into a missing directory succeeded")
+	}
+}
+
+func TestLoadSkipsNonTaskLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.md")
+	os.WriteFile(path, []byte("Intro\n\n- [ ] task\nsome note\n"), 0644)
+	items, _, _ := Load(path)
+	if len(items) != 1 || items[0].Title != "task" {
+		t.Errorf("items = %+v", items)
+	}
+}
+
+func TestSections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.md")
+	in := "- [ ] loose\n# Work ##\n\n- [ ] report\n  - [ ] charts\n    data from Q3\n## Meetings\n- [x] standup\n# Home\n- [ ] #tag is not a heading\n#nope\n"
+	os.WriteFile(path, []byte(in), 0644)
+	items, trash, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []model.Item{
+		{Title: "loose"},
+		{Title: "Work", Heading: 1},
+		{Title: "report", Level: 1},
+		{Title: "charts", Level: 2, Note: []string{"data from Q3"}},
+		{Title: "Meetings", Level: 1, Heading: 2},
+		{Title: "standup", Level: 2, Done: true},
+		{Title: "Home", Heading: 1},
+		{Title: "#tag is not a heading", Level: 1},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("items = %+v", items)
+	}
+	for i := range want {
+		got := items[i]
+		if got.Title != want[i].Title || got.Level != want[i].Level || got.Heading != want[i].Heading || got.Done != want[i].Done || len(got.Note) != len(want[i].Note) {
+			t.Errorf("item %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	// Usunięta sekcja trafia do kosza jako "## Meetings"
+	trash = append(trash, items[4:6]...)
+	items = append(items[:4], items[6:]...)
+	if err := Save(path, items, trash); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	out := "- [ ] loose\n# Work\n\n- [ ] report\n  - [ ] charts\n    data from Q3\n# Home\n- [ ] #tag is not a heading\n  - [D] ## Meetings\n    - [D] standup\n#nope\n"
+	if string(data) != out {
+		t.Errorf("saved:\n%s\nwant:\n%s", data, out)
+	}
+	again, bin, _ := Load(path)
+	if len(again) != len(items) || len(bin) != 2 || bin[0].Heading != 2 || bin[0].Title != "Meetings" {
+		t.Errorf("reloaded %+v, bin %+v", again, bin)
+	}
+}
+
+func TestSaveKeepsPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.md")
+	os.WriteFile(path, nil, 0600)
+	Save(path, []model.Item{{Title: "x"}}, nil)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestNoteRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.md")
+	file := "- [ ] spec\n  attach: docs/spec.pdf\n    indented more\n  - [ ] part\n    attach: file:///tmp/a.png\n- [ ] next\nnot a note\n- [D] gone\n  attach: x.txt\n"
+	os.WriteFile(path, []byte(file), 0644)
+
+	items, trash, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []model.Item{
+		{Title: "spec", Note: []string{"attach: docs/spec.pdf", "  indented more"}},
+		{Title: "part", Level: 1, Note: []string{"attach: file:///tmp/a.png"}},
+		{Title: "next"},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("items = %+v, want %+v", items, want)
+	}
+	if len(trash) != 1 || !reflect.DeepEqual(trash[0].Note, []string{"attach: x.txt"}) {
+		t.Errorf("trash = %+v", trash)
+	}
+
+	Save(path, items, trash)
+	data, _ := os.ReadFile(path)
+	if string(data) != file {
+		t.Errorf("file =\n%s\nwant\n%s", data, file)
+	}
+}
+
+func TestCustomStatesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.md")
+	file := "- [~] drafting\n  - [?] waiting on legal\n- [!] urgent\n- [x] done\n- [ ] open\n"
+	os.WriteFile(path, []byte(file), 0644)
+
+	items, _, _ := Load(path)
+	var states []string
+	for _, it := range items {
+		states = append(states, it.State)
+	}
+	if strings.Join(states, ",") != "~,?,!,," || items[1].Title != "waiting on legal" || items[3].Done != true {
+		t.Fatalf("items = %+v", items)
+	}
+	Save(path, items, nil)
+	if data, _ := os.ReadFile(path); string(data) != file {
+		t.Errorf("file =\n%s\nwant\n%s", data, file)
+	}
+}
+
+func TestLooseLinesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.md")
+	file := "---\ntitle: Plan\n- [ ] not a task\n---\n\nSome prose.\n\n- [ ] first\n  note\n\n  indented prose\n* [ ] other bullet\n<!--\n- [ ] commented out\n-->\n```\n- [ ] example\n```\n# Later\n\n- [ ] second\n- [D] gone\n\n> the end\n"
+	os.WriteFile(path, []byte(file), 0644)
+	items, trash, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 || items[0].Title != "first" || items[1].Heading != 1 || len(trash) != 1 {
+		t.Fatalf("items = %+v, trash %+v", items, trash)
+	}
+	if want := []string{"---", "title: Plan", "- [ ] not a task", "---", "", "Some prose.", ""}; !reflect.DeepEqual(items[0].Before, want) {
+		t.Errorf("front matter = %q", items[0].Before)
+	}
+	if want := []string{"", "> the end"}; !reflect.DeepEqual(items[2].After, want) {
+		t.Errorf("after = %q", items[2].After)
+	}
+
+	if err := Save(path, items, trash); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != file {
+		t.Errorf("file =\n%s\nwant\n%s", data, file)
+	}
+}
+
+func TestLoadLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.md")
+	in := "---\ntitle: x\n---\n# Work\n\nSome prose.\n- [ ] report\n  data from Q3\n  more\n- [D] gone\n- [ ] last\n"
+	os.WriteFile(path, []byte(in), 0644)
+	items, _, lines, err := LoadLines(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 || !reflect.DeepEqual(lines, []int{4, 7, 11}) {
+		t.Errorf("lines = %v for %+v", lines, items)
+	}
+}
@@ -0,0 +1,5 @@
+//go:build !unix && !windows
+
+package storage
+
+var transientErrors []error
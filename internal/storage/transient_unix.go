@@ -0,0 +1,9 @@
+//go:build unix
+
+package storage
+
+import "syscall"
+
+// transientErrors are returned while another process (a sync client or a
+// network filesystem) has the file busy.
+var transientErrors = []error{syscall.EBUSY, syscall.EAGAIN, syscall.ETXTBSY}
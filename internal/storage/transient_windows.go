@@ -0,0 +1,13 @@
+//go:build windows
+
+package storage
+
+import "syscall"
+
+// transientErrors are returned while another process (a sync client or a
+// virus scanner) has the file open.
+var transientErrors = []error{
+	syscall.Errno(32), // ERROR_SHARING_VIOLATION
+	syscall.Errno(33), // ERROR_LOCK_VIOLATION
+	syscall.ERROR_ACCESS_DENIED,
+}
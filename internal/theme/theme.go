@@ -0,0 +1,205 @@
+// Package theme loads color themes from the working directory, the user's
+// config dir and the built-in set.
+package theme
+
+import (
+	"embed"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// --- EMBEDDING ---
+//
+//go:embed themes.json
+var embeddedThemesFS embed.FS
+
+const defaultThemesFile = "themes.json"
+
+// --- THEME SYSTEM ---
+
+type JSONTheme struct {
+	Name      string `json:"name"`
+	Base      string `json:"base"`
+	Highlight string `json:"highlight"`
+	Text      string `json:"text"`
+	Comment   string `json:"comment"`
+	Special   string `json:"special"`
+	Error     string `json:"error"`
+	Accent    string `json:"accent"`
+
+	ANSI256 *Palette `json:"ansi256,omitempty"`
+	ANSI16  *Palette `json:"ansi16,omitempty"`
+
+	// Tints are the colors of "%red"-style task rows; missing ones use Tint's
+	// default slots
+	Tints map[string]string `json:"tints,omitempty"`
+}
+
+// Palette gives a theme's colors for terminals without truecolor, as color
+// numbers ("236") or hex. Empty slots keep the color of the richer profile,
+// which the terminal library then approximates.
+type Palette struct {
+	Base      string `json:"base,omitempty"`
+	Highlight string `json:"highlight,omitempty"`
+	Text      string `json:"text,omitempty"`
+	Comment   string `json:"comment,omitempty"`
+	Special   string `json:"special,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Accent    string `json:"accent,omitempty"`
+}
+
+type Theme struct {
+	Name      string
+	Base      lipgloss.Color
+	Highlight lipgloss.Color
+	Text      lipgloss.Color
+	Comment   lipgloss.Color
+	Special   lipgloss.Color
+	Error     lipgloss.Color
+	Accent    lipgloss.Color
+
+	ANSI256 *Palette
+	ANSI16  *Palette
+
+	Tints map[string]string
+}
+
+var Default = Theme{
+	Name:      "Gruvbox (Built-in)",
+	Base:      lipgloss.Color("#282828"),
+	Highlight: lipgloss.Color("#fabd2f"),
+	Text:      lipgloss.Color("#ebdbb2"),
+	Comment:   lipgloss.Color("#928374"),
+	Special:   lipgloss.Color("#b8bb26"),
+	Error:     lipgloss.Color("#fb4934"),
+	Accent:    lipgloss.Color("#83a598"),
+	ANSI16:    &Palette{Base: "0", Highlight: "11", Text: "15", Comment: "8", Special: "10", Error: "9", Accent: "12"},
+	Tints:     map[string]string{"orange": "#fe8019", "purple": "#d3869b"},
+}
+
+// Tint is the color of a "%name" task row. Themes name their own in
+// "tints"; otherwise the slot nearest in meaning stands in, so a tint keeps
+// its role (red for trouble, green for fine) in every theme.
+func (t Theme) Tint(name string) (lipgloss.Color, bool) {
+	if c, ok := t.Tints[name]; ok && c != "" {
+		return lipgloss.Color(c), true
+	}
+	switch name {
+	case "red", "orange":
+		return t.Error, true
+	case "yellow":
+		return t.Highlight, true
+	case "green":
+		return t.Special, true
+	case "blue", "purple":
+		return t.Accent, true
+	case "gray":
+		return t.Comment, true
+	}
+	return "", false
+}
+
+// --- COLOR PROFILES ---
+
+// ForProfile returns the theme as shown on a terminal with profile p: the
+// ansi256 palette laid over the truecolor colors, and on 16-color terminals
+// the ansi16 one over that.
+func (t Theme) ForProfile(p termenv.Profile) Theme {
+	switch p {
+	case termenv.ANSI256:
+		t.apply(t.ANSI256)
+	case termenv.ANSI:
+		t.apply(t.ANSI256)
+		t.apply(t.ANSI16)
+	}
+	return t
+}
+
+func (t *Theme) apply(pal *Palette) {
+	if pal == nil {
+		return
+	}
+	set := func(c *lipgloss.Color, v string) {
+		if v != "" {
+			*c = lipgloss.Color(v)
+		}
+	}
+	set(&t.Base, pal.Base)
+	set(&t.Highlight, pal.Highlight)
+	set(&t.Text, pal.Text)
+	set(&t.Comment, pal.Comment)
+	set(&t.Special, pal.Special)
+	set(&t.Error, pal.Error)
+	set(&t.Accent, pal.Accent)
+}
+
+// --- IO (SMART DEDUPLICATION) ---
+
+// Load merges themes.json from the working directory, the config dir of
+// appName and the built-in set; earlier sources win on name clashes.
+func Load(appName string) []Theme {
+	var finalThemes []Theme
+	seen := make(map[string]bool)
+
+	addThemes := func(source []Theme) {
+		for _, t := range source {
+			if !seen[t.Name] {
+				finalThemes = append(finalThemes, t)
+				seen[t.Name] = true
+			}
+		}
+	}
+
+	localContent, err := os.ReadFile(defaultThemesFile)
+	if err == nil {
+		addThemes(Parse(localContent))
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err == nil {
+		globalPath := filepath.Join(configDir, appName, defaultThemesFile)
+		userContent, err := os.ReadFile(globalPath)
+		if err == nil {
+			addThemes(Parse(userContent))
+		}
+	}
+
+	embeddedContent, err := embeddedThemesFS.ReadFile(defaultThemesFile)
+	if err == nil {
+		addThemes(Parse(embeddedContent))
+	}
+
+	if len(finalThemes) == 0 {
+		return nil
+	}
+
+	return finalThemes
+}
+
+func Parse(content []byte) []Theme {
+	var jsonThemes []JSONTheme
+	if err := json.Unmarshal(content, &jsonThemes); err != nil {
+		return nil
+	}
+	var result []Theme
+	for _, jt := range jsonThemes {
+		result = append(result, Theme{
+			Name:      jt.Name,
+			Base:      lipgloss.Color(jt.Base),
+			Highlight: lipgloss.Color(jt.Highlight),
+			Text:      lipgloss.Color(jt.Text),
+			Comment:   lipgloss.Color(jt.Comment),
+			Special:   lipgloss.Color(jt.Special),
+			Error:     lipgloss.Color(jt.Error),
+			Accent:    lipgloss.Color(jt.Accent),
+			ANSI256:   jt.ANSI256,
+			ANSI16:    jt.ANSI16,
+			Tints:     jt.Tints,
+		})
+	}
+	return result
+}
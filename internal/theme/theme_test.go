@@ -0,0 +1,77 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/muesli/termenv"
+)
+
+func TestParse(t *testing.T) {
+	themes := Parse([]byte(`[{"name":"Mono","base":"#000000","text":"#ffffff"}]`))
+	if len(themes) != 1 || themes[0].Name != "Mono" || string(themes[0].Text) != "#ffffff" {
+		t.Errorf("themes = %+v", themes)
+	}
+	if Parse([]byte("not json")) != nil {
+		t.Error("invalid JSON should yield nil")
+	}
+}
+
+func TestLoadPrefersUserThemes(t *testing.T) {
+	config := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", config)
+	t.Setenv("HOME", config)
+	t.Chdir(t.TempDir())
+
+	builtin := Load("todo-test")
+	if len(builtin) == 0 {
+		t.Fatal("no built-in themes")
+	}
+
+	dir := filepath.Join(config, "todo-test")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "themes.json"), []byte(`[{"name":"`+builtin[0].Name+`","base":"#123456"}]`), 0644)
+
+	themes := Load("todo-test")
+	if len(themes) != len(builtin) {
+		t.Errorf("got %d themes, want %d (duplicates must be merged)", len(themes), len(builtin))
+	}
+	if string(themes[0].Base) != "#123456" {
+		t.Errorf("user theme did not override the built-in one: %v", themes[0].Base)
+	}
+}
+
+func TestForProfile(t *testing.T) {
+	themes := Parse([]byte(`[{"name":"x","base":"#000000","text":"#ffffff","accent":"#0000ff",
+		"ansi256":{"base":"235","text":"223"},"ansi16":{"base":"0"}}]`))
+	th := themes[0]
+
+	if got := th.ForProfile(termenv.TrueColor); got.Base != "#000000" {
+		t.Errorf("truecolor base = %v", got.Base)
+	}
+	got := th.ForProfile(termenv.ANSI256)
+	if got.Base != "235" || got.Text != "223" || got.Accent != "#0000ff" {
+		t.Errorf("256 colors = %+v", got)
+	}
+	if got := th.ForProfile(termenv.ANSI); got.Base != "0" || got.Text != "223" {
+		t.Errorf("16 colors = %+v", got)
+	}
+}
+
+func TestTint(t *testing.T) {
+	th := Default
+	if c, _ := th.Tint("red"); c != th.Error {
+		t.Errorf("red = %q", c)
+	}
+	if c, _ := th.Tint("orange"); c != "#fe8019" {
+		t.Errorf("orange = %q", c)
+	}
+	th.Tints = nil
+	if c, _ := th.Tint("orange"); c != th.Error {
+		t.Errorf("orange without a theme tint = %q", c)
+	}
+	if _, ok := th.Tint("bold"); ok {
+		t.Error("bold is not a color")
+	}
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- DATE PICKER WIDGET ---
+//
+// Keys: ←/→ (h/l) and +/- move by a day, ↑/↓ (k/j) and </> by a week,
+// pgup/pgdown by a month, t jumps to today, w to the next workday, x clears
+// the date.
+
+type DatePicker struct {
+	Date time.Time
+	Set  bool
+	// Calendar marks holidays in the grid and drives the w key
+	Calendar model.Calendar
+}
+
+func NewDatePicker(date time.Time, set bool) DatePicker {
+	if date.IsZero() {
+		date = time.Now()
+	}
+	return DatePicker{Date: model.StartOfDay(date), Set: set}
+}
+
+// Update handles a key and reports whether it was consumed.
+func (p *DatePicker) Update(key string) bool {
+	days, months := 0, 0
+	switch key {
+	case "left", "h", "-":
+		days = -1
+	case "right", "l", "+", "=":
+		days = 1
+	case "up", "k", "<":
+		days = -7
+	case "down", "j", ">":
+		days = 7
+	case "pgup":
+		months = -1
+	case "pgdown":
+		months = 1
+	case "t":
+		p.Date = model.StartOfDay(time.Now())
+		p.Set = true
+		return true
+	case "w":
+		from := model.StartOfDay(time.Now())
+		if p.Set && p.Date.After(from) {
+			from = p.Date
+		}
+		p.Date = p.Calendar.NextWorkday(from)
+		p.Set = true
+		return true
+	case "x":
+		p.Set = false
+		return true
+	default:
+		return false
+	}
+	// Pierwszy ruch na pustej dacie tylko ją ustawia
+	if p.Set {
+		p.Date = p.Date.AddDate(0, months, days)
+	}
+	p.Set = true
+	return true
+}
+
+// Label is a short human description of the chosen date.
+func (p DatePicker) Label() string {
+	if !p.Set {
+		return i18n.T("none")
+	}
+	return i18n.LongDate(p.Date)
+}
+
+// View draws a month grid around the selected date, weeks starting on the
+// configured day.
+func (p DatePicker) View(active bool, t theme.Theme) string {
+	sel := p.Date
+	var s strings.Builder
+	monthStyle := lipgloss.NewStyle().Foreground(t.Accent).Bold(true)
+	s.WriteString(lipgloss.PlaceHorizontal(20, lipgloss.Center, monthStyle.Render(i18n.MonthYear(sel))) + "\n")
+	var head []string
+	for _, d := range i18n.Weekdays() {
+		head = append(head, ansi.Truncate(i18n.Weekday(d, true), 2, ""))
+	}
+	s.WriteString(lipgloss.NewStyle().Foreground(t.Comment).Render(strings.Join(head, " ")) + "\n")
+
+	first := time.Date(sel.Year(), sel.Month(), 1, 0, 0, 0, 0, sel.Location())
+	offset := i18n.WeekdayColumn(first.Weekday())
+	today := model.StartOfDay(time.Now())
+
+	day := first.AddDate(0, 0, -offset)
+	for week := 0; week < 6; week++ {
+		var cells []string
+		for wd := 0; wd < 7; wd++ {
+			style := lipgloss.NewStyle().Foreground(t.Text)
+			if day.Month() != sel.Month() || !p.Calendar.IsWorkday(day) {
+				style = style.Foreground(t.Comment)
+			}
+			if day.Equal(today) {
+				style = style.Foreground(t.Special).Bold(true)
+			}
+			if p.Set && day.Equal(sel) {
+				style = style.Foreground(t.Base).Background(t.Comment)
+				if active {
+					style = style.Background(t.Highlight)
+				}
+			}
+			cells = append(cells, style.Render(fmt.Sprintf("%2d", day.Day())))
+			day = day.AddDate(0, 0, 1)
+		}
+		s.WriteString(strings.Join(cells, " "))
+		if week < 5 {
+			s.WriteString("\n")
+		}
+	}
+	return s.String()
+}
@@ -0,0 +1,41 @@
+// Package ui contains small reusable Bubble Tea / lipgloss widgets.
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// --- OVERLAY ---
+
+// OverlayCenter draws fg on top of bg, centered. Both are multi-line strings
+// which may contain ANSI styling.
+func OverlayCenter(bg, fg string) string {
+	bgW, bgH := lipgloss.Size(bg)
+	fgW, fgH := lipgloss.Size(fg)
+	return Overlay(bg, fg, max(0, (bgW-fgW)/2), max(0, (bgH-fgH)/2))
+}
+
+// Overlay draws fg over bg with its top-left corner at column x, row y.
+func Overlay(bg, fg string, x, y int) string {
+	bgLines := strings.Split(bg, "\n")
+	fgLines := strings.Split(fg, "\n")
+
+	for i, fgLine := range fgLines {
+		row := y + i
+		if row >= len(bgLines) {
+			break
+		}
+		line := bgLines[row]
+		w := ansi.StringWidth(fgLine)
+		left := ansi.Truncate(line, x, "")
+		if pad := x - ansi.StringWidth(left); pad > 0 {
+			left += strings.Repeat(" ", pad)
+		}
+		right := ansi.TruncateLeft(line, x+w, "")
+		bgLines[row] = left + fgLine + right
+	}
+	return strings.Join(bgLines, "\n")
+}
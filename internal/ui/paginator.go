@@ -0,0 +1,18 @@
+package ui
+
+// Paginator returns the [start, end) window of height rows keeping cursor visible.
+func Paginator(cursor, height, total int) (int, int) {
+	if total == 0 {
+		return 0, 0
+	}
+	start := 0
+	end := height
+	if total < height {
+		end = total
+	}
+	if cursor >= height {
+		start = cursor - height + 1
+		end = cursor + 1
+	}
+	return start, end
+}
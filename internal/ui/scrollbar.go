@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- SCROLLBAR ---
+
+// ScrollThumb returns the rows [pos, pos+size) of a height-row track taken
+// by the thumb when shown of total entries are visible from offset. The
+// thumb only touches an end of the track when nothing lies beyond it.
+func ScrollThumb(height, offset, shown, total int) (pos, size int) {
+	if total <= shown || height < 1 {
+		return 0, height
+	}
+	size = min(height, max(1, (height*shown+total/2)/total))
+	room := height - size
+	pos = (offset*room + (total-shown)/2) / (total - shown)
+	if offset > 0 && pos == 0 && room > 0 {
+		pos = 1
+	}
+	if offset+shown < total && pos == room && pos > 0 {
+		pos--
+	}
+	return pos, size
+}
+
+// Scrollbar appends a one-column scrollbar to lines, each first padded to
+// width. Nothing is drawn when all entries are shown.
+func Scrollbar(lines []string, width, offset, shown, total int, t theme.Theme) []string {
+	if total <= shown {
+		return lines
+	}
+	pos, size := ScrollThumb(len(lines), offset, shown, total)
+	track := lipgloss.NewStyle().Foreground(t.Comment).Render("│")
+	thumb := lipgloss.NewStyle().Foreground(t.Accent).Render("┃")
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		line = ansi.Truncate(line, width, "")
+		out[i] = line + strings.Repeat(" ", width-lipgloss.Width(line))
+		if i >= pos && i < pos+size {
+			out[i] += thumb
+		} else {
+			out[i] += track
+		}
+	}
+	return out
+}
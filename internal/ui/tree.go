@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- TREE GUIDES ---
+
+// RowGuide holds the tree lines drawn in front of an item.
+type RowGuide struct {
+	Prefix    string // " │ " columns of the ancestors
+	Connector string // " ├─", " └─" or " " for top-level items
+}
+
+// GuidePrefixWidth is the width of prefix+connector, which depends only on the level.
+func GuidePrefixWidth(level int) int {
+	if level == 0 {
+		return 1
+	}
+	return 1 + 3*(level-1) + 3
+}
+
+// TreeGuides computes the guides of visible[from:to] in one backward pass:
+// cont[l] tells whether an item of level l follows before any shallower one.
+func TreeGuides(visible []model.VisibleItem, from, to int) []RowGuide {
+	guides := make([]RowGuide, to-from)
+	var cont []bool
+	for i := len(visible) - 1; i >= from; i-- {
+		level := visible[i].Data.Level
+		for len(cont) <= level {
+			cont = append(cont, false)
+		}
+		if i < to {
+			g := RowGuide{Connector: " "}
+			if level > 0 {
+				var sb strings.Builder
+				sb.WriteString(" ")
+				for l := 1; l < level; l++ {
+					if cont[l] {
+						sb.WriteString(" │ ")
+					} else {
+						sb.WriteString("   ")
+					}
+				}
+				g.Prefix = sb.String()
+				g.Connector = " └─"
+				if cont[level] {
+					g.Connector = " ├─"
+				}
+			}
+			guides[i-from] = g
+		}
+		for l := level + 1; l < len(cont); l++ {
+			cont[l] = false
+		}
+		cont[level] = true
+	}
+	return guides
+}
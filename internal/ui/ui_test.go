@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestPaginator(t *testing.T) {
+	tests := []struct{ cursor, height, total, start, end int }{
+		{0, 5, 0, 0, 0},
+		{0, 5, 3, 0, 3},
+		{4, 5, 10, 0, 5},
+		{7, 5, 10, 3, 8},
+	}
+	for _, tt := range tests {
+		start, end := Paginator(tt.cursor, tt.height, tt.total)
+		if start != tt.start || end != tt.end {
+			t.Errorf("Paginator(%d, %d, %d) = %d, %d, want %d, %d", tt.cursor, tt.height, tt.total, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	bg := "abcdef\nghijkl\nmnopqr"
+	if got := Overlay(bg, "XY", 2, 1); got != "abcdef\nghXYkl\nmnopqr" {
+		t.Errorf("Overlay = %q", got)
+	}
+	if got := OverlayCenter(bg, "Z"); got != "abcdef\nghZjkl\nmnopqr" {
+		t.Errorf("OverlayCenter = %q", got)
+	}
+}
+
+func TestDatePicker(t *testing.T) {
+	p := NewDatePicker(time.Date(2026, 1, 31, 15, 0, 0, 0, time.Local), false)
+	if p.Set || p.Label() != "none" {
+		t.Fatalf("unset picker: %+v", p)
+	}
+	p.Update("l")
+	if !p.Set || p.Date.Day() != 31 {
+		t.Errorf("first move should only set the date: %v", p.Date)
+	}
+	p.Update("l")
+	if p.Date.Month() != time.February || p.Date.Day() != 1 {
+		t.Errorf("next day = %v", p.Date)
+	}
+	p.Update("k")
+	if p.Date.Day() != 25 {
+		t.Errorf("week back = %v", p.Date)
+	}
+	if p.Update("?") {
+		t.Error("unknown key consumed")
+	}
+	p.Update("x")
+	if p.Set {
+		t.Error("x should clear")
+	}
+
+	// Piątek przed świętem w poniedziałek
+	p = NewDatePicker(time.Date(2030, 1, 4, 0, 0, 0, 0, time.Local), true)
+	p.Calendar = model.Calendar{Holidays: []string{"2030-01-07"}}
+	p.Update("w")
+	if p.Date.Day() != 8 {
+		t.Errorf("next workday = %v", p.Date)
+	}
+}
+
+func TestTreeGuides(t *testing.T) {
+	visible := model.Visible([]model.Item{
+		{Title: "a"}, {Title: "b", Level: 1}, {Title: "c", Level: 2}, {Title: "d", Level: 1}, {Title: "e"},
+	}, nil)
+	want := []RowGuide{
+		{"", " "}, {" ", " ├─"}, {"  │ ", " └─"}, {" ", " └─"}, {"", " "},
+	}
+	got := TreeGuides(visible, 0, len(visible))
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("guide %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+	// A window must see the same guides as the full pass.
+	if part := TreeGuides(visible, 2, 4); part[0] != want[2] || part[1] != want[3] {
+		t.Errorf("windowed guides = %q", part)
+	}
+}
+
+func TestScrollThumb(t *testing.T) {
+	tests := []struct{ height, offset, shown, total, pos, size int }{
+		{10, 0, 10, 10, 0, 10},
+		{10, 0, 5, 10, 0, 5},
+		{10, 5, 5, 10, 5, 5},
+		{10, 1, 5, 100, 1, 1}, // cokolwiek wyżej: kciuk nie dotyka góry
+		{10, 94, 5, 100, 8, 1},
+		{10, 95, 5, 100, 9, 1},
+	}
+	for _, tt := range tests {
+		pos, size := ScrollThumb(tt.height, tt.offset, tt.shown, tt.total)
+		if pos != tt.pos || size != tt.size {
+			t.Errorf("ScrollThumb(%d, %d, %d, %d) = %d, %d, want %d, %d", tt.height, tt.offset, tt.shown, tt.total, pos, size, tt.pos, tt.size)
+		}
+	}
+}
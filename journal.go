@@ -0,0 +1,346 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- RECOVERY JOURNAL ---
+//
+// Saves wait for saveDelay (see save.go), so a crash, a killed terminal or a
+// disk refusing writes could lose the last edits. Every change is therefore
+// also appended to a journal in the config folder (journal/<hash>.jsonl)
+// and the journal is removed once the file is written. Its first line holds
+// the whole list; each later one only what a change replaced (an index, how
+// many items went and the items that came instead), worked out on the UI
+// goroutine by comparing the ends of the lists, while encoding and writing
+// happen in a tea.Cmd. A journal still there on the next start and
+// newer than the file means the session ended before saving: the app asks
+// whether to restore the list from it or to discard it, which keeps it
+// aside as .discarded just in case. A line cut short by the crash is
+// skipped. Closing the terminal window saves like quit does.
+
+const (
+	journalDir = "journal"
+	// journalCompact is the size from which the journal starts over from the
+	// whole list, for sessions whose saves keep failing
+	journalCompact = 4 << 20
+)
+
+type journalEntry struct {
+	Time time.Time `json:"time"`
+	// Full entries carry the whole lists, the others only their edits
+	Full  bool          `json:"full,omitempty"`
+	Items []model.Item  `json:"items,omitempty"`
+	Trash []model.Item  `json:"trash,omitempty"`
+	Edits []journalEdit `json:"edits,omitempty"`
+}
+
+// journalEdit replaces Del items at At of the list (or of the bin) with Items.
+type journalEdit struct {
+	Trash bool         `json:"trash,omitempty"`
+	At    int          `json:"at"`
+	Del   int          `json:"del,omitempty"`
+	Items []model.Item `json:"items,omitempty"`
+}
+
+// journal is the app's side of the journal of one list: the lists as of the
+// last entry, kept on the UI goroutine, and the file operations waiting for
+// the next drain, which a tea.Cmd runs in the background.
+type journal struct {
+	path         string
+	items, trash []model.Item
+	started      bool // an entry was written since the last save
+	fresh        bool // ops were queued since the last Cmd
+
+	mu      sync.Mutex // guards queue
+	queue   []func()
+	writeMu sync.Mutex // one drain at a time, so ops run in order
+}
+
+func newJournal(filename string) *journal {
+	path, err := journalPath(filename)
+	if filename == "" || err != nil {
+		return nil
+	}
+	return &journal{path: path}
+}
+
+// journalPath is the journal of filename (or of a workspace's name).
+func journalPath(filename string) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(stateKey(filename)))
+	return filepath.Join(configDir, appName, journalDir, hex.EncodeToString(sum[:8])+".jsonl"), nil
+}
+
+// push queues op for the next drain.
+func (j *journal) push(op func()) {
+	j.mu.Lock()
+	j.queue = append(j.queue, op)
+	j.mu.Unlock()
+	j.fresh = true
+}
+
+// drain runs the queued ops.
+func (j *journal) drain() {
+	j.writeMu.Lock()
+	defer j.writeMu.Unlock()
+	j.mu.Lock()
+	ops := j.queue
+	j.queue = nil
+	j.mu.Unlock()
+	for _, op := range ops {
+		op()
+	}
+}
+
+// record journals the change from the last entry to items and trash.
+func (j *journal) record(items, trash []model.Item) {
+	items, trash = slices.Clone(items), slices.Clone(trash)
+	e := journalEntry{Time: time.Now()}
+	if !j.started {
+		e.Full, e.Items, e.Trash = true, items, trash
+	} else {
+		e.Edits = append(listEdits(j.items, items, false), listEdits(j.trash, trash, true)...)
+		if len(e.Edits) == 0 {
+			return
+		}
+	}
+	j.items, j.trash, j.started = items, trash, true
+	path := j.path
+	j.push(func() {
+		if e.Full {
+			writeJournal(path, e)
+		} else if size, err := appendJournal(path, e); err == nil && size > journalCompact {
+			// Starsze wpisy i tak nic nie wnoszą – liczy się stan po ostatnim
+			writeJournal(path, journalEntry{Time: e.Time, Full: true, Items: items, Trash: trash})
+		}
+	})
+}
+
+// drop removes the journal once what it holds is on disk.
+func (j *journal) drop() {
+	j.items, j.trash, j.started = nil, nil, false
+	path := j.path
+	j.push(func() { os.Remove(path) })
+}
+
+// listEdits is the edit turning old into new: what lies between the parts
+// both share at the start and at the end. Nil when they are the same.
+func listEdits(old, new []model.Item, trash bool) []journalEdit {
+	pre := 0
+	for pre < len(old) && pre < len(new) && model.SameItem(old[pre], new[pre]) {
+		pre++
+	}
+	suf := 0
+	for suf < len(old)-pre && suf < len(new)-pre && model.SameItem(old[len(old)-1-suf], new[len(new)-1-suf]) {
+		suf++
+	}
+	if pre+suf == len(old) && pre+suf == len(new) {
+		return nil
+	}
+	return []journalEdit{{Trash: trash, At: pre, Del: len(old) - pre - suf, Items: new[pre : len(new)-suf]}}
+}
+
+// writeJournal starts the journal at path over with e.
+func writeJournal(path string, e journalEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", append(data, '\n'), 0600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// appendJournal adds e to the journal at path and returns its new size.
+func appendJournal(path string, e journalEntry) (int64, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return 0, err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	_, err = f.Write(append(data, '\n'))
+	size, serr := f.Seek(0, io.SeekCurrent)
+	if cerr := f.Close(); err == nil {
+		err = cmp.Or(serr, cerr)
+	}
+	return size, err
+}
+
+// readJournal replays the journal at path: the lists after its last
+// complete entry, with the time of that entry.
+func readJournal(path string) (journalEntry, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return journalEntry{}, false
+	}
+	defer f.Close()
+	var last journalEntry
+	found := false
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			// Linia bez końca to zapis przerwany awarią
+			break
+		}
+		var e journalEntry
+		if json.Unmarshal(line, &e) != nil {
+			continue
+		}
+		if e.Full {
+			last, found = journalEntry{Time: e.Time, Items: e.Items, Trash: e.Trash}, true
+			continue
+		}
+		if !found || !applyEdits(&last, e.Edits) {
+			break
+		}
+		last.Time = e.Time
+	}
+	return last, found
+}
+
+// applyEdits replays edits on the lists of e, or reports they don't fit.
+func applyEdits(e *journalEntry, edits []journalEdit) bool {
+	for _, ed := range edits {
+		list := &e.Items
+		if ed.Trash {
+			list = &e.Trash
+		}
+		if ed.At < 0 || ed.Del < 0 || ed.At+ed.Del > len(*list) {
+			return false
+		}
+		*list = slices.Concat((*list)[:ed.At], ed.Items, (*list)[ed.At+ed.Del:])
+	}
+	return true
+}
+
+// --- APP SIDE ---
+
+// journalChange journals the change just made. A journal that can't be
+// written doesn't stop the edit; it's only a safety net.
+func (m *app) journalChange() {
+	if m.journal != nil {
+		m.journal.record(m.items, m.trash)
+	}
+}
+
+// dropJournal removes the journal once what it holds is on disk; wait
+// makes sure it is gone before returning, for quitting.
+func (m *app) dropJournal(wait bool) {
+	if m.journal == nil {
+		return
+	}
+	m.journal.drop()
+	if wait {
+		m.journal.drain()
+	}
+}
+
+// writeJournal hands what the last update journaled to a background write.
+func (m *app) writeJournal() tea.Cmd {
+	j := m.journal
+	if j == nil || !j.fresh {
+		return nil
+	}
+	j.fresh = false
+	return func() tea.Msg {
+		j.drain()
+		return nil
+	}
+}
+
+// checkJournal looks for changes a previous session didn't save and asks
+// about them. Called at start, with the lock held.
+func (m *app) checkJournal() {
+	if m.journal == nil {
+		return
+	}
+	path := m.journal.path
+	e, ok := readJournal(path)
+	switch {
+	case !ok:
+		os.Remove(path) // pusty albo cały urwany
+	case !e.Time.After(m.fileModTime), sameList(e.Items, m.items) && sameList(e.Trash, m.trash):
+		os.Remove(path) // plik jest nowszy albo już to zawiera
+	default:
+		m.recovery = &e
+	}
+}
+
+// restoreJournal takes the list from the journal.
+func (m *app) restoreJournal() {
+	e := m.recovery
+	m.recovery = nil
+	m.items, m.trash = e.Items, e.Trash
+	m.cursorMain, m.cursorTrash = 0, 0
+	m.recalcVisible()
+	m.save()
+	m.status = "Restored the list from " + e.Time.Local().Format("2006-01-02 15:04")
+}
+
+// discardJournal keeps the file as it is, with the journal moved aside.
+func (m *app) discardJournal() {
+	m.recovery = nil
+	if m.journal != nil {
+		path := m.journal.path
+		os.Rename(path, path+".discarded")
+		m.status = "Unsaved changes discarded (kept in " + abbreviateHome(path) + ".discarded)"
+	}
+}
+
+// --- RECOVERY PROMPT ---
+
+func (m *app) updateRecoveryPrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "r", "y", "enter":
+		m.restoreJournal()
+	case "d", "n":
+		m.discardJournal()
+	case "q", "ctrl+c":
+		// Dziennik zostaje na następny raz
+		m.quitting = true
+		return tea.Quit
+	}
+	return nil
+}
+
+func (m app) renderRecoveryPrompt(t theme.Theme) string {
+	e := m.recovery
+	title := lipgloss.NewStyle().Foreground(t.Accent).Bold(true).Render(filepath.Base(m.filename) + " has unsaved changes")
+	body := lipgloss.NewStyle().Foreground(t.Text).Render(fmt.Sprintf(
+		"The last session ended at %s without saving them.\nThe journal holds %d items, the file %d.",
+		e.Time.Local().Format("2006-01-02 15:04"), len(e.Items), len(m.items)))
+	keys := lipgloss.NewStyle().Foreground(t.Comment).Render("r: restore • d: discard • q: quit and decide later")
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Accent).
+		Padding(0, 1).
+		Render(title + "\n" + body + "\n\n" + keys)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestJournalRecovery(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	f := filepath.Join(t.TempDir(), "todo.md")
+	os.WriteFile(f, []byte("- [ ] a\n"), 0644)
+	journal, err := journalPath(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sesja dodaje zadanie i "pada" przed zapisem
+	crash := func() {
+		t.Helper()
+		m := initialModel(f)
+		m.width, m.height = 80, 20
+		for _, k := range []string{"n", "b", "enter"} {
+			next, _ := m.Update(keyMsg(k))
+			m = next.(app)
+		}
+		if !m.dirty {
+			t.Fatal("the new task must wait to be saved")
+		}
+		m.lock.release()
+		m.journal.drain()
+		if _, err := os.Stat(journal); err != nil {
+			t.Fatalf("no journal: %v", err)
+		}
+	}
+
+	crash()
+	m := initialModel(f)
+	m.width, m.height = 80, 20
+	if m.recovery == nil {
+		t.Fatal("the journal of the crashed session must be offered")
+	}
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "todo.md has unsaved changes") || !strings.Contains(view, "holds 2 items, the file 1") {
+		t.Errorf("prompt:\n%s", view)
+	}
+	next, _ := m.Update(keyMsg("j"))
+	m = next.(app)
+	if m.recovery == nil || m.cursorMain != 0 {
+		t.Fatal("other keys must wait for an answer")
+	}
+	next, _ = m.Update(keyMsg("r"))
+	m = next.(app)
+	if got := flat(m.items); len(got) != 2 || !strings.HasPrefix(got[1], "0:b") {
+		t.Fatalf("restored %q", got)
+	}
+	if err := m.flushSave(); err != nil {
+		t.Fatal(err)
+	}
+	m.lock.release()
+	if data, _ := os.ReadFile(f); !strings.HasPrefix(string(data), "- [ ] a\n- [ ] b created:") {
+		t.Errorf("saved %q", data)
+	}
+	if _, err := os.Stat(journal); !os.IsNotExist(err) {
+		t.Error("the journal must go once the file is saved")
+	}
+
+	crash()
+	m = initialModel(f)
+	next, _ = m.Update(keyMsg("d"))
+	m = next.(app)
+	m.lock.release()
+	if m.recovery != nil || len(m.items) != 2 || !strings.Contains(m.status, "discarded") {
+		t.Fatalf("discard: %d items, %q", len(m.items), m.status)
+	}
+	if _, err := os.Stat(journal + ".discarded"); err != nil {
+		t.Error("a discarded journal is kept aside")
+	}
+
+	// Plik zmieniony po ostatnim wpisie wygrywa bez pytania
+	writeJournal(journal, journalEntry{Time: time.Now().Add(-time.Hour), Full: true, Items: []model.Item{{Title: "old"}}})
+	m = initialModel(f)
+	m.lock.release()
+	if m.recovery != nil {
+		t.Error("a journal older than the file must not be offered")
+	}
+	if _, err := os.Stat(journal); !os.IsNotExist(err) {
+		t.Error("a stale journal must be removed")
+	}
+}
+
+func TestReadJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "j.jsonl")
+	if _, ok := readJournal(path); ok {
+		t.Error("a missing journal has no entry")
+	}
+	writeJournal(path, journalEntry{Full: true, Items: []model.Item{{Title: "one"}, {Title: "keep"}}})
+	appendJournal(path, journalEntry{Edits: []journalEdit{{At: 0, Del: 1, Items: []model.Item{{Title: "two", Level: 1, Note: []string{"x"}}}}}})
+	appendJournal(path, journalEntry{Edits: []journalEdit{{Trash: true, Items: []model.Item{{Title: "one"}}}}})
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	f.WriteString(`{"time":"2030-01-01T00:00:00Z","edits":[{"at":0,"items":[{"Title":"thr`)
+	f.Close()
+	e, ok := readJournal(path)
+	if !ok || len(e.Items) != 2 || e.Items[0].Title != "two" || e.Items[0].Level != 1 || e.Items[0].Note[0] != "x" ||
+		e.Items[1].Title != "keep" || len(e.Trash) != 1 {
+		t.Errorf("replayed = %+v, %v", e, ok)
+	}
+
+	// Zmiana zapisuje tylko to, co się zmieniło
+	old := []model.Item{{Title: "a"}, {Title: "b"}, {Title: "c"}}
+	edits := listEdits(old, []model.Item{{Title: "a"}, {Title: "B"}, {Title: "new"}, {Title: "c"}}, false)
+	if len(edits) != 1 || edits[0].At != 1 || edits[0].Del != 1 || len(edits[0].Items) != 2 {
+		t.Errorf("edits = %+v", edits)
+	}
+	if listEdits(old, old, false) != nil {
+		t.Error("no change, no edit")
+	}
+}
+
+func TestJournalKeptForNewerChanges(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	f := filepath.Join(t.TempDir(), "todo.md")
+	m := app{filename: f, items: []model.Item{{Title: "a"}}, journal: newJournal(f)}
+	journal := m.journal.path
+	m.save()
+	m.items[0].Title = "b"
+	m.save()
+	// Zapis pierwszej zmiany skończył się po drugiej
+	m.handleSaved(savedMsg{gen: 1})
+	m.journal.drain()
+	if e, _ := readJournal(journal); len(e.Items) != 1 || e.Items[0].Title != "b" {
+		t.Fatalf("the journal must outlive a save older than the last change: %+v", e)
+	}
+	m.handleSaved(savedMsg{gen: 2})
+	m.journal.drain()
+	if _, err := os.Stat(journal); !os.IsNotExist(err) {
+		t.Error("the journal must go once the last change is saved")
+	}
+}
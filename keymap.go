@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- KEYMAPS ---
+//
+// Config "keys" rebinds keys per view; "global" entries apply to every view
+// that has the action, and view entries win over them:
+//
+//	"keys": {"main": {"x": "toggle"}, "trash": {"x": "purge"}, "global": {"Q": "quit"}}
+//
+// Binding a key to "none" disables it. A keymap that leaves a view without
+// its quit/back action is rejected as a whole, so the app can't trap you.
+
+type viewKeys struct {
+	name  string
+	state appState
+	// actions maps an action to its default keys; the first one is what the
+	// view's update function handles
+	actions map[string][]string
+	// required actions must stay reachable
+	required []string
+}
+
+var viewKeymaps = []viewKeys{
+	{"main", viewMain, map[string][]string{
+		"up": {"k", "up"}, "down": {"j", "down"}, "toggle": {" "}, "fold": {"v"},
+		"new": {"n"}, "subtask": {"m"}, "edit": {"e"}, "delete": {"d", "delete"},
+		"indent": {">"}, "outdent": {"<"}, "level": {"tab"}, "theme": {"t"}, "compact": {"C"}, "wrap": {"ctrl+w"}, "find": {"ctrl+p"}, "split": {"|"}, "widen": {"["}, "narrow": {"]"}, "columns": {"c"}, "today": {"1"}, "sync": {"S"},
+		"detail": {"i"}, "pomodoro": {"P"}, "properties": {"p"}, "track": {"T"},
+		"snooze": {"s"}, "lock": {"L"}, "move": {"M"}, "duplicate": {"D"}, "zoom": {"z"}, "unzoom": {"esc"},
+		"link": {"enter"}, "return": {"ctrl+o"}, "backlinks": {"b"},
+		"block": {"w"}, "deps": {"W"}, "star": {"*"}, "url": {"o"}, "state": {"x"}, "command": {":"}, "filters": {"F"}, "bin": {"B"}, "quit": {"q"},
+	}, []string{"quit"}},
+	{"trash", viewTrash, map[string][]string{
+		"up": {"k", "up"}, "down": {"j", "down"}, "restore": {"enter"}, "purge": {"x"}, "empty": {"X"},
+		"back": {"esc", "B", "q"},
+	}, []string{"back"}},
+	{"themes", viewThemeSelector, map[string][]string{
+		"up": {"k", "up"}, "down": {"j", "down"}, "select": {"enter"}, "back": {"esc", "q"},
+	}, []string{"back"}},
+	{"lint", viewLint, map[string][]string{
+		"up": {"k", "up"}, "down": {"j", "down"}, "jump": {"enter"}, "back": {"esc", "q"},
+	}, []string{"back"}},
+	{"detail", viewDetail, map[string][]string{
+		"up": {"k", "up"}, "down": {"j", "down"}, "edit": {"enter"}, "next": {"right", "l", " "},
+		"prev": {"left", "h"}, "clear": {"x"}, "back": {"esc", "i", "q"},
+	}, []string{"back"}},
+	{"stats", viewStats, map[string][]string{
+		"week": {"w"}, "month": {"m"}, "back": {"esc", "q"},
+	}, []string{"back"}},
+	{"calendar", viewCalendar, map[string][]string{
+		"agenda": {"enter"}, "back": {"esc", "q"},
+	}, []string{"back"}},
+	{"plan", viewPlan, map[string][]string{
+		"up": {"k", "up"}, "down": {"j", "down"}, "skip": {" "}, "apply": {"enter"}, "back": {"esc", "q"},
+	}, []string{"back"}},
+	{"replace", viewReplace, map[string][]string{
+		"up": {"k", "up"}, "down": {"j", "down"}, "skip": {" "}, "apply": {"enter"}, "back": {"esc", "q"},
+	}, []string{"back"}},
+	{"filters", viewFilters, map[string][]string{
+		"up": {"k", "up"}, "down": {"j", "down"}, "apply": {"enter"}, "delete": {"x"}, "back": {"esc", "q"},
+	}, []string{"back"}},
+	{"templates", viewTemplates, map[string][]string{
+		"up": {"k", "up"}, "down": {"j", "down"}, "insert": {"enter"}, "delete": {"x"}, "back": {"esc", "q"},
+	}, []string{"back"}},
+	{"groups", viewGroups, map[string][]string{
+		"up": {"k", "up"}, "down": {"j", "down"}, "open": {"enter"}, "toggle": {" "}, "fold": {"v"},
+		"mode": {"tab"}, "back": {"esc", "q"},
+	}, []string{"back"}},
+	{"archive", viewArchive, map[string][]string{
+		"up": {"k", "up"}, "down": {"j", "down"}, "fold": {"v", " ", "enter"}, "back": {"esc", "q"},
+	}, []string{"back"}},
+	{"backlinks", viewBacklinks, map[string][]string{
+		"up": {"k", "up"}, "down": {"j", "down"}, "jump": {"enter"}, "back": {"esc", "q"},
+	}, []string{"back"}},
+	{"deps", viewDeps, map[string][]string{
+		"up": {"k", "up"}, "down": {"j", "down"}, "jump": {"enter"}, "toggle": {" "}, "back": {"esc", "q"},
+	}, []string{"back"}},
+	{"smart", viewSmart, map[string][]string{
+		"up": {"k", "up"}, "down": {"j", "down"}, "jump": {"enter"}, "toggle": {" "}, "star": {"*"},
+		"back": {"esc", "q"},
+	}, []string{"back"}},
+	{"today", viewToday, map[string][]string{
+		"up": {"k", "up"}, "down": {"j", "down"}, "jump": {"enter"}, "toggle": {" "}, "star": {"*"},
+		"reschedule": {"r"}, "back": {"esc", "1", "q"},
+	}, []string{"back"}},
+	{"review", viewReview, map[string][]string{
+		"keep": {"enter"}, "complete": {" "}, "reschedule": {"r"}, "delete": {"d"}, "move": {"m"}, "skip": {"s"},
+		"back": {"esc", "q"},
+	}, []string{"back"}},
+	{"agenda", viewAgenda, map[string][]string{
+		"up": {"k", "up"}, "down": {"j", "down"}, "jump": {"enter"}, "reschedule": {"r"},
+		"back": {"esc", "q"},
+	}, []string{"back"}},
+}
+
+// keymap translates a pressed key into the key the view handles ("" drops it).
+type keymap map[appState]map[string]string
+
+// normalizeKey accepts "space" for the space bar, as typed in config files.
+func normalizeKey(k string) string {
+	if strings.EqualFold(k, "space") {
+		return " "
+	}
+	return k
+}
+
+// parseKeymap validates the config bindings and resolves them per view.
+func parseKeymap(cfg map[string]map[string]string) (keymap, error) {
+	known := map[string]bool{"global": true}
+	for _, v := range viewKeymaps {
+		known[v.name] = true
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg)) {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown view %q", name)
+		}
+		for key, action := range cfg[name] {
+			if normalizeKey(key) == "ctrl+c" {
+				return nil, fmt.Errorf("%s: ctrl+c always quits and can't be rebound", name)
+			}
+			if action == "none" || name == "global" {
+				continue
+			}
+			if _, ok := viewActions(name)[action]; !ok {
+				return nil, fmt.Errorf("%s: unknown action %q for %q", name, action, key)
+			}
+		}
+	}
+	for key, action := range cfg["global"] {
+		if action != "none" && !slices.ContainsFunc(viewKeymaps, func(v viewKeys) bool { _, ok := v.actions[action]; return ok }) {
+			return nil, fmt.Errorf("global: unknown action %q for %q", action, key)
+		}
+	}
+
+	km := make(keymap)
+	for _, v := range viewKeymaps {
+		// Efektywne przypisania: domyślne, potem globalne, na końcu widoku
+		effective := make(map[string]string)
+		for action, keys := range v.actions {
+			for _, k := range keys {
+				effective[k] = action
+			}
+		}
+		overrides := make(map[string]string)
+		for _, section := range []string{"global", v.name} {
+			for key, action := range cfg[section] {
+				if _, ok := v.actions[action]; ok || action == "none" {
+					effective[normalizeKey(key)] = action
+					overrides[normalizeKey(key)] = action
+				}
+			}
+		}
+		for _, req := range v.required {
+			if !slices.Contains(slices.Collect(maps.Values(effective)), req) {
+				return nil, fmt.Errorf("%s: no key left for %q", v.name, req)
+			}
+		}
+		if len(overrides) == 0 {
+			continue
+		}
+		km[v.state] = make(map[string]string)
+		for key, action := range overrides {
+			if action == "none" {
+				km[v.state][key] = ""
+			} else {
+				km[v.state][key] = v.actions[action][0]
+			}
+		}
+	}
+	return km, nil
+}
+
+func viewActions(name string) map[string][]string {
+	for _, v := range viewKeymaps {
+		if v.name == name {
+			return v.actions
+		}
+	}
+	return nil
+}
+
+var specialKeys = map[string]tea.KeyType{
+	"enter": tea.KeyEnter, "esc": tea.KeyEsc, "tab": tea.KeyTab, "delete": tea.KeyDelete,
+	"up": tea.KeyUp, "down": tea.KeyDown, "left": tea.KeyLeft, "right": tea.KeyRight,
+	" ": tea.KeySpace,
+}
+
+// keyMsg builds the message a view expects for one of its default keys.
+func keyMsg(key string) tea.KeyMsg {
+	if t, ok := specialKeys[key]; ok {
+		msg := tea.KeyMsg{Type: t}
+		if t == tea.KeySpace {
+			msg.Runes = []rune{' '}
+		}
+		return msg
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+// remap applies the user keymap of the current view. It returns false when
+// the key is disabled.
+func (m app) remap(msg tea.KeyMsg) (tea.KeyMsg, bool) {
+	if m.pendingKey != "" {
+		// Drugi klawisz sekwencji (np. "gp") zostaje bez zmian
+		return msg, true
+	}
+	target, ok := m.keys[m.state][msg.String()]
+	if !ok {
+		return msg, true
+	}
+	if target == "" {
+		return msg, false
+	}
+	return keyMsg(target), true
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestParseKeymapValidation(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	tests := []struct {
+		name string
+		cfg  map[string]map[string]string
+		ok   bool
+	}{
+		{"empty", nil, true},
+		{"per view", map[string]map[string]string{"main": {"x": "toggle"}, "trash": {"X": "purge"}}, true},
+		{"quit moved", map[string]map[string]string{"main": {"q": "none", "Q": "quit"}}, true},
+		{"unknown view", map[string]map[string]string{"inbox": {"x": "toggle"}}, false},
+		{"unknown action", map[string]map[string]string{"main": {"x": "purge"}}, false},
+		{"global unknown", map[string]map[string]string{"global": {"x": "fly"}}, false},
+		{"no way out", map[string]map[string]string{"main": {"q": "toggle"}}, false},
+		{"bin trapped", map[string]map[string]string{"global": {"esc": "none", "q": "none"}, "trash": {"B": "purge"}}, false},
+		{"ctrl+c", map[string]map[string]string{"main": {"ctrl+c": "toggle"}}, false},
+	}
+	for _, tt := range tests {
+		_, err := parseKeymap(tt.cfg)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: err = %v", tt.name, err)
+		}
+	}
+}
+
+func TestKeymapPerView(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	keys, err := parseKeymap(map[string]map[string]string{
+		"main":  {"x": "toggle", "space": "none"},
+		"trash": {"x": "purge"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := app{items: []model.Item{{Title: "a"}}, keys: keys, state: viewMain}
+	m.recalcVisible()
+	press := func(k string) {
+		next, _ := m.Update(keyMsg(k))
+		m = next.(app)
+	}
+
+	press(" ")
+	if m.items[0].Done {
+		t.Fatal("disabled space still toggles")
+	}
+	press("x")
+	if !m.items[0].Done {
+		t.Fatal("x did not toggle in main")
+	}
+
+	m.trash = []model.Item{{Title: "old"}}
+	m.state = viewTrash
+	press("x")
+	if len(m.trash) != 0 {
+		t.Errorf("x did not purge in trash: %v", m.trash)
+	}
+}
+
+func TestKeyMsgRoundTrip(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	for _, k := range []string{"enter", "esc", " ", "up", "delete", "x", ":", ">"} {
+		if got := keyMsg(k).String(); got != k {
+			t.Errorf("keyMsg(%q).String() = %q", k, got)
+		}
+	}
+	if keyMsg("tab").Type != tea.KeyTab {
+		t.Error("tab")
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- COMPACT LAYOUT ---
+//
+// "compact": true in config.json (or C in the list) drops the rounded frame
+// and the blank lines around the header and footer, so the views get two
+// more columns and five more rows — a lot in a small tmux pane.
+
+// compactOverhead: header(1) + footer(1)
+const compactOverhead = 2
+
+// --- TINY WINDOWS ---
+//
+// A window too small for the frame gets the compact layout whatever the
+// config says: below minFramedRows rows of list or crampedWidth columns.
+// Two rows keep the list and the footer, a single one just the list (or
+// the command line while typing a command). Below minRowWidth columns the
+// list and the bin show one title a row, cut short, and whatever a view
+// draws is clipped to the window.
+
+const (
+	minFramedRows = 3
+	crampedWidth  = 20
+	minRowWidth   = 18
+)
+
+// compact tells whether the view is drawn without the frame.
+func (m app) compact() bool {
+	return m.config.Compact || m.inline || m.height < uiOverhead+minFramedRows || m.width < crampedWidth
+}
+
+// chromeHeight is the number of lines the header, footer and frame take.
+func (m app) chromeHeight() int {
+	if m.compact() {
+		return max(0, min(compactOverhead, m.height-1))
+	}
+	return uiOverhead
+}
+
+// tinyList draws a list too narrow for the tree: a title a row, cut short,
+// with › at the cursor.
+func tinyList(titles []string, cursor, width, height int, t theme.Theme) string {
+	from := max(0, cursor-height+1)
+	lines := make([]string, height)
+	for i := from; i < min(len(titles), from+height); i++ {
+		style, marker := lipgloss.NewStyle().Foreground(t.Text), " "
+		if i == cursor {
+			style, marker = lipgloss.NewStyle().Foreground(t.Highlight).Bold(true), "›"
+		}
+		lines[i-from] = style.Render(ansi.Truncate(marker+titles[i], width, "…"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// clipScreen cuts the view to the window, so nothing a view draws can
+// scroll the terminal.
+func clipScreen(view string, width, height int) string {
+	lines := strings.Split(view, "\n")
+	lines = lines[:min(len(lines), max(1, height))]
+	for i, line := range lines {
+		if ansi.StringWidth(line) > width {
+			lines[i] = ansi.Truncate(line, width, "")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// frame is the box a view is drawn in, height lines inside; the border
+// takes color. In the compact layout it has no border and the view is
+// rendered two columns wider (see View), so it fills the width either way.
+func (m app) frame(height int, color lipgloss.TerminalColor) lipgloss.Style {
+	style := lipgloss.NewStyle().Width(max(1, m.width-2)).Height(height)
+	if m.config.Compact {
+		return style
+	}
+	return style.Border(lipgloss.RoundedBorder()).BorderForeground(color)
+}
+
+func (m *app) toggleCompact() {
+	m.config.Compact = !m.config.Compact
+	m.status = "Compact layout off"
+	if m.config.Compact {
+		m.status = "Compact layout on"
+	}
+	m.saveLayout()
+}
+
+// --- TRUNCATED TITLES ---
+//
+// "truncate": true (or ctrl+w in the list) keeps every task on one line,
+// cutting long titles with "…" instead of wrapping them, which makes dense
+// lists easier to scan. The task being edited still wraps; i shows a title
+// in full.
+
+func (m *app) toggleTruncate() {
+	m.config.Truncate = !m.config.Truncate
+	m.status = "Long titles wrap"
+	if m.config.Truncate {
+		m.status = "Long titles truncated"
+	}
+	m.saveLayout()
+}
+
+// saveLayout keeps a layout toggle in the config file, reporting in the
+// status line when it can't.
+func (m *app) saveLayout() {
+	switch {
+	case m.configErr != nil:
+		m.status += " (not saved: the config file is invalid)"
+	default:
+		if err := saveConfig(m.config); err != nil {
+			m.status += " (not saved: " + err.Error() + ")"
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// TestTinyWindows draws every view in windows from 1×1 up: nothing may
+// panic or spill out of the window, and the main view degrades step by
+// step instead of giving up.
+func TestTinyWindows(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	views := []appState{viewMain, viewTrash, viewThemeSelector, viewLint, viewDetail, viewStats, viewAgenda, viewCalendar,
+		viewPlan, viewTemplates, viewGroups, viewArchive, viewBacklinks, viewDeps, viewSmart, viewReview, viewToday, viewReplace, viewFilters}
+	for _, state := range views {
+		for w := 1; w <= 30; w++ {
+			for h := 1; h <= 12; h++ {
+				m := app{width: w, height: h, demo: true, state: state, activeTheme: theme.Default, items: []model.Item{
+					{Title: "alpha task due:2030-01-01"}, {Title: "beta", Level: 1}, {Title: "gamma", Level: 2},
+				}, trash: []model.Item{{Title: "gone"}}}
+				m.recalcVisible()
+				name := fmt.Sprintf("view %d at %dx%d", state, w, h)
+				func() {
+					defer func() {
+						if r := recover(); r != nil {
+							t.Fatalf("%s: panic %v", name, r)
+						}
+					}()
+					for _, k := range []string{"j", "pgdown", "G", "k"} {
+						next, _ := m.Update(keyMsg(k))
+						m = next.(app)
+					}
+					m.state, m.cursorMain = state, 0
+					lines := strings.Split(ansi.Strip(m.View()), "\n")
+					if len(lines) > h {
+						t.Errorf("%s: %d lines", name, len(lines))
+					}
+					for _, line := range lines {
+						if lipgloss.Width(line) > w {
+							t.Errorf("%s: line %q too wide", name, line)
+							break
+						}
+					}
+					if state != viewMain {
+						return
+					}
+					view := strings.Join(lines, "\n")
+					if w >= 4 && !strings.Contains(view, "al") {
+						t.Errorf("%s: the cursor's task is gone:\n%s", name, view)
+					}
+					// Ramka ma pustą linię nad nagłówkiem
+					top := strings.Join(lines[:min(2, len(lines))], "\n")
+					if hasHeader := strings.Contains(top, "//"); hasHeader != (h >= 3 && w >= 3) {
+						t.Errorf("%s: header shown %v:\n%s", name, hasHeader, view)
+					}
+					if framed := strings.Contains(view, "╭"); framed != (h >= uiOverhead+minFramedRows && w >= crampedWidth) {
+						t.Errorf("%s: frame shown %v:\n%s", name, framed, view)
+					}
+				}()
+			}
+		}
+	}
+}
+
+func TestTinyWindowCommandLine(t *testing.T) {
+	m := app{width: 20, height: 1, demo: true, items: []model.Item{{Title: "alpha"}}}
+	m.recalcVisible()
+	if got := ansi.Strip(m.View()); !strings.Contains(got, "alpha") {
+		t.Errorf("one row shows %q", got)
+	}
+	next, _ := m.Update(keyMsg(":"))
+	m = next.(app)
+	if got := ansi.Strip(m.View()); !strings.HasPrefix(strings.TrimSpace(got), ":") {
+		t.Errorf("typing a command shows %q", got)
+	}
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- LINKS ---
+//
+// A title may link to other tasks wiki-style:
+//
+//	[[fix token refresh]]          a task of this list, by title or id
+//	[[backend.md]]                 another list (relative to this one)
+//	[[backend.md#fix token refresh]] a task of another list
+//
+// Enter on a task follows its first link and ctrl+o goes back. b lists the
+// tasks linking to the one under the cursor, from this list and from the
+// workspace lists `todo grep` searches.
+
+var wikiLink = regexp.MustCompile(`\[\[([^\[\]]+)\]\]`)
+
+type taskLink struct {
+	file string // "" for this list
+	task string // "" for the list itself
+}
+
+// parseLinks returns the links of a title in order.
+func parseLinks(title string) []taskLink {
+	var out []taskLink
+	for _, m := range wikiLink.FindAllStringSubmatch(title, -1) {
+		target := strings.TrimSpace(m[1])
+		file, task, found := strings.Cut(target, "#")
+		switch {
+		case found:
+			out = append(out, taskLink{file: strings.TrimSpace(file), task: strings.TrimSpace(task)})
+		case strings.HasSuffix(target, ".md"):
+			out = append(out, taskLink{file: target})
+		default:
+			out = append(out, taskLink{task: target})
+		}
+	}
+	return out
+}
+
+var metaWord = regexp.MustCompile(`^[a-z]+:[^/]\S*$`)
+
+// linkName is what a link matches: the title without its own links, tags,
+// key:value metadata and extra spaces.
+func linkName(title string) string {
+	var words []string
+	for _, w := range strings.Fields(wikiLink.ReplaceAllString(title, "")) {
+		if (len(w) > 1 && w[0] == '#') || metaWord.MatchString(w) {
+			continue
+		}
+		words = append(words, w)
+	}
+	return strings.Join(words, " ")
+}
+
+// findLinked returns the task a link names, by id or by title (ignoring case).
+func findLinked(items []model.Item, name string) int {
+	if i := model.FindByID(items, name); i != -1 {
+		return i
+	}
+	for i, it := range items {
+		if strings.EqualFold(linkName(it.Title), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// linkFile resolves the file of a link against the list it sits in.
+func linkFile(from string, l taskLink) string {
+	if l.file == "" {
+		return from
+	}
+	if filepath.IsAbs(l.file) {
+		return l.file
+	}
+	return filepath.Join(filepath.Dir(from), l.file)
+}
+
+// linkPlace is a spot to come back to with ctrl+o.
+type linkPlace struct {
+	file string
+	key  string // sessionKey of the item under the cursor
+}
+
+func (m *app) here() linkPlace {
+	p := linkPlace{file: m.filename}
+	if len(m.visibleItems) > 0 {
+		p.key = sessionKey(m.items[m.visibleItems[m.cursorMain].Index])
+	}
+	return p
+}
+
+// goTo shows a task of file, switching lists when needed. task is a
+// sessionKey when byKey is set and a link name otherwise; "" just opens file.
+func (m app) goTo(file, task string, byKey bool) (app, bool) {
+	if stateKey(file) != stateKey(m.filename) {
+		if m.saving {
+			m.status = "Still saving, try again"
+			return m, false
+		}
+		if err := m.flushSave(); err != nil {
+			m.showError("Can't save "+filepath.Base(m.filename), err.Error())
+			return m, false
+		}
+		m.saveSession()
+		m.lock.release()
+		n := initialModel(file)
+		n.width, n.height = m.width, m.height
+		n.reminders, n.toasts = m.reminders, m.toasts
+		n.pomoID, n.pomoEnd = m.pomoID, m.pomoEnd
+		n.linkBack = m.linkBack
+		m = n
+	}
+	if task == "" {
+		return m, true
+	}
+	idx := -1
+	if byKey {
+		for i, it := range m.items {
+			if sessionKey(it) == task {
+				idx = i
+				break
+			}
+		}
+	} else {
+		idx = findLinked(m.items, task)
+	}
+	if idx == -1 {
+		m.status = fmt.Sprintf("No task “%s” in %s", task, filepath.Base(file))
+		return m, true
+	}
+	m.state = viewMain
+	m.jumpTo(idx)
+	return m, true
+}
+
+// followLink opens the first link of items[idx].
+func (m app) followLink(idx int) app {
+	links := parseLinks(m.items[idx].Title)
+	if len(links) == 0 {
+		return m
+	}
+	back := m.here()
+	next, ok := m.goTo(linkFile(m.filename, links[0]), links[0].task, false)
+	if ok {
+		next.linkBack = append(next.linkBack, back)
+	}
+	return next
+}
+
+// linkReturn goes back to where the last link was followed from.
+func (m app) linkReturn() app {
+	if len(m.linkBack) == 0 {
+		m.status = "No link to go back from"
+		return m
+	}
+	p := m.linkBack[len(m.linkBack)-1]
+	m.linkBack = m.linkBack[:len(m.linkBack)-1]
+	next, ok := m.goTo(p.file, p.key, true)
+	if !ok {
+		next.linkBack = append(next.linkBack, p)
+	}
+	return next
+}
+
+// --- BACKLINKS ---
+
+type backlink struct {
+	file  string
+	idx   int
+	title string
+	path  []string
+}
+
+// backlinksIn lists the tasks of items (stored in file) linking to the task
+// target of the list targetFile.
+func backlinksIn(file string, items []model.Item, targetFile string, target model.Item) []backlink {
+	var out []backlink
+	for i, it := range items {
+		for _, l := range parseLinks(it.Title) {
+			if l.task == "" || stateKey(linkFile(file, l)) != stateKey(targetFile) {
+				continue
+			}
+			if (model.ID(target) != "" && l.task == model.ID(target)) || strings.EqualFold(l.task, linkName(target.Title)) {
+				out = append(out, backlink{file: file, idx: i, title: model.DisplayTitle(it.Title), path: ancestorTitles(items, i)})
+				break
+			}
+		}
+	}
+	return out
+}
+
+func (m *app) openBacklinks() {
+	if len(m.visibleItems) == 0 {
+		return
+	}
+	target := m.items[m.visibleItems[m.cursorMain].Index]
+	links := backlinksIn(m.filename, m.items, m.filename, target)
+	for _, file := range workspaceFiles(m.config, nil) {
+		if stateKey(file) == stateKey(m.filename) {
+			continue
+		}
+		items, _, err := storage.Load(file)
+		if err != nil {
+			continue
+		}
+		links = append(links, backlinksIn(file, items, m.filename, target)...)
+	}
+	if len(links) == 0 {
+		m.status = "Nothing links to “" + linkName(target.Title) + "”"
+		return
+	}
+	m.backlinks = links
+	m.backlinkTarget = linkName(target.Title)
+	m.cursorBacklink = 0
+	m.state = viewBacklinks
+}
+
+func (m app) updateBacklinks(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.state = viewMain
+	case "up", "k":
+		if m.cursorBacklink > 0 {
+			m.cursorBacklink--
+		}
+	case "down", "j":
+		if m.cursorBacklink < len(m.backlinks)-1 {
+			m.cursorBacklink++
+		}
+	case "enter":
+		if len(m.backlinks) == 0 {
+			break
+		}
+		b := m.backlinks[m.cursorBacklink]
+		back := m.here()
+		if stateKey(b.file) == stateKey(m.filename) {
+			m.state = viewMain
+			m.jumpTo(b.idx)
+			m.linkBack = append(m.linkBack, back)
+			break
+		}
+		next, ok := m.goTo(b.file, "", false)
+		if ok && b.idx < len(next.items) {
+			next.state = viewMain
+			next.jumpTo(b.idx)
+			next.linkBack = append(next.linkBack, back)
+		}
+		return next, nil
+	}
+	return m, nil
+}
+
+func (m app) renderBacklinks(height int, t theme.Theme) string {
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+	lines := []string{lipgloss.NewStyle().Foreground(t.Accent).Bold(true).Render("Linking to “" + m.backlinkTarget + "”")}
+	cursorLine := 0
+	for i, b := range m.backlinks {
+		marker := "  "
+		titleStyle := lipgloss.NewStyle().Foreground(t.Text)
+		if i == m.cursorBacklink {
+			marker = " ➤"
+			titleStyle = titleStyle.Foreground(t.Highlight).Bold(true)
+			cursorLine = len(lines)
+		}
+		var place []string
+		if stateKey(b.file) != stateKey(m.filename) {
+			place = append(place, filepath.Base(b.file))
+		}
+		if len(b.path) > 1 {
+			place = append(place, strings.Join(b.path[:len(b.path)-1], " > "))
+		}
+		where := strings.Join(place, " › ")
+		line := lipgloss.NewStyle().Foreground(t.Highlight).Render(marker) + " " + titleStyle.Render(b.title)
+		if where != "" {
+			line += dim.Render("  ‹ " + where)
+		}
+		lines = append(lines, line)
+	}
+
+	start, end := ui.Paginator(cursorLine, height, len(lines))
+	return m.frame(height, t.Highlight).
+		Render(strings.Join(lines[start:end], "\n"))
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestParseLinks(t *testing.T) {
+	got := parseLinks("see [[Fix token refresh]], [[backend.md]] and [[ops.md#Rotate keys]] [[]]")
+	want := []taskLink{{task: "Fix token refresh"}, {file: "backend.md"}, {file: "ops.md", task: "Rotate keys"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("links = %+v", got)
+	}
+	items := []model.Item{{Title: "a"}, {Title: "fix token refresh #auth due:2026-01-01"}}
+	if i := findLinked(items, "Fix Token Refresh"); i != 1 {
+		t.Errorf("findLinked = %d", i)
+	}
+}
+
+func TestFollowLinksAcrossFiles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	main, other := filepath.Join(dir, "todo.md"), filepath.Join(dir, "ops.md")
+	os.WriteFile(main, []byte("- [ ] Deploy [[ops.md#Rotate keys]]\n- [ ] Notes [[Deploy]]\n"), 0644)
+	os.WriteFile(other, []byte("- [ ] Intro\n- [ ] Rotate keys\n"), 0644)
+
+	m := initialModel(main)
+	defer func() { m.lock.release() }()
+	m.cursorMain = 1
+	next, _ := m.updateMain(keyMsg("enter"))
+	m = next.(app)
+	if m.cursorMain != 0 {
+		t.Fatalf("[[Deploy]] jumped to %d", m.cursorMain)
+	}
+
+	next, _ = m.updateMain(keyMsg("enter"))
+	m = next.(app)
+	if m.filename != other || model.DisplayTitle(m.items[m.visibleItems[m.cursorMain].Index].Title) != "Rotate keys" {
+		t.Fatalf("cross-file link opened %s at %d", m.filename, m.cursorMain)
+	}
+
+	m.config.Workspaces = []string{main}
+	m.openBacklinks()
+	if m.state != viewBacklinks || len(m.backlinks) != 1 || m.backlinks[0].file != main {
+		t.Fatalf("backlinks = %+v", m.backlinks)
+	}
+
+	m.state = viewMain
+	m = m.linkReturn()
+	if m.filename != main || m.cursorMain != 0 {
+		t.Errorf("ctrl+o came back to %s at %d", m.filename, m.cursorMain)
+	}
+	m = m.linkReturn()
+	if m.cursorMain != 1 || len(m.linkBack) != 0 {
+		t.Errorf("second ctrl+o at %d, %d left", m.cursorMain, len(m.linkBack))
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- CHECKLIST LINTING ---
+
+type LintConfig struct {
+	// MaxAgeMonths flags open tasks created longer ago than this (default 6).
+	MaxAgeMonths int `json:"max_age_months,omitempty"`
+}
+
+func (c *LintConfig) maxAge() int {
+	if c == nil || c.MaxAgeMonths <= 0 {
+		return 6
+	}
+	return c.MaxAgeMonths
+}
+
+type lintIssue struct {
+	index   int
+	rule    string
+	message string
+}
+
+// commonVerbs is deliberately small: a title containing any of them reads as an action.
+var commonVerbs = map[string]bool{}
+
+func init() {
+	for _, v := range strings.Fields(`
+		add adjust analyze answer apply approve archive arrange ask assign attend
+		backup book buy build call cancel change check choose clean clear close
+		collect compare complete configure confirm contact copy create debug decide
+		define delete deliver deploy design discuss do document download draft drop
+		edit email enable estimate evaluate exercise explore export file fill find
+		finish fix follow get give go implement import improve install investigate
+		invite learn list make measure meet merge migrate move order organize pack
+		pay pick plan polish practice prepare present print publish push read
+		record refactor register release remove rename renew repair replace reply
+		report request research reserve resolve return review rewrite run save
+		schedule send set setup share ship sign sort start submit subscribe support
+		take talk test text think track translate try tune update upgrade upload
+		validate verify visit wash watch write`) {
+		commonVerbs[v] = true
+	}
+}
+
+func hasVerb(title string) bool {
+	for _, w := range strings.Fields(strings.ToLower(title)) {
+		if strings.Contains(w, ":") || strings.HasPrefix(w, "#") {
+			continue
+		}
+		if commonVerbs[strings.Trim(w, ".,;:!?()\"'")] {
+			return true
+		}
+	}
+	return false
+}
+
+func lintItems(items []model.Item, cfg *LintConfig, now time.Time) []lintIssue {
+	var issues []lintIssue
+	ids := make(map[string]bool)
+	for _, it := range items {
+		if id := model.ID(it); id != "" {
+			ids[id] = true
+		}
+	}
+	cutoff := now.AddDate(0, -cfg.maxAge(), 0)
+
+	for i, it := range items {
+		if it.Heading > 0 {
+			continue
+		}
+		end := model.SubtreeEnd(items, i)
+		isParent := end > i+1
+
+		if !isParent && !hasVerb(model.DisplayTitle(it.Title)) {
+			issues = append(issues, lintIssue{i, "vague", "title has no verb"})
+		}
+
+		if !it.Done {
+			if created, err := time.Parse(model.DateLayout, model.MetaValue(it.Title, "created")); err == nil && created.Before(cutoff) {
+				issues = append(issues, lintIssue{i, "stale", fmt.Sprintf("open for more than %d months", cfg.maxAge())})
+			}
+		}
+
+		if isParent && it.Done {
+			doneChildren := 0
+			for k := i + 1; k < end; k++ {
+				if items[k].Level == it.Level+1 && items[k].Done {
+					doneChildren++
+				}
+			}
+			if doneChildren == 0 {
+				issues = append(issues, lintIssue{i, "parent", "marked done but none of its subtasks is"})
+			}
+		}
+
+		seen := make(map[string]bool)
+		for _, tag := range model.Tags(it.Title) {
+			tag = strings.ToLower(tag)
+			if seen[tag] {
+				issues = append(issues, lintIssue{i, "tags", "duplicate tag #" + tag})
+			}
+			seen[tag] = true
+		}
+
+		if rule := model.MetaValue(it.Title, "recur"); rule != "" {
+			if !model.ValidRecurrence(rule) {
+				issues = append(issues, lintIssue{i, "recur", "unknown recurrence " + rule})
+			} else if _, _, ok := model.DueTime(it.Title); !ok {
+				issues = append(issues, lintIssue{i, "recur", "repeats but has no due date"})
+			}
+		}
+
+		for _, dep := range model.MetaValues(it.Title, "blocked") {
+			if !ids[dep] {
+				issues = append(issues, lintIssue{i, "deps", "depends on missing task " + dep})
+			}
+		}
+	}
+	return issues
+}
+
+// --- CLI (todo lint) ---
+
+func runLint(args []string) {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	maxAge := fs.Int("max-age", 0, "flag open tasks older than N months (default from config, or 6)")
+	fs.Parse(args)
+
+	filename := "todo.md"
+	if fs.NArg() > 0 {
+		filename = fs.Arg(0)
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	cfg := config.Lint
+	if *maxAge > 0 {
+		cfg = &LintConfig{MaxAgeMonths: *maxAge}
+	}
+
+	items, _, lines, err := storage.LoadLines(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	issues := lintItems(items, cfg, time.Now())
+	for _, is := range issues {
+		fmt.Printf("%s:%d: [%s] %s — %s\n", filename, lines[is.index], is.rule, is.message, model.DisplayTitle(items[is.index].Title))
+	}
+	if len(issues) > 0 {
+		os.Exit(1)
+	}
+}
+
+// --- LINT VIEW ---
+
+func (m *app) openLint() {
+	m.lintIssues = lintItems(m.items, m.config.Lint, time.Now())
+	if len(m.lintIssues) == 0 {
+		m.status = "Lint: no problems found"
+		return
+	}
+	m.state = viewLint
+	m.cursorLint = 0
+}
+
+// jumpTo unfolds the ancestors of items[idx] and puts the main cursor on it.
+func (m *app) jumpTo(idx int) {
+	if root := m.zoomRoot(); root != -1 && (idx < root || idx >= model.SubtreeEnd(m.items, root)) {
+		m.zoomID = ""
+	}
+	for p := model.ParentIndex(m.items, idx); p != -1; p = model.ParentIndex(m.items, p) {
+		m.items[p].Collapsed = false
+	}
+	m.recalcVisible()
+	for i, v := range m.visibleItems {
+		if v.Index == idx {
+			m.cursorMain = i
+			break
+		}
+	}
+}
+
+func (m app) updateLint(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.state = viewMain
+	case "up", "k":
+		if m.cursorLint > 0 {
+			m.cursorLint--
+		}
+	case "down", "j":
+		if m.cursorLint < len(m.lintIssues)-1 {
+			m.cursorLint++
+		}
+	case "enter":
+		if len(m.lintIssues) > 0 {
+			idx := m.lintIssues[m.cursorLint].index
+			m.state = viewMain
+			if idx < len(m.items) {
+				m.jumpTo(idx)
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m app) renderLint(height int, t theme.Theme) string {
+	start, end := ui.Paginator(m.cursorLint, height, len(m.lintIssues))
+
+	var s strings.Builder
+	for i := start; i < end; i++ {
+		is := m.lintIssues[i]
+		cursor := "  "
+		titleStyle := lipgloss.NewStyle().Foreground(t.Text)
+		if m.cursorLint == i {
+			cursor = " ➤"
+			titleStyle = titleStyle.Foreground(t.Highlight).Bold(true)
+		}
+		title := ""
+		if is.index < len(m.items) {
+			title = model.DisplayTitle(m.items[is.index].Title)
+		}
+		s.WriteString(lipgloss.NewStyle().Foreground(t.Highlight).Render(cursor) + " ")
+		s.WriteString(lipgloss.NewStyle().Foreground(t.Error).Render(fmt.Sprintf("%-6s", is.rule)) + " ")
+		s.WriteString(titleStyle.Render(title) + " ")
+		s.WriteString(lipgloss.NewStyle().Foreground(t.Comment).Render("— " + is.message))
+		s.WriteString("\n")
+	}
+
+	return m.frame(height, t.Error).
+		Render(s.String())
+}
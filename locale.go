@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- LOCALE ---
+//
+// config.json picks the language of the UI, how dates are written and the
+// first day of the week:
+//
+//	"locale": "pl", "date_format": "dmy", "week_start": "sunday"
+//
+// The defaults are English, ISO dates and Monday; "locale": "auto" follows
+// LANG. Translations live in internal/i18n/locales.
+
+// applyLocale sets up internal/i18n from the config; what is invalid falls
+// back to the default and is reported.
+func (c Config) applyLocale() error {
+	return errors.Join(
+		i18n.SetLocale(c.Locale),
+		i18n.SetDateFormat(c.DateFormat),
+		i18n.SetWeekStart(c.WeekStart),
+	)
+}
+
+// translateHelp translates the labels of a "key:Label • key:Label" line.
+func translateHelp(help string) string {
+	parts := strings.Split(help, " • ")
+	for i, p := range parts {
+		if at := strings.LastIndex(p, ":"); at > 0 {
+			parts[i] = p[:at+1] + i18n.T(p[at+1:])
+		}
+	}
+	return strings.Join(parts, " • ")
+}
+
+// localDue writes the due date of a displayed title in the configured date
+// format.
+func localDue(title string, now time.Time) string {
+	due, hasTime, ok := model.DueTime(title)
+	if !ok {
+		return title
+	}
+	s := i18n.Date(due, now)
+	if s == due.Format(model.DateLayout) {
+		return title
+	}
+	if hasTime {
+		s += " " + due.Format("15:04")
+	}
+	return model.SetMeta(title, "due", s)
+}
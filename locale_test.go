@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/i18n"
+)
+
+func TestLocaleConfig(t *testing.T) {
+	defer Config{}.applyLocale()
+	if err := (Config{Locale: "pl", DateFormat: "dmy", WeekStart: "sunday"}).applyLocale(); err != nil {
+		t.Fatal(err)
+	}
+	if got := translateHelp("n:New • ::Command • Esc:Back"); got != "n:Nowe • ::Command • Esc:Wróć" {
+		t.Errorf("help = %q", got)
+	}
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+	if got := localDue("call Ann due:2026-10-20", now); got != "call Ann due:20.10.2026" {
+		t.Errorf("due = %q", got)
+	}
+	if i18n.WeekStart() != time.Sunday {
+		t.Error("week_start ignored")
+	}
+
+	if err := (Config{DateFormat: "us"}).applyLocale(); err == nil {
+		t.Error("invalid date_format not reported")
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- FILE LOCKING ---
+//
+// Saves replace the file via rename, so the advisory lock lives on a sidecar
+// file (.todo.md.lock) instead of the list itself. The TUI holds it for the
+// whole session; a second instance can only open the list read-only.
+//
+// Every write of a list (the TUI's saves, serve, todo add and capture,
+// import, feeds) also holds a second, short lock (.todo.md.write.lock) from
+// reading the file to writing it back, so two writers never interleave and
+// one never writes over a change it didn't read. Writers wait for it.
+
+var errLocked = errors.New("file is locked by another instance")
+
+const (
+	// writeLockWait is how long a writer waits for another one to finish
+	writeLockWait  = 5 * time.Second
+	writeLockRetry = 20 * time.Millisecond
+)
+
+type fileLock struct {
+	f *os.File
+}
+
+func lockPath(filename string) string {
+	return filepath.Join(filepath.Dir(filename), "."+filepath.Base(filename)+".lock")
+}
+
+func writeLockPath(filename string) string {
+	return filepath.Join(filepath.Dir(filename), "."+filepath.Base(filename)+".write.lock")
+}
+
+// acquireLock takes the lock without blocking; errLocked means it is held.
+func acquireLock(filename string) (*fileLock, error) {
+	return lockAt(lockPath(filename))
+}
+
+// withWriteLock runs fn, which reads and writes filename, holding the write
+// lock. It waits up to writeLockWait for a writer already holding it.
+func withWriteLock(filename string, fn func() error) error {
+	deadline := time.Now().Add(writeLockWait)
+	l, err := lockAt(writeLockPath(filename))
+	for errors.Is(err, errLocked) && time.Now().Before(deadline) {
+		time.Sleep(writeLockRetry)
+		l, err = lockAt(writeLockPath(filename))
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(filename), err)
+	}
+	defer l.release()
+	return fn()
+}
+
+func lockAt(path string) (*fileLock, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &fileLock{f: f}, nil
+}
+
+func (l *fileLock) release() {
+	if l == nil {
+		return
+	}
+	unlockFile(l.f)
+	l.f.Close()
+}
+
+// --- READ-ONLY PROMPT ---
+
+func (m *app) updateLockPrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "r", "y", "enter":
+		m.lockPrompt = false
+		m.readOnly = true
+	case "q", "n", "esc", "ctrl+c":
+		m.quitting = true
+		return tea.Quit
+	}
+	return nil
+}
+
+func (m app) renderLockPrompt(t theme.Theme) string {
+	title := lipgloss.NewStyle().Foreground(t.Error).Bold(true).Render(filepath.Base(m.filename) + " is open in another instance")
+	body := lipgloss.NewStyle().Foreground(t.Text).Render("Changes made here would overwrite each other.")
+	keys := lipgloss.NewStyle().Foreground(t.Comment).Render("r: open read-only • q: quit")
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Error).
+		Padding(0, 1).
+		Render(title + "\n" + body + "\n\n" + keys)
+}
@@ -0,0 +1,10 @@
+//go:build !unix && !windows
+
+package main
+
+import "os"
+
+// No advisory locks on this platform; every instance may write.
+func lockFile(f *os.File) error { return nil }
+
+func unlockFile(f *os.File) {}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWritersWaitForTheWriteLock(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	f := filepath.Join(t.TempDir(), "todo.md")
+	os.WriteFile(f, []byte("- [ ] a\n"), 0644)
+
+	// Inny zapis trzyma blokadę i dopisuje zadanie tuż przed jej zwolnieniem
+	held := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- withWriteLock(f, func() error {
+			close(held)
+			time.Sleep(100 * time.Millisecond)
+			return os.WriteFile(f, []byte("- [ ] a\n- [ ] b\n"), 0644)
+		})
+	}()
+	<-held
+	if _, err := addTasks(f, "c", Config{}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(f); !strings.Contains(string(data), "- [ ] b\n- [ ] c") {
+		t.Errorf("add did not wait for the other writer: %q", data)
+	}
+}
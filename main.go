@@ -1,23 +1,28 @@
 package main
 
 import (
-	"bufio"
-	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
+	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
 )
 
-// --- EMBEDDING ---
-//
-//go:embed themes.json
-var embeddedThemesFS embed.FS
-
 // --- ENUMS & CONSTANTS ---
 
 type appState int
@@ -26,77 +31,137 @@ const (
 	viewMain appState = iota
 	viewTrash
 	viewThemeSelector
+	viewLint
+	viewDetail
+	viewStats
+	viewAgenda
+	viewCalendar
+	viewPlan
+	viewTemplates
+	viewGroups
+	viewArchive
+	viewBacklinks
+	viewDeps
+	viewSmart
+	viewReview
+	viewToday
+	viewReplace
+	viewFilters
 )
 
+// gap(1) + header(1) + gap(1) + border_top(1) + border_bottom(1) + gap(1) + footer(1)
+const uiOverhead = 7
+
 const (
-	appName           = "todo-app"
-	defaultThemesFile = "themes.json"
-	configFile        = "config.json"
+	appName = "todo-app"
+
+	configFile = "config.json"
 )
 
 // --- CONFIGURATION ---
 
 type Config struct {
-	SelectedTheme string `json:"selected_theme"`
+	SelectedTheme string        `json:"selected_theme"`
+	CalDAV        *CalDAVConfig `json:"caldav,omitempty"`
+	Lint          *LintConfig   `json:"lint,omitempty"`
+	// Notify: "auto", "notify-send", "osc9", "bell" or "off"
+	Notify string `json:"notify,omitempty"`
+	// Fields declares custom key:value fields editable in the detail view
+	Fields []FieldDef `json:"fields,omitempty"`
+	// PomodoroMinutes overrides the default 25 minute focus block
+	PomodoroMinutes int `json:"pomodoro_minutes,omitempty"`
+	// AutoSort keeps the list sorted by "title", "due", "pri" or "done" when it is saved
+	AutoSort string `json:"autosort,omitempty"`
+	// BinWarn is the bin size above which the footer warns (default 100)
+	BinWarn int `json:"bin_warn,omitempty"`
+	// BinLimit caps the bin, dropping the oldest entries on save (0 = no limit)
+	BinLimit int `json:"bin_limit,omitempty"`
+	// DeleteLimit is how many items automation may delete per minute before ?force=1 is needed (default 20)
+	DeleteLimit int `json:"delete_limit,omitempty"`
+	// Keys rebinds keys per view: view name (or "global") -> key -> action
+	Keys map[string]map[string]string `json:"keys,omitempty"`
+	// Holidays are "2006-01-02" dates or "01-02" yearly ones, skipped like weekends
+	Holidays []string `json:"holidays,omitempty"`
+	// WorkdaysOnly keeps recurring tasks and date shortcuts off weekends and holidays
+	WorkdaysOnly bool `json:"workdays_only,omitempty"`
+	// CascadeComplete: "off" (default), "down", "up" or "both" (see cascade.go)
+	CascadeComplete string `json:"cascade_complete,omitempty"`
+	// DailyCapacity is how much work :plan puts on a day, e.g. "6h" (the default)
+	DailyCapacity string `json:"daily_capacity,omitempty"`
+	// Feeds are RSS/Atom subscriptions polled by serve and remind (see feeds.go)
+	Feeds []FeedConfig `json:"feeds,omitempty"`
+	// FeedMinutes is how often feeds are polled (default 30)
+	FeedMinutes int `json:"feed_minutes,omitempty"`
+	// CloudSave: "auto" (default) rewrites the file in place with retries when it
+	// looks like a synced folder, "on" always, "off" never (see storage/cloud.go)
+	CloudSave string `json:"cloud_save,omitempty"`
+	// LineEnding is what lists are saved with: "native" (default), "lf" or "crlf"
+	LineEnding string `json:"line_ending,omitempty"`
+	// Indent is what lists are saved with: "auto" (the file's own, default), "tab" or a number of spaces
+	Indent string `json:"indent,omitempty"`
+	// Header controls how the file path is shortened (see header.go)
+	Header *HeaderConfig `json:"header,omitempty"`
+	// Footer replaces the key hints with a format string (see header.go)
+	Footer *FooterConfig `json:"footer,omitempty"`
+	// ArchiveJournal files :archive'd tasks under month and day headings by
+	// completion date (see archive.go)
+	ArchiveJournal bool `json:"archive_journal,omitempty"`
+	// Workspaces are the list files `todo grep` searches (globs and ~ allowed)
+	Workspaces []string `json:"workspaces,omitempty"`
+	// IdleLockMinutes hides the list after that many minutes without input (0 = off)
+	IdleLockMinutes int `json:"idle_lock_minutes,omitempty"`
+	// IdleLockHash is the bcrypt hash of the passphrase needed to unlock
+	IdleLockHash string `json:"idle_lock_hash,omitempty"`
+	// CheckboxStates are the custom "- [~]" states between open and done (see states.go)
+	CheckboxStates []CheckboxState `json:"checkbox_states,omitempty"`
+	// SpaceCycles makes space step through the checkbox states before done
+	SpaceCycles bool `json:"space_cycles,omitempty"`
+	// Completed decides how finished tasks are shown (see completed.go)
+	Completed *CompletedConfig `json:"completed,omitempty"`
+	// ScoreWeights tune the :smart order: "priority", "due", "age", "starred" (see score.go)
+	ScoreWeights map[string]float64 `json:"score_weights,omitempty"`
+	// SplitWidth is the window width from which the list gets a detail pane (default 120, see split.go)
+	SplitWidth int `json:"split_width,omitempty"`
+	// SplitRatio is the share of the width, in percent, the detail pane takes (default 33)
+	SplitRatio int `json:"split_ratio,omitempty"`
+	// Locale is the UI language: "en" (default), "pl" or "auto" for LANG (see locale.go)
+	Locale string `json:"locale,omitempty"`
+	// DateFormat: "iso" (default), "dmy" (02.01.2006) or "relative" (in 3 days)
+	DateFormat string `json:"date_format,omitempty"`
+	// WeekStart: "monday" (default) or "sunday"
+	WeekStart string `json:"week_start,omitempty"`
+	// Compact drops the frame and the blank lines around header and footer (see layout.go)
+	Compact bool `json:"compact,omitempty"`
+	// Truncate cuts long titles to one line instead of wrapping them (see layout.go)
+	Truncate bool `json:"truncate,omitempty"`
+	// LineNumbers is "absolute" or "relative" to number the tasks (see numbers.go)
+	LineNumbers string `json:"line_numbers,omitempty"`
+	// File is the list opened when no file is given (default ./todo.md, ~ allowed)
+	File string `json:"file,omitempty"`
+	// Inbox is the list todo capture adds to (default: File, see capture.go)
+	Inbox string `json:"inbox,omitempty"`
+	// Hooks are shell commands run on "add", "complete" and "save" (see hooks.go)
+	Hooks map[string]string `json:"hooks,omitempty"`
+	// Colors forces the color profile: "auto" (default), "truecolor", "256", "16" or "none"
+	Colors string `json:"colors,omitempty"`
+	// Filters are :filter expressions saved by name (see filters.go)
+	Filters map[string]string `json:"filters,omitempty"`
+	// Perspectives bundle a filter, a sort order and folds under a name (see perspectives.go)
+	Perspectives []Perspective `json:"perspectives,omitempty"`
 }
 
 // --- THEME SYSTEM ---
 
-type JSONTheme struct {
-	Name      string `json:"name"`
-	Base      string `json:"base"`
-	Highlight string `json:"highlight"`
-	Text      string `json:"text"`
-	Comment   string `json:"comment"`
-	Special   string `json:"special"`
-	Error     string `json:"error"`
-	Accent    string `json:"accent"`
-}
-
-type Theme struct {
-	Name      string
-	Base      lipgloss.Color
-	Highlight lipgloss.Color
-	Text      lipgloss.Color
-	Comment   lipgloss.Color
-	Special   lipgloss.Color
-	Error     lipgloss.Color
-	Accent    lipgloss.Color
-}
-
-var defaultTheme = Theme{
-	Name:      "Gruvbox (Built-in)",
-	Base:      lipgloss.Color("#282828"),
-	Highlight: lipgloss.Color("#fabd2f"),
-	Text:      lipgloss.Color("#ebdbb2"),
-	Comment:   lipgloss.Color("#928374"),
-	Special:   lipgloss.Color("#b8bb26"),
-	Error:     lipgloss.Color("#fb4934"),
-	Accent:    lipgloss.Color("#83a598"),
-}
-
-var themes []Theme
+var themes []theme.Theme
 
 // --- DATA MODEL ---
 
-type item struct {
-	title     string
-	done      bool
-	level     int
-	collapsed bool
-}
-
-type visibleItem struct {
-	index int
-	data  item
-}
-
-type model struct {
-	items    []item
-	trash    []item
+type app struct {
+	items    []model.Item
+	trash    []model.Item
 	filename string
 
-	visibleItems []visibleItem
+	visibleItems []model.VisibleItem
 
 	state    appState
 	quitting bool
@@ -106,86 +171,283 @@ type model struct {
 	addSubtaskMode bool
 	inputBuf       string
 
+	cmdMode bool
+	cmdBuf  string
+
 	cursorMain  int
 	cursorTrash int
 	cursorTheme int
+	cursorLint  int
+
+	detailIdx    int
+	cursorDetail int
+	fieldEditing bool
+	fieldBuf     string
+
+	pomoID  string
+	pomoEnd time.Time
+
+	prop     propEditor
+	propOpen bool
+
+	datePopup datePopup
+	dateOpen  bool
+
+	finder     finder
+	finderOpen bool
+
+	cursorAgenda int
+	agendaBack   appState // where Esc leaves the agenda to
+	showSnoozed  bool
+
+	calendar ui.DatePicker
+
+	plan       []planEntry
+	planUnfit  []int
+	cursorPlan int
+
+	replace       []replaceEntry
+	replaceSub    substitution
+	cursorReplace int
+
+	cursorFilter int
+
+	templates      []taskTemplate
+	cursorTemplate int
+
+	backlinks      []backlink
+	backlinkTarget string
+	cursorBacklink int
+	linkBack       []linkPlace // where followed links came from, for ctrl+o
+
+	archive        []model.Item // loaded by :archive view, read-only
+	archiveVisible []model.VisibleItem
+	cursorArchive  int
+
+	groupBy     string          // tag, assignee or due
+	groupFolded map[string]bool // folded group headers, by mode and name
+	cursorGroup int
+
+	filterText  string
+	filter      query
+	perspective string // the active one's name, see perspectives.go
+	sortKey     string // the last :sort, for the {sort} placeholder
+
+	rows *renderCache
+
+	writer     *listWriter
+	saveGen    int
+	dirty      bool
+	saveQueued bool
+	saving     bool
+	saveErr    error
+
+	lock       *fileLock
+	lockPrompt bool
+	readOnly   bool
+	journal    *journal        // nil when changes aren't journaled (see journal.go)
+	recovery   *journalEntry   // unsaved changes of a crashed session (see journal.go)
+	hooks      []hookCall      // started after the update that fired them (see hooks.go)
+	plugins    []pluginCommand // nil until ":" is first opened (see plugins.go)
+	demo       bool            // --demo: changes are never saved (see demo.go)
+	workspace  *workspace      // todo all: several files shown as one (see workspace.go)
+	inline     bool            // --inline: drawn in place, no alternate screen (see inline.go)
+
+	errTitle string
+	errBody  string
+
+	lastInput  time.Time
+	idleLocked bool
+	unlockBuf  string
+	unlockErr  bool
+
+	cascadeID string // parent offered for completion
+
+	blockedDoneID string // task with open blockers waiting for y/n
+	blockPick     bool   // "w" waits for Enter on a blocker
+	blockFrom     int
+	depsID        string // task whose dependency chain is shown
+	cursorDeps    int
+
+	cursorSmart int
+	cursorToday int
+	statsRange  statsRange // the burndown range (see burndown.go)
+
+	conflicts string // sync conflict copies already reported
+
+	confirmEmpty bool // X in the bin waits for y/n
+
+	zoomID string // id of the subtree z narrowed the list to
+
+	doneAt map[string]time.Time // when tasks were finished, by sessionKey, for the hide mode
+
+	toasts []reminderToast // reminders waiting for Enter/s/S/Esc
+
+	moving   bool // a subtree is picked up by "M"
+	moveFrom int
+
+	reviewSkipped map[string]bool // tasks put off to the end of the review, by sessionKey
+	reviewMove    bool            // the move was started from the review, go back there
+
+	keys         keymap
+	pendingKey   string
+	todayKey     int // the latest "1" that may open Today (see today.go)
+	pendingCount int
+
+	lintIssues []lintIssue
+	reminders  *reminders
 
 	width       int
 	height      int
-	activeTheme Theme
+	activeTheme theme.Theme
+
+	config      Config
+	configErr   error
+	status      string
+	syncing     bool
+	fileModTime time.Time
+	themesMod   string
+	splitHidden bool
+	columns     bool
 
 	// NOWE POLE: Do obsługi przewijania (viewport)
 	viewportY int
+	listTop   int // first visible item of the list (see listWindow)
 }
 
 // --- INITIALIZATION ---
 
-func initialModel(filename string) model {
-	loadedThemes := loadThemes()
-	if len(loadedThemes) > 0 {
-		themes = loadedThemes
-	} else {
-		themes = []Theme{defaultTheme}
-	}
-
-	config := loadConfig()
-	startTheme := themes[0]
+func initialModel(filename string) app {
+	return openModel(filename, nil)
+}
 
-	for _, t := range themes {
-		if t.Name == config.SelectedTheme {
-			startTheme = t
-			break
-		}
-	}
+// openModel sets the app up on filename, or on the files of ws shown as one
+// list named filename.
+func openModel(filename string, ws *workspace) app {
+	loadThemes()
 
-	activeItems, trashItems := loadTodo(filename)
+	config, configErr := loadConfig()
+	startTheme, _ := themeByName(config.SelectedTheme)
 
-	m := model{
-		items:       activeItems,
-		trash:       trashItems,
+	m := app{
 		cursorMain:  0,
 		filename:    filename,
+		workspace:   ws,
 		activeTheme: startTheme,
+		config:      config,
+		configErr:   configErr,
+		themesMod:   themesStamp(),
+		lastInput:   time.Now(),
+		reminders:   newReminders(),
+		rows:        newRenderCache(),
+		writer:      &listWriter{writeThrough: config.cloudSafe(filename), workspace: ws},
+		journal:     newJournal(filename),
 		state:       viewMain,
 		viewportY:   0, // Startujemy od góry
 	}
+	items, trash, loadErr := m.loadList()
+	m.items, m.trash = items, trash
+	m.fileModTime = m.writer.modTime(filename)
 	m.recalcVisible()
+	m.restoreSession()
 
-	for i, t := range themes {
-		if t.Name == startTheme.Name {
-			m.cursorTheme = i
-			break
+	keys, err := parseKeymap(config.Keys)
+	if err != nil {
+		m.status = "Keymap ignored: " + err.Error()
+	}
+	m.keys = keys
+
+	if err := config.applyLocale(); err != nil {
+		m.status = err.Error()
+	}
+	if err := errors.Join(storage.SetLineEnding(config.LineEnding), storage.SetIndent(config.Indent)); err != nil {
+		m.status = err.Error()
+	}
+
+	if config.Colors != "" {
+		profile, err := colorProfile(config.Colors)
+		if err != nil {
+			m.status = err.Error()
 		}
+		lipgloss.SetColorProfile(profile)
 	}
 
-	return m
-}
+	if ws != nil {
+		err = ws.lock()
+	} else {
+		m.lock, err = acquireLock(filename)
+	}
+	m.lockPrompt = errors.Is(err, errLocked)
 
-func (m *model) recalcVisible() {
-	m.visibleItems = []visibleItem{}
-	currentCollapseLevel := -1
+	if loadErr != nil {
+		// Pusta lista zapisana na nieczytelny plik skasowałaby dane
+		m.readOnly = true
+		m.showError("Could not read "+filepath.Base(filename), loadErr.Error()+"\nOpened read-only so the file is not overwritten.")
+	} else if configErr != nil {
+		m.showError("Invalid config", configErr.Error()+"\nUsing defaults; settings won't be saved until it is fixed.")
+	} else {
+		m.checkConflicts()
+	}
+	if loadErr == nil && !m.lockPrompt {
+		m.checkJournal()
+	}
 
-	for i, item := range m.items {
-		if currentCollapseLevel != -1 {
-			if item.level > currentCollapseLevel {
-				continue
-			} else {
-				currentCollapseLevel = -1
-			}
-		}
+	_, m.cursorTheme = themeByName(startTheme.Name)
 
-		m.visibleItems = append(m.visibleItems, visibleItem{index: i, data: item})
+	return m
+}
 
-		if item.collapsed {
-			currentCollapseLevel = item.level
-		}
+func (m *app) recalcVisible() {
+	now := time.Now()
+	if m.filter != nil {
+		// Przy aktywnym filtrze pokazujemy trafienia z przodkami, bez zwijania
+		_, inFilter := queryMatches(m.items, m.filter, now)
+		m.visibleItems = model.VisibleMatching(m.items, inFilter)
+	} else {
+		m.visibleItems = model.Visible(m.items, m.hidden(now))
+	}
+	if m.zoomID != "" {
+		m.visibleItems = m.narrow(m.visibleItems)
 	}
+	m.visibleItems = m.arrangeDone(m.visibleItems, now)
 
 	if m.cursorMain >= len(m.visibleItems) {
 		m.cursorMain = max(0, len(m.visibleItems)-1)
 	}
 }
 
+// hidden reports items left out of the list together with their subtree.
+func (m *app) hidden(now time.Time) func(model.Item) bool {
+	// Odłożone zadania (snooze) znikają razem z poddrzewem
+	today := now.Format(model.DateLayout)
+	return func(it model.Item) bool {
+		return !m.showSnoozed && isSnoozed(it.Title, today)
+	}
+}
+
+// refoldVisible applies a fold toggle of the item at visible position pos
+// without rebuilding the whole list.
+func (m *app) refoldVisible(pos int) {
+	if m.filter != nil || m.config.Completed.mode() != "strike" {
+		// Filtr i tak pokazuje wszystko, niezależnie od zwinięcia
+		m.recalcVisible()
+		return
+	}
+	m.visibleItems = model.RefoldVisible(m.items, m.visibleItems, pos, m.hidden(time.Now()))
+}
+
+// refreshItem picks up an edit of items[idx] that keeps the tree shape. Only
+// edits that change what is shown (filter matches, snoozing, finished tasks
+// moving or hiding) rebuild the list.
+func (m *app) refreshItem(idx int) {
+	pos := model.VisiblePos(m.visibleItems, idx)
+	if m.filter != nil || pos == -1 || m.hidden(time.Now())(m.items[idx]) || m.config.Completed.mode() != "strike" {
+		m.recalcVisible()
+		return
+	}
+	m.visibleItems[pos].Data = m.items[idx]
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -193,20 +455,147 @@ func max(a, b int) int {
 	return b
 }
 
-func (m model) Init() tea.Cmd {
-	return nil
+func (m app) Init() tea.Cmd {
+	checkNow := func() tea.Msg { return reminderTickMsg{} }
+	cmds := []tea.Cmd{watchFile(), checkNow, caldavTick(m.config.CalDAV)}
+	if trackedIndex(m.items) != -1 {
+		cmds = append(cmds, trackTick())
+	}
+	if m.config.idleTimeout() > 0 {
+		cmds = append(cmds, idleTick())
+	}
+	return tea.Batch(cmds...)
 }
 
 // --- UPDATE LOGIC ---
 
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	a := next.(app)
+	a.scrollList()
+	// Zmiany zapisujemy z opóźnieniem, poza pętlą klawiszy
+	save := a.scheduleSave()
+	hooks := a.startHooks()
+	return a, tea.Batch(cmd, save, hooks, a.writeJournal())
+}
+
+func (m app) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.inline {
+			m.height = min(m.height, inlineRows)
+		}
+		return m, nil
+
+	case fileCheckMsg:
+		m.reloadIfChanged()
+		m.reloadThemesIfChanged()
+		return m, watchFile()
+
+	case todayKeyMsg:
+		m.openTodayFromKey(msg)
+		return m, nil
+
+	case trackTickMsg:
+		if trackedIndex(m.items) == -1 {
+			return m, nil
+		}
+		return m, trackTick()
+
+	case pomoTickMsg:
+		return m, m.handlePomoTick()
+
+	case reminderTickMsg:
+		if m.expireDone(time.Now()) {
+			m.recalcVisible()
+		}
+		return m, tea.Batch(m.checkReminders(), reminderTick())
+
+	case caldavTickMsg:
+		return m, tea.Batch(m.startSync(), caldavTick(m.config.CalDAV))
+
+	case caldavFetchedMsg:
+		return m, m.handleCalDAVFetched(msg)
+
+	case caldavPushedMsg:
+		m.handleCalDAVPushed(msg)
+		return m, nil
+
+	case idleTickMsg:
+		return m, m.checkIdle(time.Now())
+
+	case saveTickMsg:
+		return m, m.writeAsync()
+
+	case pluginDoneMsg:
+		m.applyPlugin(msg)
+		return m, nil
+
+	case hookDoneMsg:
+		if msg.err != nil {
+			m.status = "Hook " + msg.event + " failed: " + msg.err.Error()
+		}
+		return m, nil
+
+	case savedMsg:
+		m.handleSaved(msg)
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.idleLocked {
+			return m, m.updateIdleLock(msg)
+		}
+		m.lastInput = time.Now()
+		m.status = ""
+		if m.errTitle != "" {
+			// Dowolny klawisz zamyka okno błędu
+			m.errTitle, m.errBody = "", ""
+			return m, nil
+		}
+		if m.lockPrompt {
+			return m, m.updateLockPrompt(msg)
+		}
+		if m.recovery != nil {
+			return m, m.updateRecoveryPrompt(msg)
+		}
+		if m.cmdMode {
+			return m, m.updateCommand(msg)
+		}
+		if m.cascadeID != "" && m.updateCascadePrompt(msg) {
+			return m, nil
+		}
+		if m.blockedDoneID != "" && m.updateBlockedPrompt(msg) {
+			return m, nil
+		}
+		if m.confirmEmpty {
+			m.confirmEmpty = false
+			if k := msg.String(); k == "y" || k == "enter" {
+				m.emptyBin()
+			}
+			return m, nil
+		}
+		if m.fieldEditing {
+			m.updateFieldEdit(msg)
+			return m, nil
+		}
+		if m.propOpen {
+			m.updatePropEditor(msg)
+			return m, nil
+		}
+		if m.dateOpen {
+			m.updateDatePopup(msg.String())
+			return m, nil
+		}
+		if m.finderOpen {
+			m.updateFinder(msg)
+			return m, nil
+		}
+		if msg.Paste && (m.inputMode || m.state == viewMain) {
+			m.paste(string(msg.Runes))
+			return m, nil
+		}
 		if m.inputMode {
 			switch msg.Type {
 			case tea.KeyEnter:
@@ -227,9 +616,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		msg, ok := m.remap(msg)
+		if !ok {
+			return m, nil
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
-			if m.state != viewMain {
+			if m.state != viewMain && msg.String() == "q" {
 				m.state = viewMain
 				m.viewportY = 0 // Reset scrolla przy wyjściu z innych widoków
 				return m, nil
@@ -238,6 +631,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
+		if len(m.toasts) > 0 && m.updateToast(msg.String()) {
+			return m, nil
+		}
+
 		switch m.state {
 		case viewMain:
 			return m.updateMain(msg)
@@ -245,36 +642,75 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateTrash(msg)
 		case viewThemeSelector:
 			return m.updateThemeSelector(msg)
+		case viewLint:
+			return m.updateLint(msg)
+		case viewDetail:
+			return m.updateDetail(msg)
+		case viewStats:
+			return m.updateStats(msg)
+		case viewAgenda:
+			return m.updateAgenda(msg)
+		case viewCalendar:
+			return m.updateCalendar(msg)
+		case viewPlan:
+			return m.updatePlan(msg)
+		case viewTemplates:
+			return m.updateTemplates(msg)
+		case viewGroups:
+			return m.updateGroups(msg)
+		case viewArchive:
+			return m.updateArchive(msg)
+		case viewBacklinks:
+			return m.updateBacklinks(msg)
+		case viewDeps:
+			return m.updateDeps(msg)
+		case viewSmart:
+			return m.updateSmart(msg)
+		case viewReview:
+			return m.updateReview(msg)
+		case viewToday:
+			return m.updateToday(msg)
+		case viewReplace:
+			return m.updateReplace(msg)
+		case viewFilters:
+			return m.updateFilters(msg)
 		}
 	}
 	return m, nil
 }
 
-func (m *model) handleInputConfirm() {
+func (m *app) handleInputConfirm() {
 	if len(m.inputBuf) == 0 && !m.editMode {
 		m.handleInputCancel()
 		return
 	}
 
-	realIdx := m.visibleItems[m.cursorMain].index
-	m.items[realIdx].title = m.inputBuf
+	realIdx := m.visibleItems[m.cursorMain].Index
+	title := expandEstimate(expandDates(m.inputBuf, time.Now()))
+	if m.editMode {
+		m.items[realIdx].Title = title
+	} else {
+		m.items[realIdx].Title = model.SetMeta(title, "created", time.Now().Format(model.DateLayout))
+		m.fireHook("add", realIdx)
+	}
 
 	m.inputMode = false
 	m.editMode = false
 	m.inputBuf = ""
 
-	m.recalcVisible()
+	m.refreshItem(realIdx)
+	m.warnCapacity(realIdx)
 
-	saveTodo(m.filename, m.items, m.trash)
+	m.save()
 }
 
-func (m *model) handleInputCancel() {
+func (m *app) handleInputCancel() {
 	if m.editMode {
 		m.inputMode = false
 		m.editMode = false
 		m.inputBuf = ""
 	} else {
-		realIdx := m.visibleItems[m.cursorMain].index
+		realIdx := m.visibleItems[m.cursorMain].Index
 		m.items = append(m.items[:realIdx], m.items[realIdx+1:]...)
 
 		m.recalcVisible()
@@ -287,45 +723,74 @@ func (m *model) handleInputCancel() {
 	}
 }
 
-func (m model) updateMain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+func (m app) updateMain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	realIdx := -1
 	if len(m.visibleItems) > 0 {
-		realIdx = m.visibleItems[m.cursorMain].index
+		realIdx = m.visibleItems[m.cursorMain].Index
+	}
+
+	// "1" zaczyna licznik; gdy nic po nim nie przyjdzie, otwiera Today
+	if msg.String() == "1" && m.pendingKey == "" && m.pendingCount == 0 {
+		m.pendingCount = 1
+		m.todayKey++
+		return m, todayKeyTimeout(m.todayKey)
+	}
+	if m.pendingKey == "" && m.perspectiveKey(msg.String()) {
+		m.pendingCount = 0
+		return m, nil
+	}
+	if m.pendingKey == "" && m.handleCountKey(msg.String()) {
+		return m, nil
+	}
+	if m.handleNavKey(msg.String()) {
+		if m.pendingKey == "" {
+			m.pendingCount = 0
+		}
+		return m, nil
+	}
+	count := m.takeCount()
+	if m.guardHeading(msg.String(), realIdx) {
+		return m, nil
+	}
+	if m.moving && m.updateMove(msg.String(), realIdx) {
+		return m, nil
+	}
+	if m.blockPick && m.updateBlockPick(msg.String(), realIdx) {
+		return m, nil
 	}
 
 	switch msg.String() {
 	case "up", "k":
-		if m.cursorMain > 0 {
-			m.cursorMain--
-		}
+		m.cursorMain = max(0, m.cursorMain-count)
 	case "down", "j":
-		if m.cursorMain < len(m.visibleItems)-1 {
-			m.cursorMain++
-		}
+		m.cursorMain = max(0, min(len(m.visibleItems)-1, m.cursorMain+count))
 	case " ":
 		if realIdx != -1 {
-			m.items[realIdx].done = !m.items[realIdx].done
-			saveTodo(m.filename, m.items, m.trash)
-			m.recalcVisible()
+			m.toggleDone(realIdx)
 		}
 	case "v":
-		if realIdx != -1 {
-			hasChildren := false
-			if realIdx+1 < len(m.items) && m.items[realIdx+1].level > m.items[realIdx].level {
-				hasChildren = true
-			}
-
-			if hasChildren {
-				m.items[realIdx].collapsed = !m.items[realIdx].collapsed
-				m.recalcVisible()
-			}
+		if realIdx != -1 && model.ToggleFold(m.items, realIdx) {
+			m.refoldVisible(m.cursorMain)
 		}
 	case "n":
 		m.inputMode = true
 		m.editMode = false
 		m.inputBuf = ""
 
-		newItem := item{title: "", level: 0}
+		root := m.zoomRoot()
+		if root == -1 && realIdx != -1 {
+			root = m.headingAt(realIdx)
+		}
+		if root != -1 {
+			// W powiększeniu (i pod nagłówkiem pliku) nowe zadanie trafia na koniec poddrzewa
+			m.items[root].Collapsed = false
+			at := model.SubtreeEnd(m.items, root)
+			m.items = slices.Insert(m.items, at, model.Item{Level: m.items[root].Level + 1})
+			m.recalcVisible()
+			m.cursorMain = max(0, model.VisiblePos(m.visibleItems, at))
+			break
+		}
+		newItem := model.Item{Title: "", Level: 0}
 		m.items = append(m.items, newItem)
 		m.recalcVisible()
 		m.cursorMain = len(m.visibleItems) - 1
@@ -337,14 +802,14 @@ func (m model) updateMain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.inputBuf = ""
 
 			parent := &m.items[realIdx]
-			parent.collapsed = false
+			parent.Collapsed = false
 
-			newItem := item{
-				title: "",
-				level: parent.level + 1,
+			newItem := model.Item{
+				Title: "",
+				Level: parent.Level + 1,
 			}
 
-			m.items = append(m.items[:realIdx+1], append([]item{newItem}, m.items[realIdx+1:]...)...)
+			m.items = append(m.items[:realIdx+1], append([]model.Item{newItem}, m.items[realIdx+1:]...)...)
 			m.recalcVisible()
 			m.cursorMain++
 		}
@@ -353,94 +818,192 @@ func (m model) updateMain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if realIdx != -1 {
 			m.inputMode = true
 			m.editMode = true
-			m.inputBuf = m.items[realIdx].title
+			m.inputBuf = m.items[realIdx].Title
 		}
 
 	case "d", "delete":
 		if realIdx != -1 {
-			countToDelete := 1
-			currentLevel := m.items[realIdx].level
-
-			for i := realIdx + 1; i < len(m.items); i++ {
-				if m.items[i].level > currentLevel {
-					countToDelete++
-				} else {
+			// "3d" usuwa trzy kolejne rodzeństwa
+			level := m.items[realIdx].Level
+			for n := 0; n < count; n++ {
+				m.items, m.trash = model.DeleteSubtree(m.items, m.trash, realIdx)
+				if realIdx >= len(m.items) || m.items[realIdx].Level != level {
 					break
 				}
 			}
 
-			deletedSlice := make([]item, countToDelete)
-			copy(deletedSlice, m.items[realIdx:realIdx+countToDelete])
-			m.trash = append(m.trash, deletedSlice...)
-
-			m.items = append(m.items[:realIdx], m.items[realIdx+countToDelete:]...)
-
 			m.recalcVisible()
 			if m.cursorMain >= len(m.visibleItems) && m.cursorMain > 0 {
 				m.cursorMain--
 			}
 
-			saveTodo(m.filename, m.items, m.trash)
+			m.save()
+		}
+	case ">", "<":
+		if realIdx != -1 {
+			shift := model.IndentSubtree
+			if msg.String() == "<" {
+				shift = model.OutdentSubtree
+			}
+			// Kolejne elementy to następne rodzeństwo (z poddrzewami)
+			idx := realIdx
+			for n := 0; n < count && idx < len(m.items); n++ {
+				level := m.items[idx].Level
+				end := model.SubtreeEnd(m.items, idx)
+				if !shift(m.items, idx) {
+					break
+				}
+				if end >= len(m.items) || m.items[end].Level != level {
+					break
+				}
+				idx = end
+			}
+			if p := model.ParentIndex(m.items, realIdx); p != -1 {
+				m.items[p].Collapsed = false
+			}
+			m.jumpTo(realIdx)
+			m.save()
 		}
 	case "tab":
 		if realIdx != -1 {
-			if m.items[realIdx].level == 0 {
-				m.items[realIdx].level = 1
+			if m.items[realIdx].Level == 0 {
+				m.items[realIdx].Level = 1
 			} else {
-				m.items[realIdx].level = 0
+				m.items[realIdx].Level = 0
 			}
 			m.recalcVisible()
-			saveTodo(m.filename, m.items, m.trash)
+			m.save()
 		}
 	case "t":
 		m.state = viewThemeSelector
+	case "C":
+		m.toggleCompact()
+	case "ctrl+w":
+		m.toggleTruncate()
+	case "ctrl+p":
+		m.openFinder()
+	case "|":
+		m.toggleSplit()
+	case "[":
+		m.resizeSplit(splitRatioStep)
+	case "]":
+		m.resizeSplit(-splitRatioStep)
+	case "c":
+		m.toggleColumns()
+	case "S":
+		return m, m.startSync()
+	case "i":
+		m.openDetail()
+	case "P":
+		return m, m.togglePomodoro()
+	case "p":
+		m.openPropEditor()
+	case "T":
+		return m, m.toggleTracking()
+	case "s":
+		if realIdx != -1 {
+			m.openDatePopup(realIdx, "snooze", "Snooze until")
+		}
+	case "L":
+		if realIdx != -1 {
+			m.toggleLock(realIdx)
+		}
+	case ":":
+		m.cmdMode = true
+		m.cmdBuf = ""
+		m.loadPlugins()
+	case "M":
+		if realIdx != -1 {
+			m.startMove(realIdx)
+		}
+	case "D":
+		if realIdx != -1 {
+			m.duplicate(realIdx)
+		}
+	case "enter":
+		if realIdx != -1 {
+			return m.followLink(realIdx), nil
+		}
+	case "ctrl+o":
+		return m.linkReturn(), nil
+	case "b":
+		m.openBacklinks()
+	case "w":
+		if realIdx != -1 {
+			m.startBlockPick(realIdx)
+		}
+	case "W":
+		m.openDeps()
+	case "*":
+		if realIdx != -1 {
+			m.toggleStar(realIdx)
+		}
+	case "x":
+		if realIdx != -1 {
+			m.cycleState(realIdx)
+		}
+	case "o":
+		if realIdx != -1 {
+			m.openURL(realIdx, count)
+		}
+	case "z":
+		if realIdx != -1 {
+			m.zoomIn(realIdx)
+		}
+	case "esc":
+		if m.zoomID != "" {
+			m.zoomOut()
+		}
 	case "B":
 		m.state = viewTrash
 		m.cursorTrash = 0
 		m.viewportY = 0 // Reset scrolla przy wejściu do kosza
+	case "F":
+		m.openFilters()
 	}
 	return m, nil
 }
 
-func (m model) updateTrash(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+func (m app) updateTrash(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.snapTrashCursor()
 	switch msg.String() {
 	case "esc", "B":
 		m.state = viewMain
 		m.viewportY = 0 // Reset scrolla przy powrocie
 	case "up", "k":
 		if m.cursorTrash > 0 {
-			m.cursorTrash--
+			m.cursorTrash, _ = model.TrashBlock(m.trash, m.cursorTrash-1)
 		}
 	case "down", "j":
-		if m.cursorTrash < len(m.trash)-1 {
-			m.cursorTrash++
+		if len(m.trash) > 0 {
+			if _, end := model.TrashBlock(m.trash, m.cursorTrash); end < len(m.trash) {
+				m.cursorTrash = end
+			}
 		}
 	case "enter":
 		if len(m.trash) > 0 {
-			restored := m.trash[m.cursorTrash]
-			m.items = append(m.items, restored)
-			m.trash = append(m.trash[:m.cursorTrash], m.trash[m.cursorTrash+1:]...)
-			if m.cursorTrash >= len(m.trash) && m.cursorTrash > 0 {
-				m.cursorTrash--
-			}
-			saveTodo(m.filename, m.items, m.trash)
+			m.items, m.trash, _ = model.RestoreSubtree(m.items, m.trash, m.cursorTrash)
+			m.snapTrashCursor()
+			m.save()
 			m.recalcVisible()
 		}
+	case "X":
+		m.confirmEmpty = len(m.trash) > 0
 	case "x":
 		if len(m.trash) > 0 {
-			m.trash = append(m.trash[:m.cursorTrash], m.trash[m.cursorTrash+1:]...)
-			if m.cursorTrash >= len(m.trash) && m.cursorTrash > 0 {
-				m.cursorTrash--
-			}
-			saveTodo(m.filename, m.items, m.trash)
+			start, end := model.TrashBlock(m.trash, m.cursorTrash)
+			m.trash = slices.Delete(m.trash, start, end)
+			m.snapTrashCursor()
+			m.save()
 		}
 	}
 	return m, nil
 }
 
-func (m model) updateThemeSelector(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+func (m app) updateThemeSelector(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "esc":
+		_, m.cursorTheme = themeByName(m.activeTheme.Name)
 		m.state = viewMain
 	case "up", "k":
 		if m.cursorTheme > 0 {
@@ -452,7 +1015,15 @@ func (m model) updateThemeSelector(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	case "enter":
 		m.activeTheme = themes[m.cursorTheme]
-		saveConfig(m.activeTheme.Name)
+		m.config.SelectedTheme = m.activeTheme.Name
+		switch {
+		case m.configErr != nil:
+			m.status = "Theme not saved: the config file is invalid"
+		default:
+			if err := saveConfig(m.config); err != nil {
+				m.status = "Theme not saved: " + err.Error()
+			}
+		}
 		m.state = viewMain
 	}
 	return m, nil
@@ -460,7 +1031,7 @@ func (m model) updateThemeSelector(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // --- VIEW LOGIC ---
 
-func (m model) View() string {
+func (m app) View() string {
 	if m.quitting {
 		return ""
 	}
@@ -468,8 +1039,21 @@ func (m model) View() string {
 	if m.width == 0 {
 		return "loading..."
 	}
+	if m.idleLocked {
+		return m.renderIdleLock(shownTheme(m.activeTheme))
+	}
+	// Małe okno: bez ramki, a przy 1-2 wierszach także bez nagłówka
+	if m.compact() {
+		m.config.Compact = true
+	}
+	screenW, screenH := m.width, m.height
 
 	t := m.activeTheme
+	if m.state == viewThemeSelector && m.cursorTheme < len(themes) {
+		// Podgląd: cały interfejs w podświetlonym motywie
+		t = themes[m.cursorTheme]
+	}
+	t = shownTheme(t)
 	dimStyle := lipgloss.NewStyle().Foreground(t.Comment)
 
 	// --- 1. NAGŁÓWEK ---
@@ -478,6 +1062,38 @@ func (m model) View() string {
 		modeName = "BIN"
 	} else if m.state == viewThemeSelector {
 		modeName = "THEMES"
+	} else if m.state == viewLint {
+		modeName = "LINT"
+	} else if m.state == viewDetail {
+		modeName = "DETAIL"
+	} else if m.state == viewStats {
+		modeName = "STATS"
+	} else if m.state == viewAgenda {
+		modeName = "AGENDA"
+	} else if m.state == viewCalendar {
+		modeName = "CALENDAR"
+	} else if m.state == viewPlan {
+		modeName = "PLAN"
+	} else if m.state == viewTemplates {
+		modeName = "TEMPLATES"
+	} else if m.state == viewBacklinks {
+		modeName = "BACKLINKS"
+	} else if m.state == viewDeps {
+		modeName = "DEPENDENCIES"
+	} else if m.state == viewSmart {
+		modeName = "SMART ORDER"
+	} else if m.state == viewReview {
+		modeName = "REVIEW"
+	} else if m.state == viewToday {
+		modeName = "TODAY"
+	} else if m.state == viewReplace {
+		modeName = "REPLACE"
+	} else if m.state == viewFilters {
+		modeName = "FILTERS"
+	} else if m.state == viewArchive {
+		modeName = "ARCHIVE"
+	} else if m.state == viewGroups {
+		modeName = i18n.T("BY") + " " + strings.ToUpper(i18n.T(m.groupBy))
 	}
 
 	fullPath, err := filepath.Abs(m.filename)
@@ -485,17 +1101,35 @@ func (m model) View() string {
 		fullPath = m.filename
 	}
 
-	prefix := fmt.Sprintf("// %s ", modeName)
-	availableWidth := m.width - len(prefix) - 2
-	displayPath := fullPath
-	if availableWidth > 3 && len(fullPath) > availableWidth {
-		cutIdx := len(fullPath) - availableWidth + 3
-		if cutIdx < len(fullPath) {
-			displayPath = "..." + fullPath[cutIdx:]
-		}
+	prefix := fmt.Sprintf("// %s ", i18n.T(modeName))
+	suffix := m.pomodoroHeader()
+	if m.perspective != "" {
+		suffix = " ◆ " + m.perspective + suffix
+	} else if m.filter != nil {
+		suffix = " [filter: " + m.filterText + "]" + suffix
+	}
+	if title := m.zoomTitle(); title != "" && m.state == viewMain {
+		suffix = " › " + ansi.Truncate(title, max(10, m.width/3), "…") + suffix
+	}
+	if m.readOnly {
+		suffix = " [read-only]" + suffix
+	}
+	badges := m.renderBadges(t)
+	badgeW := 0
+	if badges != "" {
+		badgeW = lipgloss.Width(badges) + 2
 	}
+	availableWidth := m.width - lipgloss.Width(prefix) - lipgloss.Width(suffix) - 2 - badgeW
+	displayPath := m.config.Header.displayPath(fullPath, m.width, availableWidth)
 
-	headerText := prefix + displayPath
+	headerText := prefix + displayPath + suffix
+	if format := m.config.Header.format(); format != "" {
+		headerText = m.formatHeader(format, i18n.T(modeName), fullPath, m.width-badgeW)
+		if m.readOnly {
+			headerText += " [read-only]"
+		}
+		headerText = ansi.Truncate(headerText, m.width-2-badgeW, "…")
+	}
 	styledHeader := lipgloss.NewStyle().
 		Foreground(t.Base).
 		Background(t.Highlight).
@@ -503,50 +1137,219 @@ func (m model) View() string {
 		Padding(0, 1).
 		Render(headerText)
 
+	if badges != "" {
+		styledHeader += "  " + badges
+	}
 	centeredHeader := lipgloss.PlaceHorizontal(m.width, lipgloss.Center, styledHeader)
+	// Ścieżka przodków zajmuje pustą linię pod nagłówkiem
+	crumbs := ""
+	if c := m.breadcrumb(m.width - 4); c != "" && !m.config.Compact {
+		crumbs = lipgloss.PlaceHorizontal(m.width, lipgloss.Center, dimStyle.Render(c))
+	}
 
 	// --- 2. STOPKA ---
 	help := ""
 	switch m.state {
 	case viewMain:
 		help = "n:New • m:Sub • e:Edit • v:Fold • d:Del • B:Bin • t:Theme • q:Quit"
+		if m.config.CalDAV.enabled() {
+			help = "n:New • m:Sub • e:Edit • v:Fold • d:Del • B:Bin • S:Sync • t:Theme • q:Quit"
+		}
 	case viewTrash:
-		help = "Enter:Restore • x:Purge • Esc:Back"
+		help = "Enter:Restore • x:Purge • X:Empty • Esc:Back"
 	case viewThemeSelector:
 		help = "Enter:Select • Esc:Back"
+	case viewLint:
+		help = "Enter:Jump • Esc:Back"
+	case viewStats:
+		help = "w:Week • m:Month • Esc:Back"
+	case viewAgenda:
+		help = "Enter:Jump • r:Reschedule • Esc:Back"
+	case viewCalendar:
+		help = "←→:Day • ↑↓:Week • PgUp/PgDn:Month • t:Today • Enter:Agenda • Esc:Back"
+	case viewPlan, viewReplace:
+		help = "Space:Skip • Enter:Apply • Esc:Cancel"
+	case viewTemplates:
+		help = "Enter:Insert • x:Delete • Esc:Back"
+	case viewFilters:
+		help = "Enter:Apply • x:Delete • Esc:Back"
+	case viewBacklinks:
+		help = "Enter:Jump • Esc:Back"
+	case viewDeps:
+		help = "Enter:Jump • Space:Done • Esc:Back"
+	case viewSmart:
+		help = "Enter:Jump • Space:Done • *:Star • Esc:Back"
+	case viewToday:
+		help = "Enter:Jump • Space:Done • *:Star • r:Reschedule • Esc:Back"
+	case viewReview:
+		help = "Enter:Keep • Space:Done • r:Reschedule • d:Delete • m:Move • s:Skip • Esc:Back"
+	case viewArchive:
+		help = "Enter/v:Fold • Esc:Back"
+	case viewGroups:
+		help = "Enter:Jump/Fold • Space:Done • v:Fold • Tab:Group by • Esc:Back"
+	case viewDetail:
+		help = "Enter:Edit/Open • ←/→:Cycle • x:Clear/Detach • Esc:Back"
+		if m.fieldEditing {
+			help = "Enter:Confirm • Esc:Cancel"
+		}
+	}
+	if m.state == viewMain && m.zoomID != "" {
+		help = "Esc:Unzoom • " + help
 	}
 	if m.inputMode {
 		help = "Enter:Confirm • Esc:Cancel"
 	}
+	if m.moving {
+		help = "↑↓:Target • Enter:Drop under • T:Top level • Esc:Cancel"
+	}
+	if m.blockPick {
+		help = "↑↓:Blocker • Enter:Wait for / stop waiting • Esc:Cancel"
+	}
+	if m.dateOpen {
+		help = "←→:Day • ↑↓:Week • PgUp/PgDn:Month • t:Today • w:Workday • x:Clear • Enter:Save • Esc:Cancel"
+	}
+	if m.propOpen {
+		help = "Tab:Section • ←↑↓→:Change • Space:Tag • x:No date • Enter:Save • Esc:Cancel"
+	}
+	if m.finderOpen {
+		help = "Type:Search • ↑↓:Select • alt+c:Case • alt+r:Regex • Enter:Go to • Esc:Cancel"
+	}
 
-	footer := dimStyle.Render(help)
-	centeredFooter := lipgloss.PlaceHorizontal(m.width, lipgloss.Center, footer)
+	footer := dimStyle.Render(translateHelp(help))
+	// Podpowiedzi okienek i edycji zostają, format dotyczy samych widoków
+	modal := m.inputMode || m.moving || m.blockPick || m.dateOpen || m.propOpen || m.finderOpen || m.fieldEditing
+	if format := m.config.Footer.format(); format != "" && !modal {
+		footer = dimStyle.Render(ansi.Truncate(expandFormat(format, m.formatFields(i18n.T(modeName), translateHelp(help))), m.width, "…"))
+	}
+	if m.state == viewMain && !m.inputMode && !m.propOpen && !m.dateOpen && !m.finderOpen {
+		footer += m.binIndicator(t)
+	}
+	footer += m.saveIndicator(t)
+	if m.status != "" {
+		footer = lipgloss.NewStyle().Foreground(t.Accent).Render(m.status)
+	}
+	if m.cascadeID != "" {
+		footer = m.renderCascadePrompt(t)
+	}
+	if m.blockedDoneID != "" {
+		footer = m.renderBlockedPrompt(t)
+	}
+	if m.confirmEmpty {
+		footer = lipgloss.NewStyle().Foreground(t.Error).Render(fmt.Sprintf("Purge all %d items from the bin for good? (y/n)", len(m.trash)))
+	}
+	if m.cmdMode {
+		footer = lipgloss.NewStyle().Foreground(t.Highlight).Render(":" + m.cmdBuf + "█")
+		if hint := m.pluginHint(m.cmdBuf); hint != "" {
+			footer += dimStyle.Render("   " + hint)
+		}
+	}
+	if m.pendingCount > 0 {
+		footer = lipgloss.NewStyle().Foreground(t.Highlight).Render(fmt.Sprintf("%d", m.pendingCount))
+	}
+	centeredFooter := lipgloss.PlaceHorizontal(m.width, lipgloss.Center, ansi.Truncate(footer, m.width, "…"))
 
 	// --- 3. OBLICZANIE WYSOKOŚCI ---
-	// gap(1) + header(1) + gap(1) + border_top(1) + border_bottom(1) + gap(1) + footer(1)
-	// Łącznie zajętych linii: 7
-	const uiOverhead = 7
-	availableH := m.height - uiOverhead
+	// Łącznie zajętych linii: chromeHeight
+	availableH := m.height - m.chromeHeight()
 	if availableH < 1 {
 		availableH = 1
 	}
+	if m.config.Compact {
+		// Bez ramki widoki dostają jej dwie kolumny
+		m.width += 2
+	}
 
 	var content string
 	switch m.state {
 	case viewMain:
 		content = m.renderList(availableH, t)
+		if m.showSplit() {
+			content = m.renderSplit(availableH, t)
+		}
 	case viewTrash:
 		content = m.renderTrash(availableH, t)
 	case viewThemeSelector:
 		content = m.renderThemeSelector(availableH, t)
+	case viewLint:
+		content = m.renderLint(availableH, t)
+	case viewDetail:
+		content = m.renderDetail(availableH, t)
+	case viewStats:
+		content = m.renderStats(availableH, t)
+	case viewAgenda:
+		content = m.renderAgenda(availableH, t)
+	case viewCalendar:
+		content = m.renderCalendar(availableH, t)
+	case viewPlan:
+		content = m.renderPlan(availableH, t)
+	case viewTemplates:
+		content = m.renderTemplates(availableH, t)
+	case viewGroups:
+		content = m.renderGroups(availableH, t)
+	case viewArchive:
+		content = m.renderArchive(availableH, t)
+	case viewBacklinks:
+		content = m.renderBacklinks(availableH, t)
+	case viewDeps:
+		content = m.renderDeps(availableH, t)
+	case viewSmart:
+		content = m.renderSmart(availableH, t)
+	case viewReview:
+		content = m.renderReview(availableH, t)
+	case viewToday:
+		content = m.renderToday(availableH, t)
+	case viewReplace:
+		content = m.renderReplace(availableH, t)
+	case viewFilters:
+		content = m.renderFilters(availableH, t)
+	}
+	if len(m.toasts) > 0 {
+		toast := m.renderToast(t)
+		content = ui.Overlay(content, toast, max(0, lipgloss.Width(content)-lipgloss.Width(toast)-2), 1)
+	}
+	if m.propOpen {
+		content = ui.OverlayCenter(content, m.renderPropEditor(t))
+	}
+	if m.dateOpen {
+		content = ui.OverlayCenter(content, m.renderDatePopup(t))
+	}
+	if m.finderOpen {
+		content = ui.OverlayCenter(content, m.renderFinder(t))
+	}
+	if m.lockPrompt {
+		content = ui.OverlayCenter(content, m.renderLockPrompt(t))
+	}
+	if m.recovery != nil {
+		content = ui.OverlayCenter(content, m.renderRecoveryPrompt(t))
+	}
+	if m.errTitle != "" {
+		content = ui.OverlayCenter(content, m.renderError(t))
+	}
+
+	// Widoki wyższe niż okno (kalendarz, statystyki) są przycinane
+	if m.config.Compact {
+		content = clipScreen(content, screenW, availableH)
+	} else {
+		content = clipScreen(content, screenW, availableH+2)
 	}
 
 	// --- 4. FINALNY UKŁAD (GAP-HEADER-GAP-CONTENT-GAP-FOOTER) ---
+	if m.config.Compact {
+		switch {
+		case screenH == 1 && m.cmdMode:
+			return clipScreen(centeredFooter, screenW, 1)
+		case screenH == 1:
+			return clipScreen(content, screenW, 1)
+		case screenH == 2:
+			return clipScreen(lipgloss.JoinVertical(lipgloss.Left, content, centeredFooter), screenW, 2)
+		}
+		return clipScreen(lipgloss.JoinVertical(lipgloss.Left, centeredHeader, content, centeredFooter), screenW, screenH)
+	}
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
 		"",             // GAP GÓRA
 		centeredHeader, // HEADER
-		"",             // GAP
+		crumbs,         // GAP / BREADCRUMB
 		content,        // RAMKA (wysokość availableH + 2 linie borderu)
 		"",             // GAP
 		centeredFooter, // FOOTER
@@ -554,225 +1357,63 @@ func (m model) View() string {
 }
 
 // --- SMART WRAPPING RENDER LIST ---
-func (m *model) renderList(height int, t Theme) string {
-	if m.width < 10 {
-		return "Window too narrow"
-	}
-
-	var visualLines []string
-
-	// Zakres linii dla kursora
-	cursorStartLine := 0
-	cursorEndLine := 0
-
-	for i, vItem := range m.visibleItems {
-		item := vItem.data
-		isCursor := (m.cursorMain == i)
-
-		titleStyle := lipgloss.NewStyle().Foreground(t.Text)
-		if item.done {
-			titleStyle = lipgloss.NewStyle().Foreground(t.Comment).Strikethrough(true)
+func (m *app) renderList(height int, t theme.Theme) string {
+	if m.width-2 < minRowWidth {
+		titles := make([]string, len(m.visibleItems))
+		for i, v := range m.visibleItems {
+			titles[i] = model.DisplayTitle(v.Data.Title)
 		}
+		return tinyList(titles, m.cursorMain, m.width-2, height, t)
+	}
+	if m.rows != nil {
+		m.rows.prune(max(maxCachedRows, 4*len(m.visibleItems)))
+	}
 
-		// 1. PREFIX RODZICA
-		var parentPrefixSb strings.Builder
-		if item.level > 0 {
-			parentPrefixSb.WriteString(" ")
-			for l := 1; l < item.level; l++ {
-				hasContinuation := false
-				for k := i + 1; k < len(m.visibleItems); k++ {
-					futureItem := m.visibleItems[k].data
-					if futureItem.level < l {
-						break
-					}
-					if futureItem.level == l {
-						hasContinuation = true
-						break
-					}
-				}
-				if hasContinuation {
-					parentPrefixSb.WriteString(" │ ")
-				} else {
-					parentPrefixSb.WriteString("   ")
-				}
-			}
-		}
-		parentPrefix := parentPrefixSb.String()
-
-		// 2. KONEKTOR
-		itemConnector := ""
-		if item.level > 0 {
-			isLastInGroup := true
-			for k := i + 1; k < len(m.visibleItems); k++ {
-				futureItem := m.visibleItems[k].data
-				if futureItem.level < item.level {
-					break
-				}
-				if futureItem.level == item.level {
-					isLastInGroup = false
-					break
-				}
-			}
-			if isLastInGroup {
-				itemConnector = " └─"
-			} else {
-				itemConnector = " ├─"
-			}
-		} else {
-			itemConnector = " "
-		}
-
-		// 3. CHECKBOX
-		checkStr := "[ ]"
-		checkStyle := lipgloss.NewStyle().Foreground(t.Special)
-		if item.collapsed {
-			checkStr = "[+]"
-			checkStyle = lipgloss.NewStyle().Foreground(t.Accent)
-		} else if item.done {
-			checkStr = "[✔]"
-			checkStyle = lipgloss.NewStyle().Foreground(t.Special)
-		} else {
-			checkStr = "[ ]"
-			checkStyle = lipgloss.NewStyle().Foreground(t.Text)
-		}
-
-		cursorStr := "  "
-		if isCursor {
-			cursorStr = " ➤"
-		}
-
-		// 4. TREŚĆ
-		prefixWidth := 2 + lipgloss.Width(parentPrefix) + lipgloss.Width(itemConnector) + 3 + 1
-		availableWidth := m.width - 2 - prefixWidth
-		if availableWidth < 10 {
-			availableWidth = 10
-		}
-
-		content := item.title
-		if isCursor && m.inputMode {
-			content = m.inputBuf + "█"
-		}
-
-		wrappedRaw := lipgloss.NewStyle().Width(availableWidth).Render(content)
-		rawLines := strings.Split(wrappedRaw, "\n")
-
-		if isCursor {
-			cursorStartLine = len(visualLines)
-		}
-
-		// 5. RENDEROWANIE LINII
-		for lineIdx, rawLine := range rawLines {
-			var rowSb strings.Builder
-			rowSb.WriteString(lipgloss.NewStyle().Foreground(t.Highlight).Render(cursorStr))
-			rowSb.WriteString(lipgloss.NewStyle().Foreground(t.Comment).Render(parentPrefix))
-
-			cleanLine := strings.TrimRight(rawLine, " ")
-
-			if lineIdx == 0 {
-				rowSb.WriteString(lipgloss.NewStyle().Foreground(t.Comment).Render(itemConnector))
-				rowSb.WriteString(checkStyle.Render(checkStr))
-				rowSb.WriteString(" ")
-				if isCursor && m.inputMode {
-					rowSb.WriteString(lipgloss.NewStyle().Foreground(t.Base).Background(t.Highlight).Render(cleanLine))
-				} else {
-					rowSb.WriteString(titleStyle.Render(cleanLine))
-				}
-			} else {
-				connectorContinuation := "   "
-				if strings.Contains(itemConnector, "├─") {
-					connectorContinuation = " │ "
-				} else if strings.Contains(itemConnector, "└─") {
-					connectorContinuation = "   "
-				} else {
-					connectorContinuation = " "
-				}
-				rowSb.WriteString(lipgloss.NewStyle().Foreground(t.Comment).Render(connectorContinuation))
-
-				checkboxSpace := "   "
-				if i+1 < len(m.visibleItems) && m.visibleItems[i+1].data.level > item.level {
-					if !item.collapsed {
-						checkboxSpace = " │ "
+	var finalLines []string
+	from, to := 0, 0
+	if len(m.visibleItems) > 0 {
+		var skip int
+		from, to, skip = m.listWindow(height)
+		guides := ui.TreeGuides(m.visibleItems, from, to)
+		for i := from; i < to; i++ {
+			rows := m.itemRows(i, guides[i-from], t)
+			if m.numberWidth() > 0 {
+				rows = slices.Clone(rows)
+				for n := range rows {
+					gutter := strings.Repeat(" ", m.numberWidth())
+					if n == 0 {
+						gutter = m.lineNumber(i, t)
 					}
-				}
-				rowSb.WriteString(lipgloss.NewStyle().Foreground(t.Comment).Render(checkboxSpace))
-				rowSb.WriteString(" ")
-
-				if isCursor && m.inputMode {
-					rowSb.WriteString(lipgloss.NewStyle().Foreground(t.Base).Background(t.Highlight).Render(cleanLine))
-				} else {
-					rowSb.WriteString(titleStyle.Render(cleanLine))
+					rows[n] = gutter + rows[n]
 				}
 			}
-			visualLines = append(visualLines, rowSb.String())
-		}
-		if isCursor {
-			cursorEndLine = len(visualLines)
+			finalLines = append(finalLines, rows...)
 		}
-	}
-
-	// 6. VIEWPORT CALCULATION
-	if cursorStartLine < m.viewportY {
-		m.viewportY = cursorStartLine
-	}
-	if cursorEndLine > m.viewportY+height {
-		m.viewportY = cursorEndLine - height
-	}
-	if m.viewportY > len(visualLines)-height && len(visualLines) > height {
-		m.viewportY = len(visualLines) - height
-	}
-	if m.viewportY < 0 {
-		m.viewportY = 0
-	}
-
-	start := m.viewportY
-	end := start + height
-	if end > len(visualLines) {
-		end = len(visualLines)
-	}
-
-	// 7. SKŁADANIE WIDOKU ZE WSKAŹNIKAMI SCROLLA
-	var finalLines []string
-	for k := start; k < end; k++ {
-		finalLines = append(finalLines, visualLines[k])
+		finalLines = finalLines[skip:]
+		finalLines = finalLines[:min(height, len(finalLines))]
 	}
 	// Dopełnienie
-	for k := 0; k < height-(end-start); k++ {
+	for len(finalLines) < height {
 		finalLines = append(finalLines, "")
 	}
-
-	// LOGIKA WSKAŹNIKÓW SCROLLA (...)
-	canScrollUp := start > 0
-	canScrollDown := end < len(visualLines)
-
-	scrollMarkerStyle := lipgloss.NewStyle().
-		Foreground(t.Comment).
-		Bold(true).
-		Align(lipgloss.Center).
-		Width(m.width - 4) // Szerokość wewnątrz ramki
-
-	if canScrollUp && len(finalLines) > 0 {
-		// Nadpisujemy pierwszą linię wskaźnikiem
-		finalLines[0] = scrollMarkerStyle.Render("↑ ... ↑")
+	// Głębokie wcięcia w wąskim oknie są ucinane, nie zawijane ponownie
+	for i, line := range finalLines {
+		finalLines[i] = ansi.Truncate(line, m.width-2, "")
 	}
+	finalLines = ui.Scrollbar(finalLines, m.width-3, from, to-from, len(m.visibleItems), t)
 
-	if canScrollDown && len(finalLines) > 0 {
-		// Nadpisujemy ostatnią linię wskaźnikiem
-		finalLines[len(finalLines)-1] = scrollMarkerStyle.Render("↓ ... ↓")
-	}
-
-	finalOutput := strings.Join(finalLines, "\n")
-
-	return lipgloss.NewStyle().
-		Width(m.width - 2).Height(height).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(t.Highlight).
-		Render(finalOutput)
+	return m.frame(height, t.Highlight).
+		Render(strings.Join(finalLines, "\n"))
 }
 
 // --- SMART WRAPPING TRASH ---
-func (m *model) renderTrash(height int, t Theme) string {
-	if m.width < 10 {
-		return "Window too narrow"
+func (m *app) renderTrash(height int, t theme.Theme) string {
+	if m.width-2 < minRowWidth {
+		titles := make([]string, len(m.trash))
+		for i, it := range m.trash {
+			titles[i] = model.DisplayTitle(it.Title)
+		}
+		return tinyList(titles, m.cursorTrash, m.width-2, height, t)
 	}
 
 	var visualLines []string
@@ -781,29 +1422,32 @@ func (m *model) renderTrash(height int, t Theme) string {
 
 	if len(m.trash) == 0 {
 		emptyMsg := lipgloss.NewStyle().Foreground(t.Comment).Render("  (Bin is empty)")
-		return lipgloss.NewStyle().
-			Width(m.width - 2).Height(height).
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(t.Error).
+		return m.frame(height, t.Error).
 			Render(emptyMsg)
 	}
 
+	// Usunięte poddrzewo jest zaznaczane w całości
+	selStart, selEnd := model.TrashBlock(m.trash, min(m.cursorTrash, len(m.trash)-1))
 	for i, item := range m.trash {
-		isCursor := (m.cursorTrash == i)
+		isCursor := i == selStart
+		selected := i >= selStart && i < selEnd
 		titleStyle := lipgloss.NewStyle().Foreground(t.Comment).Strikethrough(true)
+		if selected {
+			titleStyle = titleStyle.Foreground(t.Text)
+		}
 
 		// 1. PREFIX
 		var parentPrefixSb strings.Builder
-		if item.level > 0 {
+		if item.Level > 0 {
 			parentPrefixSb.WriteString(" ")
-			for l := 1; l < item.level; l++ {
+			for l := 1; l < item.Level; l++ {
 				hasContinuation := false
 				for k := i + 1; k < len(m.trash); k++ {
 					futureItem := m.trash[k]
-					if futureItem.level < l {
+					if futureItem.Level < l {
 						break
 					}
-					if futureItem.level == l {
+					if futureItem.Level == l {
 						hasContinuation = true
 						break
 					}
@@ -819,14 +1463,14 @@ func (m *model) renderTrash(height int, t Theme) string {
 
 		// 2. KONEKTOR
 		itemConnector := ""
-		if item.level > 0 {
+		if item.Level > 0 {
 			isLastInGroup := true
 			for k := i + 1; k < len(m.trash); k++ {
 				futureItem := m.trash[k]
-				if futureItem.level < item.level {
+				if futureItem.Level < item.Level {
 					break
 				}
-				if futureItem.level == item.level {
+				if futureItem.Level == item.Level {
 					isLastInGroup = false
 					break
 				}
@@ -844,18 +1488,21 @@ func (m *model) renderTrash(height int, t Theme) string {
 		markerStr := "[D]"
 		markerStyle := lipgloss.NewStyle().Foreground(t.Error)
 		cursorStr := "  "
+		if selected {
+			cursorStr = " ┃"
+		}
 		if isCursor {
 			cursorStr = " ➤"
 		}
 
 		// 4. TREŚĆ
 		prefixWidth := 2 + lipgloss.Width(parentPrefix) + lipgloss.Width(itemConnector) + 3 + 1
-		availableWidth := m.width - 2 - prefixWidth
+		availableWidth := m.width - 3 - prefixWidth
 		if availableWidth < 10 {
 			availableWidth = 10
 		}
 
-		content := item.title
+		content := model.DisplayTitle(item.Title)
 		wrappedRaw := lipgloss.NewStyle().Width(availableWidth).Render(content)
 		rawLines := strings.Split(wrappedRaw, "\n")
 
@@ -865,6 +1512,9 @@ func (m *model) renderTrash(height int, t Theme) string {
 
 		for lineIdx, rawLine := range rawLines {
 			var rowSb strings.Builder
+			if lineIdx > 0 && selected {
+				cursorStr = " ┃"
+			}
 			rowSb.WriteString(lipgloss.NewStyle().Foreground(t.Error).Render(cursorStr))
 			rowSb.WriteString(lipgloss.NewStyle().Foreground(t.Comment).Render(parentPrefix))
 			cleanLine := strings.TrimRight(rawLine, " ")
@@ -886,7 +1536,7 @@ func (m *model) renderTrash(height int, t Theme) string {
 				rowSb.WriteString(lipgloss.NewStyle().Foreground(t.Comment).Render(connectorContinuation))
 
 				markerSpace := "   "
-				if i+1 < len(m.trash) && m.trash[i+1].level > item.level {
+				if i+1 < len(m.trash) && m.trash[i+1].Level > item.Level {
 					markerSpace = " │ "
 				}
 				rowSb.WriteString(lipgloss.NewStyle().Foreground(t.Comment).Render(markerSpace))
@@ -895,7 +1545,7 @@ func (m *model) renderTrash(height int, t Theme) string {
 			}
 			visualLines = append(visualLines, rowSb.String())
 		}
-		if isCursor {
+		if i == selEnd-1 {
 			cursorEndLine = len(visualLines)
 		}
 	}
@@ -920,43 +1570,22 @@ func (m *model) renderTrash(height int, t Theme) string {
 		end = len(visualLines)
 	}
 
-	// 7. FINAL RENDER Z SCROLL MARKERS
-	var finalLines []string
-	for k := start; k < end; k++ {
-		finalLines = append(finalLines, visualLines[k])
-	}
-	for k := 0; k < height-(end-start); k++ {
+	// 7. FINAL RENDER ZE SCROLLBAREM
+	finalLines := slices.Clone(visualLines[start:end])
+	for len(finalLines) < height {
 		finalLines = append(finalLines, "")
 	}
+	finalLines = ui.Scrollbar(finalLines, m.width-3, start, end-start, len(visualLines), t)
 
-	canScrollUp := start > 0
-	canScrollDown := end < len(visualLines)
-
-	scrollMarkerStyle := lipgloss.NewStyle().
-		Foreground(t.Error). // Czerwony dla kosza
-		Bold(true).
-		Align(lipgloss.Center).
-		Width(m.width - 4)
-
-	if canScrollUp && len(finalLines) > 0 {
-		finalLines[0] = scrollMarkerStyle.Render("↑ ... ↑")
-	}
-
-	if canScrollDown && len(finalLines) > 0 {
-		finalLines[len(finalLines)-1] = scrollMarkerStyle.Render("↓ ... ↓")
-	}
-
-	finalOutput := strings.Join(finalLines, "\n")
-
-	return lipgloss.NewStyle().
-		Width(m.width - 2).Height(height).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(t.Error).
-		Render(finalOutput)
+	return m.frame(height, t.Error).
+		Render(strings.Join(finalLines, "\n"))
 }
 
-func (m model) renderThemeSelector(height int, t Theme) string {
+// renderThemeSelector lists the themes next to the list itself, both drawn
+// in the highlighted theme t; on narrow screens the sample is left out.
+func (m app) renderThemeSelector(height int, t theme.Theme) string {
 	var s strings.Builder
+	listW := 0
 	for i, theme := range themes {
 		cursor := "  "
 		if m.cursorTheme == i {
@@ -966,214 +1595,203 @@ func (m model) renderThemeSelector(height int, t Theme) string {
 		if m.cursorTheme == i {
 			nameStyle = nameStyle.Foreground(t.Highlight).Bold(true)
 		}
+		theme = shownTheme(theme)
 		preview := lipgloss.NewStyle().Foreground(theme.Base).Render("■") + " " + lipgloss.NewStyle().Foreground(theme.Highlight).Render("■") + " " + lipgloss.NewStyle().Foreground(theme.Special).Render("■")
 		row := fmt.Sprintf("%s%s  %s", lipgloss.NewStyle().Foreground(t.Highlight).Render(cursor), nameStyle.Render(theme.Name), preview)
 		s.WriteString(row + "\n")
+		listW = max(listW, lipgloss.Width(row))
 	}
 
-	return lipgloss.NewStyle().
-		Width(m.width - 2).Height(height).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(t.Highlight).
-		Render(s.String())
-}
-
-func paginator(cursor, height, total int) (int, int) {
-	if total == 0 {
-		return 0, 0
-	}
-	start := 0
-	end := height
-	if total < height {
-		end = total
-	}
-	if cursor >= height {
-		start = cursor - height + 1
-		end = cursor + 1
+	box := m.frame(height, t.Highlight)
+	listW += 4
+	if m.width-listW < 40 {
+		return box.Render(s.String())
 	}
-	return start, end
+	sample := m
+	sample.width = m.width - listW
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		box.Width(listW-2).Render(s.String()),
+		sample.renderList(height, t))
 }
 
-// --- IO (LOADER) ---
+// --- IO (CONFIG) ---
 
-func loadTodo(filename string) ([]item, []item) {
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		return []item{}, []item{}
-	}
-	file, _ := os.Open(filename)
-	defer file.Close()
-
-	var active []item
-	var trash []item
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		trimmed := strings.TrimSpace(line)
-
-		if strings.HasPrefix(trimmed, "- [") {
-			isDone := strings.Contains(line, "- [x]")
-			isTrash := strings.Contains(line, "- [D]")
-
-			leadingSpaces := 0
-			for _, char := range line {
-				if char == ' ' {
-					leadingSpaces++
-				} else {
-					break
-				}
-			}
-			level := leadingSpaces / 2
-
-			parts := strings.SplitN(line, "]", 2)
-			if len(parts) > 1 {
-				newItem := item{title: strings.TrimSpace(parts[1]), done: isDone, level: level}
-
-				if isTrash {
-					trash = append(trash, newItem)
-				} else {
-					active = append(active, newItem)
-				}
-			}
+// loadConfig reads ./config.json, else the one in the user config dir. A
+// missing file means defaults; an unreadable or invalid one is an error.
+func loadConfig() (Config, error) {
+	var cfg Config
+	path := configFile
+	if _, err := os.Stat(path); err != nil {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return cfg, nil
 		}
+		path = filepath.Join(configDir, appName, configFile)
 	}
-	return active, trash
-}
-
-func saveTodo(filename string, items []item, trash []item) {
-	file, _ := os.Create(filename)
-	defer file.Close()
-	writer := bufio.NewWriter(file)
 
-	for _, item := range items {
-		status := " "
-		if item.done {
-			status = "x"
-		}
-		prefix := strings.Repeat("  ", item.level)
-		line := fmt.Sprintf("%s- [%s] %s\n", prefix, status, item.title)
-		writer.WriteString(line)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
 	}
-
-	for _, item := range trash {
-		prefix := strings.Repeat("  ", item.level)
-		line := fmt.Sprintf("%s- [D] %s\n", prefix, item.title)
-		writer.WriteString(line)
+	if err != nil {
+		return cfg, err
 	}
-
-	writer.Flush()
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
 }
 
-// --- IO (Config & Themes - SMART DEDUPLICATION) ---
-
-func loadThemes() []Theme {
-	var finalThemes []Theme
-	seen := make(map[string]bool)
-
-	addThemes := func(source []Theme) {
-		for _, t := range source {
-			if !seen[t.Name] {
-				finalThemes = append(finalThemes, t)
-				seen[t.Name] = true
-			}
-		}
+func saveConfig(cfg Config) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
 	}
 
-	localContent, err := os.ReadFile(defaultThemesFile)
-	if err == nil {
-		addThemes(parseThemes(localContent))
+	if _, err := os.Stat(configFile); err == nil {
+		return os.WriteFile(configFile, data, 0644)
 	}
 
 	configDir, err := os.UserConfigDir()
-	if err == nil {
-		globalPath := filepath.Join(configDir, appName, defaultThemesFile)
-		userContent, err := os.ReadFile(globalPath)
-		if err == nil {
-			addThemes(parseThemes(userContent))
-		}
-	}
-
-	embeddedContent, err := embeddedThemesFS.ReadFile(defaultThemesFile)
-	if err == nil {
-		addThemes(parseThemes(embeddedContent))
+	if err != nil {
+		return err
 	}
-
-	if len(finalThemes) == 0 {
-		return nil
+	appDir := filepath.Join(configDir, appName)
+	if err := os.MkdirAll(appDir, 0755); err != nil {
+		return err
 	}
-
-	return finalThemes
-}
-
-func parseThemes(content []byte) []Theme {
-	var jsonThemes []JSONTheme
-	if err := json.Unmarshal(content, &jsonThemes); err != nil {
-		return nil
-	}
-	var result []Theme
-	for _, jt := range jsonThemes {
-		result = append(result, Theme{
-			Name:      jt.Name,
-			Base:      lipgloss.Color(jt.Base),
-			Highlight: lipgloss.Color(jt.Highlight),
-			Text:      lipgloss.Color(jt.Text),
-			Comment:   lipgloss.Color(jt.Comment),
-			Special:   lipgloss.Color(jt.Special),
-			Error:     lipgloss.Color(jt.Error),
-			Accent:    lipgloss.Color(jt.Accent),
-		})
-	}
-	return result
+	return os.WriteFile(filepath.Join(appDir, configFile), data, 0644)
 }
 
-func loadConfig() Config {
-	var cfg Config
-
-	if _, err := os.Stat(configFile); err == nil {
-		data, _ := os.ReadFile(configFile)
-		json.Unmarshal(data, &cfg)
-		return cfg
+func main() {
+	// Podpolecenia też zapisują listy
+	if cfg, err := loadConfig(); err == nil {
+		storage.SetLineEnding(cfg.LineEnding)
+		storage.SetIndent(cfg.Indent)
 	}
-
-	configDir, err := os.UserConfigDir()
-	if err == nil {
-		globalPath := filepath.Join(configDir, appName, configFile)
-		if _, err := os.Stat(globalPath); err == nil {
-			data, _ := os.ReadFile(globalPath)
-			json.Unmarshal(data, &cfg)
-			return cfg
+	if len(os.Args) > 1 && isDemoArg(os.Args[1]) {
+		runDemo(os.Args[1:])
+		return
+	}
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		case "lint":
+			runLint(os.Args[2:])
+			return
+		case "remind":
+			runRemind(os.Args[2:])
+			return
+		case "report":
+			runReport(os.Args[2:])
+			return
+		case "activity":
+			runActivity(os.Args[2:])
+			return
+		case "import":
+			runImport(os.Args[2:])
+			return
+		case "export":
+			runExport(os.Args[2:])
+			return
+		case "grep":
+			runGrep(os.Args[2:])
+			return
+		case "print":
+			runPrint(os.Args[2:])
+			return
+		case "add":
+			runAdd(os.Args[2:])
+			return
+		case "capture":
+			runCapture(os.Args[2:])
+			return
+		case "all":
+			runWorkspace(os.Args[2:])
+			return
+		case "bugreport":
+			runBugReport(os.Args[2:])
+			return
+		case "idle-lock-hash":
+			runIdleLockHash(os.Args[2:])
+			return
 		}
 	}
 
-	return cfg
-}
-
-func saveConfig(themeName string) {
-	cfg := Config{SelectedTheme: themeName}
-	data, _ := json.MarshalIndent(cfg, "", "  ")
-
-	if _, err := os.Stat(configFile); err == nil {
-		os.WriteFile(configFile, data, 0644)
-		return
+	args, inline := cutInlineFlag(os.Args[1:])
+	filename := defaultFile()
+	if len(args) > 0 {
+		filename = args[0]
+	} else if needsWizard() {
+		chosen, ok, err := runWizard()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !ok {
+			return
+		}
+		filename = chosen
 	}
+	m := initialModel(filename)
+	m.inline = inline
+	runTUI(filename, m)
+}
 
-	configDir, err := os.UserConfigDir()
-	if err == nil {
-		appDir := filepath.Join(configDir, appName)
-		os.MkdirAll(appDir, 0755)
-		globalPath := filepath.Join(appDir, configFile)
-		os.WriteFile(globalPath, data, 0644)
+// defaultFile is the list opened without a file argument.
+func defaultFile() string {
+	if cfg, err := loadConfig(); err == nil && cfg.File != "" {
+		return expandHome(cfg.File)
 	}
+	return "todo.md"
 }
 
-func main() {
-	filename := "todo.md"
-	if len(os.Args) > 1 {
-		filename = os.Args[1]
-	}
-	p := tea.NewProgram(initialModel(filename), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+// runTUI runs the app on filename until quit and writes what is pending.
+func runTUI(filename string, m app) {
+	var opts []tea.ProgramOption
+	if !m.inline {
+		opts = append(opts, tea.WithAltScreen())
+	}
+	p := tea.NewProgram(m, opts...)
+	// Zamknięte okno terminala (SIGHUP) kończy program jak q, z zapisem
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		if _, ok := <-hup; ok {
+			p.Quit()
+		}
+	}()
+	final, err := p.Run()
+	signal.Stop(hup)
+	close(hup)
+	if err != nil {
+		// Po panice modelu nie ma; niezapisane zmiany czekają w dzienniku
+		if fm, ok := final.(app); ok {
+			fm.flushSave()
+		}
 		fmt.Printf("Error: %v", err)
 		os.Exit(1)
 	}
+	if fm, ok := final.(app); ok {
+		if fm.inline && len(fm.visibleItems) > 0 {
+			fmt.Println(fm.finalList(shownTheme(fm.activeTheme)))
+		}
+		wrote := fm.dirty
+		err := fm.flushSave()
+		if wrote && err == nil {
+			// Program już nie działa, więc hook zapisu czekamy tu
+			fm.fireHook("save", -1)
+			for _, h := range fm.hooks {
+				h.run()
+			}
+		}
+		fm.saveSession()
+		fm.lock.release()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: could not save %s: %v\n", filename, err)
+			os.Exit(1)
+		}
+	}
 }
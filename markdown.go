@@ -0,0 +1,190 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- INLINE MARKDOWN ---
+//
+// Titles in the list are shown with their inline markdown applied: **bold**
+// (or __bold__), *italic* (or _italic_), `code` and [text](url) links lose
+// their markers and are styled instead, like bare URLs. The file keeps the
+// markdown as typed; editing shows it raw. A backslash escapes a marker.
+// Underscores inside words (snake_case, key:some_value) are left alone.
+
+type mdAttr uint8
+
+const (
+	mdBold mdAttr = 1 << iota
+	mdItalic
+	mdCode
+	mdLink
+)
+
+// inlineMarkdown strips the markers from s and returns the plain text with
+// the attributes of each of its bytes.
+func inlineMarkdown(s string) (string, []mdAttr) {
+	var b strings.Builder
+	var attrs []mdAttr
+	write := func(text string, a mdAttr) {
+		b.WriteString(text)
+		for range len(text) {
+			attrs = append(attrs, a)
+		}
+	}
+	var parse func(s string, a mdAttr)
+	parse = func(s string, a mdAttr) {
+		for i := 0; i < len(s); {
+			c := s[i]
+			switch {
+			case c == '\\' && i+1 < len(s) && strings.IndexByte("\\*_`[]", s[i+1]) != -1:
+				write(s[i+1:i+2], a)
+				i += 2
+				continue
+			case c == '`':
+				if end := strings.IndexByte(s[i+1:], '`'); end > 0 {
+					write(s[i+1:i+1+end], a|mdCode)
+					i += end + 2
+					continue
+				}
+			case c == '[' && (i == 0 || s[i-1] != '['):
+				if text, rest, ok := strings.Cut(s[i+1:], "]("); ok && text != "" && !strings.ContainsAny(text, "[]") {
+					if url, _, ok := strings.Cut(rest, ")"); ok && url != "" && !strings.ContainsAny(url, " \t") {
+						parse(text, a|mdLink)
+						i += 1 + len(text) + 2 + len(url) + 1
+						continue
+					}
+				}
+			case c == '*' || c == '_':
+				marker := string(c)
+				attr := mdItalic
+				if i+1 < len(s) && s[i+1] == c {
+					marker, attr = marker+marker, mdBold
+				}
+				if end := closingMarker(s, i, marker); end != -1 {
+					parse(s[i+len(marker):end], a|attr)
+					i = end + len(marker)
+					continue
+				}
+			}
+			write(s[i:i+1], a)
+			i++
+		}
+	}
+	parse(s, 0)
+
+	// Gołe adresy też są linkami
+	plain := b.String()
+	for _, span := range urlSpans(plain) {
+		for i := span[0]; i < span[1]; i++ {
+			attrs[i] |= mdLink
+		}
+	}
+	return plain, attrs
+}
+
+// closingMarker finds where the emphasis opened by marker at s[at] ends, or
+// -1. An opener must be followed by a non-space and a closer preceded by one;
+// for "_" both must also sit at word boundaries.
+func closingMarker(s string, at int, marker string) int {
+	start := at + len(marker)
+	if start >= len(s) || s[start] == ' ' {
+		return -1
+	}
+	under := marker[0] == '_'
+	if under && at > 0 && isWordByte(s, at, -1) {
+		return -1
+	}
+	for i := start + 1; i+len(marker) <= len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == '`' {
+			if end := strings.IndexByte(s[i+1:], '`'); end != -1 {
+				i += end + 1
+			}
+			continue
+		}
+		if !strings.HasPrefix(s[i:], marker) || s[i-1] == ' ' {
+			continue
+		}
+		// "**" nie może zamykać pojedynczej "*"
+		if len(marker) == 1 && i+1 < len(s) && s[i+1] == marker[0] {
+			i++
+			continue
+		}
+		if under && i+len(marker) < len(s) && isWordByte(s, i+len(marker), 0) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// isWordByte reports whether the rune at s[i] (dir 0) or the one ending
+// right before s[i] (dir -1) is a letter or digit.
+func isWordByte(s string, i, dir int) bool {
+	var r rune
+	if dir < 0 {
+		r, _ = utf8.DecodeLastRuneInString(s[:i])
+	} else {
+		r, _ = utf8.DecodeRuneInString(s[i:])
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// mdPlain is s without its inline markdown.
+func mdPlain(s string) string {
+	plain, _ := inlineMarkdown(s)
+	return plain
+}
+
+func mdStyle(base lipgloss.Style, a mdAttr, t theme.Theme) lipgloss.Style {
+	if a&mdBold != 0 {
+		base = base.Bold(true)
+	}
+	if a&mdItalic != 0 {
+		base = base.Italic(true)
+	}
+	if a&mdCode != 0 {
+		base = base.Foreground(t.Special)
+	}
+	if a&mdLink != 0 {
+		base = base.Foreground(t.Accent).Underline(true)
+	}
+	return base
+}
+
+// styleMarkdown renders the wrapped lines of the plain text of a title,
+// each run of bytes with the style of its attributes. Wrapping only drops
+// spaces at line breaks, so every line is found in plain in order.
+func styleMarkdown(lines []string, plain string, attrs []mdAttr, base lipgloss.Style, t theme.Theme) []string {
+	out := make([]string, len(lines))
+	pos := 0
+	for n, line := range lines {
+		at := strings.Index(plain[pos:], line)
+		if at == -1 || line == "" {
+			out[n] = base.Render(line)
+			continue
+		}
+		at += pos
+		var b strings.Builder
+		for i := 0; i < len(line); {
+			j := i + 1
+			for j < len(line) && attrs[at+j] == attrs[at+i] {
+				j++
+			}
+			b.WriteString(mdStyle(base, attrs[at+i], t).Render(line[i:j]))
+			i = j
+		}
+		out[n] = b.String()
+		pos = at + len(line)
+	}
+	return out
+}
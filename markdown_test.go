@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// spans lists the plain text as runs, bold as *x*, italic as /x/, code as
+// `x` and links as <x>.
+func spans(plain string, attrs []mdAttr) string {
+	var b strings.Builder
+	for i := 0; i < len(plain); {
+		j := i + 1
+		for j < len(plain) && attrs[j] == attrs[i] {
+			j++
+		}
+		text := plain[i:j]
+		if attrs[i]&mdCode != 0 {
+			text = "`" + text + "`"
+		}
+		if attrs[i]&mdItalic != 0 {
+			text = "/" + text + "/"
+		}
+		if attrs[i]&mdBold != 0 {
+			text = "*" + text + "*"
+		}
+		if attrs[i]&mdLink != 0 {
+			text = "<" + text + ">"
+		}
+		b.WriteString(text)
+		i = j
+	}
+	return b.String()
+}
+
+func TestInlineMarkdown(t *testing.T) {
+	for in, want := range map[string]string{
+		"fix **the** build":                 "fix *the* build",
+		"read _Dune_ and *Emma* __now__":    "read /Dune/ and /Emma/ *now*",
+		"run `go test ./...` first":         "run `go test ./...` first",
+		"see [the docs](https://go.dev) ok": "see <the docs> ok",
+		"**bold _and italic_**":             "*bold **/and italic/*",
+		"2 * 3 * 4 and snake_case_name":     "2 * 3 * 4 and snake_case_name",
+		"owner:ann_smith #to_do":            "owner:ann_smith #to_do",
+		`not \*emphasis\* here`:             "not *emphasis* here",
+		"**unclosed and `open":              "**unclosed and `open",
+		"[[wiki link]] https://x.io/a_b_c.": "[[wiki link]] <https://x.io/a_b_c>.",
+	} {
+		if got := spans(inlineMarkdown(in)); got != want {
+			t.Errorf("%q: got %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestStyleMarkdownAcrossWrap(t *testing.T) {
+	var linked []string
+	plain, attrs := inlineMarkdown("see https://example.com/x now **done** later")
+	lines := strings.Split(lipgloss.NewStyle().Width(15).Render(plain), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	base := lipgloss.NewStyle().Transform(func(s string) string { linked = append(linked, s); return s })
+	out := styleMarkdown(lines, plain, attrs, base, theme.Default)
+	if len(out) != len(lines) {
+		t.Fatalf("got %d lines for %d", len(out), len(lines))
+	}
+	// every run is rendered on its own, so the URL cut by the wrap shows up
+	// as two runs and "done" as its own bold run
+	if strings.Join(linked, "|") != "see|https://example|.com/x| now |done|later" {
+		t.Errorf("runs = %q (lines %q)", linked, lines)
+	}
+}
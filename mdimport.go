@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- MARKDOWN IMPORT ---
+//
+// `todo import notes.md` (or `:import notes.md`) picks the "- [ ]" task
+// lists out of any markdown document — meeting notes, a README — and adds
+// them at the end of the list. The headings above the tasks come along as
+// parent tasks, so "## Action items" in "# Sprint sync" becomes
+// Sprint sync › Action items › the tasks. `--section "Action items"` (or
+// `:import notes.md Action items`) takes only that heading and what is
+// under it. Tasks already in the list or the bin are skipped, so importing
+// an updated document again adds only what is new.
+
+var (
+	mdHeading  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdTaskLine = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s+(.*\S)\s*$`)
+	mdFence    = regexp.MustCompile("^\\s*(```|~~~)")
+)
+
+func isMarkdownFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown", ".mdown", ".txt":
+		return true
+	}
+	return false
+}
+
+// importedTask is a task read from a document, or a heading above tasks.
+type importedTask struct {
+	model.Item
+	heading bool
+}
+
+// markdownTasks returns the tasks of a markdown document under their
+// headings, as a tree starting at level 0. With section set only the
+// heading of that name (case aside) and its subsections are read.
+func markdownTasks(data []byte, section string) ([]importedTask, error) {
+	type heading struct {
+		depth int // liczba #
+		title string
+		added bool
+	}
+	var (
+		tasks    []importedTask
+		headings []heading
+		indents  []int
+		inFence  bool
+		found    = section == ""
+		inside   = section == ""
+		sectionD int
+	)
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if mdFence.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if h := mdHeading.FindStringSubmatch(line); h != nil {
+			depth, title := len(h[1]), h[2]
+			if section != "" {
+				switch {
+				case strings.EqualFold(title, section):
+					found, inside, sectionD = true, true, depth
+					headings = nil
+				case inside && depth <= sectionD:
+					inside = false
+				}
+			}
+			for len(headings) > 0 && headings[len(headings)-1].depth >= depth {
+				headings = headings[:len(headings)-1]
+			}
+			headings = append(headings, heading{depth: depth, title: title})
+			indents = nil
+			continue
+		}
+		t := mdTaskLine.FindStringSubmatch(line)
+		if t == nil || !inside {
+			continue
+		}
+
+		// Nagłówki nad zadaniem stają się jego rodzicami
+		for i := range headings {
+			if !headings[i].added {
+				tasks = append(tasks, importedTask{model.Item{Title: headings[i].title, Level: i}, true})
+				headings[i].added = true
+			}
+		}
+		indent := len(strings.ReplaceAll(t[1], "\t", "    "))
+		for len(indents) > 0 && indents[len(indents)-1] >= indent {
+			indents = indents[:len(indents)-1]
+		}
+		tasks = append(tasks, importedTask{model.Item{Title: t[3], Done: t[2] != " ", Level: len(headings) + len(indents)}, false})
+		indents = append(indents, indent)
+	}
+	if !found {
+		return nil, fmt.Errorf("no heading %q", section)
+	}
+	return tasks, nil
+}
+
+// subtreeEnd is model.SubtreeEnd for imported tasks.
+func subtreeEnd(tasks []importedTask, idx int) int {
+	end := idx + 1
+	for end < len(tasks) && tasks[end].Level > tasks[idx].Level {
+		end++
+	}
+	return end
+}
+
+// importTasks adds the imported tree at the end of items and returns how
+// many tasks it added. Tasks whose title is already in the list or the bin
+// are left out with their subtasks, and so are headings left without tasks.
+// Headings already in the list take the new tasks under them.
+func importTasks(items, trash []model.Item, tasks []importedTask) ([]model.Item, int) {
+	seen := map[string]bool{}
+	for _, list := range [][]model.Item{items, trash} {
+		for _, it := range list {
+			seen[model.DisplayTitle(it.Title)] = true
+		}
+	}
+	var fresh []importedTask
+	for i := 0; i < len(tasks); i++ {
+		if !tasks[i].heading && seen[model.DisplayTitle(tasks[i].Title)] {
+			i = subtreeEnd(tasks, i) - 1
+			continue
+		}
+		fresh = append(fresh, tasks[i])
+	}
+
+	var out []model.Item
+	added := 0
+	for i, t := range fresh {
+		if t.heading && !slices.ContainsFunc(fresh[i+1:subtreeEnd(fresh, i)], func(t importedTask) bool { return !t.heading }) {
+			continue
+		}
+		if !t.heading {
+			added++
+		}
+		out = append(out, t.Item)
+	}
+
+	return mergeTasks(items, 0, len(items), out), added
+}
+
+// mergeTasks adds the forest tree under items[lo:hi], whose top level is
+// that of the roots of tree. A root titled like an item there can only be a
+// heading (known tasks were left out), so its children are merged into that
+// item instead of adding it twice.
+func mergeTasks(items []model.Item, lo, hi int, tree []model.Item) []model.Item {
+	for i := 0; i < len(tree); {
+		end := model.SubtreeEnd(tree, i)
+		j := lo
+		for ; j < hi; j = model.SubtreeEnd(items, j) {
+			if model.DisplayTitle(items[j].Title) == tree[i].Title {
+				break
+			}
+		}
+		n := len(items)
+		if j < hi {
+			items = mergeTasks(items, j+1, model.SubtreeEnd(items, j), tree[i+1:end])
+		} else {
+			items = slices.Insert(items, hi, tree[i:end]...)
+		}
+		hi += len(items) - n
+		i = end
+	}
+	return items
+}
+
+func readMarkdownTasks(path, section string) ([]importedTask, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := markdownTasks(data, section)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return tasks, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+const meetingNotes = "# Sprint sync\n\nSome notes, [ ] not a task.\n\n## Action items\n\n- [ ] Ship the release\n  - [x] Write the changelog\n* [ ] Book the room\n\n```\n- [ ] example in code\n```\n\n## Parking lot\n\n1. [ ] Rename the repo\n\n# Empty\n\nNothing to do.\n"
+
+func flat(items []model.Item) []string {
+	var out []string
+	for _, it := range items {
+		out = append(out, fmt.Sprintf("%d:%s:%v", it.Level, it.Title, it.Done))
+	}
+	return out
+}
+
+func TestMarkdownTasks(t *testing.T) {
+	tasks, err := markdownTasks([]byte(meetingNotes), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, n := importTasks(nil, nil, tasks)
+	want := []string{
+		"0:Sprint sync:false",
+		"1:Action items:false",
+		"2:Ship the release:false",
+		"3:Write the changelog:true",
+		"2:Book the room:false",
+		"1:Parking lot:false",
+		"2:Rename the repo:false",
+	}
+	if got := flat(items); !slices.Equal(got, want) || n != 4 {
+		t.Errorf("imported %d:\n%q", n, got)
+	}
+
+	tasks, _ = markdownTasks([]byte(meetingNotes), "action ITEMS")
+	items, _ = importTasks(nil, nil, tasks)
+	if got := flat(items); len(got) != 4 || got[0] != "0:Action items:false" {
+		t.Errorf("section = %q", got)
+	}
+	if _, err := markdownTasks([]byte(meetingNotes), "Decisions"); err == nil {
+		t.Error("a missing section must be an error")
+	}
+}
+
+func TestImportTasksAgain(t *testing.T) {
+	tasks, _ := markdownTasks([]byte(meetingNotes), "")
+	existing := []model.Item{
+		{Title: "Sprint sync"},
+		{Title: "Action items", Level: 1},
+		{Title: "Ship the release", Level: 2},
+		{Title: "Other"},
+	}
+	trash := []model.Item{{Title: "Rename the repo"}}
+	items, n := importTasks(existing, trash, tasks)
+	want := []string{
+		"0:Sprint sync:false",
+		"1:Action items:false",
+		"2:Ship the release:false",
+		"2:Book the room:false",
+		"0:Other:false",
+	}
+	if got := flat(items); !slices.Equal(got, want) || n != 1 {
+		t.Errorf("imported %d:\n%q", n, got)
+	}
+}
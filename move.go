@@ -0,0 +1,67 @@
+package main
+
+import (
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- MOVE MODE ---
+//
+// "M" picks up the selected subtree; the cursor then moves as usual and the
+// subtree is dropped under the item at the cursor ("enter") or at the top
+// level ("T"). Reloads are held off meanwhile so moveFrom stays valid.
+
+func (m *app) startMove(idx int) {
+	m.moving = true
+	m.moveFrom = idx
+}
+
+// updateMove handles a key in move mode. Navigation keys return false and
+// are handled by updateMain; everything else is consumed.
+func (m *app) updateMove(key string, target int) bool {
+	switch key {
+	case "up", "k", "down", "j", "v":
+		return false
+	case "esc":
+		m.moving = false
+		m.backToReview()
+	case "enter", "T":
+		parent := target
+		if key == "T" {
+			parent = -1
+		}
+		items, idx, ok := model.MoveSubtree(m.items, m.moveFrom, parent)
+		if !ok {
+			m.status = "Can't move a task into its own subtree"
+			return true
+		}
+		m.moving = false
+		m.items = items
+		m.jumpTo(idx)
+		m.save()
+		m.backToReview()
+	}
+	return true
+}
+
+// --- DUPLICATE ---
+
+// copyResetKeys are dropped from copies: ids must stay unique, and time
+// spent or snoozes belong to the original.
+var copyResetKeys = map[string]bool{
+	"id": true, "timer": true, "spent": true, "pomo": true, "snooze": true, "by": true, "feed": true, "reviewed": true,
+}
+
+// duplicate copies the subtree at idx below itself, reopened, and puts the
+// cursor on the copy.
+func (m *app) duplicate(idx int) {
+	items, at := model.DuplicateSubtree(m.items, idx)
+	today := time.Now().Format(model.DateLayout)
+	for i := at; i < at+(at-idx); i++ {
+		items[i].Title = model.SetMeta(model.StripMeta(items[i].Title, copyResetKeys), "created", today)
+	}
+	m.items = items
+	m.jumpTo(at)
+	m.save()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestMoveMode(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{items: []model.Item{
+		{Title: "a"},
+		{Title: "a1", Level: 1},
+		{Title: "b"},
+	}}
+	m.recalcVisible()
+	press := func(k string) {
+		t.Helper()
+		next, _ := m.updateMain(keyMsg(k))
+		m = next.(app)
+	}
+
+	press("M")
+	press("d") // ignored while moving
+	if len(m.items) != 3 {
+		t.Fatal("delete ran in move mode")
+	}
+	press("j")
+	press("enter")
+	if m.status == "" || !m.moving {
+		t.Fatal("dropped a subtree into itself")
+	}
+	press("j")
+	press("enter")
+	if m.moving || m.items[1].Title != "a" || m.items[1].Level != 1 || m.items[2].Level != 2 {
+		t.Fatalf("items = %+v", m.items)
+	}
+	if m.visibleItems[m.cursorMain].Index != 1 {
+		t.Errorf("cursor not on the moved item")
+	}
+
+	press("M")
+	press("T")
+	if m.items[1].Title != "a" || m.items[1].Level != 0 || m.items[2].Level != 1 {
+		t.Errorf("top level: items = %+v", m.items)
+	}
+	press("M")
+	press("esc")
+	if m.moving {
+		t.Error("esc did not cancel")
+	}
+}
+
+func TestDuplicate(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{items: []model.Item{
+		{Title: "packing id:aa"},
+		{Title: "socks spent:20m id:bb", Level: 1, Done: true},
+		{Title: "other"},
+	}}
+	m.recalcVisible()
+	next, _ := m.updateMain(keyMsg("D"))
+	m = next.(app)
+	if len(m.items) != 5 || m.visibleItems[m.cursorMain].Index != 2 {
+		t.Fatalf("items = %+v, cursor = %d", m.items, m.cursorMain)
+	}
+	dup := m.items[3]
+	if dup.Done || model.ID(dup) != "" || model.MetaValue(dup.Title, "spent") != "" || model.MetaValue(dup.Title, "created") == "" {
+		t.Errorf("copy = %+v", dup)
+	}
+	if !m.items[1].Done || model.ID(m.items[1]) != "bb" {
+		t.Error("original changed")
+	}
+}
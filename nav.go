@@ -0,0 +1,85 @@
+package main
+
+import (
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- NAVIGATION ---
+
+// listHeight is the number of content lines inside the main frame.
+func (m app) listHeight() int {
+	return max(1, m.height-m.chromeHeight())
+}
+
+// takeCount consumes the pending numeric prefix (default 1).
+func (m *app) takeCount() int {
+	n := max(1, m.pendingCount)
+	m.pendingCount = 0
+	return n
+}
+
+// handleCountKey accumulates digits typed before a command (e.g. "5j").
+func (m *app) handleCountKey(key string) bool {
+	if len(key) != 1 || key[0] < '0' || key[0] > '9' {
+		return false
+	}
+	if key == "0" && m.pendingCount == 0 {
+		return false
+	}
+	m.pendingCount = min(m.pendingCount*10+int(key[0]-'0'), 9999)
+	return true
+}
+
+// handleNavKey implements the vim-style motions. It returns false when the
+// key is not a navigation key.
+func (m *app) handleNavKey(key string) bool {
+	if m.pendingKey == "g" {
+		m.pendingKey = ""
+		switch key {
+		case "g":
+			// "12gg" jak "12G"
+			m.jumpToLine(m.takeCount())
+		case "p":
+			if len(m.visibleItems) > 0 {
+				if p := model.ParentIndex(m.items, m.visibleItems[m.cursorMain].Index); p != -1 {
+					m.jumpTo(p)
+				}
+			}
+		}
+		return true
+	}
+
+	last := max(0, len(m.visibleItems)-1)
+	switch key {
+	case "g":
+		m.pendingKey = "g"
+	case "G":
+		// "5G" skacze do piątej pozycji, samo "G" na koniec
+		if m.pendingCount > 0 {
+			m.jumpToLine(m.takeCount())
+		} else {
+			m.cursorMain = last
+		}
+	case "ctrl+d":
+		m.cursorMain = min(last, m.cursorMain+max(1, m.listHeight()/2))
+	case "ctrl+u":
+		m.cursorMain = max(0, m.cursorMain-max(1, m.listHeight()/2))
+	case "}":
+		for i := m.cursorMain + 1; i < len(m.visibleItems); i++ {
+			if m.visibleItems[i].Data.Level == 0 {
+				m.cursorMain = i
+				break
+			}
+		}
+	case "{":
+		for i := m.cursorMain - 1; i >= 0; i-- {
+			if m.visibleItems[i].Data.Level == 0 {
+				m.cursorMain = i
+				break
+			}
+		}
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- LINE NUMBERS ---
+//
+// "line_numbers": "absolute" or "relative" in config.json (":numbers"
+// cycles off → absolute → relative) numbers the visible tasks in a gutter,
+// as vim does. Relative numbers count from the cursor, whose task shows its
+// own position, so the count for "5j" can be read off the screen. "12G",
+// "12gg" and ":12" jump to the 12th task.
+
+func (c Config) lineNumbers() string {
+	switch c.LineNumbers {
+	case "absolute", "relative":
+		return c.LineNumbers
+	}
+	return ""
+}
+
+// numberWidth is the width of the gutter, 0 without line numbers.
+func (m app) numberWidth() int {
+	if m.config.lineNumbers() == "" {
+		return 0
+	}
+	return len(strconv.Itoa(max(1, len(m.visibleItems)))) + 1
+}
+
+// lineNumber is the gutter of the first row of visible item i.
+func (m app) lineNumber(i int, t theme.Theme) string {
+	n := i + 1
+	style := lipgloss.NewStyle().Foreground(t.Comment)
+	if i == m.cursorMain {
+		style = style.Foreground(t.Highlight)
+	} else if m.config.lineNumbers() == "relative" {
+		n = max(i-m.cursorMain, m.cursorMain-i)
+	}
+	return style.Render(fmt.Sprintf("%*d ", m.numberWidth()-1, n))
+}
+
+func (m *app) cycleNumbers() {
+	switch m.config.lineNumbers() {
+	case "":
+		m.config.LineNumbers = "absolute"
+		m.status = "Line numbers on"
+	case "absolute":
+		m.config.LineNumbers = "relative"
+		m.status = "Relative line numbers"
+	default:
+		m.config.LineNumbers = ""
+		m.status = "Line numbers off"
+	}
+	m.saveLayout()
+}
+
+// jumpToLine moves the cursor to the n-th visible task (counted from 1).
+func (m *app) jumpToLine(n int) {
+	m.cursorMain = max(0, min(len(m.visibleItems)-1, n-1))
+}
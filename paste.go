@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- SMART PASTE ---
+//
+// Pasting several lines (the terminal's bracketed paste) adds one task per
+// line instead of one long title. Bullets ("-", "*", "+", "1.") and
+// checkboxes are dropped, "[x]" marks a task done, and indentation nests
+// the tasks. While typing a task the first line completes it; in the list
+// the tasks go after the selected one.
+
+var pasteBullet = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+`)
+var pasteCheckbox = regexp.MustCompile(`^\[([ xX])\]\s*`)
+
+// pastedItems turns pasted text into tasks, the first one at level 0.
+func pastedItems(text string, now time.Time) []model.Item {
+	var items []model.Item
+	var indents []int
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		title := strings.TrimLeft(line, " \t")
+		if title == "" {
+			continue
+		}
+		indent := 0
+		for _, r := range line[:len(line)-len(title)] {
+			if r == '\t' {
+				indent += 4
+			} else {
+				indent++
+			}
+		}
+		// Głębokość to liczba płytszych wcięć nad linią
+		for len(indents) > 0 && indents[len(indents)-1] >= indent {
+			indents = indents[:len(indents)-1]
+		}
+		level := len(indents)
+		indents = append(indents, indent)
+
+		title = pasteBullet.ReplaceAllString(title, "")
+		done := false
+		if mark := pasteCheckbox.FindStringSubmatch(title); mark != nil {
+			done = mark[1] != " "
+			title = title[len(mark[0]):]
+		}
+		if title == "" {
+			continue
+		}
+		title = model.SetMeta(expandEstimate(expandDates(title, now)), "created", now.Format(model.DateLayout))
+		items = append(items, model.Item{Title: title, Level: level, Done: done})
+	}
+	return items
+}
+
+// paste handles a bracketed paste in the input line or the list.
+func (m *app) paste(text string) {
+	if !strings.ContainsAny(strings.TrimSpace(text), "\r\n") && m.inputMode {
+		m.inputBuf += text
+		return
+	}
+	now := time.Now()
+	pasted := pastedItems(text, now)
+	if len(pasted) == 0 {
+		return
+	}
+	m.status = fmt.Sprintf("Pasted %d tasks", len(pasted))
+
+	var at, level int
+	switch {
+	case m.inputMode:
+		// Pierwsza linia kończy wpisywane zadanie
+		idx := m.visibleItems[m.cursorMain].Index
+		m.inputBuf += model.StripMeta(pasted[0].Title, map[string]bool{"created": true})
+		m.handleInputConfirm()
+		at, level = model.SubtreeEnd(m.items, idx), m.items[idx].Level
+		pasted = pasted[1:]
+	case len(m.visibleItems) > 0:
+		idx := m.visibleItems[m.cursorMain].Index
+		at, level = model.SubtreeEnd(m.items, idx), m.items[idx].Level
+	case m.zoomRoot() != -1:
+		root := m.zoomRoot()
+		at, level = model.SubtreeEnd(m.items, root), m.items[root].Level+1
+	default:
+		at = len(m.items)
+	}
+	for i := range pasted {
+		pasted[i].Level += level
+	}
+	m.items = slices.Insert(m.items, at, pasted...)
+	for i := range pasted {
+		m.fireHook("add", at+i)
+	}
+	m.recalcVisible()
+	if len(pasted) > 0 {
+		m.cursorMain = max(0, model.VisiblePos(m.visibleItems, at))
+	}
+	m.save()
+}
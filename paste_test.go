@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestPastedItems(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	text := "Trip\r\n  - [ ] book hotel due:fri\n  - [x] buy tickets\n  * pack\n\n\t1. socks\n2) call mum\n"
+	items := pastedItems(text, time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local))
+	var got []string
+	for _, it := range items {
+		got = append(got, fmt.Sprintf("%d %s %v", it.Level, model.DisplayTitle(it.Title), it.Done))
+	}
+	want := []string{"0 Trip false", "1 book hotel due:2026-10-23 false", "1 buy tickets true", "1 pack false", "2 socks false", "0 call mum false"}
+	if !slices.Equal(got, want) {
+		t.Errorf("items = %q\nwant    %q", got, want)
+	}
+}
+
+func TestPasteWhileTyping(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{items: []model.Item{{Title: "inbox"}, {Title: "later"}}}
+	m.recalcVisible()
+	next, _ := m.updateMain(keyMsg("m"))
+	m = next.(app)
+	m.inputBuf = "groceries: "
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("milk\n- eggs\n  - free range"), Paste: true})
+	m = next.(app)
+	if m.inputMode {
+		t.Fatal("the paste did not finish the task being typed")
+	}
+	if got := m.shownTitles(); !slices.Equal(got, []string{"inbox", "groceries: milk", "eggs", "free range", "later"}) {
+		t.Errorf("titles = %q", got)
+	}
+	if m.items[2].Level != 1 || m.items[3].Level != 2 {
+		t.Errorf("levels = %d, %d", m.items[2].Level, m.items[3].Level)
+	}
+}
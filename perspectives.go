@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- PERSPECTIVES ---
+//
+// A perspective is a named way of looking at the list: a filter, a sort
+// order and how far the tree is unfolded, kept in config.json:
+//
+//	"perspectives": [
+//	  {"name": "Work today", "filter": "#work and due<=0d", "sort": "due", "fold": "1"},
+//	  {"name": "Errands", "filter": "#errand and not done", "fold": "none"}
+//	]
+//
+// alt+1 … alt+9 switch to the first nine (the plain digits are counts),
+// ":view Errands" to one by name, and alt+0 or ":view" alone go back to the
+// whole list. The active one is named in the header. filter takes the
+// :filter language and sort a :sort key, which reorders the file as :sort
+// does. fold "all" folds every parent, "none" unfolds them all and a number
+// keeps that many levels open; without it the folds stay as they are.
+// Changing the filter by hand leaves the perspective.
+
+type Perspective struct {
+	Name   string `json:"name"`
+	Filter string `json:"filter,omitempty"`
+	Sort   string `json:"sort,omitempty"`
+	Fold   string `json:"fold,omitempty"`
+}
+
+// foldDepth reads fold as the number of levels left open, -1 for all of
+// them; ok is false when fold is empty or invalid.
+func foldDepth(fold string) (depth int, ok bool) {
+	switch fold {
+	case "all":
+		return 0, true
+	case "none":
+		return -1, true
+	}
+	n, err := strconv.Atoi(fold)
+	return n, err == nil && n >= 0
+}
+
+// check reports what in p can't be applied.
+func (p Perspective) check() error {
+	if _, err := parseQuery(p.Filter); err != nil && p.Filter != "" {
+		return fmt.Errorf("bad filter: %w", err)
+	}
+	if _, ok := sortKeys[p.Sort]; !ok && p.Sort != "" {
+		return fmt.Errorf("unknown sort key %q", p.Sort)
+	}
+	if _, ok := foldDepth(p.Fold); !ok && p.Fold != "" {
+		return fmt.Errorf("fold must be all, none or a number, not %q", p.Fold)
+	}
+	return nil
+}
+
+// foldTo folds every parent at depth or deeper and unfolds the rest.
+func foldTo(items []model.Item, depth int) {
+	for i := range items {
+		items[i].Collapsed = depth >= 0 && items[i].Level >= depth && model.HasChildren(items, i)
+	}
+}
+
+// applyPerspective switches to p.
+func (m *app) applyPerspective(p Perspective) {
+	if err := p.check(); err != nil {
+		m.status = "Perspective " + p.Name + ": " + err.Error()
+		return
+	}
+	if depth, ok := foldDepth(p.Fold); ok {
+		foldTo(m.items, depth)
+	}
+	if p.Sort != "" {
+		m.sortItems(p.Sort)
+		m.sortKey = p.Sort
+		m.save()
+	}
+	m.setFilter(p.Filter)
+	m.cursorMain = 0
+	m.recalcVisible()
+	m.perspective = p.Name
+	m.status = fmt.Sprintf("%s: %d items shown", p.Name, len(m.visibleItems))
+}
+
+// leavePerspective goes back to the whole list.
+func (m *app) leavePerspective() {
+	if m.perspective == "" && m.filter == nil {
+		return
+	}
+	m.setFilter("")
+	m.perspective = ""
+	m.status = "Whole list"
+}
+
+// perspectiveKey handles alt+0 … alt+9.
+func (m *app) perspectiveKey(key string) bool {
+	digit, ok := strings.CutPrefix(key, "alt+")
+	if !ok || len(digit) != 1 || digit[0] < '0' || digit[0] > '9' {
+		return false
+	}
+	n := int(digit[0] - '0')
+	switch {
+	case n == 0:
+		m.leavePerspective()
+	case n > len(m.config.Perspectives):
+		m.status = fmt.Sprintf("No perspective %d — add them to \"perspectives\" in config.json", n)
+	default:
+		m.applyPerspective(m.config.Perspectives[n-1])
+	}
+	return true
+}
+
+// viewCommand is ":view [name]".
+func (m *app) viewCommand(name string) {
+	if name == "" {
+		m.leavePerspective()
+		return
+	}
+	i := slices.IndexFunc(m.config.Perspectives, func(p Perspective) bool { return strings.EqualFold(p.Name, name) })
+	if i == -1 {
+		m.status = "No perspective " + name
+		return
+	}
+	m.applyPerspective(m.config.Perspectives[i])
+}
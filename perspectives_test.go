@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pawello85/todo/internal/model"
+)
+
+func altKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}, Alt: true}
+}
+
+func TestPerspectives(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{width: 100, height: 20, demo: true, items: []model.Item{
+		{Title: "Work project #work due:2030-01-05"},
+		{Title: "spec #work", Level: 1},
+		{Title: "Errands", Collapsed: true},
+		{Title: "milk #errand", Level: 1},
+		{Title: "Aardvark #work due:2030-01-01"},
+	}}
+	m.config.Perspectives = []Perspective{
+		{Name: "Work", Filter: "#work", Sort: "due", Fold: "all"},
+		{Name: "Errands", Filter: "#errand", Fold: "none"},
+		{Name: "Broken", Sort: "colour"},
+	}
+	m.recalcVisible()
+	press := func(msg tea.KeyMsg) {
+		t.Helper()
+		next, _ := m.Update(msg)
+		m = next.(app)
+	}
+
+	press(altKey('1'))
+	if m.perspective != "Work" || m.filterText != "#work" || m.sortKey != "due" {
+		t.Fatalf("alt+1: perspective %q, filter %q, sort %q", m.perspective, m.filterText, m.sortKey)
+	}
+	if got := flat(m.items); !strings.Contains(got[0], "Aardvark") {
+		t.Errorf("not sorted by due: %q", got)
+	}
+	if !m.items[1].Collapsed || !m.items[3].Collapsed {
+		t.Error("fold all must fold the parents")
+	}
+	if !strings.Contains(m.View(), "◆ Work") {
+		t.Error("the header must name the perspective")
+	}
+
+	m.runCommand("view errands")
+	if m.perspective != "Errands" || m.items[3].Collapsed || len(m.visibleItems) != 2 {
+		t.Fatalf(":view errands: %q, visible %+v", m.perspective, m.visibleItems)
+	}
+
+	press(altKey('3'))
+	if m.perspective != "Errands" || !strings.Contains(m.status, "colour") {
+		t.Errorf("a broken perspective must be refused: %q", m.status)
+	}
+	press(altKey('7'))
+	if !strings.Contains(m.status, "No perspective 7") {
+		t.Errorf("status = %q", m.status)
+	}
+
+	m.runCommand("filter Aardvark")
+	if m.perspective != "" {
+		t.Error("a hand-made filter leaves the perspective")
+	}
+	press(altKey('2'))
+	press(altKey('0'))
+	if m.perspective != "" || m.filter != nil {
+		t.Error("alt+0 must show the whole list")
+	}
+
+	// Cyfry bez alt to nadal licznik
+	press(keyMsg("2"))
+	if m.pendingCount != 2 {
+		t.Errorf("pendingCount = %d", m.pendingCount)
+	}
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- CAPACITY PLANNING ---
+//
+// :plan spreads open undated tasks (the subtree under the cursor, or the
+// tasks matching ":plan <filter>") over the workdays of the coming week.
+// Each task takes its est:1h30m estimate (30 minutes without one) out of
+// daily_capacity, on top of what is already due that day; higher priorities
+// are placed first, each on the earliest day it fits. The proposal is shown
+// for review and only written on Enter.
+
+const (
+	defaultEstimate = 30 * time.Minute
+	defaultCapacity = 6 * time.Hour
+	planHorizon     = 7 // days
+)
+
+type planEntry struct {
+	idx  int
+	day  time.Time
+	est  time.Duration
+	skip bool
+}
+
+// taskEstimate reads est:<duration> ("45m", "2h", "1h30m") or a "~45m" word
+// (see estimate.go).
+func taskEstimate(title string) (time.Duration, bool) {
+	d, err := time.ParseDuration(model.MetaValue(title, "est"))
+	if err == nil && d > 0 {
+		return d, true
+	}
+	for _, w := range strings.Fields(title) {
+		if d, ok := estimateWord(w); ok {
+			return d, true
+		}
+	}
+	return defaultEstimate, false
+}
+
+func (c Config) dailyCapacity() time.Duration {
+	d, err := time.ParseDuration(c.DailyCapacity)
+	if err != nil || d <= 0 {
+		return defaultCapacity
+	}
+	return d
+}
+
+// planDays are the workdays among the next planHorizon days, starting today.
+func planDays(cal model.Calendar, now time.Time) []time.Time {
+	var days []time.Time
+	day := model.StartOfDay(now)
+	for n := 0; n < planHorizon; n++ {
+		if cal.IsWorkday(day) {
+			days = append(days, day)
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return days
+}
+
+// dueLoad sums the estimates of open tasks due on each day.
+func dueLoad(items []model.Item) map[time.Time]time.Duration {
+	load := make(map[time.Time]time.Duration)
+	for _, it := range items {
+		if due, _, ok := model.DueTime(it.Title); ok && !it.Done {
+			est, _ := taskEstimate(it.Title)
+			load[model.StartOfDay(due)] += est
+		}
+	}
+	return load
+}
+
+// planCandidates returns the open, undated tasks without children among
+// the given items; parents are planned through their subtasks.
+func planCandidates(items []model.Item, pick []bool) []int {
+	var out []int
+	for i, it := range items {
+		if !pick[i] || it.Done || model.MetaValue(it.Title, "due") != "" || model.HasChildren(items, i) {
+			continue
+		}
+		out = append(out, i)
+	}
+	sort.SliceStable(out, func(a, b int) bool {
+		pa, pb := model.MetaValue(items[out[a]].Title, "pri"), model.MetaValue(items[out[b]].Title, "pri")
+		if (pa == "") != (pb == "") {
+			return pa != ""
+		}
+		return pa < pb
+	})
+	return out
+}
+
+// planTasks puts each candidate on the earliest day with room left. Tasks
+// that fit nowhere are returned separately.
+func planTasks(items []model.Item, candidates []int, days []time.Time, capacity time.Duration) (plan []planEntry, unfit []int) {
+	load := dueLoad(items)
+	for _, idx := range candidates {
+		est, _ := taskEstimate(items[idx].Title)
+		placed := false
+		for _, day := range days {
+			if load[day]+est <= capacity {
+				load[day] += est
+				plan = append(plan, planEntry{idx: idx, day: day, est: est})
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			unfit = append(unfit, idx)
+		}
+	}
+	sort.SliceStable(plan, func(a, b int) bool { return plan[a].day.Before(plan[b].day) })
+	return plan, unfit
+}
+
+func (m *app) openPlan(arg string) {
+	pick := make([]bool, len(m.items))
+	if arg != "" {
+		q, err := parseQuery(arg)
+		if err != nil {
+			m.status = "Bad filter: " + err.Error()
+			return
+		}
+		pick, _ = queryMatches(m.items, q, time.Now())
+	} else if len(m.visibleItems) > 0 {
+		idx := m.visibleItems[m.cursorMain].Index
+		for i := idx; i < model.SubtreeEnd(m.items, idx); i++ {
+			pick[i] = true
+		}
+	}
+	candidates := planCandidates(m.items, pick)
+	if len(candidates) == 0 {
+		m.status = "No open undated tasks to plan"
+		return
+	}
+	m.plan, m.planUnfit = planTasks(m.items, candidates, planDays(m.config.calendar(), time.Now()), m.config.dailyCapacity())
+	m.cursorPlan = 0
+	m.state = viewPlan
+}
+
+func (m app) updatePlan(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.plan, m.planUnfit = nil, nil
+		m.state = viewMain
+	case "up", "k":
+		if m.cursorPlan > 0 {
+			m.cursorPlan--
+		}
+	case "down", "j":
+		if m.cursorPlan < len(m.plan)-1 {
+			m.cursorPlan++
+		}
+	case " ":
+		if len(m.plan) > 0 {
+			m.plan[m.cursorPlan].skip = !m.plan[m.cursorPlan].skip
+		}
+	case "enter":
+		n := 0
+		for _, e := range m.plan {
+			if e.skip {
+				continue
+			}
+			m.items[e.idx].Title = model.SetMeta(m.items[e.idx].Title, "due", model.FormatDue(e.day, false))
+			m.refreshItem(e.idx)
+			n++
+		}
+		m.plan, m.planUnfit = nil, nil
+		m.state = viewMain
+		if n > 0 {
+			m.save()
+		}
+		m.status = fmt.Sprintf("Planned %d tasks", n)
+	}
+	return m, nil
+}
+
+// formatEstimate prints durations the way est: takes them ("1h30m", "45m").
+func formatEstimate(d time.Duration) string {
+	h, mins := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", mins)
+	case mins == 0:
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dh%dm", h, mins)
+}
+
+func (m app) renderPlan(height int, t theme.Theme) string {
+	capacity := m.config.dailyCapacity()
+	load := dueLoad(m.items)
+	for _, e := range m.plan {
+		if !e.skip {
+			load[e.day] += e.est
+		}
+	}
+	today := model.StartOfDay(time.Now())
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+
+	var lines []string
+	cursorLine := 0
+	for i, e := range m.plan {
+		if i == 0 || !e.day.Equal(m.plan[i-1].day) {
+			barW := 20
+			filled := min(barW, int(float64(barW)*float64(load[e.day])/float64(capacity)))
+			lines = append(lines, lipgloss.NewStyle().Foreground(t.Accent).Bold(true).Render(agendaDayLabel(e.day, today))+"  "+
+				lipgloss.NewStyle().Foreground(t.Special).Render(strings.Repeat("█", filled))+
+				dim.Render(strings.Repeat("░", barW-filled)+" "+formatEstimate(load[e.day])+" / "+formatEstimate(capacity)))
+		}
+		marker := "  "
+		titleStyle := lipgloss.NewStyle().Foreground(t.Text)
+		if e.skip {
+			titleStyle = dim.Strikethrough(true)
+		}
+		if i == m.cursorPlan {
+			marker = " ➤"
+			titleStyle = titleStyle.Foreground(t.Highlight).Bold(true)
+			cursorLine = len(lines)
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(t.Highlight).Render(marker)+" "+
+			dim.Render(fmt.Sprintf("%5s", formatEstimate(e.est)))+" "+
+			titleStyle.Render(model.DisplayTitle(m.items[e.idx].Title)))
+	}
+	if len(m.plan) == 0 {
+		lines = append(lines, dim.Render("  (Nothing fits into the coming week)"))
+	}
+	if len(m.planUnfit) > 0 {
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(t.Error).Bold(true).Render("Doesn't fit"))
+		for _, idx := range m.planUnfit {
+			est, _ := taskEstimate(m.items[idx].Title)
+			lines = append(lines, "   "+dim.Render(fmt.Sprintf("%5s", formatEstimate(est)))+" "+
+				dim.Render(model.DisplayTitle(m.items[idx].Title)))
+		}
+	}
+
+	start, end := ui.Paginator(cursorLine, height, len(lines))
+	return m.frame(height, t.Highlight).
+		Render(strings.Join(lines[start:end], "\n"))
+}
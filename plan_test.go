@@ -0,0 +1,63 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestPlanTasks(t *testing.T) {
+	mon := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	tue, wed := mon.AddDate(0, 0, 1), mon.AddDate(0, 0, 2)
+	items := []model.Item{
+		{Title: "meeting est:3h due:2026-10-19"},
+		{Title: "report est:2h"},
+		{Title: "urgent est:4h pri:A"},
+		{Title: "call"},
+		{Title: "offsite est:8h"},
+	}
+	plan, unfit := planTasks(items, planCandidates(items, []bool{true, true, true, true, true}),
+		[]time.Time{mon, tue, wed}, 6*time.Hour)
+
+	got := map[int]time.Time{}
+	for _, e := range plan {
+		got[e.idx] = e.day
+	}
+	// urgent goes first and doesn't fit next to the meeting; report does
+	want := map[int]time.Time{2: tue, 1: mon, 3: mon}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("plan = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(unfit, []int{4}) {
+		t.Errorf("unfit = %v", unfit)
+	}
+	for i := 1; i < len(plan); i++ {
+		if plan[i].day.Before(plan[i-1].day) {
+			t.Error("plan not ordered by day")
+		}
+	}
+}
+
+func TestPlanDaysSkipHolidays(t *testing.T) {
+	fri := time.Date(2026, 12, 25, 15, 0, 0, 0, time.Local)
+	days := planDays(model.Calendar{Holidays: []string{"12-25"}}, fri)
+	if len(days) != 4 || days[0].Weekday() != time.Monday {
+		t.Errorf("days = %v", days)
+	}
+}
+
+func TestEstimates(t *testing.T) {
+	if d, ok := taskEstimate("x est:1h30m"); !ok || d != 90*time.Minute {
+		t.Errorf("est = %v, %v", d, ok)
+	}
+	if d, ok := taskEstimate("x est:soon"); ok || d != defaultEstimate {
+		t.Errorf("bad est = %v, %v", d, ok)
+	}
+	for d, want := range map[time.Duration]string{45 * time.Minute: "45m", 2 * time.Hour: "2h", 90 * time.Minute: "1h30m"} {
+		if got := formatEstimate(d); got != want {
+			t.Errorf("formatEstimate(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- PLUGINS ---
+//
+// Executables in the plugins folder of the config dir add commands to the
+// ":" line. They are asked once per session, the first time ":" is opened:
+//
+//	plugin describe  →  {"commands": [{"name": "jira", "description": "Send to Jira"}]}
+//
+// ":jira PROJ" then runs `plugin run jira` with the request on stdin,
+//
+//	{"command": "jira", "args": "PROJ", "file": "/abs/todo.md",
+//	 "task": {"id", "title", "text", "done", "level", "note"}}
+//
+// (title is the raw markdown title with its tokens, text what is shown; no
+// task on an empty list) and reads the answer from stdout:
+//
+//	{"title": "…", "note": ["…"], "subtasks": ["…"], "status": "Sent as PROJ-12"}
+//
+// Every field is optional: title and note replace the task's, subtasks are
+// added under it, status goes to the footer, and {"error": "…"} or a non-zero
+// exit reports a failure. Built-in commands win over plugin ones.
+
+const (
+	pluginDir          = "plugins"
+	pluginDescribeTime = 5 * time.Second
+	pluginRunTime      = 30 * time.Second
+)
+
+type pluginCommand struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	path        string
+}
+
+type pluginTask struct {
+	ID    string   `json:"id,omitempty"`
+	Title string   `json:"title"`
+	Text  string   `json:"text"`
+	Done  bool     `json:"done"`
+	Level int      `json:"level"`
+	Note  []string `json:"note,omitempty"`
+}
+
+type pluginRequest struct {
+	Command string      `json:"command"`
+	Args    string      `json:"args"`
+	File    string      `json:"file"`
+	Task    *pluginTask `json:"task,omitempty"`
+}
+
+type pluginResponse struct {
+	Title    *string   `json:"title"`
+	Note     *[]string `json:"note"`
+	Subtasks []string  `json:"subtasks"`
+	Status   string    `json:"status"`
+	Error    string    `json:"error"`
+}
+
+type pluginDoneMsg struct {
+	command string
+	title   string // the task's title when the plugin started, to find it again
+	idx     int
+	resp    pluginResponse
+	err     error
+}
+
+func pluginsPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, appName, pluginDir), nil
+}
+
+// pluginOutput runs a plugin and returns its stdout, with the first line of
+// stderr in the error when it fails.
+func pluginOutput(timeout time.Duration, stdin []byte, path string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if line, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); err != nil && line != "" {
+		err = fmt.Errorf("%w: %s", err, line)
+	}
+	return out, err
+}
+
+// discoverPlugins asks every executable in dir for its commands, sorted by
+// name; the first plugin to claim a name keeps it.
+func discoverPlugins(dir string) ([]pluginCommand, []error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, []error{err}
+	}
+	var cmds []pluginCommand
+	var errs []error
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() || runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		out, err := pluginOutput(pluginDescribeTime, nil, path, "describe")
+		var desc struct {
+			Commands []pluginCommand `json:"commands"`
+		}
+		if err == nil {
+			err = json.Unmarshal(out, &desc)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.Name(), err))
+			continue
+		}
+		for _, c := range desc.Commands {
+			if c.Name != "" && !slices.ContainsFunc(cmds, func(o pluginCommand) bool { return o.Name == c.Name }) {
+				c.path = path
+				cmds = append(cmds, c)
+			}
+		}
+	}
+	slices.SortFunc(cmds, func(a, b pluginCommand) int { return strings.Compare(a.Name, b.Name) })
+	return cmds, errs
+}
+
+// loadPlugins discovers the plugins once per session.
+func (m *app) loadPlugins() {
+	if m.plugins != nil {
+		return
+	}
+	m.plugins = []pluginCommand{}
+	dir, err := pluginsPath()
+	if err != nil {
+		return
+	}
+	cmds, errs := discoverPlugins(dir)
+	m.plugins = cmds
+	if len(errs) > 0 {
+		m.status = "Plugin ignored: " + errs[0].Error()
+	}
+}
+
+func (m app) plugin(name string) (pluginCommand, bool) {
+	i := slices.IndexFunc(m.plugins, func(c pluginCommand) bool { return c.Name == name })
+	if i == -1 {
+		return pluginCommand{}, false
+	}
+	return m.plugins[i], true
+}
+
+// pluginList is the :plugins answer.
+func (m app) pluginList() string {
+	if len(m.plugins) == 0 {
+		dir, _ := pluginsPath()
+		return "No plugins in " + abbreviateHome(dir)
+	}
+	var names []string
+	for _, c := range m.plugins {
+		names = append(names, c.Name)
+	}
+	return "Plugins: " + strings.Join(names, ", ")
+}
+
+// pluginHint lists the plugin commands starting with what is typed on the
+// ":" line.
+func (m app) pluginHint(line string) string {
+	name, _, hasArgs := strings.Cut(line, " ")
+	if name == "" || hasArgs {
+		return ""
+	}
+	var hints []string
+	for _, c := range m.plugins {
+		if strings.HasPrefix(c.Name, name) {
+			hints = append(hints, strings.TrimSpace(c.Name+" – "+c.Description))
+		}
+	}
+	return strings.Join(hints, " • ")
+}
+
+// runPlugin starts a plugin command on the task under the cursor.
+func (m *app) runPlugin(c pluginCommand, args string) tea.Cmd {
+	req := pluginRequest{Command: c.Name, Args: args, File: m.filename}
+	if abs, err := filepath.Abs(m.filename); err == nil {
+		req.File = abs
+	}
+	idx := -1
+	if len(m.visibleItems) > 0 {
+		idx = m.visibleItems[m.cursorMain].Index
+		it := m.items[idx]
+		req.Task = &pluginTask{model.ID(it), it.Title, model.DisplayTitle(it.Title), it.Done, it.Level, it.Note}
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		m.status = err.Error()
+		return nil
+	}
+	m.status = "Running " + c.Name + "…"
+	done := pluginDoneMsg{command: c.Name, idx: idx}
+	if req.Task != nil {
+		done.title = req.Task.Title
+	}
+	return func() tea.Msg {
+		out, err := pluginOutput(pluginRunTime, data, c.path, "run", c.Name)
+		if err == nil {
+			err = json.Unmarshal(out, &done.resp)
+		}
+		if err == nil && done.resp.Error != "" {
+			err = errors.New(done.resp.Error)
+		}
+		done.err = err
+		return done
+	}
+}
+
+// applyPlugin makes the changes a plugin answered with.
+func (m *app) applyPlugin(msg pluginDoneMsg) {
+	if msg.err != nil {
+		m.status = msg.command + " failed: " + msg.err.Error()
+		return
+	}
+	m.status = msg.resp.Status
+	r := msg.resp
+	if r.Title == nil && r.Note == nil && len(r.Subtasks) == 0 {
+		return
+	}
+	idx := msg.idx
+	if idx < 0 || idx >= len(m.items) || m.items[idx].Title != msg.title {
+		// Lista zmieniła się w międzyczasie – szukamy zadania po tytule
+		idx = slices.IndexFunc(m.items, func(it model.Item) bool { return it.Title == msg.title })
+	}
+	if idx == -1 {
+		m.status = msg.command + ": the task changed meanwhile, answer dropped"
+		return
+	}
+	if r.Title != nil && strings.TrimSpace(*r.Title) != "" {
+		m.items[idx].Title = strings.TrimSpace(*r.Title)
+	}
+	if r.Note != nil {
+		m.items[idx].Note = *r.Note
+	}
+	at := model.SubtreeEnd(m.items, idx)
+	today := time.Now().Format(model.DateLayout)
+	for _, title := range r.Subtasks {
+		if title = strings.TrimSpace(title); title == "" {
+			continue
+		}
+		m.items = slices.Insert(m.items, at, model.Item{Title: model.SetMeta(title, "created", today), Level: m.items[idx].Level + 1})
+		m.fireHook("add", at)
+		at++
+	}
+	m.recalcVisible()
+	m.save()
+}
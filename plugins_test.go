@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+const testPlugin = `#!/bin/sh
+case "$1" in
+describe) echo '{"commands": [{"name": "shout", "description": "Shout the title"}, {"name": "fail"}]}' ;;
+run)
+	cat > "$0.request"
+	if [ "$2" = fail ]; then echo "no network" >&2; exit 1; fi
+	echo '{"title": "WRITE THE REPORT id:r1", "subtasks": ["outline", " "], "status": "Shouted"}' ;;
+esac
+`
+
+func TestPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test plugin is a shell script")
+	}
+	config := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", config)
+	dir := filepath.Join(config, appName, pluginDir)
+	os.MkdirAll(dir, 0755)
+	script := filepath.Join(dir, "shouter")
+	os.WriteFile(script, []byte(testPlugin), 0755)
+	os.WriteFile(filepath.Join(dir, "README"), []byte("not a plugin"), 0644)
+
+	m := app{filename: "todo.md", items: []model.Item{{Title: "write the report id:r1", Note: []string{"by Friday"}}, {Title: "other"}}}
+	m.recalcVisible()
+	next, _ := m.Update(keyMsg(":"))
+	m = next.(app)
+	if len(m.plugins) != 2 || m.plugins[0].Name != "fail" || m.plugins[1].Name != "shout" {
+		t.Fatalf("plugins = %+v", m.plugins)
+	}
+	m.cmdBuf = "sh"
+	if hint := m.pluginHint(m.cmdBuf); hint != "shout – Shout the title" {
+		t.Errorf("hint = %q", hint)
+	}
+	m.cmdMode, m.cmdBuf = false, ""
+
+	cmd := m.runCommand("shout loudly")
+	if cmd == nil {
+		t.Fatal("a plugin command must run")
+	}
+	next, _ = m.Update(cmd())
+	m = next.(app)
+	var req pluginRequest
+	data, _ := os.ReadFile(script + ".request")
+	if err := json.Unmarshal(data, &req); err != nil || req.Args != "loudly" || req.Task == nil || req.Task.Title != "write the report id:r1" || req.Task.Text != "write the report" || req.Task.Note[0] != "by Friday" {
+		t.Errorf("request = %s (%v)", data, err)
+	}
+	got := flat(m.items)
+	if strings.Join(got, "|") != "0:WRITE THE REPORT id:r1:false|1:outline created:"+strings.Fields(m.items[1].Title)[1][len("created:"):]+":false|0:other:false" || m.status != "Shouted" {
+		t.Errorf("items = %q, status %q", got, m.status)
+	}
+
+	next, _ = m.Update(m.runCommand("fail")())
+	if m = next.(app); m.status != "fail failed: exit status 1: no network" {
+		t.Errorf("status = %q", m.status)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- POMODORO ---
+//
+// Completed pomodoros are counted in a hidden pomo:N token on the task.
+
+type pomoTickMsg struct{}
+
+func pomoTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return pomoTickMsg{}
+	})
+}
+
+func (c Config) pomodoroLength() time.Duration {
+	if c.PomodoroMinutes > 0 {
+		return time.Duration(c.PomodoroMinutes) * time.Minute
+	}
+	return 25 * time.Minute
+}
+
+func pomoCount(it model.Item) int {
+	n, _ := strconv.Atoi(model.MetaValue(it.Title, "pomo"))
+	return n
+}
+
+// togglePomodoro starts a pomodoro on the selected task, or cancels the running one.
+func (m *app) togglePomodoro() tea.Cmd {
+	if m.pomoID != "" {
+		m.pomoID = ""
+		m.status = "Pomodoro cancelled"
+		return nil
+	}
+	if len(m.visibleItems) == 0 {
+		return nil
+	}
+	realIdx := m.visibleItems[m.cursorMain].Index
+	if model.ID(m.items[realIdx]) == "" {
+		// Zadanie potrzebuje stałego id, bo lista może się zmienić w trakcie
+		m.items[realIdx].Title = model.SetMeta(m.items[realIdx].Title, "id", model.NewID())
+		m.refreshItem(realIdx)
+		m.save()
+	}
+	m.pomoID = model.ID(m.items[realIdx])
+	m.pomoEnd = time.Now().Add(m.config.pomodoroLength())
+	return pomoTick()
+}
+
+func (m *app) handlePomoTick() tea.Cmd {
+	if m.pomoID == "" {
+		return nil
+	}
+	if time.Now().Before(m.pomoEnd) {
+		return pomoTick()
+	}
+
+	id := m.pomoID
+	m.pomoID = ""
+	idx := model.FindByID(m.items, id)
+	if idx == -1 {
+		return nil
+	}
+	m.items[idx].Title = model.SetMeta(m.items[idx].Title, "pomo", strconv.Itoa(pomoCount(m.items[idx])+1))
+	m.refreshItem(idx)
+	m.save()
+
+	n := notification{title: "Pomodoro done", body: model.DisplayTitle(m.items[idx].Title)}
+	m.status = "🍅 " + n.title + ": " + n.body
+	method := m.config.Notify
+	return func() tea.Msg {
+		notify(method, n)
+		return nil
+	}
+}
+
+// pomodoroHeader is the header segment for the running timer.
+func (m app) pomodoroHeader() string {
+	if m.pomoID == "" {
+		return ""
+	}
+	left := time.Until(m.pomoEnd).Round(time.Second)
+	if left < 0 {
+		left = 0
+	}
+	return fmt.Sprintf(" 🍅 %02d:%02d", int(left.Minutes()), int(left.Seconds())%60)
+}
+
+// --- STATS VIEW ---
+
+type pomoStat struct {
+	title string
+	count int
+}
+
+func pomodoroStats(items []model.Item) []pomoStat {
+	var stats []pomoStat
+	for _, it := range items {
+		if n := pomoCount(it); n > 0 {
+			stats = append(stats, pomoStat{title: model.DisplayTitle(it.Title), count: n})
+		}
+	}
+	sort.SliceStable(stats, func(i, j int) bool { return stats[i].count > stats[j].count })
+	return stats
+}
+
+func (m app) updateStats(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.state = viewMain
+	case "w":
+		m.statsRange = presetRange("week", time.Now())
+	case "m":
+		m.statsRange = presetRange("month", time.Now())
+	}
+	return m, nil
+}
+
+func (m app) renderStats(height int, t theme.Theme) string {
+	stats := pomodoroStats(m.items)
+	countStyle := lipgloss.NewStyle().Foreground(t.Highlight).Width(6).Align(lipgloss.Right)
+	textStyle := lipgloss.NewStyle().Foreground(t.Text)
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+
+	total := 0
+	for _, st := range stats {
+		total += st.count
+	}
+
+	lines := append(m.renderBurndown(m.width-4, t), "")
+	length := m.config.pomodoroLength()
+	lines = append(lines, "  "+lipgloss.NewStyle().Foreground(t.Accent).Bold(true).Render("Pomodoros")+" "+
+		dim.Render(fmt.Sprintf("%d total · %s focused", total, model.FormatDuration(time.Duration(total)*length))), "")
+
+	if len(stats) == 0 {
+		lines = append(lines, dim.Render("  (No pomodoros yet — press P on a task)"))
+	}
+	for _, st := range stats {
+		lines = append(lines, countStyle.Render(strconv.Itoa(st.count))+"  "+textStyle.Render(st.title))
+	}
+
+	return m.frame(height, t.Highlight).
+		Render(strings.Join(lines[:min(len(lines), max(0, height))], "\n"))
+}
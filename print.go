@@ -0,0 +1,151 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+	"github.com/muesli/termenv"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+)
+
+// --- PRINT (todo print) ---
+//
+// `todo print [--filter Q] [--tree|--flat] [file.md]` writes the list to
+// stdout the way the TUI draws it, without starting it: as a tree with its
+// guides, unfolded, or with --flat one line per task with the path of its
+// parents in front. --filter takes the :filter language (or @name of a saved
+// filter). Colors follow the terminal: none when stdout is not one or
+// NO_COLOR is set, unless --color=always asks for them; "colors" in
+// config.json picks the palette as in the TUI. In a terminal long titles
+// wrap to its width, in a pipe each stays on one line.
+
+// pipeWidth is the width lists are drawn at when stdout isn't a terminal:
+// wide enough for no title to wrap.
+const pipeWidth = 4096
+
+// printProfile picks the colors for --color=mode. colors is the setting
+// from config.json and tty whether stdout is a terminal.
+func printProfile(mode, colors string, tty bool) (termenv.Profile, error) {
+	switch mode {
+	case "never":
+		return termenv.Ascii, nil
+	case "", "auto":
+		if !tty || termenv.EnvNoColor() {
+			return termenv.Ascii, nil
+		}
+	case "always":
+	default:
+		return termenv.Ascii, fmt.Errorf("unknown --color %q (want auto, always or never)", mode)
+	}
+	if colors != "" && colors != "auto" {
+		return colorProfile(colors)
+	}
+	// Sam terminal mógł nie zostać wykryty (pipe), więc pytamy tylko o TERM i COLORTERM
+	profile := termenv.NewOutput(os.Stdout, termenv.WithTTY(true)).ColorProfile()
+	if profile == termenv.Ascii && mode == "always" {
+		profile = termenv.ANSI
+	}
+	return profile, nil
+}
+
+// printModel loads filename into an app drawing what `todo print` shows.
+func printModel(filename string, cfg Config, filter string, flat bool, width int) (app, error) {
+	// Load bierze brak pliku za pustą listę, a tu to raczej literówka
+	if _, err := os.Stat(filename); err != nil {
+		return app{}, err
+	}
+	items, _, err := storage.Load(filename)
+	if err != nil {
+		return app{}, err
+	}
+	loadThemes()
+	t, _ := themeByName(cfg.SelectedTheme)
+	m := app{filename: filename, items: items, config: cfg, activeTheme: t, width: width - 2}
+	foldTo(m.items, -1)
+	if filter != "" {
+		m.setFilter(filter)
+		if m.filter == nil {
+			return app{}, fmt.Errorf("%s", strings.TrimPrefix(m.status, "Bad filter: "))
+		}
+	}
+	if flat {
+		m.items = flatTasks(m.items, m.filter, time.Now())
+		m.filter, m.filterText = nil, ""
+		m.config.Truncate = true
+	}
+	m.recalcVisible()
+	return m, nil
+}
+
+// flatTasks lists the tasks matching q (all of them when q is nil) on one
+// level, each title led by the titles of its parents.
+func flatTasks(items []model.Item, q query, now time.Time) []model.Item {
+	match := make([]bool, len(items))
+	if q != nil {
+		match, _ = queryMatches(items, q, now)
+	}
+	var out []model.Item
+	for i, it := range items {
+		if it.Heading > 0 || (q != nil && !match[i]) {
+			continue
+		}
+		path := ancestorTitles(items, i)
+		it.Title = strings.Join(append(path[:len(path)-1:len(path)-1], it.Title), " › ")
+		it.Level, it.Collapsed, it.Note = 0, false, nil
+		out = append(out, it)
+	}
+	return out
+}
+
+func runPrint(args []string) {
+	fs := flag.NewFlagSet("print", flag.ExitOnError)
+	filter := fs.String("filter", "", "show only the tasks matching a :filter query")
+	tree := fs.Bool("tree", false, "draw the tree with its guides (the default)")
+	flat := fs.Bool("flat", false, "one line per task, with its parents in front")
+	color := fs.String("color", "auto", "auto, always or never")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: todo print [--filter QUERY] [--tree|--flat] [--color WHEN] [file.md]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *tree && *flat {
+		fmt.Fprintln(os.Stderr, "Error: --tree and --flat can't be used together")
+		os.Exit(2)
+	}
+	filename := defaultFile()
+	if fs.NArg() > 0 {
+		filename = fs.Arg(0)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	tty := term.IsTerminal(os.Stdout.Fd())
+	profile, err := printProfile(*color, cfg.Colors, tty)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	lipgloss.SetColorProfile(profile)
+	width := pipeWidth
+	if w, _, err := term.GetSize(os.Stdout.Fd()); tty && err == nil && w > 0 {
+		width = w
+	}
+
+	m, err := printModel(filename, cfg, *filter, *flat, width)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(m.visibleItems) > 0 {
+		fmt.Println(m.finalList(shownTheme(m.activeTheme)))
+	}
+}
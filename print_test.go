@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+func TestPrintProfile(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("COLORTERM", "")
+	for _, c := range []struct {
+		mode, colors string
+		tty          bool
+		want         termenv.Profile
+	}{
+		{"auto", "", false, termenv.Ascii},
+		{"auto", "", true, termenv.ANSI256},
+		{"auto", "16", true, termenv.ANSI},
+		{"never", "truecolor", true, termenv.Ascii},
+		{"always", "", false, termenv.ANSI256},
+	} {
+		if got, err := printProfile(c.mode, c.colors, c.tty); err != nil || got != c.want {
+			t.Errorf("printProfile(%q, %q, %v) = %v, %v; want %v", c.mode, c.colors, c.tty, got, err, c.want)
+		}
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if got, _ := printProfile("auto", "", true); got != termenv.Ascii {
+		t.Errorf("NO_COLOR gave %v", got)
+	}
+	if got, _ := printProfile("always", "", true); got == termenv.Ascii {
+		t.Error("--color=always must win over NO_COLOR")
+	}
+	if _, err := printProfile("sometimes", "", true); err == nil {
+		t.Error("an unknown --color must be refused")
+	}
+}
+
+func TestPrint(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.Ascii)
+	f := filepath.Join(t.TempDir(), "todo.md")
+	os.WriteFile(f, []byte("# Work\n- [ ] report #work\n  - [x] draft\n  - [ ] a title long enough to wrap in a narrow terminal\n- [ ] mail\n"), 0644)
+
+	m, err := printModel(f, Config{}, "", false, pipeWidth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := m.finalList(shownTheme(m.activeTheme))
+	if strings.Contains(got, "\x1b") {
+		t.Errorf("colors without a terminal:\n%q", got)
+	}
+	want := []string{"▾ Work", "├─[ ] report #work", "│  ├─[✔] draft", "│  └─[ ] a title long enough", "└─[ ] mail"}
+	lines := strings.Split(got, "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("tree:\n%s", got)
+	}
+	for i, w := range want {
+		if !strings.Contains(lines[i], w) {
+			t.Errorf("line %d = %q, want %q", i, lines[i], w)
+		}
+	}
+
+	m, _ = printModel(f, Config{}, "", false, 30)
+	for _, line := range strings.Split(m.finalList(shownTheme(m.activeTheme)), "\n") {
+		if w := ansi.StringWidth(line); w > 30 {
+			t.Errorf("%q is %d columns wide", line, w)
+		}
+	}
+
+	m, err = printModel(f, Config{}, "#work or done", true, pipeWidth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = m.finalList(shownTheme(m.activeTheme))
+	if lines := strings.Split(got, "\n"); len(lines) != 2 || !strings.HasSuffix(lines[0], "[ ] Work › report #work") ||
+		!strings.HasSuffix(lines[1], "[✔] Work › report #work › draft") {
+		t.Errorf("flat:\n%s", got)
+	}
+
+	if _, err := printModel(f, Config{}, "due<", false, pipeWidth); err == nil {
+		t.Error("a bad filter must be reported")
+	}
+	if _, err := printModel(filepath.Join(t.TempDir(), "missing.md"), Config{}, "", false, pipeWidth); err == nil {
+		t.Error("a missing file must be reported")
+	}
+}
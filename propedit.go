@@ -0,0 +1,216 @@
+package main
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- PROPERTY EDITOR POPUP ---
+
+const (
+	propDue = iota
+	propPriority
+	propTags
+	propSections
+)
+
+var priorityLevels = []string{"", "A", "B", "C"}
+
+type propEditor struct {
+	idx     int
+	section int
+
+	picker ui.DatePicker
+	clock  string // HH:MM kept from an existing due value
+
+	priority int
+
+	tags      []string
+	checked   map[string]bool
+	cursorTag int
+}
+
+func (m *app) openPropEditor() {
+	if len(m.visibleItems) == 0 {
+		return
+	}
+	idx := m.visibleItems[m.cursorMain].Index
+	title := m.items[idx].Title
+
+	p := propEditor{idx: idx, checked: make(map[string]bool)}
+
+	due, hasTime, ok := model.DueTime(title)
+	p.picker = ui.NewDatePicker(due, ok)
+	p.picker.Calendar = m.config.calendar()
+	if hasTime {
+		p.clock = due.Format("15:04")
+	}
+
+	p.priority = max(0, slices.Index(priorityLevels, model.MetaValue(title, "pri")))
+
+	all := make(map[string]bool)
+	for _, it := range m.items {
+		for _, tag := range model.Tags(it.Title) {
+			all[tag] = true
+		}
+	}
+	for tag := range all {
+		p.tags = append(p.tags, tag)
+	}
+	sort.Strings(p.tags)
+	for _, tag := range model.Tags(title) {
+		p.checked[tag] = true
+	}
+
+	m.prop = p
+	m.propOpen = true
+}
+
+func (m *app) applyPropEditor() {
+	p := m.prop
+	if p.idx >= len(m.items) {
+		return
+	}
+	title := m.items[p.idx].Title
+
+	due := ""
+	if p.picker.Set {
+		due = model.LocalDue(p.picker.Date, p.clock)
+	}
+	title = model.SetMeta(title, "due", due)
+	title = model.SetMeta(title, "pri", priorityLevels[p.priority])
+	for _, tag := range p.tags {
+		title = model.SetTag(title, tag, p.checked[tag])
+	}
+
+	m.items[p.idx].Title = title
+	m.refreshItem(p.idx)
+	m.save()
+}
+
+func (m *app) updatePropEditor(msg tea.KeyMsg) {
+	p := &m.prop
+	switch msg.String() {
+	case "esc":
+		m.propOpen = false
+		return
+	case "enter":
+		m.applyPropEditor()
+		m.propOpen = false
+		return
+	case "tab":
+		p.section = (p.section + 1) % propSections
+		return
+	case "shift+tab":
+		p.section = (p.section + propSections - 1) % propSections
+		return
+	}
+
+	switch p.section {
+	case propDue:
+		p.picker.Update(msg.String())
+		if !p.picker.Set {
+			p.clock = ""
+		}
+	case propPriority:
+		switch msg.String() {
+		case "left", "h", "up", "k":
+			p.priority = (p.priority + len(priorityLevels) - 1) % len(priorityLevels)
+		case "right", "l", "down", "j":
+			p.priority = (p.priority + 1) % len(priorityLevels)
+		}
+	case propTags:
+		switch msg.String() {
+		case "up", "k":
+			if p.cursorTag > 0 {
+				p.cursorTag--
+			}
+		case "down", "j":
+			if p.cursorTag < len(p.tags)-1 {
+				p.cursorTag++
+			}
+		case " ":
+			if len(p.tags) > 0 {
+				tag := p.tags[p.cursorTag]
+				p.checked[tag] = !p.checked[tag]
+			}
+		}
+	}
+}
+
+func (m app) renderPropEditor(t theme.Theme) string {
+	p := m.prop
+	heading := func(section int, name string) string {
+		style := lipgloss.NewStyle().Foreground(t.Comment)
+		if p.section == section {
+			style = lipgloss.NewStyle().Foreground(t.Highlight).Bold(true)
+		}
+		return style.Render(name)
+	}
+	text := lipgloss.NewStyle().Foreground(t.Text)
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+
+	// Termin
+	dueLine := dim.Render("none")
+	if p.picker.Set {
+		dueLine = text.Render(p.picker.Label())
+		if p.clock != "" {
+			dueLine += text.Render(" " + p.clock)
+		}
+	}
+	due := heading(propDue, "Due ") + dueLine + "\n" + p.picker.View(p.section == propDue, t)
+
+	// Priorytet
+	var prios []string
+	for i, level := range priorityLevels {
+		name := level
+		if name == "" {
+			name = "–"
+		}
+		style := dim
+		if i == p.priority {
+			style = lipgloss.NewStyle().Foreground(t.Base).Background(t.Accent)
+			if p.section == propPriority {
+				style = style.Background(t.Highlight)
+			}
+		}
+		prios = append(prios, style.Render(" "+name+" "))
+	}
+	prio := heading(propPriority, "Priority ") + strings.Join(prios, " ")
+
+	// Tagi
+	var tags strings.Builder
+	tags.WriteString(heading(propTags, "Tags") + "\n")
+	if len(p.tags) == 0 {
+		tags.WriteString(dim.Render("  (no tags in this list yet)"))
+	}
+	start, end := ui.Paginator(p.cursorTag, 6, len(p.tags))
+	for i := start; i < end; i++ {
+		box := "[ ]"
+		if p.checked[p.tags[i]] {
+			box = "[✔]"
+		}
+		cursor := "  "
+		if p.section == propTags && i == p.cursorTag {
+			cursor = lipgloss.NewStyle().Foreground(t.Highlight).Render("➤ ")
+		}
+		tags.WriteString(cursor + text.Render(box+" #"+p.tags[i]))
+		if i < end-1 {
+			tags.WriteString("\n")
+		}
+	}
+
+	body := lipgloss.JoinVertical(lipgloss.Left, due, "", prio, "", tags.String())
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Accent).
+		Padding(0, 1).
+		Render(body)
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- QUERY LANGUAGE ---
+//
+// Shared by the :filter command and `serve` (?query=). Terms:
+//
+//	#work            tag
+//	done, open       status
+//	overdue, today   due date before / on today
+//	snoozed, locked  item state
+//	starred          marked with "*"
+//	pri:A            metadata token (key:* matches any value)
+//	due<7d           comparison, see below
+//	word, "a phrase" case-insensitive title text
+//
+// Terms combine with AND (also implicit), OR, NOT / -term and parentheses,
+// in any case. Comparisons take <, <=, >, >=, = and != between a metadata
+// key and a value:
+//
+//	due<7d, created>=-2w    dates (due, snooze, created, completed, reviewed):
+//	due<=fri, due=today     days from today (d, w, m, y) or any date due: takes
+//	pri>=B, priority<A      priorities, A highest; no priority is below Z
+//	est>1h, estimate<=30m   estimates
+//	size>3, owner!=ann      numbers, else text
+//
+// A task without the key matches only !=.
+
+type query func(it model.Item, now time.Time) bool
+
+func parseQuery(s string) (query, error) {
+	p := &queryParser{tokens: tokenizeQuery(s)}
+	if len(p.tokens) == 0 {
+		return func(model.Item, time.Time) bool { return true }, nil
+	}
+	q, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return q, nil
+}
+
+func tokenizeQuery(s string) []string {
+	var tokens []string
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"':
+			end := strings.IndexByte(s[i+1:], '"')
+			if end == -1 {
+				end = len(s) - i - 1
+			}
+			tokens = append(tokens, s[i:i+1+end])
+			i += end + 2
+		default:
+			j := i
+			for j < len(s) && !strings.ContainsRune(" \t()", rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		}
+	}
+	// "due < 7d" to jedno porównanie
+	var out []string
+	for i := 0; i < len(tokens); i++ {
+		if compareOps[tokens[i]] && len(out) > 0 && i+1 < len(tokens) {
+			out[len(out)-1] += tokens[i] + tokens[i+1]
+			i++
+			continue
+		}
+		out = append(out, tokens[i])
+	}
+	return out
+}
+
+var compareOps = map[string]bool{"<": true, "<=": true, ">": true, ">=": true, "=": true, "!=": true}
+
+type queryParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *queryParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *queryParser) parseOr() (query, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "or") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(it model.Item, now time.Time) bool { return l(it, now) || right(it, now) }
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (query, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		next := p.peek()
+		if next == "" || next == ")" || strings.EqualFold(next, "or") {
+			return left, nil
+		}
+		if strings.EqualFold(next, "and") {
+			p.pos++
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(it model.Item, now time.Time) bool { return l(it, now) && right(it, now) }
+	}
+}
+
+func (p *queryParser) parseNot() (query, error) {
+	tok := p.peek()
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of query")
+	case strings.EqualFold(tok, "not"):
+		p.pos++
+		q, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(it model.Item, now time.Time) bool { return !q(it, now) }, nil
+	case len(tok) > 1 && tok[0] == '-':
+		p.tokens[p.pos] = tok[1:]
+		q, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(it model.Item, now time.Time) bool { return !q(it, now) }, nil
+	case tok == "(":
+		p.pos++
+		q, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return q, nil
+	case tok == ")":
+		return nil, fmt.Errorf("unexpected )")
+	}
+	p.pos++
+	return queryTerm(tok)
+}
+
+var (
+	compareTerm = regexp.MustCompile(`^([A-Za-z][\w-]*)(<=|>=|!=|<|>|=)(.*)$`)
+	relativeDay = regexp.MustCompile(`^([+-]?\d+)([dwmy])$`)
+	queryAlias  = map[string]string{"priority": "pri", "estimate": "est"}
+	dateKeys    = map[string]bool{"due": true, "snooze": true, "created": true, "completed": true, "reviewed": true}
+)
+
+func queryTerm(tok string) (query, error) {
+	if m := compareTerm.FindStringSubmatch(tok); m != nil && !strings.HasPrefix(tok, "\"") {
+		if m[3] == "" {
+			return nil, fmt.Errorf("%s: missing value", tok)
+		}
+		return compareQuery(strings.ToLower(m[1]), m[2], m[3])
+	}
+
+	return simpleTerm(tok), nil
+}
+
+func simpleTerm(tok string) query {
+	if strings.HasPrefix(tok, "\"") {
+		return textTerm(strings.Trim(tok, "\""))
+	}
+	if strings.HasPrefix(tok, "#") && len(tok) > 1 {
+		tag := strings.ToLower(tok[1:])
+		return func(it model.Item, _ time.Time) bool {
+			for _, t := range model.Tags(it.Title) {
+				if strings.ToLower(t) == tag {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	switch strings.ToLower(tok) {
+	case "done":
+		return func(it model.Item, _ time.Time) bool { return it.Done }
+	case "open":
+		return func(it model.Item, _ time.Time) bool { return !it.Done }
+	case "overdue":
+		return func(it model.Item, now time.Time) bool {
+			due, _, ok := model.DueTime(it.Title)
+			return ok && !it.Done && due.Before(model.StartOfDay(now))
+		}
+	case "today":
+		return func(it model.Item, now time.Time) bool {
+			due, _, ok := model.DueTime(it.Title)
+			return ok && model.StartOfDay(due).Equal(model.StartOfDay(now))
+		}
+	case "snoozed":
+		return func(it model.Item, now time.Time) bool { return isSnoozed(it.Title, now.Format(model.DateLayout)) }
+	case "locked":
+		return func(it model.Item, _ time.Time) bool { return isLocked(it) }
+	case "starred":
+		return func(it model.Item, _ time.Time) bool { return isStarred(it) }
+	}
+	if key, value, ok := strings.Cut(tok, ":"); ok && key != "" {
+		key = strings.ToLower(key)
+		return func(it model.Item, _ time.Time) bool {
+			v := model.MetaValue(it.Title, key)
+			if value == "*" {
+				return v != ""
+			}
+			return strings.EqualFold(v, value)
+		}
+	}
+	return textTerm(tok)
+}
+
+// compareQuery builds a comparison term; a value that doesn't fit the key
+// is an error.
+func compareQuery(key, op, value string) (query, error) {
+	if alias, ok := queryAlias[key]; ok {
+		key = alias
+	}
+	holds := func(c int) bool {
+		switch op {
+		case "<":
+			return c < 0
+		case "<=":
+			return c <= 0
+		case ">":
+			return c > 0
+		case ">=":
+			return c >= 0
+		case "=":
+			return c == 0
+		}
+		return c != 0
+	}
+	switch {
+	case dateKeys[key]:
+		if _, ok := queryDate(value, time.Now()); !ok {
+			return nil, fmt.Errorf("%s%s%s: unknown date %q", key, op, value, value)
+		}
+		return func(it model.Item, now time.Time) bool {
+			day, ok := itemDate(it.Title, key)
+			if !ok {
+				return op == "!="
+			}
+			target, _ := queryDate(value, now)
+			return holds(day.Compare(target))
+		}, nil
+	case key == "pri":
+		want := strings.ToUpper(value)
+		return func(it model.Item, _ time.Time) bool {
+			rank := strings.ToUpper(model.MetaValue(it.Title, "pri"))
+			if rank == "" {
+				rank = "~" // bez priorytetu: poniżej Z
+			}
+			return holds(strings.Compare(want, rank))
+		}, nil
+	case key == "est":
+		want, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s%s%s: not a duration", key, op, value)
+		}
+		return func(it model.Item, _ time.Time) bool {
+			d, ok := taskEstimate(it.Title)
+			if !ok {
+				return op == "!="
+			}
+			return holds(cmp.Compare(d, want))
+		}, nil
+	}
+	wantNum, numErr := strconv.ParseFloat(value, 64)
+	return func(it model.Item, _ time.Time) bool {
+		v := model.MetaValue(it.Title, key)
+		if v == "" {
+			return op == "!="
+		}
+		if n, err := strconv.ParseFloat(v, 64); err == nil && numErr == nil {
+			return holds(cmp.Compare(n, wantNum))
+		}
+		return holds(strings.Compare(strings.ToLower(v), strings.ToLower(value)))
+	}, nil
+}
+
+// queryDate reads a comparison date: days from today ("7d", "-2w", "1m")
+// or anything a due: date takes.
+func queryDate(s string, now time.Time) (time.Time, bool) {
+	today := model.StartOfDay(now)
+	if m := relativeDay.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		switch m[2] {
+		case "d":
+			return today.AddDate(0, 0, n), true
+		case "w":
+			return today.AddDate(0, 0, 7*n), true
+		case "m":
+			return today.AddDate(0, n, 0), true
+		}
+		return today.AddDate(n, 0, 0), true
+	}
+	t, _, ok := model.ParseDate(s, now)
+	return model.StartOfDay(t), ok
+}
+
+// itemDate is the day of a date token of title.
+func itemDate(title, key string) (time.Time, bool) {
+	if key == "due" {
+		due, _, ok := model.DueTime(title)
+		return model.StartOfDay(due), ok
+	}
+	v := model.MetaValue(title, key)
+	if len(v) < len(model.DateLayout) {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(model.DateLayout, v[:len(model.DateLayout)], time.Local)
+	return t, err == nil
+}
+
+func textTerm(text string) query {
+	text = strings.ToLower(text)
+	return func(it model.Item, _ time.Time) bool {
+		return strings.Contains(strings.ToLower(model.DisplayTitle(it.Title)), text)
+	}
+}
+
+// queryMatches returns which items match q, plus the ancestors of matches
+// so results keep their place in the tree.
+func queryMatches(items []model.Item, q query, now time.Time) (match, context []bool) {
+	match = make([]bool, len(items))
+	context = make([]bool, len(items))
+	for i := range items {
+		if !q(items[i], now) {
+			continue
+		}
+		match[i] = true
+		context[i] = true
+		for p := model.ParentIndex(items, i); p != -1 && !context[p]; p = model.ParentIndex(items, p) {
+			context[p] = true
+		}
+	}
+	return match, context
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- RECURRENCE ---
+//
+// A task with a due date and recur:daily|weekday|weekly|monthly|yearly|<N>d|<N>w
+// is not checked off when completed: its due date moves to the next
+// occurrence instead. "weekday" skips weekends and the configured holidays;
+// workdays_only does the same for every rule and for the date shortcuts.
+// When the next date is rolled past a holiday or clamped to a short month,
+// a hidden anchor: token keeps the date the series is counted from, so the
+// occurrences after it return to their day.
+
+func (c Config) calendar() model.Calendar {
+	return model.Calendar{Holidays: c.Holidays}
+}
+
+// completeRecurring advances a recurring task and reports whether it did.
+func completeRecurring(it *model.Item, cfg Config, now time.Time) (time.Time, bool) {
+	rule := model.MetaValue(it.Title, "recur")
+	due, hasTime, ok := model.DueTime(it.Title)
+	if rule == "" || !ok {
+		return time.Time{}, false
+	}
+	anchor, _ := time.ParseInLocation(model.DateLayout, model.MetaValue(it.Title, "anchor"), time.Local)
+	next, anchor, ok := model.NextOccurrence(due, anchor, rule, cfg.calendar(), cfg.WorkdaysOnly, now)
+	if !ok {
+		return time.Time{}, false
+	}
+	// Krok w czasie lokalnym: "codziennie o 9:00" zostaje o 9:00 po zmianie czasu
+	it.Title = model.SetMeta(it.Title, "due", model.FormatDue(next, hasTime))
+	stamp := ""
+	if !anchor.IsZero() {
+		stamp = anchor.Format(model.DateLayout)
+	}
+	it.Title = model.SetMeta(it.Title, "anchor", stamp)
+	return next, true
+}
+
+// nextDay is the default for "tomorrow"-style shortcuts such as snoozing.
+func (c Config) nextDay(now time.Time) time.Time {
+	if c.WorkdaysOnly {
+		return c.calendar().NextWorkday(now)
+	}
+	return now.AddDate(0, 0, 1)
+}
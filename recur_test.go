@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestRecurringAfterAHoliday(t *testing.T) {
+	cfg := Config{Holidays: []string{"2026-01-09"}, WorkdaysOnly: true}
+	it := model.Item{Title: "report recur:weekly due:2026-01-02"}
+	var got []string
+	for range 3 {
+		due, _, _ := model.DueTime(it.Title)
+		if _, ok := completeRecurring(&it, cfg, due); !ok {
+			t.Fatalf("%q did not recur", it.Title)
+		}
+		got = append(got, model.MetaValue(it.Title, "due")+" "+model.MetaValue(it.Title, "anchor"))
+	}
+	// Poniedziałek po święcie pamięta piątek, od którego liczy się seria
+	want := []string{"2026-01-12 2026-01-02", "2026-01-16 ", "2026-01-23 "}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("occurrence %d = %q, want %q", i+1, got[i], want[i])
+		}
+	}
+	if !model.HiddenMetaKeys["anchor"] {
+		t.Error("anchor: is bookkeeping and must stay hidden")
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+)
+
+// --- REMINDERS ---
+
+const remindInterval = 30 * time.Second
+
+type notification struct {
+	title string
+	body  string
+}
+
+type reminderTickMsg struct{}
+
+// reminders remembers what was already announced, so every task fires once
+// per due value (changing the due date re-arms it). A snoozed reminder fires
+// again once its time comes.
+type reminders struct {
+	notified map[string]bool
+	again    map[string]time.Time
+}
+
+func newReminders() *reminders {
+	return &reminders{notified: make(map[string]bool), again: make(map[string]time.Time)}
+}
+
+// reminderKey identifies a reminder: the task (by id, else by title) and its due value.
+func reminderKey(it model.Item) string {
+	key := model.DisplayTitle(it.Title)
+	if id := model.ID(it); id != "" {
+		key = id
+	}
+	return key + "@" + model.MetaValue(it.Title, "due")
+}
+
+// snooze fires the reminder key again at until.
+func (r *reminders) snooze(key string, until time.Time) {
+	r.again[key] = until
+}
+
+// fire returns the open tasks whose due time has just passed, split into the
+// ones still due and the overdue ones.
+func (r *reminders) fire(items []model.Item, now time.Time) (due, overdue []int) {
+	for i, it := range items {
+		if it.Done {
+			continue
+		}
+		at, hasTime, ok := model.DueTime(it.Title)
+		if !ok || now.Before(at) {
+			continue
+		}
+		key := reminderKey(it)
+		if r.notified[key] {
+			until, snoozed := r.again[key]
+			if !snoozed || now.Before(until) {
+				continue
+			}
+			delete(r.again, key)
+		}
+		r.notified[key] = true
+
+		// Zadanie bez godziny jest "na dziś" do północy
+		deadline := at.Add(time.Hour)
+		if !hasTime {
+			deadline = at.AddDate(0, 0, 1)
+		}
+		if now.After(deadline) {
+			overdue = append(overdue, i)
+		} else {
+			due = append(due, i)
+		}
+	}
+	return due, overdue
+}
+
+func (r *reminders) check(items []model.Item, now time.Time) []notification {
+	due, overdue := r.fire(items, now)
+	return notifications(items, due, overdue)
+}
+
+func notifications(items []model.Item, due, overdue []int) []notification {
+	titles := func(idx []int) []string {
+		var out []string
+		for _, i := range idx {
+			out = append(out, model.DisplayTitle(items[i].Title))
+		}
+		return out
+	}
+	var out []notification
+	out = append(out, summarize("Due", titles(due))...)
+	out = append(out, summarize("Overdue", titles(overdue))...)
+	return out
+}
+
+// summarize collapses bursts (e.g. on startup) into a single notification.
+func summarize(kind string, titles []string) []notification {
+	if len(titles) > 3 {
+		return []notification{{title: fmt.Sprintf("%d tasks %s", len(titles), kind), body: titles[0] + ", …"}}
+	}
+	var out []notification
+	for _, t := range titles {
+		out = append(out, notification{title: kind, body: t})
+	}
+	return out
+}
+
+// notify delivers the notification using the configured method:
+// "auto" (default), "notify-send", "osc9", "bell" or "off".
+func notify(method string, n notification) {
+	if method == "" || method == "auto" {
+		method = "bell"
+		if _, err := exec.LookPath("notify-send"); err == nil && (os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != "") {
+			method = "notify-send"
+		}
+	}
+	switch method {
+	case "notify-send":
+		exec.Command("notify-send", "-a", appName, n.title, n.body).Run()
+	case "osc9":
+		fmt.Fprintf(os.Stdout, "\x1b]9;%s: %s\x07", n.title, n.body)
+	case "bell":
+		fmt.Fprint(os.Stdout, "\a")
+	}
+}
+
+func reminderTick() tea.Cmd {
+	return tea.Tick(remindInterval, func(time.Time) tea.Msg {
+		return reminderTickMsg{}
+	})
+}
+
+func (m *app) checkReminders() tea.Cmd {
+	due, overdue := m.reminders.fire(m.items, time.Now())
+	m.addToasts(due, "Due")
+	m.addToasts(overdue, "Overdue")
+	pending := notifications(m.items, due, overdue)
+	if len(pending) == 0 {
+		return nil
+	}
+	m.status = "🔔 " + pending[0].title + ": " + pending[0].body
+	method := m.config.Notify
+	return func() tea.Msg {
+		for _, n := range pending {
+			notify(method, n)
+		}
+		return nil
+	}
+}
+
+// --- DAEMON (todo remind) ---
+
+func runRemind(args []string) {
+	fs := flag.NewFlagSet("remind", flag.ExitOnError)
+	interval := fs.Duration("interval", time.Minute, "how often to check the file")
+	method := fs.String("notify", "", "notification method: auto, notify-send, osc9, bell")
+	fs.Parse(args)
+
+	filename := "todo.md"
+	if fs.NArg() > 0 {
+		filename = fs.Arg(0)
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *method == "" {
+		*method = cfg.Notify
+	}
+
+	r := newReminders()
+	var lastFeeds time.Time
+	for {
+		if len(cfg.Feeds) > 0 && time.Since(lastFeeds) >= cfg.feedInterval() {
+			lastFeeds = time.Now()
+			if err := updateFeeds(filename, cfg); err != nil {
+				fmt.Fprintf(os.Stderr, "%s Error: %v\n", time.Now().Format("15:04"), err)
+			}
+		}
+		items, _, err := storage.Load(filename)
+		if err != nil {
+			// Demon czeka, aż plik znów da się przeczytać
+			fmt.Fprintf(os.Stderr, "%s Error: %v\n", time.Now().Format("15:04"), err)
+		}
+		for _, n := range r.check(items, time.Now()) {
+			fmt.Printf("%s %s: %s\n", time.Now().Format("15:04"), n.title, n.body)
+			notify(*method, n)
+		}
+		time.Sleep(*interval)
+	}
+}
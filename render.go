@@ -0,0 +1,292 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- VIRTUALIZED LIST RENDERING ---
+//
+// Only the items inside the viewport are styled. Wrapped titles and styled
+// rows are cached by everything that affects their look, so an edit simply
+// produces a new key and untouched rows are reused across keystrokes.
+
+const maxCachedRows = 4096
+
+type renderCache struct {
+	wraps map[string][]string
+	rows  map[string][]string
+}
+
+// scrollList remembers where the list is scrolled to after an update, so
+// the next frame (and the next session) starts from the same row.
+func (m *app) scrollList() {
+	if m.state != viewMain {
+		return
+	}
+	if m.width-2 < minRowWidth || len(m.visibleItems) == 0 {
+		m.listTop = 0
+		return
+	}
+	m.listTop, _, _ = m.listWindow(max(1, m.height-m.chromeHeight()))
+}
+
+func newRenderCache() *renderCache {
+	return &renderCache{wraps: make(map[string][]string), rows: make(map[string][]string)}
+}
+
+// prune drops everything once the cache outgrows the list; stale keys from
+// edited items are never looked up again.
+func (c *renderCache) prune(limit int) {
+	if len(c.wraps) > limit {
+		c.wraps = make(map[string][]string)
+	}
+	if len(c.rows) > limit {
+		c.rows = make(map[string][]string)
+	}
+}
+
+func (m *app) itemContent(i int) string {
+	it := m.visibleItems[i].Data
+	if i == m.cursorMain && m.inputMode {
+		return m.inputBuf + "█"
+	}
+	content := localDue(model.DisplayTitle(it.Title), time.Now()) + trackingLabel(it.Title) + estimateLabel(m.items, m.visibleItems[i].Index)
+	if m.showColumns() {
+		content = columnTitle(content)
+	}
+	if isSnoozed(it.Title, time.Now().Format(model.DateLayout)) {
+		content = "💤 " + content
+	}
+	if isLocked(it) {
+		content = "🔒 " + content
+	}
+	if len(attachments(it)) > 0 {
+		content += " 📎"
+	}
+	if isStarred(it) {
+		content = "★ " + content
+	}
+	if isBlocked(m.items, m.visibleItems[i].Index) {
+		content = "⛔ " + content
+	}
+	if m.blockPick && m.visibleItems[i].Index == m.blockFrom {
+		content = "⏳ " + content
+	}
+	if m.moving && m.visibleItems[i].Index == m.moveFrom {
+		content = "⇅ " + content
+	}
+	return content
+}
+
+func (m *app) contentWidth(level int) int {
+	return max(10, m.width-3-(2+ui.GuidePrefixWidth(level)+3+1)-m.numberWidth())
+}
+
+// wrapped returns the title of visible item i wrapped to the list width.
+func (m *app) wrapped(i int) []string {
+	level := m.visibleItems[i].Data.Level
+	width := m.contentWidth(level)
+	content := m.itemContent(i)
+	editing := i == m.cursorMain && m.inputMode
+	table := m.showColumns() && !editing
+	single := (table || m.config.Truncate) && !editing
+	key := strconv.Itoa(width) + "\x00" + strconv.FormatBool(table) + strconv.FormatBool(single) + "\x00" + content
+	if m.rows != nil {
+		if lines, ok := m.rows.wraps[key]; ok {
+			return lines
+		}
+	}
+	if !editing {
+		content = mdPlain(content)
+	}
+	var lines []string
+	if single {
+		// W tabeli (albo z "truncate") tytuł mieści się w jednej linii
+		if table {
+			width -= columnsWidth
+		}
+		lines = []string{ansi.Truncate(content, width, "…")}
+	} else {
+		lines = strings.Split(lipgloss.NewStyle().Width(width).Render(content), "\n")
+	}
+	if m.rows != nil {
+		m.rows.wraps[key] = lines
+	}
+	return lines
+}
+
+// itemRows renders the styled rows of visible item i.
+func (m *app) itemRows(i int, g ui.RowGuide, t theme.Theme) []string {
+	it := m.visibleItems[i].Data
+	isCursor := i == m.cursorMain
+	openParent := !it.Collapsed && i+1 < len(m.visibleItems) && m.visibleItems[i+1].Data.Level > it.Level
+
+	var key string
+	if !isCursor && m.rows != nil {
+		key = strings.Join([]string{
+			strconv.Itoa(m.width), t.Name, strconv.Itoa(int(lipgloss.ColorProfile())), g.Prefix, g.Connector,
+			strconv.FormatBool(it.Done), it.State, strconv.FormatBool(it.Collapsed), strconv.FormatBool(openParent),
+			m.itemContent(i), strings.Join(columnCells(it), "\x00"), strconv.FormatBool(m.showColumns()),
+			strconv.FormatBool(m.config.Truncate), strings.Join(model.RowStyle(it.Title), ","), strconv.Itoa(it.Heading),
+		}, "\x00")
+		if rows, ok := m.rows.rows[key]; ok {
+			return rows
+		}
+	}
+
+	titleStyle := lipgloss.NewStyle().Foreground(t.Text)
+	if isBlocked(m.items, m.visibleItems[i].Index) {
+		titleStyle = titleStyle.Foreground(t.Comment)
+	}
+	titleStyle = rowStyle(titleStyle, it.Title, t)
+	if it.Done {
+		titleStyle = lipgloss.NewStyle().Foreground(t.Comment).Strikethrough(true)
+	}
+	if isCursor && m.inputMode {
+		titleStyle = lipgloss.NewStyle().Foreground(t.Base).Background(t.Highlight)
+	}
+
+	checkStr := "[ ]"
+	checkStyle := lipgloss.NewStyle().Foreground(t.Text)
+	if it.Collapsed {
+		checkStr = "[+]"
+		checkStyle = lipgloss.NewStyle().Foreground(t.Accent)
+	} else if it.Done {
+		checkStr = "[✔]"
+		checkStyle = lipgloss.NewStyle().Foreground(t.Special)
+	} else if it.State != "" {
+		state, _ := m.config.checkboxState(it.State)
+		checkStr = "[" + it.State + "]"
+		checkStyle = lipgloss.NewStyle().Foreground(state.color(t))
+	}
+	if it.Heading > 0 {
+		// Sekcja zamiast pola wyboru pokazuje, czy jest zwinięta
+		switch {
+		case it.Collapsed:
+			checkStr = " ▸"
+		case openParent:
+			checkStr = " ▾"
+		default:
+			checkStr = " ·"
+		}
+		checkStyle = lipgloss.NewStyle().Foreground(t.Accent).Bold(true)
+		if !(isCursor && m.inputMode) {
+			titleStyle = titleStyle.Foreground(t.Accent).Bold(true)
+		}
+	}
+
+	cursorStr := "  "
+	if isCursor {
+		cursorStr = " ➤"
+	}
+	guide := lipgloss.NewStyle().Foreground(t.Comment)
+
+	var titleLines []string
+	for _, line := range m.wrapped(i) {
+		titleLines = append(titleLines, strings.TrimRight(line, " "))
+	}
+	styled := make([]string, len(titleLines))
+	if it.Done || (isCursor && m.inputMode) {
+		for n, line := range titleLines {
+			styled[n] = titleStyle.Render(line)
+		}
+	} else {
+		plain, attrs := inlineMarkdown(m.itemContent(i))
+		styled = styleMarkdown(titleLines, plain, attrs, titleStyle, t)
+	}
+
+	var rows []string
+	for lineIdx := range titleLines {
+		var rowSb strings.Builder
+		rowSb.WriteString(lipgloss.NewStyle().Foreground(t.Highlight).Render(cursorStr))
+		rowSb.WriteString(guide.Render(g.Prefix))
+
+		if lineIdx == 0 {
+			rowSb.WriteString(guide.Render(g.Connector))
+			rowSb.WriteString(checkStyle.Render(checkStr))
+		} else {
+			// Kontynuacja zawiniętego tytułu
+			connectorContinuation := " "
+			switch g.Connector {
+			case " ├─":
+				connectorContinuation = " │ "
+			case " └─":
+				connectorContinuation = "   "
+			}
+			rowSb.WriteString(guide.Render(connectorContinuation))
+
+			checkboxSpace := "   "
+			if openParent {
+				checkboxSpace = " │ "
+			}
+			if it.Heading > 0 {
+				checkboxSpace = strings.TrimSuffix(checkboxSpace, " ") // znacznik sekcji ma dwie kolumny
+			}
+			rowSb.WriteString(guide.Render(checkboxSpace))
+		}
+		rowSb.WriteString(" ")
+		rowSb.WriteString(styled[lineIdx])
+		if m.showColumns() && !(isCursor && m.inputMode) {
+			rowSb.WriteString(strings.Repeat(" ", max(0, m.contentWidth(it.Level)-columnsWidth-lipgloss.Width(styled[lineIdx]))))
+			rowSb.WriteString(renderColumns(it, t))
+		}
+		rows = append(rows, rowSb.String())
+	}
+
+	if key != "" {
+		m.rows.rows[key] = rows
+	}
+	return rows
+}
+
+// listWindow picks the visible items to draw. The list stays where it was
+// scrolled to (m.listTop) while the cursor is in view; a cursor above it
+// becomes the top row and one below it puts the cursor's last line at the
+// bottom. It returns the item range and how many leading lines of the first
+// item are cut off.
+func (m *app) listWindow(height int) (from, to, skip int) {
+	cursor := m.cursorMain
+	target := height
+	if top := min(m.listTop, cursor); top > 0 {
+		lines := 0
+		for i := top; i <= cursor; i++ {
+			lines += len(m.wrapped(i))
+		}
+		if lines <= target {
+			to = cursor + 1
+			for to < len(m.visibleItems) && lines < height {
+				lines += len(m.wrapped(to))
+				to++
+			}
+			return top, to, 0
+		}
+	}
+	lines := len(m.wrapped(cursor))
+	from = cursor
+	for from > 0 && lines <= target {
+		from--
+		lines += len(m.wrapped(from))
+	}
+	if lines > target && from == cursor {
+		return cursor, cursor + 1, 0 // wyższe niż okno: od początku
+	}
+	if lines > target {
+		return from, cursor + 1, lines - target
+	}
+
+	// Wszystko do kursora się mieści – rysujemy od góry
+	to, lines = 0, 0
+	for to < len(m.visibleItems) && lines < height {
+		lines += len(m.wrapped(to))
+		to++
+	}
+	return 0, to, 0
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+func largeList(n int) app {
+	m := app{width: 100, rows: newRenderCache()}
+	for i := range n {
+		m.items = append(m.items, model.Item{Title: fmt.Sprintf("Task %d with a reasonably long title", i), Level: i % 4})
+	}
+	m.recalcVisible()
+	return m
+}
+
+func TestRenderListWindow(t *testing.T) {
+	m := largeList(5000)
+	m.cursorMain = 4000
+	out := ansi.Strip(m.renderList(20, theme.Default))
+	if !strings.Contains(out, "➤") || !strings.Contains(out, "Task 4000 ") {
+		t.Fatalf("cursor row missing from window:\n%s", out)
+	}
+	if strings.Contains(out, "Task 3900 ") {
+		t.Errorf("rows far above the viewport were rendered:\n%s", out)
+	}
+}
+
+func TestRenderListKeepsCursorVisible(t *testing.T) {
+	m := largeList(60)
+	m.width = 30 // wrap titles over several rows
+	for c := range m.visibleItems {
+		m.cursorMain = c
+		if out := ansi.Strip(m.renderList(10, theme.Default)); !strings.Contains(out, "➤") {
+			t.Fatalf("cursor %d hidden:\n%s", c, out)
+		}
+	}
+}
+
+func TestListScrollIsKept(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := largeList(60)
+	m.filename, m.height = "/tmp/scroll.md", 10+uiOverhead
+	m.cursorMain = 40
+	m.scrollList()
+	top := m.listTop
+	if top == 0 {
+		t.Fatal("the list did not scroll to the cursor")
+	}
+	// Kursor w górę o jeden: okno stoi w miejscu
+	m.cursorMain = 39
+	m.scrollList()
+	if m.listTop != top {
+		t.Errorf("moving up inside the window scrolled from %d to %d", top, m.listTop)
+	}
+
+	m.saveSession()
+	r := largeList(60)
+	r.filename, r.height = m.filename, m.height
+	r.restoreSession()
+	if r.cursorMain != 39 || r.listTop != top {
+		t.Errorf("restored cursor %d, top %d; want 39, %d", r.cursorMain, r.listTop, top)
+	}
+}
+
+func BenchmarkRenderList(b *testing.B) {
+	m := largeList(5000)
+	m.cursorMain = 2500
+	for b.Loop() {
+		m.renderList(40, theme.Default)
+	}
+}
+
+func TestThemePreview(t *testing.T) {
+	defer func(saved []theme.Theme) { themes = saved }(themes)
+	dark := theme.Default
+	light := theme.Default
+	light.Name = "Light"
+	themes = []theme.Theme{dark, light}
+
+	m := largeList(3)
+	m.activeTheme = dark
+	m.state = viewThemeSelector
+	next, _ := m.updateThemeSelector(keyMsg("j"))
+	m = next.(app)
+	out := ansi.Strip(m.renderThemeSelector(10, themes[m.cursorTheme]))
+	if !strings.Contains(out, "-> Light") || !strings.Contains(out, "Task 0 ") {
+		t.Errorf("selector without the sample list:\n%s", out)
+	}
+
+	next, _ = m.updateThemeSelector(keyMsg("esc"))
+	m = next.(app)
+	if m.activeTheme.Name != dark.Name || m.cursorTheme != 0 {
+		t.Errorf("esc kept the preview: %q, cursor %d", m.activeTheme.Name, m.cursorTheme)
+	}
+}
+
+func TestCompactLayout(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	m := largeList(40)
+	m.width, m.height = 60, 20
+	m.activeTheme = theme.Default
+
+	framed := ansi.Strip(m.View())
+	next, _ := m.updateMain(keyMsg("C"))
+	m = next.(app)
+	if !m.config.Compact {
+		t.Fatal("C did not switch to the compact layout")
+	}
+	compact := ansi.Strip(m.View())
+	if !strings.Contains(framed, "╭") || strings.Contains(compact, "╭") {
+		t.Errorf("frame shown in the wrong layout:\n%s\n---\n%s", framed, compact)
+	}
+	if lines := strings.Count(compact, "\n") + 1; lines != m.height {
+		t.Errorf("compact view has %d lines, want %d:\n%s", lines, m.height, compact)
+	}
+	if strings.Count(compact, "Task ") <= strings.Count(framed, "Task ") {
+		t.Error("compact layout shows no more tasks")
+	}
+}
+
+func TestDetailPane(t *testing.T) {
+	m := app{width: 140, height: 20, rows: newRenderCache(), activeTheme: theme.Default, items: []model.Item{
+		{Title: "ship it #release due:2026-10-20", Note: []string{"check the changelog"}},
+		{Title: "tag", Level: 1, Done: true},
+		{Title: "announce", Level: 1},
+	}}
+	m.recalcVisible()
+	out := ansi.Strip(m.View())
+	for _, want := range []string{"Subtasks  1/2", "check the changelog", "release"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("pane lacks %q:\n%s", want, out)
+		}
+	}
+
+	next, _ := m.updateMain(keyMsg("|"))
+	m = next.(app)
+	if out := ansi.Strip(m.View()); strings.Contains(out, "Subtasks") {
+		t.Errorf("| did not hide the pane:\n%s", out)
+	}
+	m.splitHidden, m.width = false, 100
+	if m.showSplit() {
+		t.Error("pane shown below split_width")
+	}
+}
+
+func TestResizeSplit(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{width: 120, height: 20, rows: newRenderCache(), activeTheme: theme.Default, items: []model.Item{{Title: "a task"}}}
+	m.recalcVisible()
+	if m.paneWidth() != 39 {
+		t.Errorf("default pane is %d wide", m.paneWidth())
+	}
+	for range 20 {
+		next, _ := m.updateMain(keyMsg("["))
+		m = next.(app)
+	}
+	if m.config.SplitRatio != maxSplitRatio || m.paneWidth() != 120-minListWidth {
+		t.Errorf("ratio %d, pane %d: the list must keep %d columns", m.config.SplitRatio, m.paneWidth(), minListWidth)
+	}
+	for _, line := range strings.Split(m.View(), "\n") {
+		if w := lipgloss.Width(line); w > m.width {
+			t.Fatalf("line %d wide: %q", w, ansi.Strip(line))
+		}
+	}
+	next, _ := m.updateMain(keyMsg("]"))
+	m = next.(app)
+	if cfg, _ := loadConfig(); cfg.SplitRatio != maxSplitRatio-splitRatioStep {
+		t.Errorf("saved ratio = %d", cfg.SplitRatio)
+	}
+
+	m.config.SplitWidth, m.width = 30, 50
+	if m.showSplit() {
+		t.Error("a 50-column window can't fit both the list and the pane")
+	}
+}
+
+func TestColumnView(t *testing.T) {
+	m := app{width: 90, height: 12, rows: newRenderCache(), activeTheme: theme.Default, items: []model.Item{
+		{Title: "ship it #release due:2026-10-20 pri:A and a very long title that goes on and on for a while"},
+		{Title: "announce #x", Level: 1},
+	}}
+	m.recalcVisible()
+	next, _ := m.updateMain(keyMsg("c"))
+	m = next.(app)
+	lines := strings.Split(ansi.Strip(m.renderList(4, theme.Default)), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("list too short: %q", lines)
+	}
+	first, second := lines[1], lines[2]
+	if !strings.Contains(first, "… 2026-10-20 A   #release") || strings.Contains(first, "due:") {
+		t.Errorf("first row = %q", first)
+	}
+	if strings.Index(first, "#release") != strings.Index(second, "#x") {
+		t.Errorf("tag columns not aligned:\n%s\n%s", first, second)
+	}
+}
+
+func TestRowTint(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.TrueColor)
+
+	m := app{width: 80, rows: newRenderCache(), items: []model.Item{{Title: "Waiting on infra %red"}, {Title: "plain"}}}
+	m.recalcVisible()
+	m.cursorMain = 1
+	row := m.itemRows(0, ui.RowGuide{}, theme.Default)[0]
+	if strings.Contains(row, "%red") || !strings.Contains(row, "251;73;52") {
+		t.Errorf("row not tinted red: %q", row)
+	}
+	m.items[0].Title = "Waiting on infra %green"
+	m.recalcVisible()
+	if row := m.itemRows(0, ui.RowGuide{}, theme.Default)[0]; !strings.Contains(row, "184;187;38") {
+		t.Errorf("cached row reused after the tint changed: %q", row)
+	}
+}
+
+func TestTruncateTitles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := largeList(3)
+	m.width = 30
+	if rows := m.itemRows(1, ui.RowGuide{}, theme.Default); len(rows) < 2 {
+		t.Fatalf("title did not wrap: %q", rows)
+	}
+
+	next, _ := m.updateMain(keyMsg("ctrl+w"))
+	m = next.(app)
+	rows := m.itemRows(1, ui.RowGuide{}, theme.Default)
+	if !m.config.Truncate || len(rows) != 1 || !strings.HasSuffix(ansi.Strip(rows[0]), "…") {
+		t.Errorf("truncated rows = %q", rows)
+	}
+	if cfg, _ := loadConfig(); !cfg.Truncate {
+		t.Error("the toggle was not saved")
+	}
+
+	m.inputMode, m.inputBuf = true, m.items[0].Title
+	if rows := m.itemRows(0, ui.RowGuide{}, theme.Default); len(rows) < 2 {
+		t.Errorf("the title being edited must wrap: %q", rows)
+	}
+}
+
+func TestLineNumbers(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := largeList(12)
+	m.runCommand("numbers")
+	m.cursorMain = 3
+	out := ansi.Strip(m.renderList(20, theme.Default))
+	if !strings.Contains(out, " 1   ") || !strings.Contains(out, " 4  ➤") || !strings.Contains(out, "12   ") {
+		t.Errorf("absolute numbers:\n%s", out)
+	}
+	m.runCommand("numbers")
+	out = ansi.Strip(m.renderList(20, theme.Default))
+	if !strings.Contains(out, " 3   ") || !strings.Contains(out, " 4  ➤") || !strings.Contains(out, " 8   ") {
+		t.Errorf("relative numbers:\n%s", out)
+	}
+
+	m.runCommand("10")
+	if m.cursorMain != 9 {
+		t.Errorf(":10 went to %d", m.cursorMain)
+	}
+	for _, k := range []string{"7", "g", "g"} {
+		next, _ := m.updateMain(keyMsg(k))
+		m = next.(app)
+	}
+	if m.cursorMain != 6 {
+		t.Errorf("7gg went to %d", m.cursorMain)
+	}
+}
+
+func TestScrollbar(t *testing.T) {
+	m := largeList(40)
+	m.cursorMain = 20
+	lines := strings.Split(ansi.Strip(m.renderList(10, theme.Default)), "\n")
+	rows := lines[1 : len(lines)-1] // bez ramki
+	thumb := 0
+	for i, row := range rows {
+		if ansi.StringWidth(row) != m.width {
+			t.Errorf("row %d is %d wide", i, ansi.StringWidth(row))
+		}
+		if strings.HasSuffix(row, "┃│") {
+			thumb++
+		} else if !strings.HasSuffix(row, "││") {
+			t.Errorf("row %d has no scrollbar: %q", i, row)
+		}
+	}
+	if thumb != 3 {
+		t.Errorf("thumb is %d rows, want 3", thumb)
+	}
+	if !strings.Contains(rows[len(rows)-1], "Task 20 ") || strings.Contains(strings.Join(rows, ""), "...") {
+		t.Errorf("the cursor must sit on the last row, without markers:\n%s", strings.Join(rows, "\n"))
+	}
+
+	m = largeList(3)
+	if out := ansi.Strip(m.renderList(10, theme.Default)); strings.Contains(out, "┃") {
+		t.Errorf("a list that fits needs no scrollbar:\n%s", out)
+	}
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- SEARCH AND REPLACE ---
+//
+// ":%s/Acme/Globex/g" renames text in every task title, ":s/…/…/" only in
+// the task under the cursor, as in vim. The old text is taken literally;
+// any character can stand in for "/" and "\/" is a literal one. Flags: g
+// replaces every occurrence in a title instead of the first, i ignores
+// case, r reads the old text as a regular expression (Go syntax, "$1" in
+// the new text for groups; a bad one is reported in the footer). Hidden
+// tokens (ids, dates of creation…) are left alone. The tasks that would
+// change are listed for review first: Space skips one, Enter writes the
+// rest.
+
+var wordSpan = regexp.MustCompile(`\S+`)
+
+type substitution struct {
+	old   string // as typed
+	re    *regexp.Regexp
+	with  string
+	all   bool
+	regex bool
+}
+
+type replaceEntry struct {
+	idx   int
+	after string
+	skip  bool
+}
+
+// isSubstitute tells ":s/a/b/" and ":%s/a/b/" from commands like ":sort".
+func isSubstitute(line string) bool {
+	line = strings.TrimPrefix(line, "%")
+	if len(line) < 2 || line[0] != 's' {
+		return false
+	}
+	r := []rune(line)[1]
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r) && r != '\\'
+}
+
+// splitDelimited cuts s at unescaped delimiters; "\d" stands for d.
+func splitDelimited(s string, delim rune) []string {
+	var parts []string
+	var cur strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\' && i+1 < len(runes) && runes[i+1] == delim:
+			cur.WriteRune(delim)
+			i++
+		case runes[i] == delim:
+			parts = append(parts, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteRune(runes[i])
+		}
+	}
+	return append(parts, cur.String())
+}
+
+// parseSubstitute reads "[%]s/old/new/[flags]"; whole is true with "%".
+func parseSubstitute(line string) (sub substitution, whole bool, err error) {
+	line, whole = strings.CutPrefix(line, "%")
+	body := []rune(line[1:])
+	parts := splitDelimited(string(body[1:]), body[0])
+	if len(parts) < 2 || len(parts) > 3 {
+		return sub, whole, fmt.Errorf("expected s%[1]cold%[1]cnew%[1]c[flags]", body[0])
+	}
+	if parts[0] == "" {
+		return sub, whole, errors.New("nothing to replace")
+	}
+	flags := ""
+	if len(parts) == 3 {
+		for _, f := range parts[2] {
+			switch f {
+			case 'g':
+				sub.all = true
+			case 'i':
+				flags = "(?i)"
+			case 'r':
+				sub.regex = true
+			default:
+				return sub, whole, fmt.Errorf("unknown flag %q", f)
+			}
+		}
+	}
+	pattern := regexp.QuoteMeta(parts[0])
+	if sub.regex {
+		pattern = parts[0]
+	}
+	if sub.re, err = regexp.Compile(flags + pattern); err != nil {
+		return sub, whole, err
+	}
+	sub.old, sub.with = parts[0], parts[1]
+	return sub, whole, nil
+}
+
+// matches returns the spans of s to replace with their groups, skipping
+// empty matches, and hidden tokens when protect is set.
+func (sub substitution) matches(s string, protect bool) [][]int {
+	var hidden [][]int
+	if protect {
+		for _, w := range wordSpan.FindAllStringIndex(s, -1) {
+			if key, _, ok := strings.Cut(s[w[0]:w[1]], ":"); ok && model.HiddenMetaKeys[key] {
+				hidden = append(hidden, w)
+			}
+		}
+	}
+	var out [][]int
+	for _, loc := range sub.re.FindAllStringSubmatchIndex(s, -1) {
+		inside := loc[0] == loc[1]
+		for _, h := range hidden {
+			inside = inside || loc[0] < h[1] && loc[1] > h[0]
+		}
+		if inside {
+			continue
+		}
+		out = append(out, loc)
+		if !sub.all {
+			break
+		}
+	}
+	return out
+}
+
+// replacement is the new text for the match at loc.
+func (sub substitution) replacement(s string, loc []int) string {
+	if !sub.regex {
+		return sub.with
+	}
+	return string(sub.re.ExpandString(nil, sub.with, s, loc))
+}
+
+// apply returns s with the substitution made and how many spans changed.
+func (sub substitution) apply(s string) (string, int) {
+	locs := sub.matches(s, true)
+	var b strings.Builder
+	last := 0
+	for _, loc := range locs {
+		b.WriteString(s[last:loc[0]])
+		b.WriteString(sub.replacement(s, loc))
+		last = loc[1]
+	}
+	b.WriteString(s[last:])
+	return b.String(), len(locs)
+}
+
+// openReplace lists the tasks a substitution would change.
+func (m *app) openReplace(line string) {
+	sub, whole, err := parseSubstitute(line)
+	if err != nil {
+		m.status = "Bad substitution: " + err.Error()
+		return
+	}
+	from, to := 0, len(m.items)
+	if !whole {
+		if len(m.visibleItems) == 0 {
+			m.status = "Select a task first"
+			return
+		}
+		from = m.visibleItems[m.cursorMain].Index
+		to = from + 1
+	}
+	var entries []replaceEntry
+	for i := from; i < to; i++ {
+		after, n := sub.apply(m.items[i].Title)
+		if n > 0 && strings.TrimSpace(model.DisplayTitle(after)) != "" {
+			entries = append(entries, replaceEntry{idx: i, after: strings.TrimSpace(after)})
+		}
+	}
+	if len(entries) == 0 {
+		m.status = "Pattern not found: " + sub.old
+		return
+	}
+	m.replace, m.replaceSub, m.cursorReplace = entries, sub, 0
+	m.state = viewReplace
+}
+
+func (m app) updateReplace(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.replace = nil
+		m.state = viewMain
+	case "up", "k":
+		m.cursorReplace = max(0, m.cursorReplace-1)
+	case "down", "j":
+		m.cursorReplace = min(len(m.replace)-1, m.cursorReplace+1)
+	case " ":
+		m.replace[m.cursorReplace].skip = !m.replace[m.cursorReplace].skip
+	case "enter":
+		n := 0
+		for _, e := range m.replace {
+			if e.skip || e.idx >= len(m.items) {
+				continue
+			}
+			m.items[e.idx].Title = e.after
+			m.refreshItem(e.idx)
+			n++
+		}
+		m.replace = nil
+		m.state = viewMain
+		if n > 0 {
+			m.save()
+		}
+		m.status = fmt.Sprintf("Replaced in %d tasks", n)
+	}
+	return m, nil
+}
+
+// highlight renders title with the matched spans in hit, or with the
+// replacements in hit when replaced is set.
+func (sub substitution) highlight(title string, replaced bool, base, hit lipgloss.Style) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range sub.matches(title, false) {
+		b.WriteString(base.Render(title[last:loc[0]]))
+		if replaced {
+			b.WriteString(hit.Render(sub.replacement(title, loc)))
+		} else {
+			b.WriteString(hit.Render(title[loc[0]:loc[1]]))
+		}
+		last = loc[1]
+	}
+	b.WriteString(base.Render(title[last:]))
+	return b.String()
+}
+
+func (m app) renderReplace(height int, t theme.Theme) string {
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+	text := lipgloss.NewStyle().Foreground(t.Text)
+	removed := lipgloss.NewStyle().Foreground(t.Error).Strikethrough(true)
+	added := lipgloss.NewStyle().Foreground(t.Special).Bold(true)
+	width := m.width - 4
+
+	skipped := 0
+	for _, e := range m.replace {
+		if e.skip {
+			skipped++
+		}
+	}
+	head := "  " + lipgloss.NewStyle().Foreground(t.Accent).Bold(true).Render(fmt.Sprintf("%d tasks to change", len(m.replace)-skipped))
+	if skipped > 0 {
+		head += dim.Render(fmt.Sprintf("  (%d skipped)", skipped))
+	}
+	lines := []string{head, ""}
+	cursorLine := 0
+	for i, e := range m.replace {
+		title := model.DisplayTitle(m.items[e.idx].Title)
+		marker := "  "
+		base := text
+		if e.skip {
+			base = dim
+		}
+		if i == m.cursorReplace {
+			marker = " ➤"
+			base = base.Bold(true)
+			cursorLine = len(lines)
+		}
+		before, after := removed, added
+		if e.skip {
+			before, after = dim, dim
+		}
+		lines = append(lines,
+			ansi.Truncate(lipgloss.NewStyle().Foreground(t.Highlight).Render(marker)+" "+m.replaceSub.highlight(title, false, base, before), width, "…"),
+			ansi.Truncate("    "+dim.Render("→ ")+m.replaceSub.highlight(title, true, base, after), width, "…"))
+	}
+
+	start, end := ui.Paginator(cursorLine, height, len(lines))
+	return m.frame(height, t.Highlight).
+		Render(strings.Join(lines[start:end], "\n"))
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestParseSubstitute(t *testing.T) {
+	for _, line := range []string{"sort title", "snoozed", "s", "stats", "s 1"} {
+		if isSubstitute(line) {
+			t.Errorf("%q taken for a substitution", line)
+		}
+	}
+	sub, whole, err := parseSubstitute(`%s#a/b#c\#d#gi`)
+	if err != nil || !whole || !sub.all || sub.old != "a/b" || sub.with != `c#d` {
+		t.Fatalf("sub = %+v, whole %v, err %v", sub, whole, err)
+	}
+	if got, n := sub.apply("A/B and a/b"); got != "c#d and c#d" || n != 2 {
+		t.Errorf("apply = %q, %d", got, n)
+	}
+	sub, _, _ = parseSubstitute("s/a.b/x/")
+	if got, _ := sub.apply("a.b axb a.b"); got != "x axb a.b" {
+		t.Errorf("without g only the first literal match goes: %q", got)
+	}
+	for _, bad := range []string{"s/only", "s//x/", "s/a/b/q", "s/a/b/c/d"} {
+		if _, _, err := parseSubstitute(bad); err == nil {
+			t.Errorf("%q must be refused", bad)
+		}
+	}
+}
+
+func TestReplace(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{width: 100, height: 20, items: []model.Item{
+		{Title: "Acme launch #acme id:acme1"},
+		{Title: "Call Acme about acme invoices", Level: 1},
+		{Title: "Unrelated"},
+		{Title: "Acme"},
+	}}
+	m.recalcVisible()
+	m.runCommand("%s/acme/Globex/gi")
+	if m.state != viewReplace || len(m.replace) != 3 {
+		t.Fatalf("state %v, entries %+v", m.state, m.replace)
+	}
+	view := m.View()
+	for _, want := range []string{"REPLACE", "3 tasks to change", "→ Globex launch #Globex"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("preview lacks %q:\n%s", want, view)
+		}
+	}
+
+	next, _ := m.Update(keyMsg("down"))
+	next, _ = next.(app).Update(keyMsg(" "))
+	next, _ = next.(app).Update(keyMsg("enter"))
+	m = next.(app)
+	// id:acme1 zostaje – ukryte tokeny są chronione
+	if m.items[0].Title != "Globex launch #Globex id:acme1" || m.items[1].Title != "Call Acme about acme invoices" || m.items[3].Title != "Globex" || m.status != "Replaced in 2 tasks" || !m.dirty {
+		t.Errorf("items = %q, status %q", flat(m.items), m.status)
+	}
+
+	m.runCommand("%s/Globex//")
+	if m.state != viewReplace || len(m.replace) != 1 {
+		t.Errorf("a title left empty is not offered: %+v", m.replace)
+	}
+	m.state = viewMain
+
+	m.jumpTo(2)
+	m.runCommand("s/acme/x/")
+	if m.state != viewMain || m.status != "Pattern not found: acme" {
+		t.Errorf(":s only looks at the selected task: %v %q", m.state, m.status)
+	}
+}
+
+func TestReplaceRegex(t *testing.T) {
+	sub, _, err := parseSubstitute(`%s/(\w+)@(\w+)/$2:$1/gr`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, n := sub.apply("mail bob@acme and ann@corp"); got != "mail acme:bob and corp:ann" || n != 2 {
+		t.Errorf("apply = %q, %d", got, n)
+	}
+	sub, _, _ = parseSubstitute(`s/x*/-/gr`)
+	if got, n := sub.apply("axb"); got != "a-b" || n != 1 {
+		t.Errorf("empty matches must be skipped: %q, %d", got, n)
+	}
+
+	m := app{items: []model.Item{{Title: "a"}}}
+	m.recalcVisible()
+	m.runCommand("%s/(a/b/r")
+	if m.state != viewMain || !strings.HasPrefix(m.status, "Bad substitution: error parsing regexp") {
+		t.Errorf("status = %q", m.status)
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- WEEKLY REVIEW ---
+//
+// :review walks through the open tasks one at a time, oldest first. Each
+// is kept (Enter), completed (space), rescheduled (r), deleted (d) or moved
+// (m), and every one of those stamps it with a hidden reviewed:<date>. Tasks
+// reviewed in the last reviewPeriod days are done for this round, so a
+// review can be left and picked up later; s skips a task until the end of
+// the queue.
+
+const reviewPeriod = 7 // days
+
+// reviewedRecently reports whether the task was reviewed in this round.
+func reviewedRecently(it model.Item, now time.Time) bool {
+	d, err := time.ParseInLocation(model.DateLayout, model.MetaValue(it.Title, "reviewed"), now.Location())
+	return err == nil && model.StartOfDay(now).Sub(d) < reviewPeriod*24*time.Hour
+}
+
+// reviewQueue returns the open tasks still to review, oldest first (tasks
+// without a creation date count as the oldest), the skipped ones last.
+func reviewQueue(items []model.Item, skipped map[string]bool, now time.Time) []int {
+	var queue []int
+	for i, it := range items {
+		if !it.Done && it.Heading == 0 && !reviewedRecently(it, now) {
+			queue = append(queue, i)
+		}
+	}
+	slices.SortStableFunc(queue, func(a, b int) int {
+		sa, sb := skipped[sessionKey(items[a])], skipped[sessionKey(items[b])]
+		if sa != sb {
+			if sa {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Compare(model.MetaValue(items[a].Title, "created"), model.MetaValue(items[b].Title, "created"))
+	})
+	return queue
+}
+
+// reviewProgress counts the open tasks reviewed in this round.
+func reviewProgress(items []model.Item, now time.Time) (reviewed, open int) {
+	for _, it := range items {
+		if it.Done {
+			continue
+		}
+		open++
+		if reviewedRecently(it, now) {
+			reviewed++
+		}
+	}
+	return reviewed, open
+}
+
+func (m *app) openReview() {
+	m.reviewSkipped = map[string]bool{}
+	m.state = viewReview
+	if len(reviewQueue(m.items, nil, time.Now())) == 0 {
+		m.status = "Nothing left to review this week"
+	}
+}
+
+// markReviewed stamps items[idx] with today's date.
+func (m *app) markReviewed(idx int) {
+	m.items[idx].Title = model.SetMeta(m.items[idx].Title, "reviewed", time.Now().Format(model.DateLayout))
+	m.refreshItem(idx)
+	m.save()
+}
+
+func (m app) updateReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == "esc" {
+		m.state = viewMain
+		return m, nil
+	}
+	queue := reviewQueue(m.items, m.reviewSkipped, time.Now())
+	if len(queue) == 0 {
+		return m, nil
+	}
+	idx := queue[0]
+	switch key {
+	case "enter":
+		m.markReviewed(idx)
+	case " ":
+		m.markReviewed(idx)
+		m.toggleDone(idx)
+	case "r":
+		m.markReviewed(idx)
+		m.openDatePopup(idx, "due", "Reschedule")
+	case "d":
+		m.items, m.trash = model.DeleteSubtree(m.items, m.trash, idx)
+		m.recalcVisible()
+		m.save()
+	case "m":
+		m.markReviewed(idx)
+		m.state = viewMain
+		m.jumpTo(idx)
+		m.startMove(idx)
+		m.reviewMove = true
+	case "s":
+		m.reviewSkipped[sessionKey(m.items[idx])] = true
+	}
+	return m, nil
+}
+
+// backToReview returns to the review after a move started there.
+func (m *app) backToReview() {
+	if m.reviewMove {
+		m.reviewMove = false
+		m.state = viewReview
+	}
+}
+
+func (m app) renderReview(height int, t theme.Theme) string {
+	now := time.Now()
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+	label := dim.Width(12)
+	text := lipgloss.NewStyle().Foreground(t.Text)
+
+	reviewed, open := reviewProgress(m.items, now)
+	barW := max(10, min(40, m.width-30))
+	filled := 0
+	if open > 0 {
+		filled = barW * reviewed / open
+	}
+	lines := []string{
+		"  " + lipgloss.NewStyle().Foreground(t.Special).Render(strings.Repeat("█", filled)) +
+			dim.Render(strings.Repeat("░", barW-filled)+" "+i18n.Tf("%d of %d reviewed", reviewed, open)),
+		"",
+	}
+
+	queue := reviewQueue(m.items, m.reviewSkipped, now)
+	if len(queue) == 0 {
+		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(t.Special).Bold(true).Render(i18n.T("All caught up for this week")))
+		return m.frame(height, t.Highlight).Render(strings.Join(lines, "\n"))
+	}
+	it := m.items[queue[0]]
+
+	var path []string
+	for p := model.ParentIndex(m.items, queue[0]); p != -1; p = model.ParentIndex(m.items, p) {
+		path = append([]string{mdPlain(model.DisplayTitle(m.items[p].Title))}, path...)
+	}
+	if len(path) > 0 {
+		lines = append(lines, "  "+dim.Render(strings.Join(path, " › ")))
+	}
+	lines = append(lines, "  "+lipgloss.NewStyle().Foreground(t.Highlight).Bold(true).Width(max(10, m.width-6)).
+		Render(mdPlain(model.DisplayTitle(it.Title))), "")
+
+	last := model.MetaValue(it.Title, "reviewed")
+	if last == "" {
+		last = i18n.T("never")
+	}
+	info := append(m.detailInfo(it), [2]string{"Reviewed", last})
+	for _, r := range info {
+		if r[1] != "" && r[0] != "ID" {
+			lines = append(lines, "  "+label.Render(i18n.T(r[0]))+text.Render(r[1]))
+		}
+	}
+	if n := model.SubtreeEnd(m.items, queue[0]) - queue[0] - 1; n > 0 {
+		lines = append(lines, "  "+label.Render(i18n.T("Subtasks"))+text.Render(fmt.Sprint(n)))
+	}
+	for i, line := range it.Note {
+		if i == 3 {
+			lines = append(lines, "  "+dim.Render("…"))
+			break
+		}
+		lines = append(lines, "  "+dim.Render(line))
+	}
+	if len(queue) > 1 {
+		lines = append(lines, "", "  "+dim.Render(i18n.Tf("%d more to go", len(queue)-1)))
+	}
+	return m.frame(height, t.Highlight).Render(strings.Join(lines[:min(len(lines), height)], "\n"))
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestWeeklyReview(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	now := time.Now()
+	old := now.AddDate(0, 0, -reviewPeriod-1).Format(model.DateLayout)
+	m := app{filename: t.TempDir() + "/todo.md", width: 80, height: 30, items: []model.Item{
+		{Title: "newest created:2026-10-01"},
+		{Title: "oldest created:2025-01-01 reviewed:" + old},
+		{Title: "finished", Done: true},
+		{Title: "seen reviewed:" + now.Format(model.DateLayout)},
+		{Title: "middle created:2026-01-01"},
+		{Title: "child", Level: 1},
+	}}
+	m.recalcVisible()
+	m.openReview()
+
+	title := func() string {
+		q := reviewQueue(m.items, m.reviewSkipped, now)
+		if len(q) == 0 {
+			return ""
+		}
+		return model.DisplayTitle(m.items[q[0]].Title)
+	}
+	var got []string
+	for _, idx := range reviewQueue(m.items, nil, now) {
+		got = append(got, strings.Fields(m.items[idx].Title)[0])
+	}
+	if want := []string{"child", "oldest", "middle", "newest"}; !slices.Equal(got, want) {
+		t.Fatalf("queue = %q", got)
+	}
+
+	send := func(k string) {
+		next, _ := m.Update(keyMsg(k))
+		m = next.(app)
+	}
+	send("s")
+	if title() != "oldest" {
+		t.Errorf("after skip = %q", title())
+	}
+	send("enter")
+	if r, open := reviewProgress(m.items, now); r != 2 || open != 5 {
+		t.Errorf("progress = %d/%d", r, open)
+	}
+	send(" ")
+	if !m.items[4].Done || !reviewedRecently(m.items[4], now) {
+		t.Errorf("completed = %+v", m.items[4])
+	}
+	send("d")
+	if len(m.trash) != 1 || !strings.HasPrefix(m.trash[0].Title, "newest") {
+		t.Errorf("bin = %+v", m.trash)
+	}
+	if title() != "child" {
+		t.Errorf("skipped task must come back last, got %q", title())
+	}
+	send("enter")
+	if title() != "" || !strings.Contains(m.View(), "All caught up") {
+		t.Errorf("review not finished: %q", title())
+	}
+	send("esc")
+	if m.state != viewMain {
+		t.Errorf("state = %v", m.state)
+	}
+}
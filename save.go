@@ -0,0 +1,286 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- SAVING ---
+//
+// Edits only mark the list dirty. The file is rewritten from a tea.Cmd at most
+// every saveDelay, so slow disks and network mounts never block a keypress,
+// and once more on quit. Until then the change waits in the recovery
+// journal (see journal.go).
+
+const saveDelay = 500 * time.Millisecond
+
+type saveTickMsg struct{}
+
+type savedMsg struct {
+	mod    time.Time
+	gen    int
+	merged bool // the file had changed outside the app (see listWriter)
+	err    error
+}
+
+// listWriter serializes writes of one file. Every snapshot carries a
+// generation, and an older snapshot never overwrites a newer one.
+//
+// A file changed by someone else since the app read it (todo add, capture,
+// serve, another editor) isn't overwritten: the write merges the app's
+// changes with theirs against base, the lists as last read or written, and
+// the app reloads the result once nothing holds it back (see watch.go).
+type listWriter struct {
+	mu  sync.Mutex
+	gen int
+	// writeThrough rewrites the file in place for sync clients (see cloudSafe)
+	writeThrough bool
+	// workspace, when set, writes its files instead (see workspace.go)
+	workspace *workspace
+
+	base, baseTrash []model.Item
+	based           bool
+}
+
+// loaded records the lists just read from the file as the base of merges.
+func (w *listWriter) loaded(items, trash []model.Item) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.base, w.baseTrash, w.based = slices.Clone(items), slices.Clone(trash), true
+}
+
+func (w *listWriter) write(filename string, items, trash []model.Item, gen int) (merged bool, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if gen < w.gen {
+		return false, nil
+	}
+	if w.workspace != nil {
+		if merged, err = w.workspace.save(items, trash); err != nil {
+			return false, err
+		}
+	} else {
+		ours, oursTrash := items, trash
+		err = withWriteLock(filename, func() error {
+			items, trash, merged = mergeOnDisk(filename, w.base, w.baseTrash, items, trash, w.based)
+			return saveList(filename, items, trash, w.writeThrough)
+		})
+		if err != nil {
+			return false, err
+		}
+		recordHistory(filename, items, time.Now())
+		// Następny zapis scala względem tego, co wysłała aplikacja
+		w.base, w.baseTrash, w.based = slices.Clone(ours), slices.Clone(oursTrash), true
+	}
+	w.gen = gen
+	return merged, nil
+}
+
+// mergeOnDisk merges items and trash with the file's lists when they are no
+// longer base. Called with the write lock held.
+func mergeOnDisk(filename string, base, baseTrash, items, trash []model.Item, based bool) ([]model.Item, []model.Item, bool) {
+	if _, err := os.Stat(filename); !based || err != nil {
+		return items, trash, false // nowy albo skasowany plik zapisujemy od zera
+	}
+	disk, diskTrash, err := storage.Load(filename)
+	if err != nil || (sameList(disk, base) && sameList(diskTrash, baseTrash)) {
+		return items, trash, false
+	}
+	return model.Merge(base, items, disk), model.Merge(baseTrash, trash, diskTrash), true
+}
+
+// modTime is when the list was last modified on disk.
+func (w *listWriter) modTime(filename string) time.Time {
+	if w != nil && w.workspace != nil {
+		return w.workspace.modTime()
+	}
+	return fileModTime(filename)
+}
+
+// save records a change to be written shortly.
+func (m *app) save() {
+	if m.demo {
+		return // --demo niczego nie zapisuje
+	}
+	if m.readOnly {
+		// Inna instancja trzyma blokadę – wracamy do stanu z dysku
+		m.reload()
+		m.status = "Read-only: change discarded"
+		return
+	}
+	if m.workspace != nil {
+		m.tagWorkspaceBin()
+	}
+	if trash := m.config.capBin(m.trash); len(trash) < len(m.trash) {
+		m.trash = trash
+		m.cursorTrash = min(m.cursorTrash, max(0, len(trash)-1))
+	}
+	m.dirty = true
+	m.saveErr = nil
+	m.saveGen++
+	m.journalChange()
+}
+
+// scheduleSave starts the debounce timer when there is something to write
+// and no write is already queued or running. After a failed write it waits
+// for the next change (or quit) before trying again.
+func (m *app) scheduleSave() tea.Cmd {
+	if !m.dirty || m.saveQueued || m.saving || m.saveErr != nil {
+		return nil
+	}
+	m.saveQueued = true
+	return tea.Tick(saveDelay, func(time.Time) tea.Msg { return saveTickMsg{} })
+}
+
+// writeAsync hands a copy of the lists to a background write.
+func (m *app) writeAsync() tea.Cmd {
+	m.saveQueued = false
+	if !m.dirty {
+		return nil
+	}
+	if m.writer == nil {
+		m.writer = &listWriter{}
+	}
+	m.autoSort()
+	m.dirty, m.saving = false, true
+	w, filename, gen := m.writer, m.filename, m.saveGen
+	items, trash := slices.Clone(m.items), slices.Clone(m.trash)
+	return func() tea.Msg {
+		merged, err := w.write(filename, items, trash, gen)
+		return savedMsg{mod: w.modTime(filename), gen: gen, merged: merged, err: err}
+	}
+}
+
+// autoSort applies "autosort" from config.json. It runs when a save is
+// written rather than in save(), whose callers still hold indices, and
+// waits for a later save while a view or editor holds some.
+func (m *app) autoSort() {
+	if m.config.AutoSort == "" || m.holdsIndices() {
+		return
+	}
+	m.sortItems(m.config.AutoSort)
+}
+
+func (m *app) handleSaved(msg savedMsg) {
+	m.saving = false
+	if msg.err != nil {
+		// Zmiany zostają w pamięci i trafią na dysk przy następnej próbie
+		m.dirty = true
+		m.saveErr = msg.err
+		m.status = "Save failed: " + msg.err.Error()
+		return
+	}
+	if msg.merged {
+		// Stary czas pliku: watcher wczyta scaloną listę, gdy będzie można
+		m.status = "Merged with changes made to " + filepath.Base(m.filename) + " outside the app"
+	} else {
+		m.fileModTime = msg.mod
+	}
+	if msg.gen == m.saveGen {
+		m.dropJournal(false) // nowszych zmian nie ma
+	}
+	m.fireHook("save", -1)
+}
+
+// flushSave writes pending changes synchronously; used on quit.
+func (m *app) flushSave() error {
+	if !m.dirty {
+		return nil
+	}
+	if m.writer == nil {
+		m.writer = &listWriter{}
+	}
+	items := m.items
+	if less, ok := sortKeys[m.config.AutoSort]; ok {
+		// Sortujemy tylko kopię na dysk – indeksy w m.items zostają ważne
+		items = permute(items, sortTree(items, less))
+	}
+	if _, err := m.writer.write(m.filename, items, m.trash, m.saveGen); err != nil {
+		return err
+	}
+	m.dirty = false
+	m.fileModTime = m.writer.modTime(m.filename)
+	m.dropJournal(true)
+	return nil
+}
+
+func (m app) saveIndicator(t theme.Theme) string {
+	switch {
+	case m.saveErr != nil:
+		return lipgloss.NewStyle().Foreground(t.Error).Render(" • ⚠ not saved")
+	case m.saving:
+		return lipgloss.NewStyle().Foreground(t.Comment).Render(" • saving…")
+	case m.dirty:
+		return lipgloss.NewStyle().Foreground(t.Accent).Render(" • unsaved changes")
+	}
+	return ""
+}
+
+// cloudSafe tells whether to save for a sync client: the atomic rename is
+// seen as delete+create by iCloud/Drive/Dropbox and leads to conflict copies.
+func (c Config) cloudSafe(filename string) bool {
+	switch c.CloudSave {
+	case "on":
+		return true
+	case "off":
+		return false
+	}
+	return storage.InCloudFolder(filename)
+}
+
+func saveList(filename string, items, trash []model.Item, writeThrough bool) error {
+	if writeThrough {
+		return storage.SaveWriteThrough(filename, items, trash)
+	}
+	return storage.Save(filename, items, trash)
+}
+
+// checkConflicts reports conflict copies left by a sync client next to the
+// list, once per set of files.
+func (m *app) checkConflicts() {
+	if !m.config.cloudSafe(m.filename) {
+		return
+	}
+	copies, err := storage.ConflictCopies(m.filename)
+	if err != nil || len(copies) == 0 {
+		m.conflicts = ""
+		return
+	}
+	seen := strings.Join(copies, "\n")
+	if seen == m.conflicts {
+		return
+	}
+	m.conflicts = seen
+	m.showError("Sync conflict copies found", seen+"\nMerge what you need into the list and delete them.")
+}
+
+// --- IO ERRORS ---
+
+// showError opens a modal that any key dismisses.
+func (m *app) showError(title, body string) {
+	m.errTitle, m.errBody = title, body
+}
+
+func (m app) renderError(t theme.Theme) string {
+	title := lipgloss.NewStyle().Foreground(t.Error).Bold(true).Render(m.errTitle)
+	body := lipgloss.NewStyle().Foreground(t.Text).Render(m.errBody)
+	keys := lipgloss.NewStyle().Foreground(t.Comment).Render("any key: close")
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Error).
+		Padding(0, 1).
+		Render(title + "\n" + body + "\n\n" + keys)
+}
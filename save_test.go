@@ -0,0 +1,185 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestSaveIsDebounced(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	f := filepath.Join(t.TempDir(), "todo.md")
+	os.WriteFile(f, []byte("- [ ] a\n"), 0644)
+	m := initialModel(f)
+	defer m.lock.release()
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	m = next.(app)
+	if data, _ := os.ReadFile(f); string(data) != "- [ ] a\n" {
+		t.Fatalf("file written synchronously: %q", data)
+	}
+	if !m.dirty || cmd == nil {
+		t.Fatalf("dirty = %v, cmd = %v; want a scheduled save", m.dirty, cmd)
+	}
+
+	next, cmd = m.Update(saveTickMsg{})
+	m = next.(app)
+	if !m.saving || cmd == nil {
+		t.Fatal("tick did not start a write")
+	}
+	next, _ = m.Update(cmd())
+	m = next.(app)
+	if m.saving || m.dirty {
+		t.Errorf("saving = %v, dirty = %v after write", m.saving, m.dirty)
+	}
+	if data, _ := os.ReadFile(f); string(data) != "- [x] a\n" {
+		t.Errorf("file = %q", data)
+	}
+}
+
+func TestListWriterKeepsNewest(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	f := filepath.Join(t.TempDir(), "todo.md")
+	w := &listWriter{}
+	w.write(f, []model.Item{{Title: "new"}}, nil, 2)
+	w.write(f, []model.Item{{Title: "old"}}, nil, 1)
+	if data, _ := os.ReadFile(f); !strings.Contains(string(data), "new") {
+		t.Errorf("stale snapshot overwrote the file: %q", data)
+	}
+}
+
+func TestFlushSaveOnQuit(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	f := filepath.Join(t.TempDir(), "todo.md")
+	m := app{filename: f, items: []model.Item{{Title: "a"}}}
+	m.save()
+	m.flushSave()
+	if data, _ := os.ReadFile(f); string(data) != "- [ ] a\n" || m.dirty {
+		t.Errorf("file = %q, dirty = %v", data, m.dirty)
+	}
+}
+
+func TestUnreadableFileOpensReadOnly(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir() // katalog zamiast pliku: otwiera się, ale nie czyta
+	m := initialModel(dir)
+	defer m.lock.release()
+	if !m.readOnly || m.errTitle == "" {
+		t.Fatalf("readOnly = %v, errTitle = %q", m.readOnly, m.errTitle)
+	}
+	if m.save(); m.dirty {
+		t.Error("read-only list scheduled a save")
+	}
+}
+
+func TestSaveFailureIsReported(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "gone", "todo.md")
+	m := app{filename: f, items: []model.Item{{Title: "a"}}}
+	m.save()
+	cmd := m.writeAsync()
+	m.handleSaved(cmd().(savedMsg))
+	if m.saveErr == nil || !m.dirty || !strings.HasPrefix(m.status, "Save failed") {
+		t.Fatalf("saveErr = %v, dirty = %v, status = %q", m.saveErr, m.dirty, m.status)
+	}
+	if m.scheduleSave() != nil {
+		t.Error("failed save retried without a new change")
+	}
+	if m.flushSave() == nil {
+		t.Error("flushSave hid the error")
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Chdir(t.TempDir())
+	if _, err := loadConfig(); err != nil {
+		t.Fatalf("missing config: %v", err)
+	}
+	os.MkdirAll(filepath.Join(dir, appName), 0755)
+	os.WriteFile(filepath.Join(dir, appName, configFile), []byte("{broken"), 0644)
+	if _, err := loadConfig(); err == nil {
+		t.Error("invalid config accepted")
+	}
+}
+
+func TestCloudFolderConflicts(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := filepath.Join(t.TempDir(), "Dropbox")
+	os.Mkdir(dir, 0755)
+	f := filepath.Join(dir, "todo.md")
+	os.WriteFile(f, []byte("- [ ] a\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "todo (conflicted copy 2026-01-02).md"), nil, 0644)
+
+	m := initialModel(f)
+	defer m.lock.release()
+	if !m.writer.writeThrough {
+		t.Error("a Dropbox folder is not saved write-through")
+	}
+	if !strings.Contains(m.errBody, "conflicted copy") {
+		t.Fatalf("conflict copy not reported: %q", m.errBody)
+	}
+	m.errTitle, m.errBody = "", ""
+	m.checkConflicts()
+	if m.errTitle != "" {
+		t.Error("the same conflict copy was reported twice")
+	}
+
+	m.config.CloudSave = "off"
+	if m.config.cloudSafe(f) {
+		t.Error(`cloud_save "off" ignored`)
+	}
+}
+
+func TestBinLimitAndEmpty(t *testing.T) {
+	m := app{config: Config{BinLimit: 3}, trash: []model.Item{
+		{Title: "old"}, {Title: "old child", Level: 1}, {Title: "mid"}, {Title: "new"}, {Title: "newest"},
+	}}
+	m.state = viewTrash
+	m.save()
+	if len(m.trash) != 3 || m.trash[0].Title != "mid" {
+		t.Errorf("trash = %+v", m.trash)
+	}
+
+	next, _ := m.update(keyMsg("X"))
+	m = next.(app)
+	next, _ = m.update(keyMsg("n"))
+	m = next.(app)
+	if len(m.trash) != 3 || m.confirmEmpty {
+		t.Fatal("n did not cancel emptying the bin")
+	}
+	next, _ = m.update(keyMsg("X"))
+	next, _ = next.(app).update(keyMsg("y"))
+	if m = next.(app); len(m.trash) != 0 {
+		t.Errorf("bin not emptied: %+v", m.trash)
+	}
+}
+
+func TestAutoSortKeepsHeldIndices(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	f := filepath.Join(t.TempDir(), "todo.md")
+	m := app{filename: f, config: Config{AutoSort: "title"}, items: []model.Item{{Title: "b"}, {Title: "c"}}}
+	m.recalcVisible()
+	m.detailIdx, m.state = 1, viewDetail
+	m.items[1].Title = "a"
+	m.save()
+	if m.items[m.detailIdx].Title != "a" {
+		t.Fatal("save() must not reorder the list")
+	}
+	m.writeAsync()()
+	if m.items[m.detailIdx].Title != "a" {
+		t.Error("the detail view must keep its task until it is closed")
+	}
+
+	m.state = viewMain
+	m.save()
+	m.saving = false
+	m.writeAsync()()
+	if got := flat(m.items); got[0] != "0:a:false" {
+		t.Errorf("not sorted on the next write: %q", got)
+	}
+}
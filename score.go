@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- SCORE & SMART ORDER ---
+//
+// Every open task gets a score: the weighted sum of its priority (A 1, B ⅔,
+// C ⅓), how close its due date is (1 when due today or overdue, fading to 0
+// two weeks out), its age (1 after a month) and whether it is starred ("*"
+// toggles star:1). ":smart" lists the actionable tasks — open, not snoozed,
+// not blocked and without open subtasks — best first, without touching the
+// file order. The weights are set in config.json:
+//
+//	"score_weights": {"priority": 3, "due": 4, "age": 1, "starred": 2}
+//
+// Weights left out keep these defaults; 0 ignores a factor.
+
+var defaultScoreWeights = map[string]float64{"priority": 3, "due": 4, "age": 1, "starred": 2}
+
+const (
+	dueHorizonDays = 14
+	ageFullDays    = 30
+)
+
+func (c Config) scoreWeight(factor string) float64 {
+	if w, ok := c.ScoreWeights[factor]; ok {
+		return w
+	}
+	return defaultScoreWeights[factor]
+}
+
+func isStarred(it model.Item) bool {
+	return model.MetaValue(it.Title, "star") != ""
+}
+
+func (m *app) toggleStar(idx int) {
+	value := "1"
+	if isStarred(m.items[idx]) {
+		value = ""
+	}
+	m.items[idx].Title = model.SetMeta(m.items[idx].Title, "star", value)
+	m.refreshItem(idx)
+	m.save()
+}
+
+// scoreFactors returns the unweighted factors of a task, each within 0..1.
+func scoreFactors(it model.Item, now time.Time) map[string]float64 {
+	f := map[string]float64{}
+	if p := model.MetaValue(it.Title, "pri"); p != "" {
+		for i, level := range priorityLevels[1:] {
+			if p == level {
+				f["priority"] = float64(len(priorityLevels)-1-i) / float64(len(priorityLevels)-1)
+			}
+		}
+	}
+	today := model.StartOfDay(now)
+	if due, _, ok := model.DueTime(it.Title); ok {
+		days := model.StartOfDay(due).Sub(today).Hours() / 24
+		f["due"] = math.Max(0, math.Min(1, 1-days/dueHorizonDays))
+	}
+	if created, err := time.ParseInLocation(model.DateLayout, model.MetaValue(it.Title, "created"), time.Local); err == nil {
+		f["age"] = math.Max(0, math.Min(1, today.Sub(created).Hours()/24/ageFullDays))
+	}
+	if isStarred(it) {
+		f["starred"] = 1
+	}
+	return f
+}
+
+func taskScore(it model.Item, cfg Config, now time.Time) float64 {
+	score := 0.0
+	for factor, v := range scoreFactors(it, now) {
+		score += cfg.scoreWeight(factor) * v
+	}
+	return score
+}
+
+// actionable reports whether items[idx] can be worked on right now.
+func actionable(items []model.Item, idx int, today string) bool {
+	it := items[idx]
+	if it.Done || it.Heading > 0 || isSnoozed(it.Title, today) || isBlocked(items, idx) {
+		return false
+	}
+	for i := idx + 1; i < model.SubtreeEnd(items, idx); i++ {
+		if !items[i].Done {
+			return false
+		}
+	}
+	return true
+}
+
+type scoredTask struct {
+	idx   int
+	score float64
+}
+
+// smartOrder returns the actionable picked tasks, highest score first; ties
+// keep the file order.
+func smartOrder(items []model.Item, pick []bool, cfg Config, now time.Time) []scoredTask {
+	today := now.Format(model.DateLayout)
+	var out []scoredTask
+	for i := range items {
+		if pick[i] && actionable(items, i, today) {
+			out = append(out, scoredTask{i, taskScore(items[i], cfg, now)})
+		}
+	}
+	sort.SliceStable(out, func(a, b int) bool { return out[a].score > out[b].score })
+	return out
+}
+
+func (m *app) smartRows(now time.Time) []scoredTask {
+	return smartOrder(m.items, m.groupPick(now), m.config, now)
+}
+
+func (m app) updateSmart(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows := m.smartRows(time.Now())
+	m.cursorSmart = min(m.cursorSmart, max(0, len(rows)-1))
+	switch msg.String() {
+	case "esc":
+		m.state = viewMain
+	case "up", "k":
+		if m.cursorSmart > 0 {
+			m.cursorSmart--
+		}
+	case "down", "j":
+		if m.cursorSmart < len(rows)-1 {
+			m.cursorSmart++
+		}
+	case "enter":
+		if len(rows) > 0 {
+			m.state = viewMain
+			m.jumpTo(rows[m.cursorSmart].idx)
+		}
+	case " ":
+		if len(rows) > 0 {
+			m.toggleDone(rows[m.cursorSmart].idx)
+		}
+	case "*":
+		if len(rows) > 0 {
+			m.toggleStar(rows[m.cursorSmart].idx)
+		}
+	}
+	return m, nil
+}
+
+func (m app) renderSmart(height int, t theme.Theme) string {
+	rows := m.smartRows(time.Now())
+	cursor := min(m.cursorSmart, max(0, len(rows)-1))
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+
+	var lines []string
+	for i, r := range rows {
+		it := m.items[r.idx]
+		marker := "  "
+		style := lipgloss.NewStyle().Foreground(t.Text)
+		if i == cursor {
+			marker = " ➤"
+			style = style.Foreground(t.Highlight).Bold(true)
+		}
+		title := model.DisplayTitle(it.Title)
+		if isStarred(it) {
+			title = "★ " + title
+		}
+		line := lipgloss.NewStyle().Foreground(t.Highlight).Render(marker) + " " +
+			lipgloss.NewStyle().Foreground(t.Accent).Render(fmt.Sprintf("%5.1f", r.score)) + "  " + style.Render(title)
+		if path := ancestorTitles(m.items, r.idx); len(path) > 1 {
+			line += dim.Render("  ‹ " + strings.Join(path[:len(path)-1], " > "))
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, dim.Render("  Nothing actionable — everything is done, snoozed or blocked"))
+	}
+
+	start, end := ui.Paginator(cursor, height, len(lines))
+	return m.frame(height, t.Highlight).
+		Render(strings.Join(lines[start:end], "\n"))
+}
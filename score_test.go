@@ -0,0 +1,47 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestSmartOrder(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+	items := []model.Item{
+		{Title: "someday"},
+		{Title: "due tomorrow due:2026-10-17"},
+		{Title: "urgent pri:A"},
+		{Title: "starred star:1"},
+		{Title: "overdue due:2026-10-01 snooze:2026-10-20"},
+		{Title: "project pri:A"},
+		{Title: "step created:2026-09-01", Level: 1},
+		{Title: "waits blocked:x pri:A"},
+		{Title: "prerequisite id:x"},
+		{Title: "finished pri:A", Done: true},
+	}
+	pick := make([]bool, len(items))
+	for i := range pick {
+		pick[i] = true
+	}
+	titles := func(cfg Config) []string {
+		var out []string
+		for _, r := range smartOrder(items, pick, cfg, now) {
+			out = append(out, linkName(items[r.idx].Title))
+		}
+		return out
+	}
+
+	// due 3.7, pri 3, star 2, age 1; snoozed, blocked, done and parents are left out
+	want := []string{"due tomorrow", "urgent", "starred", "step", "someday", "prerequisite"}
+	if got := titles(Config{}); !slices.Equal(got, want) {
+		t.Errorf("default order = %v, want %v", got, want)
+	}
+
+	want = []string{"starred", "step", "due tomorrow", "urgent", "someday", "prerequisite"}
+	if got := titles(Config{ScoreWeights: map[string]float64{"starred": 10, "due": 0.5, "priority": 0.1}}); !slices.Equal(got, want) {
+		t.Errorf("weighted order = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSections(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	f := filepath.Join(t.TempDir(), "todo.md")
+	os.WriteFile(f, []byte("# Work\n- [ ] report\n- [x] mail\n## Later\n- [ ] slides\n\n# Home\n- [ ] dishes\n"), 0644)
+	m := initialModel(f)
+	defer m.lock.release()
+	m.width, m.height = 80, 20
+
+	if c := countTasks(m.items, time.Now()); c.open != 3 || c.done != 1 {
+		t.Errorf("counts = %+v, headings must not count", c)
+	}
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "▾ Work") || strings.Contains(view, "[ ] Work") {
+		t.Errorf("heading not drawn as a section:\n%s", view)
+	}
+
+	next, _ := m.Update(keyMsg(" "))
+	m = next.(app)
+	if m.items[0].Done || !strings.Contains(m.status, "heading") {
+		t.Fatalf("space checked off a heading: %q", m.status)
+	}
+
+	next, _ = m.Update(keyMsg("v"))
+	m = next.(app)
+	if len(m.visibleItems) != 3 {
+		t.Fatalf("folded Work shows %d rows", len(m.visibleItems))
+	}
+	if !strings.Contains(ansi.Strip(m.View()), "▸ Work") {
+		t.Error("a folded section shows ▸")
+	}
+
+	m.jumpTo(6)
+	next, _ = m.Update(keyMsg(" "))
+	m = next.(app)
+	if err := m.flushSave(); err != nil {
+		t.Fatal(err)
+	}
+	want := "# Work\n- [ ] report\n- [x] mail\n## Later\n- [ ] slides\n\n# Home\n- [x] dishes\n"
+	if data, _ := os.ReadFile(f); string(data) != want {
+		t.Errorf("saved:\n%s", data)
+	}
+}
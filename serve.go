@@ -0,0 +1,446 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+)
+
+// --- HTTP API (todo serve) ---
+//
+// Every request loads the file, applies the change and saves it back while
+// holding the server lock, so the TUI (watching the file) picks it up. Reads
+// only load it: tasks without an id get one derived from their place and
+// title, which the first write then stores, so ids from a GET stay valid.
+// It listens on localhost unless --addr says otherwise, and refuses other
+// addresses without --token or --user unless --insecure is given.
+
+type apiTask struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Done   bool   `json:"done"`
+	Level  int    `json:"level"`
+	Parent string `json:"parent,omitempty"`
+	By     string `json:"by,omitempty"`
+}
+
+type apiServer struct {
+	mu       sync.Mutex
+	filename string
+	guard    deleteGuard
+	config   Config
+}
+
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:8080", "listen address")
+	token := fs.String("token", os.Getenv("TODO_SERVE_TOKEN"), "require this bearer token (or ?token=)")
+	user := fs.String("user", "", "require basic auth with this user (password from TODO_SERVE_PASSWORD)")
+	usersFile := fs.String("users", "", "basic auth users file with name:password lines")
+	certFile := fs.String("tls-cert", "", "TLS certificate file")
+	keyFile := fs.String("tls-key", "", "TLS key file")
+	selfSigned := fs.Bool("tls-self-signed", false, "serve TLS with a generated self-signed certificate")
+	insecure := fs.Bool("insecure", false, "allow a non-local --addr without --token or --user")
+	fs.Parse(args)
+
+	filename := "todo.md"
+	if fs.NArg() > 0 {
+		filename = fs.Arg(0)
+	}
+
+	auth := serveAuth{token: *token, users: make(map[string]string)}
+	if *user != "" {
+		password := os.Getenv("TODO_SERVE_PASSWORD")
+		if password == "" {
+			fmt.Fprintln(os.Stderr, "Error: --user needs TODO_SERVE_PASSWORD")
+			os.Exit(1)
+		}
+		auth.users[*user] = password
+	}
+	if *usersFile != "" {
+		if err := loadUsers(*usersFile, auth.users); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if !auth.enabled() && !isLoopback(*addr) {
+		if !*insecure {
+			fmt.Fprintf(os.Stderr, "Error: %s would be reachable from the network without auth; use --token or --user (or --insecure)\n", *addr)
+			os.Exit(1)
+		}
+		log.Printf("warning: %s is reachable from the network without auth (use --token or --user)", *addr)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	srv := &apiServer{filename: filename, guard: deleteGuard{limit: cfg.deleteLimit()}, config: cfg}
+	httpSrv := &http.Server{Addr: *addr, Handler: auth.wrap(srv.routes())}
+	if len(cfg.Feeds) > 0 {
+		go srv.pollFeeds(context.Background())
+	}
+
+	switch {
+	case *certFile != "" || *keyFile != "":
+		log.Printf("serving %s on https://%s", filename, *addr)
+		err = httpSrv.ListenAndServeTLS(*certFile, *keyFile)
+	case *selfSigned:
+		cert, cerr := selfSignedCert()
+		if cerr != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", cerr)
+			os.Exit(1)
+		}
+		httpSrv.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+		log.Printf("serving %s on https://%s (self-signed)", filename, *addr)
+		err = httpSrv.ListenAndServeTLS("", "")
+	default:
+		log.Printf("serving %s on %s", filename, *addr)
+		err = httpSrv.ListenAndServe()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func (s *apiServer) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/tasks", s.handleList)
+	mux.HandleFunc("POST /api/tasks", s.handleAdd)
+	mux.HandleFunc("POST /api/tasks/{id}/toggle", s.handleToggle)
+	mux.HandleFunc("DELETE /api/tasks/{id}", s.handleDelete)
+	mux.HandleFunc("GET /api/trash", s.handleTrash)
+	mux.HandleFunc("POST /api/trash/{id}/restore", s.handleRestore)
+	mux.HandleFunc("GET /api/activity", s.handleActivity)
+	return mux
+}
+
+// withFile runs fn on the freshly loaded lists and saves them if fn reports a
+// change, holding the write lock throughout. An unreadable file is an error
+// rather than an empty list, so a request can never overwrite it.
+func (s *apiServer) withFile(fn func(items, trash []model.Item) ([]model.Item, []model.Item, bool)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := false
+	err := withWriteLock(s.filename, func() error {
+		items, trash, err := storage.Load(s.filename)
+		if err != nil {
+			return err
+		}
+		changed := derivedIDs(items, "task")
+		changed = derivedIDs(trash, "bin") || changed
+
+		items, trash, modified := fn(items, trash)
+		trash = s.config.capBin(trash)
+		if !changed && !modified {
+			return nil
+		}
+		if err := saveList(s.filename, items, trash, s.config.cloudSafe(s.filename)); err != nil {
+			return err
+		}
+		recordHistory(s.filename, items, time.Now())
+		saved = true
+		return nil
+	})
+	if saved {
+		s.fireHook("save", nil)
+	}
+	return err
+}
+
+// readFile runs fn on the freshly loaded lists without saving them.
+func (s *apiServer) readFile(fn func(items, trash []model.Item)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items, trash, err := storage.Load(s.filename)
+	if err != nil {
+		return err
+	}
+	derivedIDs(items, "task")
+	derivedIDs(trash, "bin")
+	fn(items, trash)
+	return nil
+}
+
+// derivedIDs gives items without an id one made from their position and
+// title, the same on every load until the file changes. Returns true if
+// anything changed.
+func derivedIDs(items []model.Item, list string) bool {
+	changed := false
+	for i := range items {
+		if model.ID(items[i]) == "" {
+			sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%s", list, i, items[i].Title)))
+			items[i].Title = model.SetMeta(items[i].Title, "id", hex.EncodeToString(sum[:4]))
+			changed = true
+		}
+	}
+	return changed
+}
+
+func toAPITasks(items []model.Item) []apiTask {
+	tasks := make([]apiTask, 0, len(items))
+	for i := range items {
+		tasks = append(tasks, toAPITask(items, i))
+	}
+	return tasks
+}
+
+func toAPITask(items []model.Item, idx int) apiTask {
+	t := apiTask{
+		ID:    model.ID(items[idx]),
+		Title: model.DisplayTitle(items[idx].Title),
+		Done:  items[idx].Done,
+		Level: items[idx].Level,
+		By:    model.MetaValue(items[idx].Title, "by"),
+	}
+	if p := model.ParentIndex(items, idx); p != -1 {
+		t.Parent = model.ID(items[p])
+	}
+	return t
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+// handleList supports ?query= (see query.go) and ?offset=/&limit= paging.
+// The number of matches before paging is sent in X-Total-Count.
+func (s *apiServer) handleList(w http.ResponseWriter, r *http.Request) {
+	q, err := parseQuery(r.URL.Query().Get("query"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "query: "+err.Error())
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, err := queryInt(r, "limit", -1)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tasks := []apiTask{}
+	total := 0
+	err = s.readFile(func(items, trash []model.Item) {
+		match, _ := queryMatches(items, q, time.Now())
+		for i := range items {
+			if !match[i] {
+				continue
+			}
+			if total >= offset && (limit < 0 || len(tasks) < limit) {
+				tasks = append(tasks, toAPITask(items, i))
+			}
+			total++
+		}
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return n, nil
+}
+
+func (s *apiServer) handleTrash(w http.ResponseWriter, r *http.Request) {
+	var tasks []apiTask
+	err := s.readFile(func(items, trash []model.Item) {
+		tasks = toAPITasks(trash)
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+func (s *apiServer) handleAdd(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Title  string `json:"title"`
+		Parent string `json:"parent"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Title == "" {
+		writeError(w, http.StatusBadRequest, "expected JSON body with a non-empty title")
+		return
+	}
+
+	var task apiTask
+	status := http.StatusCreated
+	err := s.withFile(func(items, trash []model.Item) ([]model.Item, []model.Item, bool) {
+		title := model.SetMeta(expandEstimate(expandDates(req.Title, time.Now())), "created", time.Now().Format(model.DateLayout))
+		title = model.SetMeta(title, "by", requestUser(r))
+		newItem := model.Item{Title: model.SetMeta(title, "id", model.NewID())}
+		idx := len(items)
+		if req.Parent != "" {
+			p := model.FindByID(items, req.Parent)
+			if p == -1 {
+				status = http.StatusNotFound
+				return items, trash, false
+			}
+			newItem.Level = items[p].Level + 1
+			idx = model.SubtreeEnd(items, p)
+		}
+		items = append(items[:idx], append([]model.Item{newItem}, items[idx:]...)...)
+		task = toAPITask(items, idx)
+		return items, trash, true
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if status != http.StatusCreated {
+		writeError(w, status, "parent not found")
+		return
+	}
+	s.logActivity(r, "add", task)
+	writeJSON(w, status, task)
+}
+
+func (s *apiServer) handleToggle(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var task *apiTask
+	err := s.withFile(func(items, trash []model.Item) ([]model.Item, []model.Item, bool) {
+		idx := model.FindByID(items, id)
+		if idx == -1 {
+			return items, trash, false
+		}
+		setDone(items, idx, !items[idx].Done, s.config, time.Now())
+		t := toAPITask(items, idx)
+		task = &t
+		return items, trash, true
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if task == nil {
+		writeError(w, http.StatusNotFound, "task not found")
+		return
+	}
+	action := "reopen"
+	if task.Done {
+		action = "done"
+	}
+	s.logActivity(r, action, *task)
+	writeJSON(w, http.StatusOK, task)
+}
+
+// handleDelete is rate-guarded: past the per-minute limit it answers 429
+// unless ?force=1 is given, and snapshots the file before forced deletes.
+func (s *apiServer) handleDelete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	force := r.URL.Query().Get("force") == "1" || r.URL.Query().Get("force") == "true"
+	found, limited := false, false
+	var snapErr error
+	var deleted apiTask
+	err := s.withFile(func(items, trash []model.Item) ([]model.Item, []model.Item, bool) {
+		idx := model.FindByID(items, id)
+		if idx == -1 {
+			return items, trash, false
+		}
+		found = true
+		now := time.Now()
+		n := model.SubtreeEnd(items, idx) - idx
+		if !s.guard.allow(n, now) {
+			if !force {
+				limited = true
+				return items, trash, false
+			}
+			if s.guard.needsSnapshot(now) {
+				path, err := snapshotFile(s.filename, now)
+				if err != nil {
+					snapErr = err
+					return items, trash, false
+				}
+				s.guard.snapshot = now
+				log.Printf("delete limit exceeded, snapshot saved to %s", path)
+			}
+		}
+		s.guard.record(n, now)
+		deleted = toAPITask(items, idx)
+		items, trash = model.DeleteSubtree(items, trash, idx)
+		return items, trash, true
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	switch {
+	case !found:
+		writeError(w, http.StatusNotFound, "task not found")
+	case limited:
+		writeError(w, http.StatusTooManyRequests,
+			fmt.Sprintf("more than %d deletes per minute; retry with ?force=1 (the file is snapshotted first)", s.guard.limit))
+	case snapErr != nil:
+		writeError(w, http.StatusInternalServerError, "snapshot failed: "+snapErr.Error())
+	default:
+		s.logActivity(r, "delete", deleted)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *apiServer) handleRestore(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var task *apiTask
+	err := s.withFile(func(items, trash []model.Item) ([]model.Item, []model.Item, bool) {
+		idx := model.FindByID(trash, id)
+		if idx == -1 {
+			return items, trash, false
+		}
+		items, trash, idx = model.RestoreSubtree(items, trash, idx)
+		t := toAPITask(items, idx)
+		task = &t
+		return items, trash, true
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if task == nil {
+		writeError(w, http.StatusNotFound, "task not found in bin")
+		return
+	}
+	s.logActivity(r, "restore", *task)
+	writeJSON(w, http.StatusOK, task)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServeReadsDoNotSave(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	f := filepath.Join(t.TempDir(), "todo.md")
+	const list = "- [ ] a\n- [ ] b\n"
+	os.WriteFile(f, []byte(list), 0644)
+	srv := &apiServer{filename: f}
+	h := srv.routes()
+
+	var tasks []apiTask
+	for range 2 {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/tasks", nil))
+		var got []apiTask
+		json.Unmarshal(rec.Body.Bytes(), &got)
+		if len(got) != 2 || got[0].ID == "" || (tasks != nil && got[1].ID != tasks[1].ID) {
+			t.Fatalf("tasks %+v after %+v", got, tasks)
+		}
+		tasks = got
+	}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/trash", nil))
+	if data, _ := os.ReadFile(f); string(data) != list {
+		t.Errorf("a GET rewrote the file: %q", data)
+	}
+
+	// Id z GET musi działać przy pierwszym zapisie
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/api/tasks/"+tasks[1].ID+"/toggle", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("toggle: %d %s", rec.Code, rec.Body)
+	}
+	if data, _ := os.ReadFile(f); !strings.Contains(string(data), "- [x] b") || !strings.Contains(string(data), "id:"+tasks[0].ID) {
+		t.Errorf("saved %q", data)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- SESSION STATE ---
+//
+// Cursor, scroll position, folds and the active view are remembered per
+// file so reopening a long list lands where it was left. Items are matched by id, falling back
+// to the title.
+
+const sessionFile = "session.json"
+
+type session struct {
+	Cursor      string   `json:"cursor,omitempty"`
+	CursorIndex int      `json:"cursor_index"`
+	Top         string   `json:"top,omitempty"` // the first item shown
+	CursorTrash int      `json:"cursor_trash,omitempty"`
+	Collapsed   []string `json:"collapsed,omitempty"`
+	View        string   `json:"view,omitempty"`
+}
+
+var sessionViews = map[string]appState{
+	"main":   viewMain,
+	"trash":  viewTrash,
+	"stats":  viewStats,
+	"agenda": viewAgenda,
+}
+
+func sessionKey(it model.Item) string {
+	if id := model.ID(it); id != "" {
+		return id
+	}
+	return it.Title
+}
+
+func sessionPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, appName, sessionFile), nil
+}
+
+func loadSessions() map[string]session {
+	all := make(map[string]session)
+	if path, err := sessionPath(); err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			json.Unmarshal(data, &all)
+		}
+	}
+	return all
+}
+
+func (m app) saveSession() {
+	path, err := sessionPath()
+	if err != nil {
+		return
+	}
+	s := session{CursorIndex: m.cursorMain, CursorTrash: m.cursorTrash, View: "main"}
+	if len(m.visibleItems) > 0 {
+		s.Cursor = sessionKey(m.items[m.visibleItems[m.cursorMain].Index])
+		s.Top = sessionKey(m.items[m.visibleItems[min(m.listTop, len(m.visibleItems)-1)].Index])
+	}
+	for _, it := range m.items {
+		if it.Collapsed {
+			s.Collapsed = append(s.Collapsed, sessionKey(it))
+		}
+	}
+	for name, state := range sessionViews {
+		if state == m.state {
+			s.View = name
+		}
+	}
+
+	all := loadSessions()
+	all[stateKey(m.filename)] = s
+	data, _ := json.MarshalIndent(all, "", "  ")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, data, 0644)
+}
+
+func (m *app) restoreSession() {
+	s, ok := loadSessions()[stateKey(m.filename)]
+	if !ok {
+		return
+	}
+	for i := range m.items {
+		if slices.Contains(s.Collapsed, sessionKey(m.items[i])) {
+			m.items[i].Collapsed = true
+		}
+	}
+	m.recalcVisible()
+
+	m.cursorMain = min(max(0, s.CursorIndex), max(0, len(m.visibleItems)-1))
+	if s.Cursor != "" {
+		for i, it := range m.items {
+			if sessionKey(it) == s.Cursor {
+				m.jumpTo(i)
+				break
+			}
+		}
+	}
+	for i, v := range m.visibleItems {
+		if s.Top != "" && sessionKey(v.Data) == s.Top {
+			m.listTop = i // listWindow pilnuje, żeby kursor był widoczny
+			break
+		}
+	}
+	m.cursorTrash = min(max(0, s.CursorTrash), max(0, len(m.trash)-1))
+	if state, ok := sessionViews[s.View]; ok {
+		m.state = state
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- SORTING & LOCKED SECTIONS ---
+//
+// A locked item (lock:1) keeps its whole subtree in the curated order:
+// sorting and bulk sweeps skip it.
+
+func isLocked(it model.Item) bool {
+	return model.MetaValue(it.Title, "lock") != ""
+}
+
+// lockedAt reports whether items[idx] sits inside (or is) a locked section.
+func lockedAt(items []model.Item, idx int) bool {
+	for i := idx; i != -1; i = model.ParentIndex(items, i) {
+		if isLocked(items[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+var sortKeys = map[string]func(a, b model.Item) bool{
+	"title": func(a, b model.Item) bool {
+		return strings.ToLower(model.DisplayTitle(a.Title)) < strings.ToLower(model.DisplayTitle(b.Title))
+	},
+	"done": func(a, b model.Item) bool {
+		return !a.Done && b.Done
+	},
+	"due": func(a, b model.Item) bool {
+		da, _, okA := model.DueTime(a.Title)
+		db, _, okB := model.DueTime(b.Title)
+		if okA != okB {
+			return okA
+		}
+		return okA && da.Before(db)
+	},
+	"pri": func(a, b model.Item) bool {
+		pa, pb := model.MetaValue(a.Title, "pri"), model.MetaValue(b.Title, "pri")
+		if (pa == "") != (pb == "") {
+			return pa != ""
+		}
+		return pa < pb
+	},
+}
+
+// sortTree sorts siblings at every level (stable), moving subtrees as units.
+// It returns the new order as indices into items.
+func sortTree(items []model.Item, less func(a, b model.Item) bool) []int {
+	var sortRange func(lo, hi int, locked bool) []int
+	sortRange = func(lo, hi int, locked bool) []int {
+		type block struct{ head, end int }
+		var blocks []block
+		for i := lo; i < hi; {
+			end := min(model.SubtreeEnd(items, i), hi)
+			blocks = append(blocks, block{i, end})
+			i = end
+		}
+		if !locked {
+			sort.SliceStable(blocks, func(a, b int) bool {
+				return less(items[blocks[a].head], items[blocks[b].head])
+			})
+		}
+		var order []int
+		for _, b := range blocks {
+			order = append(order, b.head)
+			order = append(order, sortRange(b.head+1, b.end, locked || isLocked(items[b.head]))...)
+		}
+		return order
+	}
+	return sortRange(0, len(items), false)
+}
+
+func permute(items []model.Item, order []int) []model.Item {
+	out := make([]model.Item, len(order))
+	for i, idx := range order {
+		out[i] = items[idx]
+	}
+	return out
+}
+
+// sortItems re-orders the list and keeps the cursor on the same task.
+func (m *app) sortItems(key string) bool {
+	less, ok := sortKeys[key]
+	if !ok {
+		return false
+	}
+	cur := -1
+	if len(m.visibleItems) > 0 {
+		cur = m.visibleItems[m.cursorMain].Index
+	}
+	order := sortTree(m.items, less)
+	m.items = permute(m.items, order)
+	m.recalcVisible()
+	if cur != -1 {
+		m.jumpTo(slices.Index(order, cur))
+	}
+	return true
+}
+
+func (m *app) toggleLock(idx int) {
+	value := "1"
+	if isLocked(m.items[idx]) {
+		value = ""
+	}
+	m.items[idx].Title = model.SetMeta(m.items[idx].Title, "lock", value)
+	m.refreshItem(idx)
+	m.save()
+}
+
+// cleanDone sweeps finished tasks (with their subtrees) into the bin,
+// leaving locked sections alone.
+func cleanDone(items, trash []model.Item) ([]model.Item, []model.Item, int) {
+	swept := 0
+	for i := 0; i < len(items); {
+		if !sweepable(items, i) {
+			i++
+			continue
+		}
+		// Po usunięciu poddrzewa pod indeksem i jest już kolejny element
+		items, trash = model.DeleteSubtree(items, trash, i)
+		swept++
+	}
+	return items, trash, swept
+}
+
+// sweepable reports whether items[idx] is finished and neither it nor
+// anything around or below it is locked.
+func sweepable(items []model.Item, idx int) bool {
+	if !items[idx].Done || lockedAt(items, idx) {
+		return false
+	}
+	for k := idx + 1; k < model.SubtreeEnd(items, idx); k++ {
+		if isLocked(items[k]) {
+			return false
+		}
+	}
+	return true
+}
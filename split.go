@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- DETAIL PANE ---
+//
+// On a terminal at least "split_width" columns wide (default 120) the list
+// shares the screen with a pane describing the selected task: its dates,
+// tags and timestamps, subtask progress and note. "|" hides or shows it;
+// "[" and "]" move the divider, and the pane's share of the width is saved
+// as "split_ratio" (in percent).
+
+const (
+	defaultSplitWidth = 120
+	defaultSplitRatio = 33
+	minSplitRatio     = 20
+	maxSplitRatio     = 70
+	splitRatioStep    = 5
+	minPaneWidth      = 24
+	minListWidth      = 40
+)
+
+func (c Config) splitWidth() int {
+	if c.SplitWidth > 0 {
+		return c.SplitWidth
+	}
+	return defaultSplitWidth
+}
+
+func (c Config) splitRatio() int {
+	if c.SplitRatio > 0 {
+		return max(minSplitRatio, min(maxSplitRatio, c.SplitRatio))
+	}
+	return defaultSplitRatio
+}
+
+// showSplit reports whether the main view gets the detail pane.
+func (m app) showSplit() bool {
+	return !m.splitHidden && m.width >= max(m.config.splitWidth(), minListWidth+minPaneWidth) && len(m.visibleItems) > 0
+}
+
+// paneWidth is the width of the detail pane, leaving the list at least
+// minListWidth columns whatever the ratio.
+func (m app) paneWidth() int {
+	return max(minPaneWidth, min(m.width-minListWidth, m.width*m.config.splitRatio()/100))
+}
+
+// resizeSplit moves the divider by delta percent of the width; a positive
+// delta widens the pane.
+func (m *app) resizeSplit(delta int) {
+	if !m.showSplit() {
+		m.status = "The detail pane is hidden"
+		return
+	}
+	m.config.SplitRatio = max(minSplitRatio, min(maxSplitRatio, m.config.splitRatio()+delta))
+	m.status = fmt.Sprintf("Detail pane: %d%%", m.config.SplitRatio)
+	m.saveLayout()
+}
+
+func (m *app) toggleSplit() {
+	m.splitHidden = !m.splitHidden
+	switch {
+	case m.splitHidden:
+		m.status = "Detail pane hidden"
+	case m.width < m.config.splitWidth():
+		m.status = fmt.Sprintf("The detail pane needs a window %d columns wide (split_width)", m.config.splitWidth())
+	default:
+		m.status = "Detail pane shown"
+	}
+}
+
+// subtaskProgress counts the finished and all subtasks of items[idx].
+func subtaskProgress(items []model.Item, idx int) (done, total int) {
+	for i := idx + 1; i < model.SubtreeEnd(items, idx); i++ {
+		total++
+		if items[i].Done {
+			done++
+		}
+	}
+	return done, total
+}
+
+// paneLines describes items[idx] in lines at most width wide.
+func (m app) paneLines(idx, width int, t theme.Theme) []string {
+	it := m.items[idx]
+	label := lipgloss.NewStyle().Foreground(t.Comment).Width(10)
+	text := lipgloss.NewStyle().Foreground(t.Text)
+	heading := lipgloss.NewStyle().Foreground(t.Accent)
+
+	var lines []string
+	title := lipgloss.NewStyle().Foreground(t.Highlight).Bold(true).Width(width).Render(mdPlain(model.DisplayTitle(it.Title)))
+	lines = append(lines, strings.Split(title, "\n")...)
+	lines = append(lines, "")
+	for _, r := range m.detailInfo(it) {
+		if r[1] != "" {
+			lines = append(lines, label.Render(i18n.T(r[0]))+text.Render(ansi.Truncate(r[1], max(1, width-10), "…")))
+		}
+	}
+
+	if done, total := subtaskProgress(m.items, idx); total > 0 {
+		barW := max(0, min(20, width-10-len(fmt.Sprint(done, total))-4))
+		filled := barW * done / total
+		bar := lipgloss.NewStyle().Foreground(t.Special).Render(strings.Repeat("█", filled)) +
+			lipgloss.NewStyle().Foreground(t.Comment).Render(strings.Repeat("░", barW-filled))
+		lines = append(lines, label.Render(i18n.T("Subtasks"))+text.Render(fmt.Sprintf("%d/%d ", done, total))+bar)
+	}
+
+	if len(it.Note) > 0 {
+		lines = append(lines, "", heading.Render(i18n.T("Note")))
+		for _, line := range it.Note {
+			if ref, ok := strings.CutPrefix(line, attachPrefix); ok {
+				line = "📎 " + ref
+			}
+			wrapped := text.Width(width).Render(line)
+			lines = append(lines, strings.Split(wrapped, "\n")...)
+		}
+	}
+	return lines
+}
+
+// renderSplit draws the list on the left and the detail pane on the right.
+func (m *app) renderSplit(height int, t theme.Theme) string {
+	paneW := m.paneWidth()
+	list := *m
+	list.width = m.width - paneW
+	left := list.renderList(height, t)
+
+	lines := m.paneLines(m.visibleItems[m.cursorMain].Index, paneW-4, t)
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	pane := *m
+	pane.width = paneW
+	right := pane.frame(height, t.Comment).Padding(0, 1).Render(strings.Join(lines, "\n"))
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- CHECKBOX STATES ---
+//
+// Between open and done a task may be in a state of its own, written as the
+// checkbox mark: "- [~] task". "x" cycles the open states of the selected
+// task; with "space_cycles": true space walks open → states → done instead
+// of toggling. The states and their colors are set in config.json, a color
+// being a theme slot (accent, error, special, comment, highlight, text) or a
+// hex value:
+//
+//	"checkbox_states": [{"mark": "~", "name": "in progress", "color": "accent"}]
+//
+// Marks found in a file but not configured are kept and shown uncolored.
+
+type CheckboxState struct {
+	Mark  string `json:"mark"`
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+var defaultCheckboxStates = []CheckboxState{
+	{Mark: "~", Name: "in progress", Color: "accent"},
+	{Mark: "?", Name: "waiting", Color: "comment"},
+	{Mark: "!", Name: "urgent", Color: "error"},
+}
+
+// checkboxStates returns the usable states: single characters other than
+// the ones the file format already uses.
+func (c Config) checkboxStates() []CheckboxState {
+	if c.CheckboxStates == nil {
+		return defaultCheckboxStates
+	}
+	var out []CheckboxState
+	for _, s := range c.CheckboxStates {
+		if len([]rune(s.Mark)) == 1 && !strings.Contains(" xXD[]+", s.Mark) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (c Config) checkboxState(mark string) (CheckboxState, bool) {
+	i := slices.IndexFunc(c.checkboxStates(), func(s CheckboxState) bool { return s.Mark == mark })
+	if i == -1 {
+		return CheckboxState{Mark: mark}, false
+	}
+	return c.checkboxStates()[i], true
+}
+
+func (s CheckboxState) color(t theme.Theme) lipgloss.TerminalColor {
+	switch strings.ToLower(s.Color) {
+	case "", "text":
+		return t.Text
+	case "accent":
+		return t.Accent
+	case "error":
+		return t.Error
+	case "special":
+		return t.Special
+	case "comment":
+		return t.Comment
+	case "highlight":
+		return t.Highlight
+	}
+	return lipgloss.Color(s.Color)
+}
+
+// nextState is the open state after mark: "" (plain open) and then each
+// configured state in turn; ok is false past the last one.
+func (c Config) nextState(mark string) (string, bool) {
+	marks := []string{""}
+	for _, s := range c.checkboxStates() {
+		marks = append(marks, s.Mark)
+	}
+	i := slices.Index(marks, mark)
+	if i+1 >= len(marks) {
+		return "", false
+	}
+	return marks[i+1], true
+}
+
+// cycleState moves an open task to its next state, back to plain open after
+// the last one.
+func (m *app) cycleState(idx int) {
+	it := &m.items[idx]
+	if it.Done {
+		m.status = "Reopen the task with space first"
+		return
+	}
+	if it.Heading > 0 {
+		m.status = "A section heading can't be checked off"
+		return
+	}
+	it.State, _ = m.config.nextState(it.State)
+	m.refreshItem(idx)
+	m.save()
+	if s, ok := m.config.checkboxState(it.State); ok && s.Name != "" {
+		m.status = "[" + s.Mark + "] " + s.Name
+	}
+}
+
+// stateName is the status of a task in words, for the detail view.
+func (c Config) stateName(it model.Item) string {
+	switch {
+	case it.Done:
+		return i18n.T("done")
+	case it.State == "":
+		return i18n.T("open")
+	}
+	if s, _ := c.checkboxState(it.State); s.Name != "" {
+		return i18n.T(s.Name)
+	}
+	return "[" + it.State + "]"
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestCycleStates(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{items: []model.Item{{Title: "draft"}}}
+	m.recalcVisible()
+	var seen []string
+	for range 4 {
+		m.cycleState(0)
+		seen = append(seen, m.items[0].State)
+	}
+	if got := seen[0] + seen[1] + seen[2] + "|" + seen[3]; got != "~?!|" {
+		t.Errorf("states = %q", seen)
+	}
+
+	m.config = Config{CheckboxStates: []CheckboxState{{Mark: "x"}, {Mark: ">", Name: "delegated"}}, SpaceCycles: true}
+	m.toggleDone(0)
+	if m.items[0].State != ">" || m.items[0].Done {
+		t.Fatalf("space didn't move to the only valid state: %+v", m.items[0])
+	}
+	if m.config.stateName(m.items[0]) != "delegated" {
+		t.Errorf("name = %q", m.config.stateName(m.items[0]))
+	}
+	m.toggleDone(0)
+	if !m.items[0].Done || m.items[0].State != "" {
+		t.Fatalf("space after the last state must complete: %+v", m.items[0])
+	}
+	m.toggleDone(0)
+	if m.items[0].Done || m.items[0].State != "" {
+		t.Errorf("reopened task = %+v", m.items[0])
+	}
+}
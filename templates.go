@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- TEMPLATES ---
+//
+// ":template save <name>" stores the subtree under the cursor as
+// templates/<name>.md in the config dir (plain list markdown, reopened and
+// without bookkeeping). ":template" opens a picker; Enter inserts the chosen
+// one after the cursor's subtree, at its level.
+
+const templateDir = "templates"
+
+type taskTemplate struct {
+	name  string
+	items []model.Item
+}
+
+func templatePath(name string) (string, error) {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid template name %q", name)
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, appName, templateDir, name+".md"), nil
+}
+
+// loadTemplates reads every template, sorted by name.
+func loadTemplates() ([]taskTemplate, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(configDir, appName, templateDir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	var out []taskTemplate
+	for _, path := range paths {
+		items, _, err := storage.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, taskTemplate{name: strings.TrimSuffix(filepath.Base(path), ".md"), items: items})
+	}
+	slices.SortFunc(out, func(a, b taskTemplate) int { return strings.Compare(a.name, b.name) })
+	return out, nil
+}
+
+// saveTemplate writes the subtree at idx as a template with its root at level 0.
+func saveTemplate(name string, items []model.Item, idx int) error {
+	path, err := templatePath(name)
+	if err != nil {
+		return err
+	}
+	block := slices.Clone(items[idx:model.SubtreeEnd(items, idx)])
+	base := block[0].Level
+	for i := range block {
+		block[i].Level -= base
+		block[i].Done = false
+		block[i].Title = model.StripMeta(block[i].Title, copyResetKeys)
+		block[i].Title = model.SetMeta(block[i].Title, "created", "")
+		block[i].Before, block[i].After = nil, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return storage.Save(path, block, nil)
+}
+
+// instantiate inserts a template after the subtree at idx, at the same level
+// (at the end of the list when idx is -1), and returns where it starts.
+func instantiate(items, tmpl []model.Item, idx int, now time.Time) ([]model.Item, int) {
+	level, at := 0, len(items)
+	if idx != -1 {
+		level, at = items[idx].Level, model.SubtreeEnd(items, idx)
+	}
+	block := slices.Clone(tmpl)
+	for i := range block {
+		block[i].Level += level
+		block[i].Title = model.SetMeta(block[i].Title, "created", now.Format(model.DateLayout))
+		block[i].Before, block[i].After = nil, nil // opis szablonu zostaje w jego pliku
+	}
+	return slices.Insert(items, at, block...), at
+}
+
+func (m *app) templateCommand(arg string) {
+	sub, name, _ := strings.Cut(arg, " ")
+	switch sub {
+	case "":
+		m.openTemplates()
+	case "save":
+		if len(m.visibleItems) == 0 {
+			return
+		}
+		name = strings.TrimSpace(name)
+		if err := saveTemplate(name, m.items, m.visibleItems[m.cursorMain].Index); err != nil {
+			m.status = "Template not saved: " + err.Error()
+			return
+		}
+		m.status = "Saved template “" + name + "”"
+	default:
+		m.status = "Usage: :template [save <name>]"
+	}
+}
+
+func (m *app) openTemplates() {
+	tmpls, err := loadTemplates()
+	if err != nil {
+		m.showError("Can't read templates", err.Error())
+		return
+	}
+	if len(tmpls) == 0 {
+		m.status = "No templates yet — save one with :template save <name>"
+		return
+	}
+	m.templates = tmpls
+	m.cursorTemplate = 0
+	m.state = viewTemplates
+}
+
+func (m app) updateTemplates(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.state = viewMain
+	case "up", "k":
+		if m.cursorTemplate > 0 {
+			m.cursorTemplate--
+		}
+	case "down", "j":
+		if m.cursorTemplate < len(m.templates)-1 {
+			m.cursorTemplate++
+		}
+	case "enter":
+		if len(m.templates) == 0 {
+			break
+		}
+		idx := -1
+		if len(m.visibleItems) > 0 {
+			idx = m.visibleItems[m.cursorMain].Index
+		}
+		var at int
+		m.items, at = instantiate(m.items, m.templates[m.cursorTemplate].items, idx, time.Now())
+		m.state = viewMain
+		m.jumpTo(at)
+		m.save()
+	case "x":
+		if len(m.templates) == 0 {
+			break
+		}
+		path, err := templatePath(m.templates[m.cursorTemplate].name)
+		if err == nil {
+			err = os.Remove(path)
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			m.status = "Template not deleted: " + err.Error()
+			break
+		}
+		m.templates = slices.Delete(m.templates, m.cursorTemplate, m.cursorTemplate+1)
+		m.cursorTemplate = max(0, min(m.cursorTemplate, len(m.templates)-1))
+	}
+	return m, nil
+}
+
+func (m app) renderTemplates(height int, t theme.Theme) string {
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+	var lines []string
+	cursorLine := 0
+	for i, tmpl := range m.templates {
+		marker := "  "
+		nameStyle := lipgloss.NewStyle().Foreground(t.Text)
+		if i == m.cursorTemplate {
+			marker = " ➤"
+			nameStyle = nameStyle.Foreground(t.Highlight).Bold(true)
+			cursorLine = len(lines)
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(t.Highlight).Render(marker)+" "+
+			nameStyle.Render(tmpl.name)+dim.Render(fmt.Sprintf("  (%d tasks)", len(tmpl.items))))
+		if i == m.cursorTemplate {
+			// Podgląd zawartości wybranego szablonu
+			for _, it := range tmpl.items {
+				lines = append(lines, dim.Render("     "+strings.Repeat("  ", it.Level)+"[ ] "+model.DisplayTitle(it.Title)))
+			}
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, dim.Render("  (No templates)"))
+	}
+
+	start, end := ui.Paginator(cursorLine, height, len(lines))
+	return m.frame(height, t.Highlight).
+		Render(strings.Join(lines[start:end], "\n"))
+}
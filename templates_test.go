@@ -0,0 +1,43 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestTemplateRoundTrip(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	items := []model.Item{
+		{Title: "work"},
+		{Title: "release id:ab created:2026-01-02", Level: 1},
+		{Title: "tag spent:1h", Level: 2, Done: true},
+		{Title: "announce", Level: 2},
+	}
+	if err := saveTemplate("release checklist", items, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveTemplate("../evil", items, 1); err == nil {
+		t.Error("accepted a name with a path")
+	}
+
+	tmpls, err := loadTemplates()
+	if err != nil || len(tmpls) != 1 || tmpls[0].name != "release checklist" {
+		t.Fatalf("templates = %+v, %v", tmpls, err)
+	}
+	want := []model.Item{{Title: "release"}, {Title: "tag", Level: 1}, {Title: "announce", Level: 1}}
+	if !reflect.DeepEqual(tmpls[0].items, want) {
+		t.Errorf("template = %+v, want %+v", tmpls[0].items, want)
+	}
+
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	got, at := instantiate([]model.Item{{Title: "a"}, {Title: "a1", Level: 1}, {Title: "b"}}, tmpls[0].items, 0, now)
+	if at != 2 || len(got) != 6 || got[2].Level != 0 || got[3].Level != 1 || got[5].Title != "b" {
+		t.Errorf("at = %d, items = %+v", at, got)
+	}
+	if model.MetaValue(got[2].Title, "created") != "2026-03-01" {
+		t.Errorf("created = %q", got[2].Title)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- ROW TINTS ---
+//
+// A "%red" word in a title (or %orange, %yellow, %green, %blue, %purple,
+// %gray) draws the task in that color, e.g. to make blockers stand out;
+// %bold, %italic and %underline add emphasis. The words are hidden like
+// metadata. Colors come from the theme (Theme.Tint), so %red means the same
+// thing in every palette.
+
+// rowStyle applies the row style words of title to the style of its text.
+func rowStyle(base lipgloss.Style, title string, t theme.Theme) lipgloss.Style {
+	for _, w := range model.RowStyle(title) {
+		switch w {
+		case "bold":
+			base = base.Bold(true)
+		case "italic":
+			base = base.Italic(true)
+		case "underline":
+			base = base.Underline(true)
+		default:
+			if c, ok := t.Tint(w); ok {
+				base = base.Foreground(c)
+			}
+		}
+	}
+	return base
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- IN-APP REMINDERS ---
+//
+// Every reminder that fires while the TUI is open also pops up a toast in
+// the corner of the list, whatever the notify setting. Enter jumps to the
+// task, s/S remind again in 10 minutes / an hour, Esc dismisses. Toasts queue
+// up; other keys work as usual while one is shown.
+
+type reminderToast struct {
+	key   string // reminderKey of the task
+	kind  string // "Due" or "Overdue"
+	title string
+	at    string // due time, "" for a whole day
+}
+
+func (m *app) addToasts(idx []int, kind string) {
+	for _, i := range idx {
+		it := m.items[i]
+		t := reminderToast{key: reminderKey(it), kind: kind, title: model.DisplayTitle(it.Title)}
+		if due, hasTime, _ := model.DueTime(it.Title); hasTime {
+			t.at = due.Format("15:04")
+		}
+		m.toasts = append(m.toasts, t)
+	}
+}
+
+// updateToast handles the toast keys; false lets the key through.
+func (m *app) updateToast(key string) bool {
+	t := m.toasts[0]
+	switch key {
+	case "enter":
+		idx := -1
+		for i, it := range m.items {
+			if reminderKey(it) == t.key {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			m.status = "The task of this reminder is gone"
+			break
+		}
+		m.state = viewMain
+		m.jumpTo(idx)
+	case "s", "S":
+		d := 10 * time.Minute
+		if key == "S" {
+			d = time.Hour
+		}
+		m.reminders.snooze(t.key, time.Now().Add(d))
+		m.status = "Reminding again at " + time.Now().Add(d).Format("15:04")
+	case "esc":
+	default:
+		return false
+	}
+	m.toasts = m.toasts[1:]
+	return true
+}
+
+func (m app) renderToast(t theme.Theme) string {
+	toast := m.toasts[0]
+	width := min(44, max(20, m.width-8))
+	kindStyle := lipgloss.NewStyle().Foreground(t.Accent).Bold(true)
+	if toast.kind == "Overdue" {
+		kindStyle = kindStyle.Foreground(t.Error)
+	}
+	head := "🔔 " + toast.kind
+	if toast.at != "" {
+		head += " · " + toast.at
+	}
+	if len(m.toasts) > 1 {
+		head += fmt.Sprintf("  (+%d)", len(m.toasts)-1)
+	}
+	body := lipgloss.NewStyle().Foreground(t.Text).Render(ansi.Truncate(toast.title, width, "…"))
+	keys := lipgloss.NewStyle().Foreground(t.Comment).Render(ansi.Truncate("Enter:Jump • s:10m • S:1h • Esc:Dismiss", width, "…"))
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Highlight).
+		Padding(0, 1).
+		Render(kindStyle.Render(head) + "\n" + body + "\n" + keys)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+func TestReminderToast(t *testing.T) {
+	past := time.Now().Add(-time.Minute).Format("2006-01-02T15:04")
+	m := app{width: 80, height: 20, reminders: newReminders(), items: []model.Item{
+		{Title: "other"}, {Title: "call bank due:" + past},
+	}}
+	m.recalcVisible()
+
+	m.checkReminders()
+	if len(m.toasts) != 1 {
+		t.Fatalf("toasts = %v", m.toasts)
+	}
+	if view := m.renderToast(theme.Default); !strings.Contains(view, "call bank") || !strings.Contains(view, "s:10m") {
+		t.Errorf("toast:\n%s", view)
+	}
+	m.checkReminders()
+	if len(m.toasts) != 1 {
+		t.Fatal("a reminder fired twice")
+	}
+
+	if m.updateToast("j") {
+		t.Error("toast swallowed an unrelated key")
+	}
+	if !m.updateToast("enter") || m.visibleItems[m.cursorMain].Index != 1 || len(m.toasts) != 0 {
+		t.Errorf("enter should jump to the task and close the toast, cursor %d", m.cursorMain)
+	}
+
+	// Drzemka: przypomnienie wraca po czasie
+	m.addToasts([]int{1}, "Due")
+	m.updateToast("s")
+	key := reminderKey(m.items[1])
+	if due, _ := m.reminders.fire(m.items, time.Now()); len(due)+len(m.toasts) != 0 {
+		t.Error("snoozed reminder fired early")
+	}
+	if due, overdue := m.reminders.fire(m.items, m.reminders.again[key].Add(time.Second)); len(due)+len(overdue) != 1 {
+		t.Error("snoozed reminder didn't fire again")
+	}
+}
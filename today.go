@@ -0,0 +1,182 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/i18n"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// --- TODAY ---
+//
+// "1" shows what needs doing today, wherever it sits in the tree: open
+// tasks that are overdue, due today or starred ("*"), each with the path of
+// its parents. Folds, zoom and filters of the list don't apply. "1" may
+// also start a count ("12j", "10G"), so the view opens only when no other
+// key follows within todayKeyDelay, like vim's timeoutlen.
+
+const todayKeyDelay = 600 * time.Millisecond
+
+// todayKeyMsg comes todayKeyDelay after the "1" numbered gen.
+type todayKeyMsg struct{ gen int }
+
+func todayKeyTimeout(gen int) tea.Cmd {
+	return tea.Tick(todayKeyDelay, func(time.Time) tea.Msg { return todayKeyMsg{gen} })
+}
+
+// openTodayFromKey opens the view if the "1" of msg is still a lone count.
+func (m *app) openTodayFromKey(msg todayKeyMsg) {
+	if msg.gen != m.todayKey || m.pendingCount != 1 || m.pendingKey != "" || m.state != viewMain || m.inputMode || m.cmdMode {
+		return
+	}
+	m.pendingCount = 0
+	m.state = viewToday
+	m.cursorToday = 0
+}
+
+type todaySection int
+
+const (
+	todayOverdue todaySection = iota
+	todayDue
+	todayStarred
+)
+
+var todaySectionNames = []string{"Overdue", "Due today", "Starred"}
+
+type todayEntry struct {
+	idx     int
+	section todaySection
+}
+
+// todayEntries returns the tasks of the Today view: overdue ones first,
+// then those due today, by time, then the starred rest in file order.
+// Snoozed tasks wait for their day.
+func todayEntries(items []model.Item, now time.Time) []todayEntry {
+	today := model.StartOfDay(now)
+	var out []todayEntry
+	for i, it := range items {
+		if it.Done || isSnoozed(it.Title, today.Format(model.DateLayout)) {
+			continue
+		}
+		due, _, ok := model.DueTime(it.Title)
+		switch {
+		case ok && model.StartOfDay(due).Before(today):
+			out = append(out, todayEntry{i, todayOverdue})
+		case ok && model.StartOfDay(due).Equal(today):
+			out = append(out, todayEntry{i, todayDue})
+		case isStarred(it):
+			out = append(out, todayEntry{i, todayStarred})
+		}
+	}
+	sort.SliceStable(out, func(a, b int) bool {
+		if out[a].section != out[b].section {
+			return out[a].section < out[b].section
+		}
+		if out[a].section == todayStarred {
+			return false
+		}
+		da, _, _ := model.DueTime(items[out[a].idx].Title)
+		db, _, _ := model.DueTime(items[out[b].idx].Title)
+		return da.Before(db)
+	})
+	return out
+}
+
+func (m app) updateToday(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	entries := todayEntries(m.items, time.Now())
+	m.cursorToday = min(m.cursorToday, max(0, len(entries)-1))
+	switch msg.String() {
+	case "esc":
+		m.state = viewMain
+	case "up", "k":
+		if m.cursorToday > 0 {
+			m.cursorToday--
+		}
+	case "down", "j":
+		if m.cursorToday < len(entries)-1 {
+			m.cursorToday++
+		}
+	case "enter":
+		if len(entries) > 0 {
+			m.state = viewMain
+			m.jumpTo(entries[m.cursorToday].idx)
+		}
+	case " ":
+		if len(entries) > 0 {
+			m.toggleDone(entries[m.cursorToday].idx)
+		}
+	case "*":
+		if len(entries) > 0 {
+			m.toggleStar(entries[m.cursorToday].idx)
+		}
+	case "r":
+		if len(entries) > 0 {
+			m.openDatePopup(entries[m.cursorToday].idx, "due", "Reschedule")
+		}
+	}
+	return m, nil
+}
+
+func (m app) renderToday(height int, t theme.Theme) string {
+	entries := todayEntries(m.items, time.Now())
+	cursor := min(m.cursorToday, max(0, len(entries)-1))
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+
+	var lines []string
+	if len(entries) > 0 {
+		lines = append(lines, m.renderTodayLoad(entries, t), "")
+	}
+	cursorLine := 0
+	for i, e := range entries {
+		if i == 0 || e.section != entries[i-1].section {
+			style := lipgloss.NewStyle().Foreground(t.Accent).Bold(true)
+			if e.section == todayOverdue {
+				style = style.Foreground(t.Error)
+			}
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, style.Render(i18n.T(todaySectionNames[e.section])))
+		}
+
+		it := m.items[e.idx]
+		marker := "  "
+		titleStyle := lipgloss.NewStyle().Foreground(t.Text)
+		if i == cursor {
+			marker = " ➤"
+			titleStyle = titleStyle.Foreground(t.Highlight).Bold(true)
+			cursorLine = len(lines)
+		}
+		when := "     "
+		if due, hasTime, ok := model.DueTime(it.Title); ok && e.section == todayOverdue {
+			when = i18n.Date(due, time.Now())
+		} else if ok && hasTime {
+			when = due.Format("15:04")
+		}
+		title := model.StripMeta(model.DisplayTitle(it.Title), map[string]bool{"due": true})
+		if isStarred(it) {
+			title = "★ " + title
+		}
+		line := lipgloss.NewStyle().Foreground(t.Highlight).Render(marker) + " " +
+			dim.Render(when) + " " + titleStyle.Render(title)
+		if path := ancestorTitles(m.items, e.idx); len(path) > 1 {
+			line += dim.Render("  ‹ " + strings.Join(path[:len(path)-1], " > "))
+		}
+		lines = append(lines, ansi.Truncate(line, m.width-4, "…"))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, dim.Render("  "+i18n.T("Nothing due today")))
+	}
+
+	start, end := ui.Paginator(cursorLine, height, len(lines))
+	return m.frame(height, t.Highlight).
+		Render(strings.Join(lines[start:end], "\n"))
+}
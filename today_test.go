@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestTodayView(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	now := time.Now()
+	day := func(n int) string { return now.AddDate(0, 0, n).Format(model.DateLayout) }
+	m := app{width: 100, height: 20, items: []model.Item{
+		{Title: "Project", Collapsed: true},
+		{Title: "late due:" + day(-2), Level: 1},
+		{Title: "now due:" + day(0), Level: 1},
+		{Title: "later due:" + day(3), Level: 1},
+		{Title: "pinned star:1"},
+		{Title: "old done due:" + day(-1), Done: true},
+		{Title: "asleep due:" + day(0) + " snooze:" + day(2)},
+	}}
+	m.recalcVisible()
+
+	next, _ := m.Update(keyMsg("1"))
+	m = next.(app)
+	if m.state != viewMain {
+		t.Fatal("1 must wait for a key that would make it a count")
+	}
+	next, _ = m.Update(todayKeyMsg{gen: m.todayKey})
+	m = next.(app)
+	if m.state != viewToday {
+		t.Fatalf("state = %v", m.state)
+	}
+	var got []string
+	for _, e := range todayEntries(m.items, now) {
+		got = append(got, strings.Fields(m.items[e.idx].Title)[0])
+	}
+	if strings.Join(got, " ") != "late now pinned" {
+		t.Errorf("entries = %q", got)
+	}
+	view := m.View()
+	for _, want := range []string{"Overdue", "Due today", "Starred", "‹ Project"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view lacks %q", want)
+		}
+	}
+
+	// Enter odsłania zadanie ukryte w zwiniętym rodzicu
+	next, _ = m.Update(keyMsg("down"))
+	next, _ = next.(app).Update(keyMsg("enter"))
+	m = next.(app)
+	if m.state != viewMain || m.visibleItems[m.cursorMain].Index != 2 {
+		t.Errorf("jumped to %+v in %v", m.visibleItems[m.cursorMain], m.state)
+	}
+
+	m.pendingCount = 2
+	next, _ = m.Update(keyMsg("1"))
+	if m = next.(app); m.state != viewMain || m.pendingCount != 21 {
+		t.Errorf("a digit after a count must extend it: %v %d", m.state, m.pendingCount)
+	}
+	m.pendingCount = 0
+}
+
+func TestCountsStartingWithOne(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{width: 100, height: 40}
+	for i := range 30 {
+		m.items = append(m.items, model.Item{Title: "task " + strconv.Itoa(i)})
+	}
+	m.recalcVisible()
+	press := func(keys ...string) {
+		t.Helper()
+		for _, k := range keys {
+			next, _ := m.Update(keyMsg(k))
+			m = next.(app)
+		}
+	}
+
+	press("1", "2", "j")
+	if m.cursorMain != 12 || m.state != viewMain {
+		t.Fatalf("12j: cursor %d in %v", m.cursorMain, m.state)
+	}
+	gen := m.todayKey
+	press("1", "0", "G")
+	if m.cursorMain != 9 {
+		t.Errorf("10G: cursor %d", m.cursorMain)
+	}
+	// Spóźniony sygnał starego "1" nic nie otwiera
+	next, _ := m.Update(todayKeyMsg{gen: gen})
+	if m = next.(app); m.state != viewMain {
+		t.Error("a 1 that became a count must not open Today")
+	}
+}
@@ -0,0 +1,562 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+)
+
+// --- TODOIST ---
+//
+// `todo import --from todoist Project.csv` brings over a project exported
+// from Todoist (or a backup .zip, or a folder of such CSVs; `:import` spots
+// them too). Each project becomes a top-level task, its sections the tasks
+// under it, and its tasks and subtasks go below those. Priorities p1–p3
+// become pri:A–C, dates due: (with recur: for the simple "every …" rules),
+// @labels #tags and descriptions notes. Without a file the open tasks are
+// fetched from the Todoist API with the token from --token or
+// TODOIST_API_TOKEN. Tasks already in the list or the bin are skipped.
+//
+// `todo export --to todoist [--out DIR] [todo.md]` goes the other way: a CSV
+// per top-level task with subtasks, for Todoist's project import, and
+// Inbox.csv for the rest. Finished tasks are left out.
+
+const todoistAPI = "https://api.todoist.com/api/v1"
+
+var (
+	todoistHTTP = &http.Client{Timeout: 30 * time.Second}
+	// todoistBackupID is the " [2203306141]" Todoist adds to backup file names
+	todoistBackupID = regexp.MustCompile(`\s*\[\d+\]$`)
+	todoistHeader   = []string{"TYPE", "CONTENT", "DESCRIPTION", "PRIORITY", "INDENT", "AUTHOR", "RESPONSIBLE", "DATE", "DATE_LANG", "TIMEZONE"}
+)
+
+// isTodoistExport reports whether path is a Todoist backup zip or folder,
+// or a CSV with Todoist's header.
+func isTodoistExport(path string) bool {
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		return true
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return true
+	}
+	if !strings.EqualFold(filepath.Ext(path), ".csv") {
+		return false
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	head := make([]byte, 64)
+	n, _ := io.ReadFull(f, head)
+	head = bytes.TrimPrefix(head[:n], []byte("\ufeff"))
+	return bytes.HasPrefix(bytes.ToUpper(head), []byte("TYPE,CONTENT"))
+}
+
+// todoistProject is the project name of an exported file.
+func todoistProject(name string) string {
+	name = strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	return todoistBackupID.ReplaceAllString(name, "")
+}
+
+// readTodoist reads a CSV, a backup zip or a folder of CSVs.
+func readTodoist(path string, now time.Time) ([]importedTask, error) {
+	var tasks []importedTask
+	add := func(name string, data []byte) error {
+		project, err := parseTodoistCSV(data, todoistProject(name), now)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(name), err)
+		}
+		tasks = append(tasks, project...)
+		return nil
+	}
+
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		names, _ := filepath.Glob(filepath.Join(path, "*.csv"))
+		for _, name := range names {
+			data, err := os.ReadFile(name)
+			if err != nil {
+				return nil, err
+			}
+			if err := add(name, data); err != nil {
+				return nil, err
+			}
+		}
+		return tasks, nil
+	}
+	if !strings.EqualFold(filepath.Ext(path), ".zip") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return tasks, add(path, data)
+	}
+
+	z, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer z.Close()
+	for _, f := range z.File {
+		if !strings.EqualFold(filepath.Ext(f.Name), ".csv") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		if err := add(f.Name, data); err != nil {
+			return nil, err
+		}
+	}
+	return tasks, nil
+}
+
+// parseTodoistCSV reads a project export (TYPE, CONTENT, DESCRIPTION,
+// PRIORITY, INDENT, DATE… by header name) under a heading for the project.
+func parseTodoistCSV(data []byte, project string, now time.Time) ([]importedTask, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	col := map[string]int{}
+	for i, name := range rows[0] {
+		col[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := col["content"]; !ok {
+		return nil, fmt.Errorf("no CONTENT column in the CSV header")
+	}
+	field := func(row []string, name string) string {
+		if i, ok := col[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	tasks := []importedTask{{model.Item{Title: project}, true}}
+	base := 1 // poziom zadań bez wcięcia: pod projektem albo pod sekcją
+	last := -1
+	for _, row := range rows[1:] {
+		content := field(row, "content")
+		switch strings.ToLower(field(row, "type")) {
+		case "section":
+			tasks = append(tasks, importedTask{model.Item{Title: content, Level: 1}, true})
+			base, last = 2, -1
+		case "task":
+			indent, _ := strconv.Atoi(field(row, "indent"))
+			priority, _ := strconv.Atoi(field(row, "priority"))
+			it := model.Item{
+				Title: todoistTitle(content, priority, nil, field(row, "date"), now),
+				Level: base + max(indent, 1) - 1,
+				Note:  todoistNote(field(row, "description")),
+			}
+			tasks = append(tasks, importedTask{it, false})
+			last = len(tasks) - 1
+		case "note":
+			if last != -1 && content != "" {
+				tasks[last].Note = append(tasks[last].Note, todoistNote(content)...)
+			}
+		}
+	}
+	return tasks, nil
+}
+
+// todoistTitle is the task line of a Todoist task; priority counts from 1
+// for p1, the way the CSV does.
+func todoistTitle(content string, priority int, labels []string, date string, now time.Time) string {
+	var words []string
+	for _, w := range strings.Fields(content) {
+		if len(w) > 1 && w[0] == '@' {
+			labels = append(labels, w[1:])
+			continue
+		}
+		words = append(words, w)
+	}
+	title := strings.Join(words, " ")
+	for _, l := range labels {
+		if l = strings.Join(strings.Fields(l), "-"); l != "" {
+			title = model.SetTag(title, l, true)
+		}
+	}
+	if priority >= 1 && priority < len(priorityLevels) {
+		title = model.SetMeta(title, "pri", priorityLevels[priority])
+	}
+	due, recur := todoistDue(date, now)
+	title = model.SetMeta(title, "due", due)
+	return model.SetMeta(title, "recur", recur)
+}
+
+func todoistNote(s string) []string {
+	if s = strings.TrimSpace(strings.ReplaceAll(s, "\r\n", "\n")); s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+var todoistEvery = map[string]string{
+	"day": "daily", "days": "daily",
+	"weekday": "weekday", "workday": "weekday",
+	"week": "weekly", "weeks": "weekly",
+	"month": "monthly", "months": "monthly",
+	"year": "yearly", "years": "yearly",
+	"other day": "2d", "other week": "2w",
+}
+
+// todoistDue turns a Todoist date ("2024-05-01", "tomorrow 9:00", "every
+// monday") into a due: value and a recur: rule, empty when not understood.
+func todoistDue(s string, now time.Time) (due, recur string) {
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if s == "" {
+		return "", ""
+	}
+	if rule, ok := strings.CutPrefix(s, "every "); ok {
+		rule, start, _ := strings.Cut(rule, " starting ")
+		rule, clock, _ := strings.Cut(rule, " at ")
+		words := strings.Fields(rule)
+		switch {
+		case todoistEvery[rule] != "":
+			recur = todoistEvery[rule]
+		case len(words) == 2 && (words[1] == "days" || words[1] == "weeks"):
+			if n, err := strconv.Atoi(words[0]); err == nil && n > 0 {
+				recur = strconv.Itoa(n) + words[1][:1]
+			}
+		case len(words) == 1:
+			// "every monday": co tydzień od najbliższego poniedziałku
+			if _, _, ok := model.ParseDate(rule, now); ok {
+				recur = "weekly"
+				start = cmp.Or(start, rule)
+			}
+		}
+		if recur == "" && start == "" {
+			return "", ""
+		}
+		s = cmp.Or(start, "today")
+		if clock != "" && !strings.Contains(s, ":") {
+			s += " " + clock
+		}
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, strings.ToUpper(s), now.Location()); err == nil {
+			return model.FormatDue(t.In(now.Location()), true), recur
+		}
+	}
+	if t, hasTime, ok := model.ParseDate(s, now); ok {
+		return model.FormatDue(t, hasTime), recur
+	}
+	return "", ""
+}
+
+// --- TODOIST API ---
+
+type todoistProjectJSON struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	ParentID   string `json:"parent_id"`
+	ChildOrder int    `json:"child_order"`
+}
+
+type todoistSectionJSON struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	Name      string `json:"name"`
+	Order     int    `json:"section_order"`
+}
+
+type todoistTaskJSON struct {
+	ID          string   `json:"id"`
+	Content     string   `json:"content"`
+	Description string   `json:"description"`
+	ProjectID   string   `json:"project_id"`
+	SectionID   string   `json:"section_id"`
+	ParentID    string   `json:"parent_id"`
+	ChildOrder  int      `json:"child_order"`
+	Priority    int      `json:"priority"` // 4 to p1
+	Labels      []string `json:"labels"`
+	Due         *struct {
+		Date        string `json:"date"`
+		String      string `json:"string"`
+		IsRecurring bool   `json:"is_recurring"`
+	} `json:"due"`
+}
+
+// todoistGet fetches every page of a list endpoint of the API.
+func todoistGet[T any](ctx context.Context, base, token, path string) ([]T, error) {
+	var all []T
+	cursor := ""
+	for {
+		target := base + path
+		if cursor != "" {
+			target += "?cursor=" + url.QueryEscape(cursor)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := todoistHTTP.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Results    []T     `json:"results"`
+			NextCursor *string `json:"next_cursor"`
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("todoist %s: %s", path, resp.Status)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("todoist %s: %w", path, err)
+		}
+		all = append(all, page.Results...)
+		if page.NextCursor == nil || *page.NextCursor == "" {
+			return all, nil
+		}
+		cursor = *page.NextCursor
+	}
+}
+
+// fetchTodoist reads the open tasks of every project from the API.
+func fetchTodoist(ctx context.Context, token string, now time.Time) ([]importedTask, error) {
+	return fetchTodoistFrom(ctx, todoistAPI, token, now)
+}
+
+func fetchTodoistFrom(ctx context.Context, base, token string, now time.Time) ([]importedTask, error) {
+	projects, err := todoistGet[todoistProjectJSON](ctx, base, token, "/projects")
+	if err != nil {
+		return nil, err
+	}
+	sections, err := todoistGet[todoistSectionJSON](ctx, base, token, "/sections")
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := todoistGet[todoistTaskJSON](ctx, base, token, "/tasks")
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(projects, func(a, b todoistProjectJSON) int { return cmp.Compare(a.ChildOrder, b.ChildOrder) })
+	slices.SortStableFunc(sections, func(a, b todoistSectionJSON) int { return cmp.Compare(a.Order, b.Order) })
+	slices.SortStableFunc(tasks, func(a, b todoistTaskJSON) int { return cmp.Compare(a.ChildOrder, b.ChildOrder) })
+
+	var out []importedTask
+	var addTasks func(match func(todoistTaskJSON) bool, level int)
+	addTasks = func(match func(todoistTaskJSON) bool, level int) {
+		for _, t := range tasks {
+			if !match(t) {
+				continue
+			}
+			date := ""
+			if t.Due != nil {
+				date = t.Due.Date
+				if rule, _, _ := strings.Cut(strings.ToLower(t.Due.String), " starting "); t.Due.IsRecurring {
+					// Reguła z opisu, data z pola date
+					date = rule + " starting " + t.Due.Date
+				}
+			}
+			it := model.Item{
+				Title: todoistTitle(t.Content, 5-t.Priority, t.Labels, date, now),
+				Level: level,
+				Note:  todoistNote(t.Description),
+			}
+			out = append(out, importedTask{it, false})
+			addTasks(func(c todoistTaskJSON) bool { return c.ParentID == t.ID }, level+1)
+		}
+	}
+	var addProjects func(parent string, level int)
+	addProjects = func(parent string, level int) {
+		for _, p := range projects {
+			if p.ParentID != parent {
+				continue
+			}
+			out = append(out, importedTask{model.Item{Title: p.Name, Level: level}, true})
+			addTasks(func(t todoistTaskJSON) bool {
+				return t.ProjectID == p.ID && t.SectionID == "" && t.ParentID == ""
+			}, level+1)
+			for _, s := range sections {
+				if s.ProjectID != p.ID {
+					continue
+				}
+				out = append(out, importedTask{model.Item{Title: s.Name, Level: level + 1}, true})
+				addTasks(func(t todoistTaskJSON) bool { return t.SectionID == s.ID && t.ParentID == "" }, level+2)
+			}
+			addProjects(p.ID, level+1)
+		}
+	}
+	addProjects("", 0)
+	return out, nil
+}
+
+// --- TODOIST EXPORT ---
+
+// todoistExportKeys are the tokens that become CSV columns or mean nothing
+// to Todoist.
+var todoistExportKeys = func() map[string]bool {
+	keys := map[string]bool{"due": true, "pri": true, "recur": true, "snooze": true}
+	for k := range model.HiddenMetaKeys {
+		keys[k] = true
+	}
+	return keys
+}()
+
+var todoistRules = map[string]string{
+	"daily": "every day", "weekday": "every workday", "weekdays": "every workday",
+	"workday": "every workday", "workdays": "every workday",
+	"weekly": "every week", "monthly": "every month", "yearly": "every year",
+}
+
+// todoistRow is the CSV line of a task at the given indent (1 to 4).
+func todoistRow(it model.Item, indent int) []string {
+	var words []string
+	for _, w := range strings.Fields(model.StripMeta(it.Title, todoistExportKeys)) {
+		if len(w) > 1 && w[0] == '#' {
+			w = "@" + w[1:]
+		}
+		words = append(words, w)
+	}
+	priority := "4"
+	if p := slices.Index(priorityLevels, model.MetaValue(it.Title, "pri")); p > 0 {
+		priority = strconv.Itoa(p)
+	}
+	date := ""
+	if due, hasTime, ok := model.DueTime(it.Title); ok {
+		date = due.Format(model.DateLayout)
+		if hasTime {
+			date = due.Format("2006-01-02 15:04")
+		}
+		rule := model.MetaValue(it.Title, "recur")
+		if every, ok := todoistRules[strings.ToLower(rule)]; ok {
+			date = every + " starting " + date
+		} else if n, err := strconv.Atoi(strings.TrimRight(rule, "dw")); err == nil && len(rule) > 1 {
+			unit := map[byte]string{'d': "days", 'w': "weeks"}[rule[len(rule)-1]]
+			date = fmt.Sprintf("every %d %s starting %s", n, unit, date)
+		}
+	}
+	return []string{"task", strings.Join(words, " "), strings.Join(it.Note, "\n"), priority, strconv.Itoa(min(indent, 4)), "", "", date, "en", ""}
+}
+
+type todoistFile struct {
+	name string
+	rows [][]string
+}
+
+// todoistExport splits the open tasks into project CSVs: one per top-level
+// task with subtasks, the lone top-level tasks going to the Inbox.
+func todoistExport(items []model.Item) []todoistFile {
+	inbox := todoistFile{name: "Inbox", rows: [][]string{todoistHeader}}
+	var files []todoistFile
+	for i := 0; i < len(items); i = model.SubtreeEnd(items, i) {
+		if items[i].Done {
+			continue
+		}
+		if !model.HasChildren(items, i) {
+			inbox.rows = append(inbox.rows, todoistRow(items[i], 1))
+			continue
+		}
+		f := todoistFile{name: model.StripMeta(items[i].Title, todoistExportKeys), rows: [][]string{todoistHeader}}
+		for j := i + 1; j < model.SubtreeEnd(items, i); j++ {
+			if items[j].Done {
+				j = model.SubtreeEnd(items, j) - 1
+				continue
+			}
+			f.rows = append(f.rows, todoistRow(items[j], items[j].Level-items[i].Level))
+		}
+		files = append(files, f)
+	}
+	if len(inbox.rows) > 1 {
+		files = append(files, inbox)
+	}
+	return files
+}
+
+// todoistFileName makes a project name safe as a file name.
+func todoistFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < ' ' {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" || name == "." || name == ".." {
+		name = "Project"
+	}
+	return name + ".csv"
+}
+
+func writeTodoist(dir string, files []todoistFile) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, f := range files {
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		w.WriteAll(f.rows)
+		if err := w.Error(); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, todoistFileName(f.name)), buf.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	to := fs.String("to", "", `target format ("todoist")`)
+	out := fs.String("out", "todoist", "directory to write the CSV files to")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: todo export --to todoist [--out DIR] [todo.md]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if !strings.EqualFold(*to, "todoist") {
+		fs.Usage()
+		os.Exit(2)
+	}
+	filename := "todo.md"
+	if fs.NArg() > 0 {
+		filename = fs.Arg(0)
+	}
+	items, _, err := storage.Load(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	files := todoistExport(items)
+	if err := writeTodoist(*out, files); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Exported %d projects to %s\n", len(files), *out)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+const todoistCSV = "\ufeffTYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE\n" +
+	"task,Call mom @family,,1,1,Ann,,2026-10-20,en,Europe/Warsaw\n" +
+	"task,Bring flowers,,4,2,Ann,,,en,\n" +
+	"note,Roses or tulips,,,,,,,,\n" +
+	"section,Errands,,,,,,,,\n" +
+	"task,Water plants,\"Balcony\nand kitchen\",3,1,Ann,,every monday at 9:00,en,\n"
+
+func TestParseTodoistCSV(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local) // piątek
+	tasks, err := parseTodoistCSV([]byte(todoistCSV), todoistProject("Home [2203306141].csv"), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, n := importTasks(nil, nil, tasks)
+	want := []string{
+		"0:Home:false",
+		"1:Call mom #family pri:A due:2026-10-20:false",
+		"2:Bring flowers:false",
+		"1:Errands:false",
+		"2:Water plants pri:C due:" + model.FormatDue(time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local), true) + " recur:weekly:false",
+	}
+	if got := flat(items); !slices.Equal(got, want) || n != 3 {
+		t.Errorf("imported %d:\n%q", n, got)
+	}
+	if !slices.Equal(items[2].Note, []string{"Roses or tulips"}) || !slices.Equal(items[4].Note, []string{"Balcony", "and kitchen"}) {
+		t.Errorf("notes = %q, %q", items[2].Note, items[4].Note)
+	}
+	if _, n := importTasks(items, nil, tasks); n != 0 {
+		t.Errorf("re-import added %d tasks", n)
+	}
+}
+
+func TestTodoistDue(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	for in, want := range map[string]string{
+		"":                               "|",
+		"tomorrow":                       "2026-10-17|",
+		"every day":                      "2026-10-16|daily",
+		"every 3 weeks starting oct 20":  "2026-10-20|3w",
+		"every other day":                "2026-10-16|2d",
+		"every last friday of the month": "|",
+		"sometime soon":                  "|",
+	} {
+		due, recur := todoistDue(in, now)
+		if got := due + "|" + recur; got != want {
+			t.Errorf("todoistDue(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFetchTodoist(t *testing.T) {
+	pages := map[string]string{
+		"/projects": `{"results":[{"id":"p2","name":"Sub","parent_id":"p1","child_order":1},{"id":"p1","name":"Work","child_order":0}],"next_cursor":null}`,
+		"/sections": `{"results":[{"id":"s1","project_id":"p1","name":"Later","section_order":1}],"next_cursor":null}`,
+		"/tasks":    `{"results":[{"id":"t2","content":"Child","project_id":"p1","parent_id":"t1","priority":1}],"next_cursor":"c2"}`,
+		"/tasks c2": `{"results":[{"id":"t1","content":"Report","project_id":"p1","priority":4,"labels":["q4"],"due":{"date":"2026-10-20","string":"every week","is_recurring":true}},{"id":"t3","content":"Plan","project_id":"p1","section_id":"s1","priority":2},{"id":"t4","content":"Deep","project_id":"p2","priority":1}],"next_cursor":null}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "nope", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(pages[strings.TrimSpace(r.URL.Path+" "+r.URL.Query().Get("cursor"))]))
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	tasks, err := fetchTodoistFrom(context.Background(), srv.URL, "secret", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, _ := importTasks(nil, nil, tasks)
+	want := []string{
+		"0:Work:false",
+		"1:Report #q4 pri:A due:2026-10-20 recur:weekly:false",
+		"2:Child:false",
+		"1:Later:false",
+		"2:Plan pri:C:false",
+		"1:Sub:false",
+		"2:Deep:false",
+	}
+	if got := flat(items); !slices.Equal(got, want) {
+		t.Errorf("tasks:\n%q", got)
+	}
+	if _, err := fetchTodoistFrom(context.Background(), srv.URL, "wrong", now); err == nil {
+		t.Error("a rejected token must be an error")
+	}
+}
+
+func TestTodoistExport(t *testing.T) {
+	items := []model.Item{
+		{Title: "Work #job"},
+		{Title: "Report pri:A due:2026-10-20 recur:2w id:ab12", Level: 1, Note: []string{"draft", "final"}},
+		{Title: "Child #q4", Level: 2},
+		{Title: "Old", Level: 1, Done: true},
+		{Title: "Under old", Level: 2},
+		{Title: "Milk"},
+		{Title: "Gone", Done: true},
+	}
+	files := todoistExport(items)
+	if len(files) != 2 || files[0].name != "Work #job" || files[1].name != "Inbox" {
+		t.Fatalf("files = %+v", files)
+	}
+	rows := files[0].rows
+	if len(rows) != 3 {
+		t.Fatalf("rows = %q", rows)
+	}
+	if got := strings.Join(rows[1], ","); got != "task,Report,draft\nfinal,1,1,,,every 2 weeks starting 2026-10-20,en," {
+		t.Errorf("row = %q", got)
+	}
+	if got := strings.Join(rows[2][:5], ","); got != "task,Child @q4,,4,2" {
+		t.Errorf("row = %q", got)
+	}
+
+	dir := t.TempDir()
+	if err := writeTodoist(dir, files); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := readTodoist(dir, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, _ := importTasks(nil, nil, tasks)
+	want := []string{"0:Inbox:false", "1:Milk:false", "0:Work #job:false", "1:Report pri:A due:2026-10-20 recur:2w:false", "2:Child #q4:false"}
+	if got := flat(back); !slices.Equal(got, want) {
+		t.Errorf("round trip = %q", got)
+	}
+}
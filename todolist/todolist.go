@@ -0,0 +1,209 @@
+// Package todolist is the task tree of todo as a Bubble Tea component, for
+// charm-based tools that want to embed a todo pane. It reads and writes the
+// same markdown files as the todo app, bin included:
+//
+//	list, err := todolist.New("todo.md")
+//	...
+//	list.SetSize(40, 20)
+//
+// and is then driven like any tea.Model. Keys: up/k, down/j, g/G, space
+// toggles done, v folds. Every change is written back by the returned
+// command, which reports the result as a SavedMsg.
+package todolist
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+	"github.com/pawello85/todo/internal/theme"
+	"github.com/pawello85/todo/internal/ui"
+)
+
+// Item is one line of the list: its title with inline key:value metadata,
+// done state, fold state and depth, or a "#" section heading.
+type Item = model.Item
+
+// SavedMsg reports the write that followed a change; Err is nil on success.
+type SavedMsg struct {
+	Filename string
+	Err      error
+}
+
+// Styles are the colors of the pane.
+type Styles struct {
+	Cursor  lipgloss.Style
+	Title   lipgloss.Style
+	Done    lipgloss.Style
+	Check   lipgloss.Style
+	Checked lipgloss.Style
+	Folded  lipgloss.Style
+	Guide   lipgloss.Style
+}
+
+// DefaultStyles follow the built-in theme of the todo app.
+func DefaultStyles() Styles {
+	t := theme.Default
+	return Styles{
+		Cursor:  lipgloss.NewStyle().Foreground(t.Highlight),
+		Title:   lipgloss.NewStyle().Foreground(t.Text),
+		Done:    lipgloss.NewStyle().Foreground(t.Comment).Strikethrough(true),
+		Check:   lipgloss.NewStyle().Foreground(t.Text),
+		Checked: lipgloss.NewStyle().Foreground(t.Special),
+		Folded:  lipgloss.NewStyle().Foreground(t.Accent),
+		Guide:   lipgloss.NewStyle().Foreground(t.Comment),
+	}
+}
+
+// Model is the pane; create it with New or NewFromItems.
+type Model struct {
+	Styles Styles
+	// Focused panes react to keys; an unfocused one only renders.
+	Focused bool
+
+	filename string
+	items    []Item
+	trash    []Item
+	visible  []model.VisibleItem
+	cursor   int
+	width    int
+	height   int
+}
+
+// New loads filename. A missing file gives an empty list that is created on
+// the first change.
+func New(filename string) (Model, error) {
+	items, trash, err := storage.Load(filename)
+	if err != nil {
+		return Model{}, err
+	}
+	m := NewFromItems(items)
+	m.filename = filename
+	m.trash = trash
+	return m, nil
+}
+
+// NewFromItems shows items without a backing file; changes are kept in
+// memory only.
+func NewFromItems(items []Item) Model {
+	m := Model{Styles: DefaultStyles(), Focused: true, items: items}
+	m.refresh()
+	return m
+}
+
+// Items returns the current list.
+func (m Model) Items() []Item { return m.items }
+
+// Selected returns the item under the cursor.
+func (m Model) Selected() (Item, bool) {
+	if len(m.visible) == 0 {
+		return Item{}, false
+	}
+	return m.visible[m.cursor].Data, true
+}
+
+// SetSize sets the pane size; a height of 0 shows every row.
+func (m *Model) SetSize(width, height int) {
+	m.width, m.height = width, height
+}
+
+func (m *Model) refresh() {
+	m.visible = model.Visible(m.items, nil)
+	m.cursor = max(0, min(m.cursor, len(m.visible)-1))
+}
+
+func (m Model) save() tea.Cmd {
+	if m.filename == "" {
+		return nil
+	}
+	filename, items, trash := m.filename, m.items, m.trash
+	return func() tea.Msg {
+		return SavedMsg{Filename: filename, Err: storage.Save(filename, items, trash)}
+	}
+}
+
+func (m Model) Init() tea.Cmd { return nil }
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok || !m.Focused || len(m.visible) == 0 {
+		return m, nil
+	}
+	switch key.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.visible)-1 {
+			m.cursor++
+		}
+	case "g", "home":
+		m.cursor = 0
+	case "G", "end":
+		m.cursor = len(m.visible) - 1
+	case " ":
+		// Kopia, żeby nie zmieniać listy widzianej przez wcześniejsze wartości Model
+		idx := m.visible[m.cursor].Index
+		if m.items[idx].Heading > 0 {
+			break // nagłówków sekcji się nie odhacza
+		}
+		m.items = append([]Item(nil), m.items...)
+		m.items[idx].Done = !m.items[idx].Done
+		m.refresh()
+		return m, m.save()
+	case "v":
+		idx := m.visible[m.cursor].Index
+		m.items = append([]Item(nil), m.items...)
+		if model.ToggleFold(m.items, idx) {
+			m.refresh()
+			return m, m.save()
+		}
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	if len(m.visible) == 0 {
+		return m.Styles.Guide.Render("  (No tasks)")
+	}
+	from, to := 0, len(m.visible)
+	if m.height > 0 {
+		from, to = ui.Paginator(m.cursor, m.height, len(m.visible))
+	}
+	guides := ui.TreeGuides(m.visible, from, to)
+
+	var rows []string
+	for i := from; i < to; i++ {
+		it, g := m.visible[i].Data, guides[i-from]
+		cursor := "  "
+		if i == m.cursor && m.Focused {
+			cursor = " ➤"
+		}
+		check, checkStyle, titleStyle := "[ ]", m.Styles.Check, m.Styles.Title
+		switch {
+		case it.Heading > 0 && it.Collapsed:
+			check, checkStyle = " ▸ ", m.Styles.Folded
+		case it.Heading > 0:
+			check, checkStyle = " ▾ ", m.Styles.Folded
+		case it.Collapsed:
+			check, checkStyle = "[+]", m.Styles.Folded
+		case it.Done:
+			check, checkStyle = "[✔]", m.Styles.Checked
+		}
+		if it.Done {
+			titleStyle = m.Styles.Done
+		}
+		title := model.DisplayTitle(it.Title)
+		if m.width > 0 {
+			// Jedna linia na zadanie; za długie tytuły obcinamy
+			title = ansi.Truncate(title, max(1, m.width-2-ui.GuidePrefixWidth(it.Level)-3-1), "…")
+		}
+		rows = append(rows, m.Styles.Cursor.Render(cursor)+m.Styles.Guide.Render(g.Prefix+g.Connector)+
+			checkStyle.Render(check)+" "+titleStyle.Render(title))
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,69 @@
+package todolist
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func key(k string) tea.KeyMsg {
+	switch k {
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+func TestModelTogglesAndSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.md")
+	if err := os.WriteFile(path, []byte("- [ ] Groceries\n  - [ ] Milk\n  - [ ] Bread\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	list, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var model tea.Model = list
+	model, _ = model.Update(key("j"))
+	model, cmd := model.Update(key(" "))
+	if cmd == nil {
+		t.Fatal("toggle returned no save command")
+	}
+	if msg := cmd().(SavedMsg); msg.Err != nil {
+		t.Fatal(msg.Err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "- [x] Milk") {
+		t.Errorf("file after toggle:\n%s", data)
+	}
+	if !model.(Model).Items()[1].Done || list.Items()[1].Done {
+		t.Error("toggle must change the new model only")
+	}
+
+	view := model.View()
+	if !strings.Contains(view, "Groceries") || !strings.Contains(view, "[✔]") {
+		t.Errorf("view:\n%s", view)
+	}
+
+	// Zwinięcie rodzica chowa dzieci
+	model, _ = model.Update(key("k"))
+	model, _ = model.Update(key("v"))
+	if view := model.View(); strings.Contains(view, "Milk") || !strings.Contains(view, "[+]") {
+		t.Errorf("folded view:\n%s", view)
+	}
+}
+
+func TestNewFromItemsHasNoSave(t *testing.T) {
+	list := NewFromItems([]Item{{Title: "a"}})
+	if _, cmd := list.Update(key(" ")); cmd != nil {
+		t.Error("in-memory list must not save")
+	}
+	list.SetSize(20, 1)
+	if strings.Count(list.View(), "\n") != 0 {
+		t.Errorf("height 1 view:\n%s", list.View())
+	}
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+)
+
+// --- TIME TRACKING ---
+//
+// The accumulated total lives in spent:<duration>, a running session in
+// timer:<unix start>, so tracking survives restarts. Finished sessions are
+// also appended to a log in the config dir for per-day reporting.
+
+const timeLogFile = "timelog.jsonl"
+
+type trackTickMsg struct{}
+
+type timeLogEntry struct {
+	File  string    `json:"file"`
+	ID    string    `json:"id"`
+	Title string    `json:"title"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func trackTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return trackTickMsg{}
+	})
+}
+
+func spentTime(title string) time.Duration {
+	d, _ := time.ParseDuration(model.MetaValue(title, "spent"))
+	return d
+}
+
+func timerStart(title string) (time.Time, bool) {
+	v, err := strconv.ParseInt(model.MetaValue(title, "timer"), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(v, 0), true
+}
+
+// formatSpent is the compact token form: "1h30m", "45m", "20s".
+func formatSpent(d time.Duration) string {
+	s := d.Round(time.Second).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// trackedIndex returns the item with a running timer, or -1.
+func trackedIndex(items []model.Item) int {
+	for i, it := range items {
+		if _, ok := timerStart(it.Title); ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// stopTimer books the running session of items[idx] and returns it.
+func stopTimer(items []model.Item, idx int, now time.Time) (timeLogEntry, bool) {
+	start, ok := timerStart(items[idx].Title)
+	if !ok {
+		return timeLogEntry{}, false
+	}
+	title := items[idx].Title
+	title = model.SetMeta(title, "timer", "")
+	title = model.SetMeta(title, "spent", formatSpent(spentTime(title)+now.Sub(start)))
+	items[idx].Title = title
+	return timeLogEntry{ID: model.ID(items[idx]), Title: model.DisplayTitle(title), Start: start, End: now}, true
+}
+
+func (m *app) toggleTracking() tea.Cmd {
+	if len(m.visibleItems) == 0 {
+		return nil
+	}
+	realIdx := m.visibleItems[m.cursorMain].Index
+	now := time.Now()
+
+	running := trackedIndex(m.items)
+	if running != -1 {
+		if entry, ok := stopTimer(m.items, running, now); ok {
+			entry.File = stateKey(m.filename)
+			appendTimeLog(entry)
+			m.status = "⏱ Stopped: " + entry.Title + " (" + model.FormatDuration(entry.End.Sub(entry.Start)) + ")"
+		}
+	}
+
+	var cmd tea.Cmd
+	if running != realIdx {
+		if model.ID(m.items[realIdx]) == "" {
+			m.items[realIdx].Title = model.SetMeta(m.items[realIdx].Title, "id", model.NewID())
+		}
+		m.items[realIdx].Title = model.SetMeta(m.items[realIdx].Title, "timer", strconv.FormatInt(now.Unix(), 10))
+		m.status = "⏱ Tracking: " + model.DisplayTitle(m.items[realIdx].Title)
+		cmd = trackTick()
+	}
+
+	m.recalcVisible()
+	m.save()
+	return cmd
+}
+
+// trackingLabel is the running-timer suffix rendered in the tracked row.
+func trackingLabel(title string) string {
+	start, ok := timerStart(title)
+	if !ok {
+		return ""
+	}
+	d := time.Since(start).Round(time.Second)
+	return fmt.Sprintf(" ⏱ %d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+// --- TIME LOG ---
+
+func timeLogPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, appName, timeLogFile), nil
+}
+
+func appendTimeLog(e timeLogEntry) {
+	path, err := timeLogPath()
+	if err != nil {
+		return
+	}
+	os.MkdirAll(filepath.Dir(path), 0755)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	data, _ := json.Marshal(e)
+	f.Write(append(data, '\n'))
+}
+
+func readTimeLog(filename string) []timeLogEntry {
+	path, err := timeLogPath()
+	if err != nil {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	key := stateKey(filename)
+	var out []timeLogEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e timeLogEntry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil && e.File == key {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// --- REPORT ---
+
+type dayTotal struct {
+	day   string
+	title string
+	spent time.Duration
+}
+
+// perDay splits sessions at midnight and sums them per day and task.
+func perDay(entries []timeLogEntry) []dayTotal {
+	sums := make(map[[2]string]time.Duration)
+	for _, e := range entries {
+		for start := e.Start; start.Before(e.End); {
+			next := model.StartOfDay(start).AddDate(0, 0, 1)
+			end := e.End
+			if next.Before(end) {
+				end = next
+			}
+			sums[[2]string{start.Format(model.DateLayout), e.Title}] += end.Sub(start)
+			start = end
+		}
+	}
+	var out []dayTotal
+	for k, d := range sums {
+		out = append(out, dayTotal{day: k[0], title: k[1], spent: d})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].day != out[j].day {
+			return out[i].day < out[j].day
+		}
+		return out[i].title < out[j].title
+	})
+	return out
+}
+
+func writeReport(w io.Writer, items []model.Item, entries []timeLogEntry) {
+	fmt.Fprintln(w, "Per task")
+	now := time.Now()
+	var total time.Duration
+	for _, it := range items {
+		d := spentTime(it.Title)
+		if start, ok := timerStart(it.Title); ok {
+			d += now.Sub(start)
+		}
+		if d == 0 {
+			continue
+		}
+		total += d
+		fmt.Fprintf(w, "  %8s  %s\n", model.FormatDuration(d), model.DisplayTitle(it.Title))
+	}
+	fmt.Fprintf(w, "  %8s  total\n\nPer day\n", model.FormatDuration(total))
+
+	days := perDay(entries)
+	for i := 0; i < len(days); {
+		j := i
+		var sum time.Duration
+		for ; j < len(days) && days[j].day == days[i].day; j++ {
+			sum += days[j].spent
+		}
+		fmt.Fprintf(w, "  %s  %8s\n", days[i].day, model.FormatDuration(sum))
+		for k := i; k < j; k++ {
+			fmt.Fprintf(w, "    %8s  %s\n", model.FormatDuration(days[k].spent), days[k].title)
+		}
+		i = j
+	}
+}
+
+func writeReportCSV(w io.Writer, entries []timeLogEntry) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"date", "task", "minutes"})
+	for _, d := range perDay(entries) {
+		cw.Write([]string{d.day, d.title, strconv.FormatFloat(d.spent.Minutes(), 'f', 1, 64)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// exportReport writes <file>.timereport.csv next to the todo file.
+func (m *app) exportReport() {
+	path := m.filename + ".timereport.csv"
+	f, err := os.Create(path)
+	if err != nil {
+		m.status = "Report failed: " + err.Error()
+		return
+	}
+	defer f.Close()
+	if err := writeReportCSV(f, readTimeLog(m.filename)); err != nil {
+		m.status = "Report failed: " + err.Error()
+		return
+	}
+	m.status = "Report written to " + path
+}
+
+func runReport(args []string) {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	asCSV := fs.Bool("csv", false, "print per-day totals as CSV")
+	fs.Parse(args)
+
+	filename := "todo.md"
+	if fs.NArg() > 0 {
+		filename = fs.Arg(0)
+	}
+
+	entries := readTimeLog(filename)
+	if *asCSV {
+		writeReportCSV(os.Stdout, entries)
+		return
+	}
+	items, _, err := storage.Load(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	writeReport(os.Stdout, items, entries)
+}
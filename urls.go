@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- URLS ---
+//
+// http(s) URLs in titles are underlined in the Accent color (markdown.go
+// styles them along with the rest of the inline markup), and "o" opens the
+// first one of the selected task in the default browser ("2o" the second,
+// and so on).
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// titleURLs returns the URLs of a title in order, without trailing
+// punctuation that belongs to the sentence.
+func titleURLs(title string) []string {
+	var out []string
+	for _, span := range urlSpans(title) {
+		out = append(out, title[span[0]:span[1]])
+	}
+	return out
+}
+
+func urlSpans(s string) [][2]int {
+	var out [][2]int
+	for _, loc := range urlPattern.FindAllStringIndex(s, -1) {
+		end := loc[1]
+		for end > loc[0] && strings.ContainsRune(".,;:!?)]}'", rune(s[end-1])) {
+			end--
+		}
+		if end-loc[0] > len("https://") {
+			out = append(out, [2]int{loc[0], end})
+		}
+	}
+	return out
+}
+
+// systemOpen hands a URL or path to the desktop's default handler.
+var systemOpen = func(target string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", target)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	default:
+		cmd = exec.Command("xdg-open", target)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// openURL opens the n-th (from 1) URL of items[idx].
+func (m *app) openURL(idx, n int) {
+	urls := titleURLs(model.DisplayTitle(m.items[idx].Title))
+	switch {
+	case len(urls) == 0:
+		m.status = "No link in this task"
+		return
+	case n > len(urls):
+		m.status = fmt.Sprintf("This task has %d links", len(urls))
+		return
+	}
+	if err := systemOpen(urls[n-1]); err != nil {
+		m.showError("Can't open the link", err.Error())
+		return
+	}
+	m.status = "Opened " + urls[n-1]
+}
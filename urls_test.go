@@ -0,0 +1,46 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestTitleURLs(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	got := titleURLs("read https://go.dev/doc/effective_go. then (see http://example.com/a?b=1) https:// #go")
+	want := []string{"https://go.dev/doc/effective_go", "http://example.com/a?b=1"}
+	if !slices.Equal(got, want) {
+		t.Errorf("urls = %q, want %q", got, want)
+	}
+}
+
+func TestOpenURLKey(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	var opened []string
+	defer func(f func(string) error) { systemOpen = f }(systemOpen)
+	systemOpen = func(target string) error {
+		opened = append(opened, target)
+		return nil
+	}
+
+	m := app{items: []model.Item{{Title: "compare https://a.example and https://b.example"}, {Title: "no link"}}}
+	m.recalcVisible()
+	press := func(k string) {
+		t.Helper()
+		next, _ := m.updateMain(keyMsg(k))
+		m = next.(app)
+	}
+	press("o")
+	press("2")
+	press("o")
+	press("3")
+	press("o")
+	if want := []string{"https://a.example", "https://b.example"}; !slices.Equal(opened, want) {
+		t.Errorf("opened = %q, want %q", opened, want)
+	}
+	if m.status != "This task has 2 links" {
+		t.Errorf("status = %q", m.status)
+	}
+}
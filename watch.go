@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+// --- FILE WATCHING ---
+//
+// Polling the modification time is enough here and keeps us free of
+// platform-specific notification APIs.
+
+const watchInterval = time.Second
+
+type fileCheckMsg struct{}
+
+func watchFile() tea.Cmd {
+	return tea.Tick(watchInterval, func(time.Time) tea.Msg {
+		return fileCheckMsg{}
+	})
+}
+
+func fileModTime(filename string) time.Time {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// reloadIfChanged picks up edits made by other processes (e.g. `todo serve`).
+func (m *app) reloadIfChanged() {
+	if m.holdsIndices() || m.dirty || m.saving {
+		return
+	}
+	mod := m.writer.modTime(m.filename)
+	if mod.Equal(m.fileModTime) {
+		return
+	}
+	m.fileModTime = mod
+	m.reload()
+}
+
+// holdsIndices tells whether an editor or view keeps positions in m.items
+// that a reload or a sort would invalidate.
+func (m *app) holdsIndices() bool {
+	return m.inputMode || m.fieldEditing || m.propOpen || m.dateOpen || m.moving || m.blockPick ||
+		m.state == viewDetail || m.state == viewPlan || m.state == viewReplace
+}
+
+// reload re-reads the file, keeping folds of unchanged items.
+func (m *app) reload() {
+	collapsed := make(map[string]bool)
+	for _, it := range m.items {
+		if it.Collapsed {
+			collapsed[it.Title] = true
+		}
+	}
+
+	items, trash, err := m.loadList()
+	if err != nil {
+		m.showError("Could not reload "+filepath.Base(m.filename), err.Error()+"\nKeeping the list in memory.")
+		return
+	}
+	m.items, m.trash = items, trash
+	m.checkConflicts()
+	for i := range m.items {
+		m.items[i].Collapsed = collapsed[m.items[i].Title]
+	}
+	m.recalcVisible()
+	if m.state == viewDetail {
+		// Indeks szczegółów mógł się zdezaktualizować
+		m.state = viewMain
+		m.fieldEditing = false
+	}
+	if m.cursorTrash >= len(m.trash) {
+		m.cursorTrash = max(0, len(m.trash)-1)
+	}
+}
+
+// --- THEME RELOADING ---
+//
+// The themes.json files are polled on the same tick, so theme authors see
+// their edits without restarting. The active theme is looked up again by
+// name; if it is gone the first theme takes over.
+
+func loadThemes() {
+	themes = theme.Load(appName)
+	if len(themes) == 0 {
+		themes = []theme.Theme{theme.Default}
+	}
+}
+
+// themeByName returns the named theme and its index, or the first theme.
+func themeByName(name string) (theme.Theme, int) {
+	for i, t := range themes {
+		if t.Name == name {
+			return t, i
+		}
+	}
+	return themes[0], 0
+}
+
+// themesStamp sums up the modification times of the themes.json files.
+func themesStamp() string {
+	files := themeFiles()
+	var stamp strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(files)) {
+		fmt.Fprintf(&stamp, "%d;", fileModTime(files[key]).UnixNano())
+	}
+	return stamp.String()
+}
+
+func (m *app) reloadThemesIfChanged() {
+	stamp := themesStamp()
+	if stamp == m.themesMod {
+		return
+	}
+	m.themesMod = stamp
+	m.reloadThemes()
+}
+
+func (m *app) reloadThemes() {
+	selected := themes[min(m.cursorTheme, len(themes)-1)].Name
+	loadThemes()
+	m.activeTheme, _ = themeByName(m.activeTheme.Name)
+	_, m.cursorTheme = themeByName(selected)
+	// Wiersze w pamięci mają stare kolory
+	m.rows = newRenderCache()
+	m.status = "Themes reloaded"
+}
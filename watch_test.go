@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestThemeHotReload(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	os.WriteFile("themes.json", []byte(`[{"name":"Mine","base":"#111111"},{"name":"Other","base":"#222222"}]`), 0644)
+
+	m := initialModel("todo.md")
+	m.config.SelectedTheme = "Mine"
+	m.activeTheme, _ = themeByName("Mine")
+	m.cursorTheme = 1
+
+	os.WriteFile("themes.json", []byte(`[{"name":"New"},{"name":"Other"},{"name":"Mine","base":"#333333"}]`), 0644)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes("themes.json", later, later)
+	m.reloadThemesIfChanged()
+	if m.activeTheme.Name != "Mine" || string(m.activeTheme.Base) != "#333333" {
+		t.Errorf("active theme = %+v", m.activeTheme)
+	}
+	if themes[m.cursorTheme].Name != "Other" {
+		t.Errorf("selector cursor on %q", themes[m.cursorTheme].Name)
+	}
+
+	os.WriteFile("themes.json", []byte(`[{"name":"New"}]`), 0644)
+	os.Chtimes("themes.json", later.Add(time.Minute), later.Add(time.Minute))
+	m.reloadThemesIfChanged()
+	if m.activeTheme.Name != themes[0].Name {
+		t.Errorf("removed theme kept: %q", m.activeTheme.Name)
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+)
+
+// --- FIRST-RUN WIZARD ---
+//
+// Started without a file argument, with no config and no todo.md in the
+// current folder, todo asks three things before opening the list: the
+// theme, where the list lives (saved as "file" in config.json, which is
+// then opened by default) and whether to start from an example showing
+// subtasks, folding and the bin. Esc on the first step takes the defaults
+// (an empty ./todo.md); either way the config is written, so the wizard
+// shows only once.
+
+const (
+	wizardTheme = iota
+	wizardPlace
+	wizardExample
+	wizardSteps
+)
+
+type wizardChoice struct {
+	label string
+	file  string // the config "file" value, "" for ./todo.md
+}
+
+type wizard struct {
+	step    int
+	cursors [wizardSteps]int
+	places  []wizardChoice
+	width   int
+	height  int
+	done    bool
+	aborted bool
+}
+
+// needsWizard reports whether this is a first run: no config anywhere and
+// no list in the current folder.
+func needsWizard() bool {
+	if _, err := os.Stat("todo.md"); err == nil {
+		return false
+	}
+	if _, err := os.Stat(configFile); err == nil {
+		return false
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(configDir, appName, configFile))
+	return os.IsNotExist(err)
+}
+
+func newWizard() wizard {
+	places := []wizardChoice{{"This folder (./todo.md)", ""}}
+	if configDir, err := os.UserConfigDir(); err == nil {
+		path := abbreviateHome(filepath.Join(configDir, appName, "todo.md"))
+		places = append(places, wizardChoice{"Config folder (" + path + ")", path})
+	}
+	if _, err := os.UserHomeDir(); err == nil {
+		path := "~" + string(filepath.Separator) + "todo.md"
+		places = append(places, wizardChoice{"Home folder (" + path + ")", path})
+	}
+	return wizard{places: places}
+}
+
+func (w wizard) Init() tea.Cmd { return nil }
+
+func (w wizard) options() int {
+	switch w.step {
+	case wizardTheme:
+		return len(themes)
+	case wizardPlace:
+		return len(w.places)
+	}
+	return 2
+}
+
+func (w wizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		w.width, w.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		c := &w.cursors[w.step]
+		switch msg.String() {
+		case "ctrl+c":
+			w.aborted = true
+			return w, tea.Quit
+		case "up", "k":
+			*c = max(0, *c-1)
+		case "down", "j":
+			*c = min(w.options()-1, *c+1)
+		case "esc":
+			if w.step == wizardTheme {
+				w.cursors = [wizardSteps]int{wizardExample: 1} // bez przykładu
+				w.done = true
+				return w, tea.Quit
+			}
+			w.step--
+		case "enter":
+			w.step++
+			if w.step == wizardSteps {
+				w.done = true
+				return w, tea.Quit
+			}
+		}
+	}
+	return w, nil
+}
+
+func (w wizard) View() string {
+	if w.done || w.aborted {
+		return ""
+	}
+	t := shownTheme(themes[w.cursors[wizardTheme]])
+	title := lipgloss.NewStyle().Foreground(t.Highlight).Bold(true)
+	dim := lipgloss.NewStyle().Foreground(t.Comment)
+	text := lipgloss.NewStyle().Foreground(t.Text)
+
+	var question string
+	var options []string
+	switch w.step {
+	case wizardTheme:
+		question = "Pick a theme"
+		for _, th := range themes {
+			th = shownTheme(th)
+			swatch := ""
+			for _, c := range []lipgloss.Color{th.Highlight, th.Accent, th.Special, th.Error} {
+				swatch += lipgloss.NewStyle().Foreground(c).Render("██")
+			}
+			options = append(options, swatch+" "+th.Name)
+		}
+	case wizardPlace:
+		question = "Where should the list live?"
+		for _, p := range w.places {
+			options = append(options, p.label)
+		}
+	case wizardExample:
+		question = "Start with an example list?"
+		options = []string{"Yes, show me subtasks, folding and the bin", "No, start empty"}
+	}
+
+	lines := []string{
+		title.Render("Welcome to todo") + dim.Render(fmt.Sprintf("    step %d/%d", w.step+1, wizardSteps)),
+		"",
+		text.Render(question),
+		"",
+	}
+	for i, o := range options {
+		if i == w.cursors[w.step] {
+			lines = append(lines, title.Render("➤ ")+text.Bold(true).Render(o))
+		} else {
+			lines = append(lines, "  "+text.Render(o))
+		}
+	}
+	back := "Esc:Back"
+	if w.step == wizardTheme {
+		back = "Esc:Skip"
+	}
+	lines = append(lines, "", dim.Render("↑↓:Choose • Enter:Next • "+back))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Accent).
+		Padding(1, 3).
+		Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(w.width, w.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// finish saves the choices and returns the list file to open.
+func (w wizard) finish() (string, error) {
+	place := w.places[min(w.cursors[wizardPlace], len(w.places)-1)]
+	cfg := Config{SelectedTheme: themes[w.cursors[wizardTheme]].Name, File: place.file}
+	if err := saveConfig(cfg); err != nil {
+		return "", err
+	}
+	filename := "todo.md"
+	if place.file != "" {
+		filename = expandHome(place.file)
+	}
+	if w.cursors[wizardExample] != 0 {
+		return filename, nil
+	}
+	if _, err := os.Stat(filename); err == nil {
+		return filename, nil // istniejącej listy nie nadpisujemy
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return "", err
+	}
+	items, trash := exampleList(time.Now())
+	return filename, storage.Save(filename, items, trash)
+}
+
+// exampleList is the list offered on first run.
+func exampleList(now time.Time) (items, trash []model.Item) {
+	due := now.AddDate(0, 0, 2).Format(model.DateLayout)
+	items = []model.Item{
+		{Title: "Welcome to todo! j/k move, space completes a task"},
+		{Title: "Subtasks sit under their parent: m adds one, > and < indent", Level: 1},
+		{Title: "v folds a task with its subtasks – try it on “Welcome” above", Level: 1},
+		{Title: "Plan the weekend #home due:" + due},
+		{Title: "Buy groceries", Level: 1},
+		{Title: "Call the plumber", Level: 1, Done: true},
+		{Title: "d moves a task to the bin; B opens it and Enter restores"},
+		{Title: "n adds a task, e edits one, : runs commands, q quits"},
+	}
+	trash = []model.Item{{Title: "An old task waiting in the bin"}}
+	return items, trash
+}
+
+// runWizard shows the wizard and returns the list file to open; ok is false
+// when it was cancelled with ctrl+c.
+func runWizard() (filename string, ok bool, err error) {
+	loadThemes()
+	final, err := tea.NewProgram(newWizard(), tea.WithAltScreen()).Run()
+	if err != nil {
+		return "", false, err
+	}
+	w := final.(wizard)
+	if w.aborted {
+		return "", false, nil
+	}
+	filename, err = w.finish()
+	return filename, err == nil, err
+}
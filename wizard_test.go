@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pawello85/todo/internal/storage"
+	"github.com/pawello85/todo/internal/theme"
+)
+
+func TestWizard(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Chdir(t.TempDir())
+	saved := themes
+	t.Cleanup(func() { themes = saved })
+	themes = []theme.Theme{theme.Default, {Name: "Other"}}
+	if !needsWizard() {
+		t.Fatal("a first run must show the wizard")
+	}
+
+	var m tea.Model = newWizard()
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	for _, k := range []string{"down", "enter", "down", "down", "enter"} {
+		m, _ = m.Update(keyMsg(k))
+	}
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "step 3/3") || !strings.Contains(view, "Start with an example") {
+		t.Errorf("view:\n%s", view)
+	}
+	m, cmd := m.Update(keyMsg("enter"))
+	w := m.(wizard)
+	if !w.done || cmd == nil {
+		t.Fatal("the last step must finish the wizard")
+	}
+
+	filename, err := w.finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(home, "todo.md"); filename != want {
+		t.Errorf("list at %s, want %s", filename, want)
+	}
+	cfg, _ := loadConfig()
+	if cfg.SelectedTheme != "Other" || cfg.File != "~"+string(filepath.Separator)+"todo.md" {
+		t.Errorf("config = %+v", cfg)
+	}
+	if defaultFile() != filename {
+		t.Errorf("defaultFile = %s", defaultFile())
+	}
+	items, trash, _ := storage.Load(filename)
+	if len(items) == 0 || items[1].Level != 1 || len(trash) == 0 {
+		t.Errorf("example list: %d items, %d in the bin", len(items), len(trash))
+	}
+	if needsWizard() {
+		t.Error("the wizard must show only once")
+	}
+
+	// Esc na początku bierze domyślne ustawienia i nie tworzy przykładu
+	os.Remove(filepath.Join(home, ".config", appName, configFile))
+	m, _ = newWizard().Update(keyMsg("esc"))
+	filename, err = m.(wizard).finish()
+	if err != nil || filename != "todo.md" {
+		t.Errorf("skipped wizard opens %s (%v)", filename, err)
+	}
+	if _, err := os.Stat("todo.md"); err == nil {
+		t.Error("skipping must not seed an example")
+	}
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+	"github.com/pawello85/todo/internal/storage"
+)
+
+// --- WORKSPACE (todo all) ---
+//
+// `todo all [PATTERN…]` opens several lists as one, for one file per
+// project: every file becomes a top-level heading with its tasks below
+// it, and saving writes each file back from under its heading, leaving the
+// untouched ones alone. Without patterns the "workspaces" of config.json are
+// opened. Tasks moved under another heading move to that file; a new task
+// goes to the file under the cursor. The headings themselves can't be
+// edited, deleted or moved. Each file keeps its own bin and lock, and an
+// edit to any of them from outside reloads the whole view.
+
+// workspace is the set of files shown together. Headings carry ws:N (the
+// file's index) and id:wsN, so deleted tasks find their way back under them;
+// bin entries carry ws:N too, to be written to the file they came from.
+type workspace struct {
+	name         string // the list's name in the header, e.g. ~/projects/*.md
+	files        []string
+	writeThrough []bool
+
+	mu          sync.Mutex
+	lists, bins [][]model.Item // what each file holds on disk
+	locks       []*fileLock
+}
+
+// newWorkspace collects the files matching patterns, or the configured
+// workspaces without any.
+func newWorkspace(patterns []string, cfg Config) (*workspace, error) {
+	if len(patterns) == 0 {
+		patterns = cfg.Workspaces
+	}
+	if len(patterns) == 0 {
+		return nil, errors.New("no files: pass globs or set \"workspaces\" in config.json")
+	}
+	var missing []string
+	files := workspaceFiles(Config{Workspaces: patterns}, func(pattern string) { missing = append(missing, pattern) })
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("no file matches %s", strings.Join(missing, ", "))
+	}
+	ws := &workspace{files: files, name: workspaceName(files)}
+	for _, f := range files {
+		ws.writeThrough = append(ws.writeThrough, cfg.cloudSafe(f))
+	}
+	return ws, nil
+}
+
+// workspaceName is the folder the files share with a glob for them.
+func workspaceName(files []string) string {
+	dir, ext := filepath.Dir(files[0]), filepath.Ext(files[0])
+	for _, f := range files[1:] {
+		for !strings.HasPrefix(filepath.Dir(f)+string(filepath.Separator), dir+string(filepath.Separator)) && dir != filepath.Dir(dir) {
+			dir = filepath.Dir(dir)
+		}
+		if filepath.Ext(f) != ext {
+			ext = ""
+		}
+	}
+	return filepath.Join(dir, "*"+ext)
+}
+
+func headingID(file int) string {
+	return "ws" + strconv.Itoa(file)
+}
+
+// headingFile reports which file it heads, if it is a workspace heading.
+func headingFile(it model.Item, n int) (int, bool) {
+	file, err := strconv.Atoi(model.MetaValue(it.Title, "ws"))
+	if err != nil || file < 0 || file >= n || it.Level != 0 || model.ID(it) != headingID(file) {
+		return 0, false
+	}
+	return file, true
+}
+
+// load reads every file and merges them under their headings.
+func (ws *workspace) load() (items, trash []model.Item, err error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	lists, bins := make([][]model.Item, len(ws.files)), make([][]model.Item, len(ws.files))
+	for i, f := range ws.files {
+		if lists[i], bins[i], err = storage.Load(f); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", f, err)
+		}
+		name := strings.TrimSuffix(filepath.Base(f), filepath.Ext(f))
+		items = append(items, model.Item{Title: fmt.Sprintf("%s ws:%d id:%s", name, i, headingID(i)), Heading: 1})
+		for _, it := range lists[i] {
+			it.Level++
+			items = append(items, it)
+		}
+		for s := 0; s < len(bins[i]); {
+			_, end := model.TrashBlock(bins[i], s)
+			block := slices.Clone(bins[i][s:end])
+			// Wpisy spod pliku wracają pod jego nagłówek
+			if from := model.MetaValue(block[0].Title, "from"); from == "" || from == "-" {
+				block[0].Title = model.SetMeta(block[0].Title, "from", headingID(i))
+			}
+			block[0].Title = model.SetMeta(block[0].Title, "ws", strconv.Itoa(i))
+			for _, it := range block {
+				it.Level++
+				trash = append(trash, it)
+			}
+			s = end
+		}
+	}
+	ws.lists, ws.bins = lists, bins
+	return items, trash, nil
+}
+
+// splitWorkspace cuts the merged lists back into a list and a bin per file.
+// Tasks above the first heading go to the first file.
+func splitWorkspace(items, trash []model.Item, n int) (lists, bins [][]model.Item) {
+	lists, bins = make([][]model.Item, n), make([][]model.Item, n)
+	file := 0
+	for _, it := range items {
+		if f, ok := headingFile(it, n); ok {
+			file = f
+			continue
+		}
+		it.Title = model.SetMeta(it.Title, "ws", "")
+		it.Level = max(0, it.Level-1)
+		it.Collapsed = false
+		lists[file] = append(lists[file], it)
+	}
+	for s := 0; s < len(trash); {
+		_, end := model.TrashBlock(trash, s)
+		file := 0
+		if f, err := strconv.Atoi(model.MetaValue(trash[s].Title, "ws")); err == nil && f >= 0 && f < n {
+			file = f
+		}
+		for k, it := range trash[s:end] {
+			if k == 0 {
+				it.Title = model.SetMeta(it.Title, "ws", "")
+				if model.MetaValue(it.Title, "from") == headingID(file) {
+					it.Title = model.SetMeta(it.Title, "from", "-")
+				}
+			}
+			it.Level = max(0, it.Level-1)
+			bins[file] = append(bins[file], it)
+		}
+		s = end
+	}
+	return lists, bins
+}
+
+// sameList compares lists as written, folds aside.
+func sameList(a, b []model.Item) bool {
+	return slices.EqualFunc(a, b, func(x, y model.Item) bool {
+		return x.Title == y.Title && x.Done == y.Done && x.Level == y.Level && x.State == y.State &&
+			slices.Equal(x.Note, y.Note) && slices.Equal(x.Before, y.Before) && slices.Equal(x.After, y.After)
+	})
+}
+
+// save writes the files whose part of the merged lists changed, merging
+// changes made to them outside the app as listWriter does.
+func (ws *workspace) save(items, trash []model.Item) (merged bool, err error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	lists, bins := splitWorkspace(items, trash, len(ws.files))
+	for i, f := range ws.files {
+		if sameList(lists[i], ws.lists[i]) && sameList(bins[i], ws.bins[i]) {
+			continue
+		}
+		list, bin := lists[i], bins[i]
+		err := withWriteLock(f, func() error {
+			var m bool
+			list, bin, m = mergeOnDisk(f, ws.lists[i], ws.bins[i], list, bin, true)
+			merged = merged || m
+			return saveList(f, list, bin, ws.writeThrough[i])
+		})
+		if err != nil {
+			return merged, fmt.Errorf("%s: %w", filepath.Base(f), err)
+		}
+		ws.lists[i], ws.bins[i] = lists[i], bins[i]
+		recordHistory(f, list, time.Now())
+	}
+	return merged, nil
+}
+
+// modTime is the latest modification of the files.
+func (ws *workspace) modTime() time.Time {
+	var latest time.Time
+	for _, f := range ws.files {
+		if mod := fileModTime(f); mod.After(latest) {
+			latest = mod
+		}
+	}
+	return latest
+}
+
+// lock takes the lock of every file; errLocked when any is held elsewhere.
+func (ws *workspace) lock() error {
+	var held error
+	for _, f := range ws.files {
+		l, err := acquireLock(f)
+		if err != nil {
+			held = cmp.Or(held, err)
+			continue
+		}
+		ws.locks = append(ws.locks, l)
+	}
+	return held
+}
+
+func (ws *workspace) release() {
+	for _, l := range ws.locks {
+		l.release()
+	}
+	ws.locks = nil
+}
+
+// --- APP SIDE ---
+
+// loadList reads the list from disk: the file, or every file of the
+// workspace.
+func (m *app) loadList() ([]model.Item, []model.Item, error) {
+	if m.workspace != nil {
+		return m.workspace.load()
+	}
+	items, trash, err := storage.Load(m.filename)
+	if err == nil {
+		m.writer.loaded(items, trash)
+	}
+	return items, trash, err
+}
+
+// headingAt returns the index of the workspace heading above items[idx], or
+// -1 outside a workspace.
+func (m app) headingAt(idx int) int {
+	if m.workspace == nil {
+		return -1
+	}
+	for i := min(idx, len(m.items)-1); i >= 0; i-- {
+		if _, ok := headingFile(m.items[i], len(m.workspace.files)); ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// headingKeys are the main view keys refused on a workspace heading.
+var headingKeys = map[string]bool{
+	" ": true, "x": true, "e": true, "d": true, "delete": true, ">": true, "<": true, "tab": true,
+	"M": true, "D": true, "s": true, "L": true,
+}
+
+// guardHeading refuses key on a workspace heading.
+func (m *app) guardHeading(key string, idx int) bool {
+	if idx == -1 || !headingKeys[key] || m.headingAt(idx) != idx {
+		return false
+	}
+	m.status = "That's the heading of " + abbreviateHome(m.workspace.files[m.fileAt(idx)]) + "; it can't be changed"
+	return true
+}
+
+// fileAt is the index of the file items[idx] is written to.
+func (m app) fileAt(idx int) int {
+	if h := m.headingAt(idx); h != -1 {
+		file, _ := headingFile(m.items[h], len(m.workspace.files))
+		return file
+	}
+	return 0
+}
+
+// tagWorkspaceBin marks bin entries with the file they were deleted from,
+// looked up while their parent is still around.
+func (m *app) tagWorkspaceBin() {
+	for s := 0; s < len(m.trash); {
+		_, end := model.TrashBlock(m.trash, s)
+		root := &m.trash[s]
+		if p := model.FindByID(m.items, model.MetaValue(root.Title, "from")); p != -1 {
+			root.Title = model.SetMeta(root.Title, "ws", strconv.Itoa(m.fileAt(p)))
+		} else if model.MetaValue(root.Title, "ws") == "" {
+			root.Title = model.SetMeta(root.Title, "ws", "0")
+		}
+		s = end
+	}
+}
+
+func runWorkspace(args []string) {
+	fs := flag.NewFlagSet("all", flag.ExitOnError)
+	inline := fs.Bool("inline", false, "draw in place instead of on the whole screen")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: todo all [--inline] [PATTERN ...]")
+		fmt.Fprintln(fs.Output(), "Opens the files matching the patterns (or the \"workspaces\" of config.json) as one list.")
+	}
+	fs.Parse(args)
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	ws, err := newWorkspace(fs.Args(), cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	defer ws.release()
+	m := openModel(ws.name, ws)
+	m.inline = *inline
+	runTUI(ws.name, m)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestWorkspace(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	alpha, beta, other := filepath.Join(dir, "alpha.md"), filepath.Join(dir, "beta.md"), filepath.Join(dir, "notes.txt")
+	os.WriteFile(alpha, []byte("- [ ] a1\n  - [ ] a2\n- [D] gone from:-\n"), 0644)
+	os.WriteFile(beta, []byte("- [x] b1\n"), 0644)
+	os.WriteFile(other, []byte("- [ ] not in the glob\n"), 0644)
+
+	if _, err := newWorkspace(nil, Config{}); err == nil {
+		t.Error("no patterns and no workspaces must be refused")
+	}
+	ws, err := newWorkspace([]string{filepath.Join(dir, "*.md")}, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws.name != filepath.Join(dir, "*.md") {
+		t.Errorf("name = %q", ws.name)
+	}
+	m := openModel(ws.name, ws)
+	defer ws.release()
+	want := []string{"0:alpha ws:0 id:ws0:false", "1:a1:false", "2:a2:false", "0:beta ws:1 id:ws1:false", "1:b1:true"}
+	if got := flat(m.items); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("merged = %q", got)
+	}
+	if model.DisplayTitle(m.items[0].Title) != "alpha" {
+		t.Errorf("heading shows %q", model.DisplayTitle(m.items[0].Title))
+	}
+
+	// Nagłówków nie da się usunąć ani przesunąć
+	m.cursorMain = 0
+	next, _ := m.Update(keyMsg("d"))
+	m = next.(app)
+	if len(m.items) != 5 || !strings.Contains(m.status, "alpha.md") {
+		t.Fatalf("heading deleted: %q", m.status)
+	}
+
+	// a2 przenosimy pod beta, a nowe zadanie trafia do pliku pod kursorem
+	m.items = append(m.items, model.Item{Title: "a2", Level: 1})
+	m.items = append(m.items[:2], m.items[3:]...)
+	m.recalcVisible()
+	m.jumpTo(4)
+	next, _ = m.Update(keyMsg("n"))
+	m = next.(app)
+	for _, r := range "b2" {
+		next, _ = m.Update(keyMsg(string(r)))
+		m = next.(app)
+	}
+	next, _ = m.Update(keyMsg("enter"))
+	m = next.(app)
+	m.jumpTo(1)
+	next, _ = m.Update(keyMsg("d"))
+	m = next.(app)
+	if err := m.flushSave(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(alpha)
+	if got := string(data); got != "- [D] gone from:-\n- [D] a1 from:-\n" {
+		t.Errorf("alpha.md = %q", got)
+	}
+	data, _ = os.ReadFile(beta)
+	if got := string(data); !strings.HasPrefix(got, "- [x] b1\n- [ ] a2\n- [ ] b2 created:") {
+		t.Errorf("beta.md = %q", got)
+	}
+	if data, _ := os.ReadFile(other); string(data) != "- [ ] not in the glob\n" {
+		t.Errorf("notes.txt = %q", data)
+	}
+
+	// Przywrócony wpis wraca pod swój nagłówek
+	m.state = viewTrash
+	m.cursorTrash = 1
+	next, _ = m.Update(keyMsg("enter"))
+	m = next.(app)
+	if got := flat(m.items)[1]; !strings.HasPrefix(got, "1:a1") {
+		t.Errorf("restored to %q", got)
+	}
+}
+
+func TestSplitWorkspaceUnchangedFilesKept(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.md"), filepath.Join(dir, "b.md")
+	os.WriteFile(a, []byte("- [ ] one\n"), 0644)
+	os.WriteFile(b, []byte("- [ ] two\n"), 0644)
+	stamp := time.Now().Add(-time.Hour).Truncate(time.Second)
+	os.Chtimes(b, stamp, stamp)
+
+	ws := &workspace{files: []string{a, b}, writeThrough: []bool{false, false}}
+	items, trash, err := ws.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	items[1].Done = true
+	if _, err := ws.save(items, trash); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(a); string(data) != "- [x] one\n" {
+		t.Errorf("a.md = %q", data)
+	}
+	if !fileModTime(b).Equal(stamp) {
+		t.Error("b.md was rewritten without a change")
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+// --- ZOOM ---
+//
+// z narrows the main list to the subtree under the cursor, like org-mode's
+// narrowing; the header names the focused task and Esc brings back the whole
+// list. The zoomed task is remembered by id, so edits and saves that move it
+// keep the zoom; it ends by itself once the task is gone.
+
+func (m *app) zoomRoot() int {
+	if m.zoomID == "" {
+		return -1
+	}
+	return model.FindByID(m.items, m.zoomID)
+}
+
+// narrow keeps the visible items inside the zoomed subtree.
+func (m *app) narrow(visible []model.VisibleItem) []model.VisibleItem {
+	root := m.zoomRoot()
+	if root == -1 {
+		m.zoomID = ""
+		return visible
+	}
+	end := model.SubtreeEnd(m.items, root)
+	return slices.DeleteFunc(visible, func(v model.VisibleItem) bool { return v.Index < root || v.Index >= end })
+}
+
+func (m *app) zoomIn(idx int) {
+	if model.ID(m.items[idx]) == "" {
+		m.items[idx].Title = model.SetMeta(m.items[idx].Title, "id", model.NewID())
+		m.save()
+	}
+	m.zoomID = model.ID(m.items[idx])
+	m.items[idx].Collapsed = false
+	m.recalcVisible()
+	m.cursorMain = 0
+}
+
+func (m *app) zoomOut() {
+	idx := -1
+	if len(m.visibleItems) > 0 {
+		idx = m.visibleItems[m.cursorMain].Index
+	}
+	m.zoomID = ""
+	m.recalcVisible()
+	if idx != -1 {
+		m.jumpTo(idx)
+	}
+}
+
+// zoomTitle is the focused task for the header, "" when not zoomed.
+func (m *app) zoomTitle() string {
+	if root := m.zoomRoot(); root != -1 {
+		return model.DisplayTitle(m.items[root].Title)
+	}
+	return ""
+}
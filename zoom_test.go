@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/pawello85/todo/internal/model"
+)
+
+func TestZoom(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := app{width: 80, height: 20, items: []model.Item{
+		{Title: "Home"}, {Title: "Work"}, {Title: "Backend", Level: 1}, {Title: "Auth", Level: 2}, {Title: "Later"},
+	}}
+	m.recalcVisible()
+	press := func(k string) {
+		t.Helper()
+		next, _ := m.updateMain(keyMsg(k))
+		m = next.(app)
+	}
+
+	m.cursorMain = 1
+	press("z")
+	if got := m.shownTitles(); len(got) != 3 || got[0] != "Work" || got[2] != "Auth" {
+		t.Fatalf("zoomed list = %v", got)
+	}
+	if m.zoomTitle() != "Work" {
+		t.Errorf("zoom title = %q", m.zoomTitle())
+	}
+
+	// Nowe zadanie w powiększeniu zostaje w poddrzewie
+	press("n")
+	m.inputBuf = "Frontend"
+	m.handleInputConfirm()
+	if it := m.items[4]; model.DisplayTitle(it.Title) != "Frontend" || it.Level != 1 {
+		t.Errorf("new item = %+v", it)
+	}
+
+	m.cursorMain = 2
+	press("esc")
+	if m.zoomID != "" || len(m.visibleItems) != 6 || m.visibleItems[m.cursorMain].Index != 3 {
+		t.Errorf("esc should show everything with the cursor kept on Auth, cursor %d", m.cursorMain)
+	}
+
+	// Skok poza powiększenie je kończy
+	m.cursorMain = 1
+	press("z")
+	m.jumpTo(0)
+	if m.zoomID != "" || m.cursorMain != 0 {
+		t.Error("jumping out of the zoom should end it")
+	}
+}
+
+func (m app) shownTitles() []string {
+	var out []string
+	for _, v := range m.visibleItems {
+		out = append(out, model.DisplayTitle(v.Data.Title))
+	}
+	return out
+}